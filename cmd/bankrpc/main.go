@@ -0,0 +1,181 @@
+// ============================================
+// 银行 gRPC 服务
+// ============================================
+//
+// 运行：
+//   go run ./cmd/bankrpc                        # 本机启动服务并运行客户端演示
+//   go run ./cmd/bankrpc -listen :9090          # 只启动服务，Ctrl+C 优雅退出
+//   go run ./cmd/bankrpc -connect localhost:9090 # 对已有服务运行客户端演示
+// ============================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"c03/pkg/bank"
+	"c03/pkg/bankrpc"
+	"c03/pkg/bankrpc/bankpb"
+)
+
+func main() {
+	listen := flag.String("listen", "", "只运行服务端，监听该地址")
+	connect := flag.String("connect", "", "只运行客户端，连接该地址")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch {
+	case *listen != "":
+		ln, err := net.Listen("tcp", *listen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := serve(ctx, ln); err != nil {
+			log.Fatal(err)
+		}
+	case *connect != "":
+		if err := runClient(ctx, *connect); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- serve(ctx, ln) }()
+		if err := runClient(ctx, ln.Addr().String()); err != nil {
+			log.Print(err)
+		}
+		cancel()
+		if err := <-done; err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// serve 运行服务直到 ctx 取消，然后 GracefulStop：不再接受新调用，等待进行中的调用完成
+func serve(ctx context.Context, ln net.Listener) error {
+	srv := bankrpc.NewServer(bank.NewBank())
+	srv.HistoryDelay = 50 * time.Millisecond
+	gs := bankrpc.NewGRPCServer(srv, log.New(os.Stdout, "  [server] ", 0))
+
+	stop := context.AfterFunc(ctx, gs.GracefulStop)
+	defer stop()
+	log.Printf("gRPC server listening on %s", ln.Addr())
+	if err := gs.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func runClient(ctx context.Context, addr string) error {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(bankrpc.UnaryClientLogging(log.New(os.Stdout, "  [client] ", 0))),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := bankpb.NewBankClient(conn)
+
+	// 每次调用都带截止时间，服务端通过 ctx 感知
+	call := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, time.Second)
+	}
+
+	c, cancel := call()
+	alice, err := client.Open(c, &bankpb.OpenRequest{Owner: "张三", InitialBalance: 1000})
+	cancel()
+	if err != nil {
+		return err
+	}
+	c, cancel = call()
+	bob, err := client.Open(c, &bankpb.OpenRequest{Owner: "李四"})
+	cancel()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("开户: %s(%s) 余额 %.2f，%s(%s) 余额 %.2f\n",
+		alice.Owner, alice.Id, alice.Balance, bob.Owner, bob.Id, bob.Balance)
+
+	for _, amount := range []float64{200, 300, 100} {
+		c, cancel = call()
+		_, err := client.Transfer(c, &bankpb.TransferRequest{From: alice.Id, To: bob.Id, Amount: amount})
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+
+	// 业务错误以状态码的形式返回
+	c, cancel = call()
+	_, err = client.Withdraw(c, &bankpb.AmountRequest{Id: bob.Id, Amount: 1e6})
+	cancel()
+	fmt.Printf("超额取款: code=%s msg=%s\n", status.Code(err), status.Convert(err).Message())
+
+	// 服务端流：逐条接收流水
+	c, cancel = call()
+	stream, err := client.History(c, &bankpb.GetRequest{Id: alice.Id})
+	if err != nil {
+		cancel()
+		return err
+	}
+	fmt.Printf("%s 的流水:\n", alice.Owner)
+	for {
+		tx, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cancel()
+			return err
+		}
+		fmt.Printf("  %s %-12s %8.2f 余额 %8.2f %s\n",
+			tx.CreatedAt.AsTime().Format(time.TimeOnly), tx.Type, tx.Amount, tx.BalanceAfter, tx.Counterparty)
+	}
+	cancel()
+
+	// 截止时间短于服务端推送所需的时间：流在中途以 DeadlineExceeded 结束
+	c, cancel = context.WithTimeout(ctx, 120*time.Millisecond)
+	defer cancel()
+	stream, err = client.History(c, &bankpb.GetRequest{Id: alice.Id})
+	if err != nil {
+		return err
+	}
+	received := 0
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) == codes.DeadlineExceeded {
+				fmt.Printf("超时前收到 %d 条流水，随后: %s\n", received, status.Code(err))
+				return nil
+			}
+			if err == io.EOF {
+				fmt.Printf("收到全部 %d 条流水\n", received)
+				return nil
+			}
+			return err
+		}
+		received++
+	}
+}
@@ -0,0 +1,82 @@
+// ============================================
+// 银行 REST 服务
+// ============================================
+//
+// 运行：
+//   go run ./cmd/bankserver -addr :8080
+//   go run ./cmd/bankserver -addr 127.0.0.1:8080 -pprof   同时开启 /debug/pprof/
+//   go run ./cmd/bankserver -audit audit.log              修改类请求写入审计日志
+//
+// 设置了环境变量 BANK_AUDIT_PASSPHRASE 时审计日志加密保存（口令不放在命令行参数里，
+// 命令行参数在 ps 中对其他用户可见）。读取：go run ./cmd/bankserver -read-audit audit.log
+//
+// 示例：
+//   curl -X POST localhost:8080/accounts -d '{"owner":"张三","initial_balance":1000}'
+//   curl -X POST localhost:8080/accounts/10001/deposit -d '{"amount":500}'
+//   curl -X POST localhost:8080/transfers -d '{"from":"10001","to":"10002","amount":200}'
+//   curl localhost:8080/accounts/10001/history
+// ============================================
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"c03/pkg/audit"
+	"c03/pkg/bank"
+	"c03/pkg/httpserver"
+)
+
+const passphraseEnv = "BANK_AUDIT_PASSPHRASE"
+
+func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	enablePprof := flag.Bool("pprof", false, "在 /debug/pprof/ 下提供 profile（只应在内网开启）")
+	auditPath := flag.String("audit", "", "审计日志文件，为空则不记录")
+	readAudit := flag.String("read-audit", "", "以 JSON Lines 输出审计日志的内容后退出")
+	flag.Parse()
+
+	auditOpts := []audit.Option{audit.WithPassphrase(os.Getenv(passphraseEnv))}
+	if *readAudit != "" {
+		entries, err := audit.ReadFile(*readAudit, auditOpts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range entries {
+			enc.Encode(e)
+		}
+		return
+	}
+
+	if err := serve(*addr, *auditPath, *enablePprof, auditOpts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serve 运行服务直到收到 Ctrl+C；返回前关闭审计日志
+func serve(addr, auditPath string, enablePprof bool, auditOpts []audit.Option) error {
+	var handler http.Handler = bank.NewHandler(bank.NewBank())
+	if auditPath != "" {
+		auditLog, err := audit.Open(auditPath, auditOpts...)
+		if err != nil {
+			return err
+		}
+		defer auditLog.Close()
+		handler = audit.Middleware(auditLog, log.Default())(handler)
+		log.Printf("审计日志: %s（加密: %v）", auditPath, auditLog.Encrypted())
+	}
+
+	var opts []httpserver.Option
+	if enablePprof {
+		opts = append(opts, httpserver.WithPprof())
+	}
+
+	// Ctrl+C 时等待进行中的请求完成后再退出
+	return httpserver.Serve(context.Background(), addr, handler, opts...)
+}
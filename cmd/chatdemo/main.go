@@ -0,0 +1,331 @@
+// ============================================
+// 多用户聊天路由演示
+// ============================================
+//
+// 运行：
+//   go run ./cmd/chatdemo
+// ============================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"c03/pkg/backoff"
+	"c03/pkg/chat"
+	"c03/pkg/clock"
+)
+
+// consume 打印收件箱中的所有消息，直到收件箱被关闭
+func consume(userID string, inbox <-chan chat.IPayload, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for p := range inbox {
+		switch msg := p.(type) {
+		case chat.ChatMessage:
+			fmt.Printf("[%s] 收到 %s 的消息: %s\n", userID, msg.From(), msg.Text)
+		case chat.Attachment:
+			fmt.Printf("[%s] 收到 %s 的附件: %s (%d 字节)\n", userID, msg.From(), msg.FileName, msg.Size)
+		}
+	}
+	fmt.Printf("[%s] 收件箱已关闭\n", userID)
+}
+
+// demonstrateRouting 注册、路由、超时读取与关闭
+func demonstrateRouting() {
+	fmt.Println("=== 多用户路由 ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router := chat.NewChatRouter(8)
+	go router.Run(ctx)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"alice", "bob", "carol"} {
+		inbox, err := router.Register(id)
+		if err != nil {
+			fmt.Println("注册失败:", err)
+			continue
+		}
+		wg.Add(1)
+		go consume(id, inbox, &wg)
+	}
+
+	router.Send(chat.NewChatMessage("alice", "bob", "你好, bob"))
+	router.Send(chat.NewChatMessage("bob", "alice", "你好, alice"))
+	router.Send(chat.NewAttachment("carol", "alice", "report.pdf", 2048))
+
+	// 向未注册用户发送会立即返回错误
+	if err := router.Send(chat.NewChatMessage("alice", "dave", "在吗?")); err != nil {
+		fmt.Println("发送失败:", err)
+	}
+
+	// 注销 carol，她的收件箱会被关闭
+	router.Unregister("carol")
+
+	// 用 RecvChanData 带超时地读取收件箱
+	daveInbox, _ := router.Register("dave")
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 100*time.Millisecond)
+	if _, err := chat.RecvChanData(timeoutCtx, daveInbox); errors.Is(err, chat.ErrChanTimeout) {
+		fmt.Println("[dave] 等待超时:", err)
+	}
+	cancelTimeout()
+
+	// 取消 ctx：路由器投递完已提交的消息后关闭所有收件箱
+	cancel()
+	<-router.Done()
+	wg.Wait()
+
+	if _, err := chat.RecvChanData(context.Background(), daveInbox); errors.Is(err, chat.ErrChanClosed) {
+		fmt.Println("[dave] 收件箱已关闭:", err)
+	}
+
+	if err := router.Send(chat.NewChatMessage("alice", "bob", "还在吗?")); err != nil {
+		fmt.Println("关闭后发送:", err)
+	}
+}
+
+// demonstrateAck 消费方前两次收到消息时故意不 Ack，观察重投递
+// 使用假时钟：手动把时间推过 ACK 超时，不需要真的等待
+// 等待时间按指数退避增长：第一次投递后 200ms，第二次之后 400ms
+func demonstrateAck() {
+	fmt.Println("\n=== ACK 与重投递 ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fc := clock.NewFakeClock(time.Now())
+	router := chat.NewChatRouter(8,
+		chat.WithAck(200*time.Millisecond, 3),
+		chat.WithAckBackoff(backoff.Backoff{Initial: 200 * time.Millisecond, Multiplier: 2}),
+		chat.WithClock(fc),
+	)
+	go router.Run(ctx)
+
+	inbox, _ := router.Register("bob")
+	router.Send(chat.NewChatMessage("alice", "bob", "收到请回复"))
+
+	wait := backoff.Backoff{Initial: 200 * time.Millisecond, Multiplier: 2}
+	for attempts := 1; ; attempts++ {
+		msg, err := chat.RecvChanData(ctx, inbox)
+		if err != nil {
+			break
+		}
+		fmt.Printf("[bob] 第 %d 次收到消息 %s\n", attempts, msg.ID()[:8])
+		if attempts < 3 {
+			// 模拟处理失败，不发送 ACK；时间推过本次的等待时间后路由器会重投递
+			d := wait.Next()
+			fmt.Printf("      不确认，时间前进 %v\n", d)
+			fc.Advance(d)
+			continue
+		}
+		router.Ack("bob", msg.ID())
+		break
+	}
+	fmt.Println("待确认消息数:", router.Pending())
+
+	stats := router.Stats()
+	fmt.Printf("指标: 路由 %v，重投递 %d，在线 %d\n", stats.Routed, stats.Redelivered, stats.ActiveUsers)
+
+	cancel()
+	<-router.Done()
+}
+
+// demonstrateTCP 通过真实的 TCP 连接使用路由器
+func demonstrateTCP() {
+	fmt.Println("\n=== TCP 传输 ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router := chat.NewChatRouter(8)
+	go router.Run(ctx)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("监听失败:", err)
+		cancel()
+		return
+	}
+	serveDone := make(chan error, 1)
+	go func() { serveDone <- chat.NewServer(router).Serve(ctx, ln) }()
+	addr := ln.Addr().String()
+
+	alice, err := chat.Dial(ctx, addr, "alice")
+	if err != nil {
+		fmt.Println("alice 登录失败:", err)
+		cancel()
+		return
+	}
+	bob, err := chat.Dial(ctx, addr, "bob")
+	if err != nil {
+		fmt.Println("bob 登录失败:", err)
+		alice.Close()
+		cancel()
+		return
+	}
+
+	// 同名用户重复登录会被服务端拒绝
+	if _, err := chat.Dial(ctx, addr, "bob"); err != nil {
+		fmt.Println("重复登录:", err)
+	}
+
+	if id, err := alice.SendText(ctx, "bob", "通过 TCP 发来的问候"); err != nil {
+		fmt.Println("发送失败:", err)
+	} else {
+		fmt.Println("服务端分配的消息 ID:", id)
+	}
+	if _, err := alice.SendText(ctx, "carol", "在吗?"); err != nil {
+		fmt.Println("发送失败:", err)
+	}
+
+	msg, err := chat.RecvChanData(ctx, bob.Messages())
+	if err == nil {
+		if m, ok := msg.(chat.ChatMessage); ok {
+			fmt.Printf("[bob] 收到 %s 的消息: %s\n", m.From(), m.Text)
+		}
+	}
+
+	alice.Close()
+	bob.Close()
+	cancel()
+	<-serveDone
+	<-router.Done()
+}
+
+// demonstratePriority 消费方暂停时积压消息，恢复后高优先级消息先到达
+func demonstratePriority() {
+	fmt.Println("\n=== 优先级通道 ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	router := chat.NewChatRouter(1)
+	inbox, _ := router.Register("bob")
+
+	// Run 启动前先提交消息，模拟路由器积压
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		router.Send(chat.NewChatMessage("alice", "bob", "普通消息"))
+		urgent := chat.NewChatMessage("ops", "bob", "紧急告警")
+		urgent.Prio = chat.PriorityHigh
+		router.Send(urgent)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	go router.Run(ctx)
+	<-sent
+
+	for range 2 {
+		msg, _ := chat.RecvChanData(ctx, inbox)
+		if m, ok := msg.(chat.ChatMessage); ok {
+			fmt.Printf("[bob] 优先级 %d: %s\n", m.Priority(), m.Text)
+		}
+	}
+
+	cancel()
+	<-router.Done()
+}
+
+// demonstrateMiddleware 日志、校验与敏感词过滤以中间件的形式挂到 Send 上
+func demonstrateMiddleware() {
+	fmt.Println("\n=== 中间件 ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := log.New(os.Stdout, "[router] ", 0)
+	router := chat.NewChatRouter(8, chat.WithMiddleware(
+		chat.LoggingMiddleware(logger),
+		chat.ValidationMiddleware(20),
+		chat.ProfanityFilterMiddleware("笨蛋"),
+	))
+	go router.Run(ctx)
+
+	inbox, _ := router.Register("bob")
+	router.Send(chat.NewChatMessage("alice", "bob", "你这个笨蛋"))
+	router.Send(chat.NewChatMessage("alice", "bob", "   "))
+	router.Send(chat.NewChatMessage("bob", "bob", "自言自语"))
+
+	if msg, err := chat.RecvChanData(ctx, inbox); err == nil {
+		if m, ok := msg.(chat.ChatMessage); ok {
+			fmt.Println("[bob] 收到:", m.Text)
+		}
+	}
+
+	cancel()
+	<-router.Done()
+}
+
+// demonstrateOverflow 消费方不读收件箱时，不同溢出策略保留下来的消息
+func demonstrateOverflow() {
+	fmt.Println("\n=== 收件箱溢出策略 ===")
+
+	for _, policy := range []chat.OverflowPolicy{chat.OverflowDropNewest, chat.OverflowDropOldest, chat.OverflowBlock} {
+		ctx, cancel := context.WithCancel(context.Background())
+		router := chat.NewChatRouter(2, chat.WithOverflow(policy, 20*time.Millisecond))
+		go router.Run(ctx)
+
+		inbox, _ := router.Register("bob")
+		for i := 1; i <= 4; i++ {
+			router.Send(chat.NewChatMessage("alice", "bob", fmt.Sprintf("消息%d", i)))
+		}
+		time.Sleep(100 * time.Millisecond) // 等待路由器处理完积压
+
+		var kept []string
+		for len(inbox) > 0 {
+			if m, ok := (<-inbox).(chat.ChatMessage); ok {
+				kept = append(kept, m.Text)
+			}
+		}
+		fmt.Printf("%-12s 保留 %v，丢弃 %d 条\n", policy, kept, router.Drops()["bob"])
+
+		cancel()
+		<-router.Done()
+	}
+}
+
+// demonstrateBus 按主题订阅，使用通配符并取消订阅
+func demonstrateBus() {
+	fmt.Println("\n=== 主题消息总线 ===")
+
+	bus := chat.NewBus(4)
+	defer bus.Close()
+
+	all, _ := bus.Subscribe("room.#")
+	attachments, _ := bus.Subscribe("room.*.attachment")
+
+	ctx := context.Background()
+	publish := func(topic string, msg chat.IPayload) {
+		n, err := bus.Publish(ctx, topic, msg)
+		if err != nil {
+			fmt.Println("发布失败:", err)
+			return
+		}
+		fmt.Printf("发布到 %-22s 投递给 %d 个订阅者\n", topic, n)
+	}
+	publish("room.go.chat", chat.NewChatMessage("alice", "", "大家好"))
+	publish("room.go.attachment", chat.NewAttachment("bob", "", "slides.pdf", 4096))
+
+	attachments.Unsubscribe()
+	publish("room.rust.attachment", chat.NewAttachment("carol", "", "notes.md", 512))
+
+	// 取消订阅后 channel 被关闭，range 会在取完缓冲中的消息后结束
+	for p := range attachments.C() {
+		fmt.Printf("[附件订阅] %s 来自 %s\n", p.Type(), p.From())
+	}
+	all.Unsubscribe()
+	for p := range all.C() {
+		fmt.Printf("[全部订阅] %s 来自 %s\n", p.Type(), p.From())
+	}
+
+	// 发布时不能使用通配符
+	publish("room.*", chat.NewChatMessage("alice", "", "?"))
+}
+
+func main() {
+	demonstrateRouting()
+	demonstrateAck()
+	demonstrateTCP()
+	demonstratePriority()
+	demonstrateMiddleware()
+	demonstrateOverflow()
+	demonstrateBus()
+}
@@ -0,0 +1,74 @@
+// ============================================
+// TCP / HTTP 聊天服务
+// ============================================
+//
+// 运行：
+//   go run ./cmd/chatserver -addr :9000 -http :8080
+//
+// 浏览器打开 http://localhost:8080/ 即可通过 SSE 聊天，
+// 与 TCP 客户端共用同一个路由器，两边的用户可以互发消息。
+// 指标：http://localhost:8080/stats 或 http://localhost:8080/debug/vars
+//
+// 协议：每行一个 JSON 帧，第一帧登录，之后发送消息，例如用 nc 测试：
+//   nc localhost 9000
+//   {"kind":"login","from":"alice"}
+//   {"kind":"chat","id":"m1","to":"bob","text":"你好"}
+// ============================================
+
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"c03/pkg/chat"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "TCP 监听地址")
+	httpAddr := flag.String("http", "", "HTTP/SSE 监听地址，为空则不启动")
+	inboxSize := flag.Int("inbox", 16, "每个用户收件箱的缓冲大小")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	router := chat.NewChatRouter(*inboxSize,
+		chat.WithMiddleware(
+			chat.LoggingMiddleware(log.Default()),
+			chat.ValidationMiddleware(1000),
+		),
+		// 客户端 2 秒内读不走消息就丢弃，避免一个慢客户端卡住所有人
+		chat.WithOverflow(chat.OverflowBlock, 2*time.Second),
+	)
+	go router.Run(ctx)
+
+	router.PublishExpvar("chat_router")
+
+	if *httpAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/", chat.NewHTTPHandler(router))
+		mux.Handle("GET /debug/vars", expvar.Handler())
+		srv := &http.Server{Addr: *httpAddr, Handler: mux}
+		context.AfterFunc(ctx, func() { srv.Close() })
+		go func() {
+			log.Printf("chat http listening on %s", *httpAddr)
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				log.Print(err)
+			}
+		}()
+	}
+
+	log.Printf("chat server listening on %s", *addr)
+	if err := chat.NewServer(router).ListenAndServe(ctx, *addr); err != nil {
+		log.Fatal(err)
+	}
+	<-router.Done()
+	log.Print("chat server stopped")
+}
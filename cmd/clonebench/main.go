@@ -0,0 +1,72 @@
+// ============================================
+// 深拷贝基准：反射、gob、Clone 方法
+// ============================================
+//
+// 对比 tutorial/09 第 9.1 节的三种深拷贝方式，订单分别有 3 件和 100 件商品：
+//   反射    DeepCopy：逐个 reflect.Value 递归复制
+//   gob     CloneViaGob：编码再解码，每次都要重新发送类型描述
+//   Clone   为 Order 写的 Clone 方法（代码生成工具生成的就是这样的代码）
+//
+// 各方式对未导出字段、空切片等的处理不同，见 tutorial/09_reflect/clone.go 开头的表格。
+//
+// 运行：
+//   go run ./cmd/clonebench
+//   go run ./cmd/clonebench -benchtime 200ms
+// ============================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"c03/pkg/unitext"
+	reflection "c03/tutorial/09_reflect"
+)
+
+// sinkOrder 防止编译器把没有用到的结果优化掉
+var sinkOrder *reflection.Order
+
+func main() {
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, items := range []int{3, 100} {
+		order := reflection.NewSampleOrder(items)
+		fmt.Printf("\n订单（%d 件商品）\n", items)
+		fmt.Printf("  %s %12s %12s %10s %8s\n", unitext.PadDisplayWidth("方式", 8), "ns/op", "B/op", "allocs/op", "相对")
+		var base float64
+		for _, c := range []struct {
+			name  string
+			clone func(*reflection.Order) (*reflection.Order, error)
+		}{
+			{"Clone", func(o *reflection.Order) (*reflection.Order, error) { return o.Clone(), nil }},
+			{"反射", reflection.DeepCopy[*reflection.Order]},
+			{"gob", reflection.CloneViaGob[*reflection.Order]},
+		} {
+			if _, err := c.clone(order); err != nil {
+				log.Fatalf("%s: %v", c.name, err)
+			}
+			res := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkOrder, _ = c.clone(order)
+				}
+			})
+			ns := float64(res.T.Nanoseconds()) / float64(res.N)
+			if base == 0 {
+				base = ns
+			}
+			fmt.Printf("  %s %12.0f %12d %10d %7.1fx\n", unitext.PadDisplayWidth(c.name, 8), ns, res.AllocedBytesPerOp(), res.AllocsPerOp(), ns/base)
+		}
+	}
+}
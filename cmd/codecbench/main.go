@@ -0,0 +1,294 @@
+// ============================================
+// 二进制编码与 JSON 的对比
+// ============================================
+//
+// 两组对比，先验证往返正确，再跑基准：
+//   - 聊天帧：pkg/chat 的长度前缀二进制帧 vs JSON 帧
+//     往返用 pkg/prop 随机生成帧验证，另外检查流式读写、截断和超大帧
+//   - 缓存：tutorial/06 的 Cache.Save/Load（gob）vs 把同一个 map 编码成 JSON
+//
+// 二进制帧不写字段名，数字和时间按 varint 编码，比 JSON 小一半左右，
+// 解码也不需要反射和文本解析；代价是格式要自己维护，加字段时两端都要改。
+//
+// 运行：
+//   go run ./cmd/codecbench
+//   go run ./cmd/codecbench -benchtime 200ms -runs 2000
+//
+// 对应的单元测试和 go test 基准在 pkg/chat/binary_test.go、tutorial/06_sync_context/persist_test.go。
+// ============================================
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"c03/pkg/chat"
+	"c03/pkg/prop"
+	"c03/pkg/unitext"
+	synccontext "c03/tutorial/06_sync_context"
+)
+
+// sinks 防止编译器把没有用到的结果优化掉
+var (
+	sinkBytes []byte
+	sinkFrame chat.Frame
+)
+
+func main() {
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	runs := flag.Int("runs", 500, "往返性质的随机用例数")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("往返检查")
+	for _, c := range []struct {
+		name string
+		fn   func() error
+	}{
+		{fmt.Sprintf("随机帧 AppendBinary / DecodeBinaryFrame（%d 次）", *runs), func() error { return checkFrameRoundTrip(*runs) }},
+		{"WriteBinaryFrame / ReadBinaryFrame 流式读写", checkFrameStream},
+		{"Cache.Save / Cache.Load", checkCacheRoundTrip},
+	} {
+		if err := c.fn(); err != nil {
+			log.Fatalf("  ✗ %s\n    %v", c.name, err)
+		}
+		fmt.Printf("  ✓ %s\n", c.name)
+	}
+
+	frame, err := chat.FrameFromPayload(chat.NewChatMessage("alice", "bob", "今晚一起吃饭吗？"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jsonFrame, _ := json.Marshal(frame)
+	binFrame, _ := frame.AppendBinary(nil)
+
+	cache := synccontext.NewCache()
+	for i := range 10000 {
+		cache.Set(fmt.Sprintf("user:%05d", i), fmt.Sprintf(`{"name":"用户%d","score":%d}`, i, i*7))
+	}
+	var gobCache, jsonCache bytes.Buffer
+	if err := cache.Save(&gobCache); err != nil {
+		log.Fatal(err)
+	}
+	if err := json.NewEncoder(&jsonCache).Encode(cache.Snapshot()); err != nil {
+		log.Fatal(err)
+	}
+
+	groups := []struct {
+		title   string
+		benches []bench
+	}{
+		{"聊天帧", []bench{
+			{"JSON   编码", len(jsonFrame), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkBytes, _ = json.Marshal(frame)
+				}
+			}},
+			{"二进制 编码", len(binFrame), func(b *testing.B) {
+				b.ReportAllocs()
+				buf := make([]byte, 0, 256)
+				for b.Loop() {
+					sinkBytes, _ = frame.AppendBinary(buf[:0])
+				}
+			}},
+			{"JSON   解码", len(jsonFrame), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					var f chat.Frame
+					json.Unmarshal(jsonFrame, &f)
+					sinkFrame = f
+				}
+			}},
+			{"二进制 解码", len(binFrame), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkFrame, _ = chat.DecodeBinaryFrame(binFrame)
+				}
+			}},
+		}},
+		{"缓存（10000 个键）", []bench{
+			{"JSON 保存", jsonCache.Len(), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					json.NewEncoder(io.Discard).Encode(cache.Snapshot())
+				}
+			}},
+			{"gob  保存", gobCache.Len(), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					cache.Save(io.Discard)
+				}
+			}},
+			{"JSON 加载", jsonCache.Len(), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					var m map[string]string
+					json.Unmarshal(jsonCache.Bytes(), &m)
+				}
+			}},
+			{"gob  加载", gobCache.Len(), func(b *testing.B) {
+				b.ReportAllocs()
+				c := synccontext.NewCache()
+				for b.Loop() {
+					c.Load(bytes.NewReader(gobCache.Bytes()))
+				}
+			}},
+		}},
+	}
+
+	for _, g := range groups {
+		fmt.Printf("\n%s\n", g.title)
+		fmt.Printf("  %s %8s %12s %12s %10s\n", unitext.PadDisplayWidth("基准", 14), "大小", "ns/op", "B/op", "allocs/op")
+		for _, r := range g.benches {
+			res := testing.Benchmark(r.fn)
+			fmt.Printf("  %s %10d %12d %12d %10d\n", unitext.PadDisplayWidth(r.name, 14), r.size, res.NsPerOp(), res.AllocedBytesPerOp(), res.AllocsPerOp())
+		}
+	}
+}
+
+// bench 一个基准，size 是编码结果的字节数
+type bench struct {
+	name string
+	size int
+	fn   func(b *testing.B)
+}
+
+// ============================================
+// 往返检查
+// ============================================
+
+// frameGen 随机帧：字段随机留空，覆盖掩码的各种组合；文本包含多字节字符
+func frameGen() prop.Gen[chat.Frame] {
+	str := prop.String("ab中文😀 \n\"\\")
+	num := prop.Int(-1<<40, 1<<40)
+	return prop.Gen[chat.Frame]{
+		Generate: func(r *rand.Rand, size int) chat.Frame {
+			pick := func() string {
+				if r.IntN(3) == 0 {
+					return ""
+				}
+				return str.Generate(r, size)
+			}
+			f := chat.Frame{
+				Kind: chat.FrameKind(pick()), ID: pick(), From: pick(), To: pick(),
+				Text: pick(), FileName: pick(), Error: pick(),
+				Size:     int64(num.Generate(r, size)),
+				Priority: chat.Priority(r.IntN(3) - 1),
+			}
+			if r.IntN(4) != 0 {
+				f.SentAt = time.Unix(r.Int64N(1<<40)-1<<39, r.Int64N(int64(time.Second)))
+			}
+			return f
+		},
+	}
+}
+
+func framesEqual(a, b chat.Frame) bool {
+	if !a.SentAt.Equal(b.SentAt) {
+		return false
+	}
+	a.SentAt, b.SentAt = time.Time{}, time.Time{}
+	return a == b
+}
+
+func checkFrameRoundTrip(runs int) error {
+	return prop.Check(frameGen(), func(f chat.Frame) bool {
+		data, err := f.AppendBinary(nil)
+		if err != nil {
+			return false
+		}
+		back, err := chat.DecodeBinaryFrame(data)
+		return err == nil && framesEqual(f, back)
+	}, prop.WithRuns(runs))
+}
+
+func checkFrameStream() error {
+	msgs := []chat.IPayload{
+		chat.NewChatMessage("alice", "bob", "你好"),
+		chat.NewAttachment("bob", "alice", "报告.pdf", 1<<20),
+		chat.NewChatMessage("alice", "bob", strings.Repeat("长消息", 1000)),
+	}
+	var buf bytes.Buffer
+	var frames []chat.Frame
+	for _, m := range msgs {
+		f, err := chat.FrameFromPayload(m)
+		if err != nil {
+			return err
+		}
+		frames = append(frames, f)
+		if err := chat.WriteBinaryFrame(&buf, f); err != nil {
+			return err
+		}
+	}
+	stream := buf.Bytes()
+
+	r := bufio.NewReader(bytes.NewReader(stream))
+	for i, want := range frames {
+		got, err := chat.ReadBinaryFrame(r)
+		if err != nil {
+			return fmt.Errorf("第 %d 帧: %w", i, err)
+		}
+		if !framesEqual(want, got) {
+			return fmt.Errorf("第 %d 帧不一致: %+v", i, got)
+		}
+	}
+	if _, err := chat.ReadBinaryFrame(r); err != io.EOF {
+		return fmt.Errorf("流结束时返回 %v，期望 io.EOF", err)
+	}
+
+	// 去掉最后一个字节：前两帧完整，第三帧中途结束
+	truncated := bytes.NewReader(stream[:len(stream)-1])
+	var err error
+	for err == nil {
+		_, err = chat.ReadBinaryFrame(truncated)
+	}
+	if err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("帧中途结束时返回 %v，期望 io.ErrUnexpectedEOF", err)
+	}
+
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 0x0f} // uvarint 约 4GB
+	if _, err := chat.ReadBinaryFrame(bytes.NewReader(huge)); !errors.Is(err, chat.ErrFrameTooLarge) {
+		return fmt.Errorf("超大帧返回 %v，期望 ErrFrameTooLarge", err)
+	}
+	if _, err := chat.DecodeBinaryFrame([]byte{0x01, 0x05, 'a'}); !errors.Is(err, chat.ErrBadFrame) {
+		return fmt.Errorf("字符串被截断时返回 %v，期望 ErrBadFrame", err)
+	}
+	return nil
+}
+
+func checkCacheRoundTrip() error {
+	src := synccontext.NewCache()
+	src.Set("", "空键")
+	src.Set("中文", strings.Repeat("值", 100))
+	src.Set("换行\n", "\x00二进制\xff")
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		return err
+	}
+	dst := synccontext.NewCache()
+	if err := dst.Load(&buf); err != nil {
+		return err
+	}
+	if a, b := src.Snapshot(), dst.Snapshot(); !maps.Equal(a, b) {
+		return fmt.Errorf("加载后 %v，期望 %v", b, a)
+	}
+	return nil
+}
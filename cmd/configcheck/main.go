@@ -0,0 +1,136 @@
+// ============================================
+// 配置检查工具
+// ============================================
+//
+// 加载配置文件并打印替换后的结果，出错时以非 0 状态退出。
+// 按扩展名选择格式：.json 支持环境变量替换，.ini 使用 INI 解析器。
+// 加载后用 AppConfig.Validate 检查取值范围（端口、连接数）。
+//
+// -watch 时不退出：监视文件，每次修改后重新加载，打印变化的字段；
+// 新内容无效时打印错误并继续使用旧配置。Ctrl+C 结束。
+//
+// 运行：
+//   DATABASE_URL=postgres://localhost/app go run ./cmd/configcheck cmd/configcheck/sample.json
+//   PORT=9000 DATABASE_URL=x go run ./cmd/configcheck cmd/configcheck/sample.json
+//   go run ./cmd/configcheck cmd/configcheck/sample.json   # 缺少 DATABASE_URL，报错
+//   go run ./cmd/configcheck cmd/configcheck/sample.ini
+//   go run ./cmd/configcheck -watch cmd/configcheck/sample.ini   # 另开一个终端修改文件
+// ============================================
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"time"
+
+	"c03/pkg/config"
+	"c03/pkg/validate"
+)
+
+// AppConfig 示例配置结构，同一个结构体同时用于 JSON 和 INI
+type AppConfig struct {
+	Name   string `json:"name" ini:"name" config:"required"`
+	Server struct {
+		Host    string        `json:"host" ini:"host"`
+		Port    int           `json:"port" ini:"port" config:"required"`
+		Debug   bool          `json:"debug" ini:"debug"`
+		Timeout time.Duration `json:"timeout,omitempty" ini:"timeout"`
+	} `json:"server" ini:"server"`
+	Database struct {
+		DSN      string   `json:"dsn" ini:"dsn" config:"required"`
+		MaxConns int      `json:"max_conns" ini:"max_conns"`
+		Replicas []string `json:"replicas,omitempty" ini:"replicas"`
+	} `json:"database" ini:"database"`
+	PriceNote string `json:"price_note" ini:"price_note"`
+}
+
+// Validate 检查取值范围，config.New 每次加载后调用
+func (c *AppConfig) Validate() error {
+	return validate.Validate(c).
+		Field("Server.Port", validate.Between(1, 65535)).
+		Field("Database.MaxConns", validate.Min(0)).
+		Error()
+}
+
+func main() {
+	watch := flag.Bool("watch", false, "监视文件，修改后重新加载")
+	flag.Parse()
+	log.SetFlags(0)
+	if flag.NArg() != 1 {
+		log.Fatal("用法: configcheck [-watch] <config.json|config.ini>")
+	}
+
+	cfg, err := config.New[AppConfig](flag.Arg(0), config.WithLogger(log.New(os.Stderr, "", log.LstdFlags)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	printJSON(cfg.Get())
+	if !*watch {
+		return
+	}
+
+	cfg.Subscribe(func(old, cur *AppConfig) {
+		for _, line := range diff(old, cur) {
+			fmt.Println("  " + line)
+		}
+	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "监视 %s 中，Ctrl+C 结束\n", flag.Arg(0))
+	if err := cfg.Watch(ctx); !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+func printJSON(cfg *AppConfig) {
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(out))
+}
+
+// diff 逐个叶子字段比较，返回 "server.port: 8080 -> 9000" 形式的变化
+func diff(old, cur *AppConfig) []string {
+	var a, b map[string]any
+	toMap(old, &a)
+	toMap(cur, &b)
+	var lines []string
+	flatDiff("", a, b, &lines)
+	return lines
+}
+
+func toMap(cfg *AppConfig, dst *map[string]any) {
+	raw, _ := json.Marshal(cfg)
+	json.Unmarshal(raw, dst)
+}
+
+func flatDiff(prefix string, a, b map[string]any, lines *[]string) {
+	keys := slices.Sorted(maps.Keys(b))
+	for k := range maps.Keys(a) {
+		if _, ok := b[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		am, aok := a[k].(map[string]any)
+		bm, bok := b[k].(map[string]any)
+		if aok && bok {
+			flatDiff(prefix+k+".", am, bm, lines)
+			continue
+		}
+		if !reflect.DeepEqual(a[k], b[k]) {
+			*lines = append(*lines, fmt.Sprintf("%s%s: %v -> %v", prefix, k, a[k], b[k]))
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// ============================================
+// 写时复制容器与 RWMutex 的读多写少基准
+// ============================================
+//
+// 先检查 pkg/syncutil 的 COWMap / COWSlice：
+//   - 读者不会看到写了一半的数据（一次 Update 里的多处修改同时可见）
+//   - 写入不影响已经取出的快照
+//
+// 再用 RunParallel 比较三种并发 map 在不同写入比例下的每次操作耗时：
+//   COWMap         读：一次原子 Load；写：复制整个 map
+//   RWMutex + map  读：RLock / RUnlock（所有读者争用同一个计数器）
+//   SyncMap        sync.Map 的泛型包装
+//
+// 多核上只读或极少写时 COWMap 最快，而且随核数增加几乎线性扩展；
+// 写入比例升高、map 变大时复制的开销很快超过锁的开销。
+// 单核机器上没有缓存行争用，RWMutex 的劣势体现不出来。
+//
+// 运行：
+//   go run ./cmd/cowbench
+//   go run ./cmd/cowbench -keys 10000 -procs 1,4,8 -benchtime 500ms
+// ============================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c03/pkg/syncutil"
+	"c03/pkg/unitext"
+)
+
+var sinkInt int
+
+// concurrentMap 三种实现共同的读写接口
+type concurrentMap interface {
+	Load(key int) (int, bool)
+	Store(key, value int)
+}
+
+type rwMap struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func (r *rwMap) Load(key int) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.m[key]
+	return v, ok
+}
+
+func (r *rwMap) Store(key, value int) {
+	r.mu.Lock()
+	r.m[key] = value
+	r.mu.Unlock()
+}
+
+func main() {
+	keys := flag.Int("keys", 1000, "map 中的键数")
+	procs := flag.String("procs", "1,2,4,8", "逗号分隔的 GOMAXPROCS 取值")
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+	var procList []int
+	for f := range strings.SplitSeq(*procs, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 1 {
+			log.Fatalf("-procs: 无效的取值 %q", f)
+		}
+		procList = append(procList, n)
+	}
+
+	fmt.Println("正确性检查")
+	for _, c := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"并发读者看不到写了一半的 Update", checkAtomicUpdate},
+		{"写入不影响已取出的快照", checkSnapshotIsolation},
+	} {
+		if err := c.fn(); err != nil {
+			log.Fatalf("  ✗ %s\n    %v", c.name, err)
+		}
+		fmt.Printf("  ✓ %s\n", c.name)
+	}
+
+	impls := []struct {
+		name string
+		new  func() concurrentMap
+	}{
+		{"COWMap", func() concurrentMap { return new(syncutil.COWMap[int, int]) }},
+		{"RWMutex + map", func() concurrentMap { return &rwMap{m: make(map[int]int)} }},
+		{"SyncMap", func() concurrentMap { return new(syncutil.SyncMap[int, int]) }},
+	}
+	// 每 writeEvery 次操作有一次写，0 表示只读
+	ratios := []struct {
+		name       string
+		writeEvery int
+	}{
+		{"只读", 0},
+		{"0.1% 写", 1000},
+		{"1% 写", 100},
+		{"10% 写", 10},
+	}
+
+	fmt.Printf("\n%d 个键，CPU 核数 %d，单位 ns/op\n", *keys, runtime.NumCPU())
+	prev := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(prev)
+	for _, p := range procList {
+		runtime.GOMAXPROCS(p)
+		fmt.Printf("\nGOMAXPROCS=%d\n  %s", p, unitext.PadDisplayWidth("", 16))
+		for _, r := range ratios {
+			fmt.Printf(" %s", unitext.PadDisplayWidth(r.name, 10))
+		}
+		fmt.Println()
+		for _, impl := range impls {
+			fmt.Printf("  %s", unitext.PadDisplayWidth(impl.name, 16))
+			for _, r := range ratios {
+				m := impl.new()
+				fill(m, *keys)
+				res := testing.Benchmark(func(b *testing.B) { benchMixed(b, m, *keys, r.writeEvery) })
+				fmt.Printf(" %s", unitext.PadDisplayWidth(strconv.FormatInt(res.NsPerOp(), 10), 10))
+			}
+			fmt.Println()
+		}
+	}
+}
+
+func fill(m concurrentMap, n int) {
+	if cow, ok := m.(*syncutil.COWMap[int, int]); ok {
+		// 一次 Update 写入全部键，避免 n 次复制
+		cow.Update(func(next map[int]int) {
+			for i := range n {
+				next[i] = i
+			}
+		})
+		return
+	}
+	for i := range n {
+		m.Store(i, i)
+	}
+}
+
+// benchMixed 每个 goroutine 按顺序访问各个键，每 writeEvery 次操作写一次
+func benchMixed(b *testing.B, m concurrentMap, keys, writeEvery int) {
+	b.RunParallel(func(pb *testing.PB) {
+		i, sum := 0, 0
+		for pb.Next() {
+			i++
+			key := i % keys
+			if writeEvery > 0 && i%writeEvery == 0 {
+				m.Store(key, i)
+				continue
+			}
+			v, _ := m.Load(key)
+			sum += v
+		}
+		sinkInt = sum
+	})
+}
+
+// checkAtomicUpdate 写者每次 Update 同时修改 a 和 b，读者从快照中看到的 a、b 必须相等；
+// COWSlice 的每个快照里所有元素也必须相等
+func checkAtomicUpdate() error {
+	var m syncutil.COWMap[string, int]
+	var s syncutil.COWSlice[int]
+	s.Append(0, 0, 0, 0)
+
+	var stop atomic.Bool
+	var torn atomic.Int64
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for !stop.Load() {
+				snap := m.Snapshot()
+				if snap["a"] != snap["b"] {
+					torn.Add(1)
+				}
+				vs := s.Load()
+				for _, v := range vs {
+					if v != vs[0] {
+						torn.Add(1)
+					}
+				}
+				runtime.Gosched()
+			}
+		})
+	}
+	for i := range 2000 {
+		m.Update(func(next map[string]int) {
+			next["a"] = i
+			next["b"] = i
+		})
+		s.Update(func(cur []int) []int {
+			for j := range cur {
+				cur[j] = i
+			}
+			return cur
+		})
+		if i%100 == 0 {
+			runtime.Gosched()
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+	if n := torn.Load(); n > 0 {
+		return fmt.Errorf("读者 %d 次看到了不一致的快照", n)
+	}
+	if v, _ := m.Load("a"); v != 1999 || s.At(3) != 1999 {
+		return fmt.Errorf("最后一次写入后 a=%d，s[3]=%d，期望 1999", v, s.At(3))
+	}
+	return nil
+}
+
+func checkSnapshotIsolation() error {
+	var m syncutil.COWMap[string, int]
+	m.Store("x", 1)
+	old := m.Snapshot()
+	m.Store("x", 2)
+	m.Store("y", 3)
+	m.Delete("x")
+	if len(old) != 1 || old["x"] != 1 {
+		return fmt.Errorf("写入后旧的 map 快照变成了 %v", old)
+	}
+	if _, ok := m.Load("x"); ok || m.Len() != 1 {
+		return fmt.Errorf("删除 x 后 Len=%d", m.Len())
+	}
+
+	var s syncutil.COWSlice[string]
+	s.Append("a", "b")
+	snap := s.Load()
+	// DeleteFunc 会把尾部清零、Set 原地赋值：都必须作用在副本上，不能碰到 snap 的底层数组
+	s.DeleteFunc(func(v string) bool { return v == "a" })
+	s.Append("c")
+	s.Set(0, "B")
+	if strings.Join(snap, ",") != "a,b" {
+		return fmt.Errorf("写入后旧的切片快照变成了 %v", snap)
+	}
+	if got := strings.Join(s.Load(), ","); got != "B,c" {
+		return fmt.Errorf("当前内容为 %s，期望 B,c", got)
+	}
+	var empty syncutil.COWSlice[int]
+	empty.DeleteFunc(func(int) bool { return true })
+	if empty.Len() != 0 {
+		return errors.New("空 COWSlice 的 Len 应当为 0")
+	}
+	return nil
+}
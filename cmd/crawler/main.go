@@ -0,0 +1,59 @@
+// ============================================
+// 并发网页爬虫
+// ============================================
+//
+// 运行：
+//   go run ./cmd/crawler -depth 1 -workers 4 -out pages https://go.dev/
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"c03/pkg/crawler"
+)
+
+func main() {
+	depth := flag.Int("depth", 1, "最大抓取深度（起始页为 0）")
+	workers := flag.Int("workers", 4, "并发数")
+	out := flag.String("out", "", "页面保存目录，为空则不保存")
+	sameHost := flag.Bool("same-host", true, "只跟随同域名链接")
+	timeout := flag.Duration("timeout", 30*time.Second, "整体超时")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "用法: crawler [flags] <url>")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	c := crawler.New(crawler.Config{
+		MaxDepth: *depth,
+		Workers:  *workers,
+		OutDir:   *out,
+		SameHost: *sameHost,
+	})
+	pages, err := c.Crawl(ctx, flag.Arg(0))
+	for _, p := range pages {
+		if p.Err != nil {
+			fmt.Printf("[%d] %s 失败: %v\n", p.Depth, p.URL, p.Err)
+			continue
+		}
+		fmt.Printf("[%d] %s %d 个链接 %s\n", p.Depth, p.URL, len(p.Links), p.SavedAs)
+	}
+	fmt.Printf("共抓取 %d 个页面\n", len(pages))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
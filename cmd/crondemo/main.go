@@ -0,0 +1,156 @@
+// ============================================
+// cron 调度器演示
+// ============================================
+//
+// 运行：
+//   go run ./cmd/crondemo                               # 用假时钟模拟半天的调度
+//   go run ./cmd/crondemo -next "*/15 9-17 * * MON-FRI" # 打印接下来的执行时间
+// ============================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/cron"
+)
+
+func main() {
+	next := flag.String("next", "", "只打印该表达式接下来的执行时间")
+	n := flag.Int("n", 5, "与 -next 一起使用：打印的次数")
+	flag.Parse()
+
+	if *next != "" {
+		printNext(*next, *n)
+		return
+	}
+	simulate()
+}
+
+func printNext(expr string, n int) {
+	sched, err := cron.Parse(expr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t := time.Now()
+	for range n {
+		t = sched.Next(t)
+		if t.IsZero() {
+			fmt.Println("（之后不会再触发）")
+			return
+		}
+		fmt.Println(t.Format("2006-01-02 Mon 15:04"))
+	}
+}
+
+// simulate 用假时钟从 00:00 跑到 12:00，每次直接跳到最早的下一次执行时间
+func simulate() {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local) // 周五
+	fc := clock.NewFakeClock(start)
+	// 演示中不打印 panic 堆栈
+	s := cron.New(cron.WithClock(fc), cron.WithLogger(log.New(io.Discard, "", 0)))
+
+	// 第一次执行会卡住，直到 release 被关闭，用来观察重叠策略
+	release := make(chan struct{})
+	blockFirst := func() cron.Job {
+		var calls atomic.Int32
+		return func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				<-release
+			}
+			return nil
+		}
+	}
+
+	must(s.Add("heartbeat", "*/30 * * * *", cron.Skip, func(ctx context.Context) error { return nil }))
+	must(s.Add("report-skip", "0 */2 * * *", cron.Skip, blockFirst()))
+	must(s.Add("report-queue", "0 */2 * * *", cron.Queue, blockFirst()))
+	must(s.Add("flaky", "15 3,9 * * *", cron.Skip, func(ctx context.Context) error {
+		return errors.New("上游不可用")
+	}))
+	must(s.Add("buggy", "45 6 * * FRI", cron.Skip, func(ctx context.Context) error {
+		var m map[string]int
+		m["x"] = 1 // panic：写入 nil map
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	end := start.Add(12 * time.Hour)
+	held := true
+	for {
+		// Run 重新设置好定时器，说明上一批到期的任务已经启动；
+		// 再等这些任务执行完（被卡住的第一次 report 除外），结果才是确定的
+		fc.BlockUntil(1)
+		waitIdle(s, held)
+		entries := s.Entries()
+		due := entries[0].Next
+		if due.After(end) {
+			break
+		}
+		var names []string
+		for _, e := range entries {
+			if e.Next.Equal(due) {
+				names = append(names, e.Name)
+			}
+		}
+		fmt.Printf("[%s] 到期: %s\n", due.Format("15:04"), strings.Join(names, ", "))
+		fc.Set(due)
+		if due.Hour() == 6 && due.Minute() == 0 {
+			fc.BlockUntil(1)
+			fmt.Println("        —— 第一次 report 在 06:00 之后才结束 ——")
+			close(release)
+			held = false
+		}
+	}
+
+	cancel()
+	<-done
+
+	fmt.Printf("\n%-13s %-6s %5s %5s %5s %5s  %s\n", "任务", "策略", "执行", "跳过", "失败", "panic", "表达式")
+	for _, e := range s.Entries() {
+		fmt.Printf("%-13s %-6s %5d %5d %5d %5d  %s\n",
+			e.Name, e.Policy, e.Stats.Runs, e.Stats.Skipped, e.Stats.Failed, e.Stats.Panics, e.Schedule)
+	}
+}
+
+// waitIdle 等待所有任务执行完；held 为 true 时两个 report 任务各有一次被卡住
+func waitIdle(s *cron.Scheduler, held bool) {
+	for {
+		busy := false
+		for _, e := range s.Entries() {
+			expected := 0
+			if held && strings.HasPrefix(e.Name, "report-") {
+				expected = 1
+			}
+			busy = busy || e.Running > expected
+		}
+		if !busy {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
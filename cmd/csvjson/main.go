@@ -0,0 +1,59 @@
+// ============================================
+// CSV <-> JSON 转换工具
+// ============================================
+//
+// 按文件扩展名判断方向，读标准输入时用 -from 指定：
+//   go run ./cmd/csvjson cmd/csvtool/sample.csv > students.json
+//   go run ./cmd/csvjson students.json
+//   cat data.csv | go run ./cmd/csvjson -from csv
+// ============================================
+
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"c03/pkg/csvutil"
+)
+
+func main() {
+	from := flag.String("from", "", "输入格式 csv 或 json，默认按扩展名判断")
+	flag.Parse()
+	log.SetFlags(0)
+
+	var in io.Reader = os.Stdin
+	format := *from
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(flag.Arg(0))), ".")
+		}
+	default:
+		log.Fatal("用法: csvjson [-from csv|json] [文件]")
+	}
+
+	var err error
+	switch format {
+	case "csv":
+		err = csvutil.CSVToJSON(in, os.Stdout)
+	case "json":
+		err = csvutil.JSONToCSV(in, os.Stdout)
+	default:
+		log.Fatalf("无法判断输入格式 %q，请用 -from csv 或 -from json 指定", format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
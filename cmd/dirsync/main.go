@@ -0,0 +1,83 @@
+// ============================================
+// 目录同步工具
+// ============================================
+//
+// 运行：
+//   go run ./cmd/dirsync -n ./src ./backup                  # 只打印计划
+//   go run ./cmd/dirsync -exclude '*.tmp,.git' ./src ./backup
+//   go run ./cmd/dirsync -direction both ./laptop ./usb
+//   go run ./cmd/dirsync -progress ./photos /mnt/backup/photos
+// ============================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"c03/pkg/cli"
+	"c03/pkg/dirsync"
+)
+
+func main() {
+	direction := flag.String("direction", "a->b", "同步方向：a->b、b->a 或 both")
+	exclude := flag.String("exclude", "", "逗号分隔的排除模式，如 '*.tmp,.git'")
+	dryRun := flag.Bool("n", false, "只打印要复制的文件，不实际复制")
+	showProgress := flag.Bool("progress", false, "在标准错误上显示复制进度")
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 2 {
+		log.Fatal("用法: dirsync [flags] <目录 A> <目录 B>")
+	}
+
+	opts := dirsync.Options{DryRun: *dryRun}
+	switch *direction {
+	case "a->b":
+		opts.Direction = dirsync.AToB
+	case "b->a":
+		opts.Direction = dirsync.BToA
+	case "both":
+		opts.Direction = dirsync.Both
+	default:
+		log.Fatalf("未知的同步方向 %q", *direction)
+	}
+	if *exclude != "" {
+		opts.Exclude = strings.Split(*exclude, ",")
+	}
+	var bar *cli.ProgressBar
+	if *showProgress {
+		opts.Progress = func(total int64) io.Writer {
+			bar = cli.NewProgressBar(os.Stderr, total)
+			return bar
+		}
+	}
+
+	sum, err := dirsync.Sync(flag.Arg(0), flag.Arg(1), opts)
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	verb := "复制"
+	if opts.DryRun {
+		verb = "将复制"
+	}
+	for _, a := range sum.Copied {
+		fmt.Printf("%s %s -> %s (%d 字节)\n", verb, a.From, a.To, a.Bytes)
+	}
+	for _, a := range sum.Failed {
+		fmt.Printf("失败 %s -> %s: %v\n", a.From, a.To, a.Err)
+	}
+	fmt.Printf("\n%s %d 个文件 / %d 字节，无需复制 %d，失败 %d\n",
+		verb, len(sum.Copied), sum.Bytes, sum.UpToDate, len(sum.Failed))
+	if len(sum.Failed) > 0 {
+		log.Fatal("部分文件同步失败")
+	}
+}
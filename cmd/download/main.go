@@ -0,0 +1,81 @@
+// ============================================
+// HTTP 下载工具
+// ============================================
+//
+// 分块并发下载，Ctrl+C 中断后重新运行同一条命令会从断点继续。
+//
+// 运行：
+//   go run ./cmd/download https://go.dev/dl/go1.25.5.src.tar.gz
+//   go run ./cmd/download -c 8 -o go.tgz -sha256 <hex> https://go.dev/dl/go1.25.5.src.tar.gz
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"time"
+
+	"c03/pkg/backoff"
+	"c03/pkg/cli"
+	"c03/pkg/download"
+)
+
+func main() {
+	out := flag.String("o", "", "保存路径，默认取 URL 的最后一段")
+	concurrency := flag.Int("c", 4, "分块并发数")
+	sum := flag.String("sha256", "", "期望的 SHA-256，下载完成后校验")
+	retries := flag.Int("retries", 3, "每块失败后的重试次数（指数退避，从 500ms 开始）")
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		log.Fatal("用法: download [flags] <url>")
+	}
+	rawURL := flag.Arg(0)
+
+	dest := *out
+	if dest == "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dest = path.Base(u.Path)
+		if dest == "/" || dest == "." {
+			dest = "index.html"
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var bar *cli.ProgressBar
+	err := download.Download(ctx, rawURL, dest, download.Options{
+		Concurrency: *concurrency,
+		SHA256:      *sum,
+		Retries:     *retries,
+		Backoff:     backoff.Backoff{Initial: 500 * time.Millisecond, Jitter: backoff.FullJitter},
+		Progress: func(total, done int64) io.Writer {
+			bar = cli.NewProgressBar(os.Stderr, total)
+			bar.Add(done)
+			return bar
+		},
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Fatal("已中断，重新运行同一条命令可以继续下载")
+		}
+		log.Fatal(err)
+	}
+	fmt.Println("已保存到", dest)
+}
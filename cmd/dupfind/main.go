@@ -0,0 +1,58 @@
+// ============================================
+// 重复文件查找工具
+// ============================================
+//
+// 运行：
+//   go run ./cmd/dupfind .
+//   go run ./cmd/dupfind -workers 8 -timeout 30s ~/Downloads
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"c03/pkg/fsutil"
+)
+
+func main() {
+	workers := flag.Int("workers", runtime.NumCPU(), "并发计算哈希的 goroutine 数")
+	timeout := flag.Duration("timeout", 0, "整体超时，0 表示不限制")
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		log.Fatal("用法: dupfind [flags] <目录>")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	groups, err := fsutil.FindDuplicates(ctx, flag.Arg(0), *workers)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var wasted int64
+	for _, g := range groups {
+		fmt.Printf("%s  %d 字节 x %d\n", g.Hash[:12], g.Size, len(g.Paths))
+		for _, p := range g.Paths {
+			fmt.Printf("    %s\n", p)
+		}
+		wasted += g.Wasted()
+	}
+	fmt.Printf("\n%d 组重复文件，可节省 %d 字节，耗时 %v\n", len(groups), wasted, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,105 @@
+// ============================================
+// TCP 回显服务与客户端
+// ============================================
+//
+// 运行：
+//   go run ./cmd/echo -listen :7000 -max-conns 2       # 启动服务
+//   go run ./cmd/echo -connect localhost:7000 你好 世界  # 每个参数回显一次
+//   go run ./cmd/echo -pipe                            # 用 net.Pipe 在内存中自检
+//
+// 服务端按 Ctrl+C 后不再接受新连接，等待已有连接结束后退出。
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"c03/pkg/echo"
+)
+
+func main() {
+	listen := flag.String("listen", "", "以服务端运行，监听该地址")
+	connect := flag.String("connect", "", "以客户端运行，连接该地址")
+	pipe := flag.Bool("pipe", false, "用 net.Pipe 连接内存中的服务端自检")
+	maxConns := flag.Int("max-conns", 0, "最大并发连接数，0 表示不限制")
+	idle := flag.Duration("idle", time.Minute, "连接空闲超时")
+	drain := flag.Duration("drain", 5*time.Second, "关闭时等待已有连接的最长时间")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch {
+	case *listen != "":
+		srv := echo.NewServer()
+		srv.MaxConns = *maxConns
+		srv.IdleTimeout = *idle
+		srv.DrainTimeout = *drain
+		if err := srv.ListenAndServe(ctx, *listen); err != nil {
+			log.Fatal(err)
+		}
+	case *connect != "":
+		c, err := echo.Dial(ctx, *connect)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		roundTrips(c, flag.Args())
+	case *pipe:
+		selfCheck()
+	default:
+		fmt.Fprintln(os.Stderr, "用法: echo -listen addr | -connect addr [消息...] | -pipe")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+}
+
+func roundTrips(c *echo.Client, msgs []string) {
+	if len(msgs) == 0 {
+		msgs = []string{"hello"}
+	}
+	for _, m := range msgs {
+		start := time.Now()
+		got, err := c.Echo([]byte(m))
+		if err != nil {
+			log.Fatal(err)
+		}
+		label := fmt.Sprintf("%q", got)
+		if len(got) > 32 {
+			label = fmt.Sprintf("%d 字节", len(got))
+		}
+		fmt.Printf("%s 往返 %v\n", label, time.Since(start).Round(time.Microsecond))
+	}
+}
+
+// selfCheck 不占用端口：net.Pipe 的一端交给 Server.Handle，另一端给客户端
+func selfCheck() {
+	srv := echo.NewServer()
+	srv.IdleTimeout = 200 * time.Millisecond
+
+	serverSide, clientSide := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		srv.Handle(serverSide)
+		close(done)
+	}()
+
+	c := echo.NewClient(clientSide)
+	roundTrips(c, []string{"ping", string(make([]byte, 64<<10))})
+
+	// 客户端不再发送数据，服务端在 IdleTimeout 后断开
+	<-done
+	fmt.Println("空闲超时后服务端关闭了连接")
+	if _, err := c.Echo([]byte("late")); err != nil {
+		fmt.Println("再次发送失败:", err)
+	}
+}
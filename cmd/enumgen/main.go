@@ -0,0 +1,342 @@
+// ============================================
+// enumgen：为 iota 枚举生成 String / MarshalJSON / Parse
+// ============================================
+//
+// iota 常量打印出来只是数字：fmt.Println(Friday) 输出 4，写进 JSON 也是 4，
+// 调整常量顺序后，已保存的数据含义就全变了。enumgen 与 golang.org/x/tools/cmd/stringer 类似，
+// 为指定类型生成：
+//
+//   func (x T) String() string               名字；未定义的值输出 T(42)
+//   func (x T) MarshalJSON() ([]byte, error) 以名字编码，未定义的值报错
+//   func (x *T) UnmarshalJSON([]byte) error  从名字解码
+//   func ParseT(s string) (T, error)         名字转值，先精确匹配，再忽略大小写匹配
+//
+// -flags 中的类型按位标志处理：String 输出 "Read|Write"，ParseT 接受同样的格式。
+//
+// 在定义枚举的文件中加入：
+//
+//   //go:generate go run c03/cmd/enumgen -type=Weekday,Permission -flags=Permission
+//
+// 然后运行 go generate ./... 或 go generate <file>，输出到 <file>_enum.go。
+//
+// 只对 go generate 所在的文件（$GOFILE）做类型检查，而不是整个包：
+// 常量一般和类型定义在同一个文件里，这样既快，也不受包里其他文件编译错误的影响。
+// 检查中的错误（如引用了其他文件中的标识符）会被忽略，只要常量本身能求值即可。
+// ============================================
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+func main() {
+	typeList := flag.String("type", "", "逗号分隔的类型名，必填")
+	flagList := flag.String("flags", "", "按位标志处理的类型，必须同时出现在 -type 中")
+	output := flag.String("output", "", "输出文件，默认为 <第一个输入文件>_enum.go")
+	trimPrefix := flag.String("trimprefix", "", "生成名字时去掉的常量前缀")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("enumgen: ")
+
+	if *typeList == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	files := flag.Args()
+	if len(files) == 0 {
+		gofile := os.Getenv("GOFILE")
+		if gofile == "" {
+			log.Fatal("没有输入文件：在 //go:generate 中运行，或在参数中给出文件")
+		}
+		files = []string{gofile}
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(files[0], ".go") + "_enum.go"
+	}
+
+	pkgName, enums, err := load(files, split(*typeList), *trimPrefix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	flagTypes := split(*flagList)
+	for _, name := range flagTypes {
+		if !slices.ContainsFunc(enums, func(e *enum) bool { return e.name == name }) {
+			log.Fatalf("-flags 中的 %s 不在 -type 中", name)
+		}
+	}
+	for _, e := range enums {
+		e.flags = slices.Contains(flagTypes, e.name)
+	}
+
+	src, err := generate(pkgName, enums, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func split(s string) []string {
+	var out []string
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// ============================================
+// 收集常量
+// ============================================
+
+// enum 一个枚举类型及其常量
+type enum struct {
+	name     string
+	unsigned bool
+	flags    bool
+	values   []value // 按值排序，值相同时按声明顺序
+}
+
+type value struct {
+	ident string // 常量名
+	name  string // 输出的名字（去掉 -trimprefix 后）
+	v     constant.Value
+}
+
+// load 解析并类型检查 files，返回包名和 types 中每个类型的常量
+func load(files, typeNames []string, trimPrefix string) (string, []*enum, error) {
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		parsed = append(parsed, f)
+	}
+
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) {}, // 只关心常量，忽略其余错误
+	}
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	pkg, _ := conf.Check(parsed[0].Name.Name, fset, parsed, info)
+
+	var enums []*enum
+	for _, typeName := range typeNames {
+		obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			return "", nil, fmt.Errorf("找不到类型 %s", typeName)
+		}
+		basic, ok := obj.Type().Underlying().(*types.Basic)
+		if !ok || basic.Info()&types.IsInteger == 0 {
+			return "", nil, fmt.Errorf("%s 的底层类型必须是整数", typeName)
+		}
+		e := &enum{name: typeName, unsigned: basic.Info()&types.IsUnsigned != 0}
+
+		// Defs 是 map，先收集再按声明位置排序，保证输出稳定
+		var consts []*types.Const
+		for id, def := range info.Defs {
+			c, ok := def.(*types.Const)
+			if !ok || id.Name == "_" || c.Parent() != pkg.Scope() || !types.Identical(c.Type(), obj.Type()) {
+				continue
+			}
+			consts = append(consts, c)
+		}
+		slices.SortFunc(consts, func(a, b *types.Const) int { return int(a.Pos() - b.Pos()) })
+		for _, c := range consts {
+			e.values = append(e.values, value{
+				ident: c.Name(),
+				name:  strings.TrimPrefix(c.Name(), trimPrefix),
+				v:     c.Val(),
+			})
+		}
+		if len(e.values) == 0 {
+			return "", nil, fmt.Errorf("类型 %s 没有包级常量", typeName)
+		}
+		slices.SortStableFunc(e.values, func(a, b value) int {
+			switch {
+			case constant.Compare(a.v, token.LSS, b.v):
+				return -1
+			case constant.Compare(b.v, token.LSS, a.v):
+				return 1
+			}
+			return 0
+		})
+		enums = append(enums, e)
+	}
+	return pkg.Name(), enums, nil
+}
+
+// ============================================
+// 生成代码
+// ============================================
+
+func generate(pkgName string, enums []*enum, args []string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"enumgen %s\"; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	b.WriteString("import (\n\"encoding/json\"\n\"fmt\"\n\"strconv\"\n\"strings\"\n)\n")
+	for _, e := range enums {
+		writeEnum(&b, e)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码: %w", err)
+	}
+	return src, nil
+}
+
+func writeEnum(b *bytes.Buffer, e *enum) {
+	T := e.name
+	names := "_" + T + "_names"
+
+	// 值 -> 名字；同一个值有多个常量时只保留第一个，与 stringer 一致
+	fmt.Fprintf(b, "\nvar %s = map[%s]string{\n", names, T)
+	seen := make(map[string]bool)
+	for _, v := range e.values {
+		if key := v.v.ExactString(); !seen[key] {
+			seen[key] = true
+			fmt.Fprintf(b, "%s: %q,\n", v.ident, v.name)
+		}
+	}
+	b.WriteString("}\n")
+
+	// 名字 -> 值：所有常量都可以被解析
+	fmt.Fprintf(b, "\nvar _%s_values = map[string]%s{\n", T, T)
+	for _, v := range e.values {
+		fmt.Fprintf(b, "%q: %s,\n", v.name, v.ident)
+	}
+	b.WriteString("}\n")
+
+	// String 中未定义值的数字部分
+	num := "strconv.FormatInt(int64(x), 10)"
+	if e.unsigned {
+		num = "strconv.FormatUint(uint64(x), 10)"
+	}
+
+	if e.flags {
+		// 位标志：先找完整匹配，再按单个位拆分，剩下的位以十六进制输出
+		fmt.Fprintf(b, `
+func (x %[1]s) String() string {
+	if s, ok := %[2]s[x]; ok {
+		return s
+	}
+	var parts []string
+	rest := x
+	for _, bit := range _%[1]s_bits {
+		if x&bit == bit {
+			parts = append(parts, %[2]s[bit])
+			rest &^= bit
+		}
+	}
+	if rest != 0 || len(parts) == 0 {
+		parts = append(parts, "0x"+strconv.FormatUint(uint64(rest), 16))
+	}
+	return strings.Join(parts, "|")
+}
+
+var _%[1]s_bits = []%[1]s{`, T, names)
+		for _, v := range e.values {
+			if u, ok := constant.Uint64Val(v.v); ok && u != 0 && u&(u-1) == 0 && seen[v.v.ExactString()] {
+				seen[v.v.ExactString()] = false // 只列出一次
+				fmt.Fprintf(b, "%s, ", v.ident)
+			}
+		}
+		fmt.Fprintf(b, `}
+
+// Parse%[1]s 把 "A|B" 形式的字符串解析为 %[1]s
+func Parse%[1]s(s string) (%[1]s, error) {
+	var x %[1]s
+	for part := range strings.SplitSeq(s, "|") {
+		v, err := _%[1]s_parse(strings.TrimSpace(part))
+		if err != nil {
+			return 0, err
+		}
+		x |= v
+	}
+	return x, nil
+}
+
+func _%[1]s_valid(x %[1]s) bool {
+	var all %[1]s
+	for _, bit := range _%[1]s_bits {
+		all |= bit
+	}
+	_, named := %[2]s[x]
+	return named || x&^all == 0
+}
+`, T, names)
+	} else {
+		fmt.Fprintf(b, `
+func (x %[1]s) String() string {
+	if s, ok := %[2]s[x]; ok {
+		return s
+	}
+	return "%[1]s(" + %[3]s + ")"
+}
+
+// Parse%[1]s 把名字解析为 %[1]s
+func Parse%[1]s(s string) (%[1]s, error) {
+	return _%[1]s_parse(s)
+}
+
+func _%[1]s_valid(x %[1]s) bool {
+	_, ok := %[2]s[x]
+	return ok
+}
+`, T, names, num)
+	}
+
+	fmt.Fprintf(b, `
+func _%[1]s_parse(s string) (%[1]s, error) {
+	if v, ok := _%[1]s_values[s]; ok {
+		return v, nil
+	}
+	for name, v := range _%[1]s_values {
+		if strings.EqualFold(name, s) {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("无效的 %[1]s: %%q", s)
+}
+
+// MarshalJSON 以名字编码
+func (x %[1]s) MarshalJSON() ([]byte, error) {
+	if !_%[1]s_valid(x) {
+		return nil, fmt.Errorf("%[1]s: 无法编码未定义的值 %%s", x)
+	}
+	return json.Marshal(x.String())
+}
+
+// UnmarshalJSON 从名字解码
+func (x *%[1]s) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%[1]s 应为字符串: %%w", err)
+	}
+	v, err := Parse%[1]s(s)
+	if err != nil {
+		return err
+	}
+	*x = v
+	return nil
+}
+`, T)
+}
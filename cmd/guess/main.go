@@ -0,0 +1,53 @@
+// ============================================
+// 猜数字游戏
+// ============================================
+//
+// tutorial/01_basic_syntax 练习 5 的交互版本：随机生成一个数，
+// 根据"太大了/太小了"的提示猜中它。-max 限制次数作为难度：
+// 1-100 用二分法最多 7 次一定能猜中。
+//
+// 运行：
+//   go run ./cmd/guess
+//   go run ./cmd/guess -max 7
+//   go run ./cmd/guess -lo 1 -hi 1000 -max 10
+// ============================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	basicsyntax "c03/tutorial/01_basic_syntax"
+)
+
+func main() {
+	lo := flag.Int("lo", 1, "最小值")
+	hi := flag.Int("hi", 100, "最大值")
+	maxAttempts := flag.Int("max", 0, "最多猜几次（难度），0 表示不限")
+	flag.Parse()
+	log.SetFlags(0)
+
+	if *lo > *hi {
+		log.Fatalf("-lo %d 大于 -hi %d", *lo, *hi)
+	}
+	if *maxAttempts < 0 {
+		log.Fatal("-max 不能为负数")
+	}
+
+	game := basicsyntax.GuessGame{Lo: *lo, Hi: *hi, MaxAttempts: *maxAttempts}
+	res, err := game.Play(game.RandomTarget(), os.Stdin, os.Stdout)
+	if errors.Is(err, basicsyntax.ErrAborted) {
+		fmt.Printf("下次再来，答案是 %d\n", res.Target)
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !res.Won {
+		os.Exit(1)
+	}
+}
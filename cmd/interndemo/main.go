@@ -0,0 +1,91 @@
+// ============================================
+// 字符串驻留演示：日志分析的内存占用
+// ============================================
+//
+// 生成一份 20 万行的模拟日志（级别只有 4 种，消息来自少量模板和 500 个用户），
+// 分别在不驻留和驻留的情况下用 logstat.ReadAll 全部读入内存，比较堆内存占用。
+//
+// 运行：
+//   go run ./cmd/interndemo
+//   go run ./cmd/interndemo -lines 1000000
+// ============================================
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"time"
+
+	"c03/pkg/intern"
+	"c03/pkg/logstat"
+)
+
+func main() {
+	lines := flag.Int("lines", 200_000, "模拟日志的行数")
+	flag.Parse()
+
+	data := generate(*lines)
+	fmt.Printf("模拟日志: %d 行，%.1f MB\n\n", *lines, float64(len(data))/(1<<20))
+
+	plain := measure(func() any {
+		entries, _ := logstat.ReadAll(bytes.NewReader(data), logstat.Filter{}, nil)
+		return entries
+	})
+	in := intern.New()
+	interned := measure(func() any {
+		entries, _ := logstat.ReadAll(bytes.NewReader(data), logstat.Filter{}, in)
+		return entries
+	})
+
+	fmt.Printf("不驻留: %6.1f MB\n", mb(plain))
+	fmt.Printf("驻留:   %6.1f MB（节省 %.0f%%）\n", mb(interned), 100*(1-float64(interned)/float64(plain)))
+
+	runtime.KeepAlive(data) // data 在两次测量中都应计入"之前"，不能提前被回收
+
+	st := in.Stats()
+	fmt.Printf("\n驻留表: %d 个不同的值，共 %d 字节\n", st.Unique, st.UniqueBytes)
+	fmt.Printf("查找 %d 次，命中率 %.2f%%，少保存 %.1f MB 字符串内容\n", st.Lookups, 100*st.HitRate(), mb(st.SavedBytes))
+}
+
+// measure 返回 load 的结果在 GC 之后仍占用的堆内存
+func measure(load func() any) int64 {
+	before := heapAlloc()
+	result := load()
+	after := heapAlloc()
+	runtime.KeepAlive(result)
+	return int64(after) - int64(before)
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+func mb(n int64) float64 { return float64(n) / (1 << 20) }
+
+// generate 固定种子生成日志，每次运行内容相同
+func generate(n int) []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	levels := []string{"DEBUG", "INFO", "INFO", "INFO", "WARN", "ERROR"}
+	templates := []string{
+		"user u%03d logged in",
+		"user u%03d logged out",
+		"GET /api/orders 200 for u%03d",
+		"cache miss for user u%03d",
+		"payment failed for u%03d: card declined",
+	}
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	var buf bytes.Buffer
+	for i := range n {
+		t := start.Add(time.Duration(i) * time.Second)
+		msg := fmt.Sprintf(templates[r.IntN(len(templates))], r.IntN(500))
+		fmt.Fprintf(&buf, "%s [%s] %s\n", t.Format(logstat.TimeLayout), levels[r.IntN(len(levels))], msg)
+	}
+	return buf.Bytes()
+}
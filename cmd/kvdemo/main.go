@@ -0,0 +1,374 @@
+// ============================================
+// 持久化键值存储演示
+// ============================================
+//
+// 先跑一组崩溃恢复检查，每项在独立的临时目录中进行：
+//   - 写入后重新打开，数据不变
+//   - WAL 末尾留下半条记录（模拟写到一半时进程被杀），重新打开时截断
+//   - 并发读写的同时压缩，压缩后重新打开，数据不变
+//   - 定期压缩（假时钟），重新打开时从快照加载
+//   - 快照已经写好、旧 WAL 段还没删除时崩溃，重新打开时跳过旧段
+//   - 中间的 WAL 段损坏，Open 返回 ErrCorrupt
+// 然后比较默认写入和 WithSync（每次 fsync）的吞吐量。
+//
+// 运行：
+//   go run ./cmd/kvdemo
+//   go run -race ./cmd/kvdemo -n 2000
+// ============================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/kvstore"
+	"c03/pkg/unitext"
+)
+
+func main() {
+	n := flag.Int("n", 5000, "每项检查写入的键数")
+	flag.Parse()
+	log.SetFlags(0)
+
+	fmt.Println("崩溃恢复检查")
+	for _, c := range []struct {
+		name string
+		fn   func(dir string, n int) error
+	}{
+		{"重新打开后数据不变", checkReopen},
+		{"截断 WAL 末尾的半条记录", checkTornTail},
+		{"并发读写时压缩", checkConcurrentCompact},
+		{"定期压缩", checkPeriodicCompact},
+		{"压缩后旧段未删除", checkLeftoverSegment},
+		{"中间段损坏返回 ErrCorrupt", checkCorruptMiddle},
+	} {
+		dir, err := os.MkdirTemp("", "kvdemo-*")
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = c.fn(dir, *n)
+		os.RemoveAll(dir)
+		if err != nil {
+			log.Fatalf("  ✗ %s\n    %v", c.name, err)
+		}
+		fmt.Printf("  ✓ %s\n", c.name)
+	}
+
+	fmt.Println("\n写入吞吐量")
+	for _, c := range []struct {
+		name string
+		n    int
+		opts []kvstore.Option
+	}{
+		{"默认（写到操作系统）", *n, nil},
+		{"WithSync（每次 fsync）", min(*n, 500), []kvstore.Option{kvstore.WithSync()}},
+	} {
+		rate, err := throughput(c.n, c.opts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("  %s %10.0f 次/秒\n", unitext.PadDisplayWidth(c.name, 24), rate)
+	}
+}
+
+// fill 写入 n 个键，每 10 个删除一个，返回期望的内容
+func fill(s *kvstore.KVStore, n int) (map[string]string, error) {
+	want := make(map[string]string)
+	for i := range n {
+		k, v := fmt.Sprintf("user:%05d", i), fmt.Sprintf(`{"score":%d}`, i*7)
+		if err := s.Put(k, []byte(v)); err != nil {
+			return nil, err
+		}
+		want[k] = v
+		if i%10 == 9 {
+			del := fmt.Sprintf("user:%05d", i-5)
+			if err := s.Delete(del); err != nil {
+				return nil, err
+			}
+			delete(want, del)
+		}
+	}
+	return want, nil
+}
+
+// contents 读出全部键值
+func contents(s *kvstore.KVStore) map[string]string {
+	got := make(map[string]string)
+	for _, k := range s.Keys() {
+		v, _ := s.Get(k)
+		got[k] = string(v)
+	}
+	return got
+}
+
+// reopen 关闭后重新打开，比较内容
+func reopen(dir string, s *kvstore.KVStore, want map[string]string) (*kvstore.KVStore, error) {
+	if err := s.Close(); err != nil {
+		return nil, err
+	}
+	s, err := kvstore.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	if got := contents(s); !maps.Equal(got, want) {
+		s.Close()
+		return nil, fmt.Errorf("重新打开后有 %d 个键，期望 %d 个", len(got), len(want))
+	}
+	return s, nil
+}
+
+func checkReopen(dir string, n int) error {
+	s, err := kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	want, err := fill(s, n)
+	if err != nil {
+		return err
+	}
+	if s, err = reopen(dir, s, want); err != nil {
+		return err
+	}
+	defer s.Close()
+	if r := s.Stats().Recovery; r.Replayed != n+n/10 {
+		return fmt.Errorf("重放了 %d 条记录，期望 %d", r.Replayed, n+n/10)
+	}
+	return nil
+}
+
+func checkTornTail(dir string, n int) error {
+	s, err := kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	want, err := fill(s, n)
+	if err != nil {
+		return err
+	}
+	s.Put("torn", []byte("这条写到一半"))
+	seg := walFile(dir, s.Stats().WALSegment)
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	// 去掉最后一条记录的最后 3 个字节
+	info, err := os.Stat(seg)
+	if err != nil {
+		return err
+	}
+	if err := os.Truncate(seg, info.Size()-3); err != nil {
+		return err
+	}
+
+	s, err = kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if got := contents(s); !maps.Equal(got, want) {
+		return fmt.Errorf("恢复后有 %d 个键，期望 %d 个（不含 torn）", len(got), len(want))
+	}
+	if r := s.Stats().Recovery; r.TruncatedBytes == 0 {
+		return fmt.Errorf("没有截断半条记录: %+v", r)
+	}
+	// 截断之后可以继续写入
+	if err := s.Put("after", []byte("ok")); err != nil {
+		return err
+	}
+	want["after"] = "ok"
+	_, err = reopen(dir, s, want)
+	return err
+}
+
+func checkConcurrentCompact(dir string, n int) error {
+	s, err := kvstore.Open(dir, kvstore.WithCompactThreshold(64<<10))
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	want := make(map[string]string)
+	errs := make(chan error, 4)
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range n / 4 {
+				k, v := fmt.Sprintf("w%d:%05d", w, i), fmt.Sprintf("%d", i)
+				if err := s.Put(k, []byte(v)); err != nil {
+					errs <- err
+					return
+				}
+				mu.Lock()
+				want[k] = v
+				mu.Unlock()
+				s.Get(fmt.Sprintf("w%d:%05d", (w+1)%4, i))
+			}
+		})
+	}
+	wg.Go(func() {
+		for range 5 {
+			if err := s.Compact(); err != nil {
+				errs <- err
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	if s.Stats().Compactions < 5 {
+		return fmt.Errorf("只压缩了 %d 次", s.Stats().Compactions)
+	}
+	if s, err = reopen(dir, s, want); err != nil {
+		return err
+	}
+	return s.Close()
+}
+
+func checkPeriodicCompact(dir string, n int) error {
+	fc := clock.NewFakeClock(time.Now())
+	s, err := kvstore.Open(dir, kvstore.WithCompactInterval(time.Minute), kvstore.WithClock(fc))
+	if err != nil {
+		return err
+	}
+	want, err := fill(s, n)
+	if err != nil {
+		return err
+	}
+	fc.BlockUntil(1) // 后台 goroutine 的 ticker 已经创建
+	fc.Advance(time.Minute)
+	// 压缩在后台 goroutine 中进行，等它完成
+	for deadline := time.Now().Add(5 * time.Second); s.Stats().Compactions == 0; {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("时钟前进 1 分钟后没有压缩")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s, err = reopen(dir, s, want); err != nil {
+		return err
+	}
+	defer s.Close()
+	if r := s.Stats().Recovery; r.SnapshotKeys != len(want) || r.Replayed != 0 {
+		return fmt.Errorf("重新打开时快照加载 %d 个键、重放 %d 条记录，期望 %d 和 0", r.SnapshotKeys, r.Replayed, len(want))
+	}
+	return nil
+}
+
+func checkLeftoverSegment(dir string, n int) error {
+	s, err := kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	want, err := fill(s, n)
+	if err != nil {
+		return err
+	}
+	old := walFile(dir, s.Stats().WALSegment)
+	data, err := os.ReadFile(old)
+	if err != nil {
+		return err
+	}
+	if err := s.Compact(); err != nil {
+		return err
+	}
+	s.Put("new", []byte("压缩之后写入"))
+	want["new"] = "压缩之后写入"
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	// 把压缩时删掉的旧段放回去，模拟快照写好之后、删除旧段之前崩溃：
+	// Open 应当按快照头部的段号跳过并删除它
+	if err := os.WriteFile(old, data, 0o644); err != nil {
+		return err
+	}
+	s, err = kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if got := contents(s); !maps.Equal(got, want) {
+		return fmt.Errorf("恢复后有 %d 个键，期望 %d 个", len(got), len(want))
+	}
+	if _, err := os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("快照已覆盖的旧段 %s 没有被删除", filepath.Base(old))
+	}
+	return nil
+}
+
+func checkCorruptMiddle(dir string, n int) error {
+	s, err := kvstore.Open(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := fill(s, n); err != nil {
+		return err
+	}
+	first := walFile(dir, s.Stats().WALSegment)
+	if err := s.Close(); err != nil {
+		return err
+	}
+	// 手动开一个新段，first 变成中间的段
+	if err := os.WriteFile(walFile(dir, 99), nil, 0o644); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(first, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteAt([]byte{0xff, 0xff}, 100)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	s, err = kvstore.Open(dir)
+	if !errors.Is(err, kvstore.ErrCorrupt) {
+		if s != nil {
+			s.Close()
+		}
+		return fmt.Errorf("Open 返回 %v，期望 ErrCorrupt", err)
+	}
+	return nil
+}
+
+// walFile 段号对应的 WAL 文件名，与 kvstore 包内的命名规则一致
+func walFile(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("wal-%016d.log", seq))
+}
+
+// throughput n 次 Put 的每秒次数
+func throughput(n int, opts ...kvstore.Option) (float64, error) {
+	dir, err := os.MkdirTemp("", "kvdemo-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+	s, err := kvstore.Open(dir, append(opts, kvstore.WithLogger(log.New(io.Discard, "", 0)))...)
+	if err != nil {
+		return 0, err
+	}
+	defer s.Close()
+
+	value := []byte(`{"name":"用户","score":100}`)
+	start := time.Now()
+	for i := range n {
+		if err := s.Put(fmt.Sprintf("k%d", i), value); err != nil {
+			return 0, err
+		}
+	}
+	return float64(n) / time.Since(start).Seconds(), nil
+}
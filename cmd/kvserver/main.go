@@ -0,0 +1,298 @@
+// ============================================
+// KV 存储的 HTTP 服务：信号统一处理
+// ============================================
+//
+// 把 HTTP 服务、cron 调度器、KV 存储和热加载的配置接到同一个 signals.Registry 上：
+//   SIGINT / SIGTERM  按注册顺序关闭：http（排空请求）→ cron（等压缩任务结束）→ kvstore（刷盘）
+//   SIGHUP            重新加载配置文件（read_only、max_value_bytes 立即生效）
+//
+// 接口：
+//   PUT    /kv/{key}   请求体为值
+//   GET    /kv/{key}
+//   DELETE /kv/{key}
+//
+// 运行：
+//   go run ./cmd/kvserver -config cmd/kvserver/sample.json -dir /tmp/kv
+//   curl -X PUT localhost:8080/kv/a -d hello
+//   kill -HUP <pid>      # 修改配置后重新加载
+//   go run ./cmd/kvserver -selftest   # 给自己发 SIGHUP、SIGTERM，检查重新加载和关闭顺序
+// ============================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"c03/pkg/config"
+	"c03/pkg/cron"
+	"c03/pkg/httpserver"
+	"c03/pkg/kvstore"
+	"c03/pkg/signals"
+)
+
+// serverConfig 可以热加载的配置
+type serverConfig struct {
+	ReadOnly      bool `json:"read_only"`
+	MaxValueBytes int  `json:"max_value_bytes" config:"required"`
+}
+
+func (c *serverConfig) Validate() error {
+	if c.MaxValueBytes <= 0 {
+		return fmt.Errorf("max_value_bytes 必须为正数: %d", c.MaxValueBytes)
+	}
+	return nil
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	dir := flag.String("dir", "kvdata", "数据目录")
+	configPath := flag.String("config", "cmd/kvserver/sample.json", "配置文件，SIGHUP 时重新加载")
+	selftest := flag.Bool("selftest", false, "在临时目录中启动，给自己发送信号并检查结果")
+	flag.Parse()
+	log.SetFlags(log.Ltime | log.Lmicroseconds)
+
+	if *selftest {
+		if err := runSelftest(); err != nil {
+			log.Fatalf("✗ %v", err)
+		}
+		fmt.Println("✓ 重新加载、拒绝无效配置、按顺序关闭、数据落盘")
+		return
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	svc, err := start(ln, *dir, *configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("pid %d；kill -HUP 重新加载配置，Ctrl+C 退出", os.Getpid())
+	if err := svc.reg.Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// service 运行中的各个组件
+type service struct {
+	reg   *signals.Registry
+	cfg   *config.Config[serverConfig]
+	store *kvstore.KVStore
+	http  chan error // ServeListener 的返回值
+}
+
+// start 按关闭顺序注册各组件，然后开始服务；返回后调用 reg.Run 等待信号
+func start(ln net.Listener, dir, configPath string) (*service, error) {
+	reg := signals.New(signals.WithTimeout(15 * time.Second))
+
+	// 1. http：最先注册，最先关闭，不再接受会写入存储的请求
+	httpOpt := httpserver.WithSignals(reg)
+
+	// 2. cron：每分钟压缩一次 WAL
+	sched := cron.New()
+	reg.Go("cron", 0, sched.Run)
+
+	// 3. kvstore：最后关闭
+	store, err := kvstore.Open(dir, kvstore.WithSignals(reg))
+	if err != nil {
+		return nil, err
+	}
+	if err := sched.Add("compact", "* * * * *", cron.Skip, func(context.Context) error {
+		return store.Compact()
+	}); err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.New[serverConfig](configPath)
+	if err != nil {
+		reg.Shutdown(context.Background())
+		return nil, err
+	}
+	reg.OnReload("config", 0, func(context.Context) error { return cfg.Reload() })
+
+	svc := &service{reg: reg, cfg: cfg, store: store, http: make(chan error, 1)}
+	go func() {
+		svc.http <- httpserver.ServeListener(context.Background(), ln, svc.handler(), httpOpt)
+	}()
+	return svc, nil
+}
+
+func (s *service) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /kv/{key}", func(w http.ResponseWriter, r *http.Request) {
+		v, ok := s.store.Get(r.PathValue("key"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(v)
+	})
+	mux.HandleFunc("PUT /kv/{key}", func(w http.ResponseWriter, r *http.Request) {
+		cfg := s.cfg.Get() // 每个请求读一次，SIGHUP 之后的请求立即使用新配置
+		if cfg.ReadOnly {
+			http.Error(w, "只读模式", http.StatusForbidden)
+			return
+		}
+		v, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(cfg.MaxValueBytes)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := s.store.Put(r.PathValue("key"), v); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /kv/{key}", func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.Get().ReadOnly {
+			http.Error(w, "只读模式", http.StatusForbidden)
+			return
+		}
+		if err := s.store.Delete(r.PathValue("key")); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// ============================================
+// 自检
+// ============================================
+
+func runSelftest() error {
+	dir, err := os.MkdirTemp("", "kvserver-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	configPath := filepath.Join(dir, "config.json")
+	writeConfig := func(content string) error { return os.WriteFile(configPath, []byte(content), 0o644) }
+	if err := writeConfig(`{"read_only": false, "max_value_bytes": 16}`); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	svc, err := start(ln, filepath.Join(dir, "data"), configPath)
+	if err != nil {
+		return err
+	}
+	runErr := make(chan error, 1)
+	go func() { runErr <- svc.reg.Run(context.Background()) }()
+
+	base := "http://" + ln.Addr().String() + "/kv/"
+	put := func(key, value string) (int, error) {
+		req, _ := http.NewRequest(http.MethodPut, base+key, strings.NewReader(value))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+	expect := func(what string, got int, err error, want int) error {
+		if err != nil {
+			return fmt.Errorf("%s: %w", what, err)
+		}
+		if got != want {
+			return fmt.Errorf("%s 返回 %d，期望 %d", what, got, want)
+		}
+		return nil
+	}
+
+	code, err := put("a", "hello")
+	if err := expect("PUT a", code, err, http.StatusNoContent); err != nil {
+		return err
+	}
+	code, err = put("big", strings.Repeat("x", 17))
+	if err := expect("PUT 超过 max_value_bytes 的值", code, err, http.StatusRequestEntityTooLarge); err != nil {
+		return err
+	}
+
+	// SIGHUP：切换为只读
+	if err := writeConfig(`{"read_only": true, "max_value_bytes": 16}`); err != nil {
+		return err
+	}
+	if err := signalSelf(syscall.SIGHUP); err != nil {
+		return err
+	}
+	if !waitFor(func() bool { return svc.cfg.Version() == 2 }) {
+		return errors.New("SIGHUP 之后配置没有重新加载")
+	}
+	code, err = put("b", "world")
+	if err := expect("只读模式下 PUT", code, err, http.StatusForbidden); err != nil {
+		return err
+	}
+
+	// 无效的配置：重新加载失败，只读模式保持不变
+	if err := writeConfig(`{"read_only": false, "max_value_bytes": 0}`); err != nil {
+		return err
+	}
+	if err := svc.reg.Reload(context.Background()); err == nil {
+		return errors.New("加载 max_value_bytes 为 0 的配置应当失败")
+	}
+	if !svc.cfg.Get().ReadOnly {
+		return errors.New("无效配置替换了旧配置")
+	}
+
+	// SIGTERM：按 http → cron → kvstore 关闭
+	if err := signalSelf(syscall.SIGTERM); err != nil {
+		return err
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			return fmt.Errorf("关闭失败: %w", err)
+		}
+	case <-time.After(20 * time.Second):
+		return errors.New("SIGTERM 之后没有在 20 秒内完成关闭")
+	}
+	if err := <-svc.http; err != nil {
+		return fmt.Errorf("HTTP 服务: %w", err)
+	}
+	// 重新打开数据目录，检查关闭时数据确实落盘
+	store, err := kvstore.Open(filepath.Join(dir, "data"))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if v, ok := store.Get("a"); !ok || string(v) != "hello" {
+		return fmt.Errorf("重新打开后 a = %q, %v", v, ok)
+	}
+	return nil
+}
+
+func signalSelf(sig os.Signal) error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
+
+func waitFor(cond func() bool) bool {
+	for range 200 {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
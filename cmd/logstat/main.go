@@ -0,0 +1,71 @@
+// ============================================
+// 日志分析工具
+// ============================================
+//
+// 运行：
+//   go run ./cmd/logstat cmd/logstat/sample.log
+//   go run ./cmd/logstat -from "2024-01-15 10:31:00" -to "2024-01-15 10:33:00" cmd/logstat/sample.log
+//   cat app.log | go run ./cmd/logstat
+// ============================================
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"c03/pkg/logstat"
+)
+
+func main() {
+	from := flag.String("from", "", "起始时间（包含），格式 "+logstat.TimeLayout)
+	to := flag.String("to", "", "结束时间（不包含），格式 "+logstat.TimeLayout)
+	flag.Parse()
+
+	var filter logstat.Filter
+	var err error
+	if filter.From, err = parseTime(*from); err != nil {
+		log.Fatalf("-from: %v", err)
+	}
+	if filter.To, err = parseTime(*to); err != nil {
+		log.Fatalf("-to: %v", err)
+	}
+
+	// 多个文件依次读取，相当于把它们拼接成一个流；没有参数时读标准输入
+	var input io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		readers := make([]io.Reader, 0, flag.NArg())
+		for _, name := range flag.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			readers = append(readers, f)
+		}
+		input = io.MultiReader(readers...)
+	}
+
+	summary, err := logstat.Analyze(input, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(out))
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(logstat.TimeLayout, s, time.Local)
+}
@@ -0,0 +1,194 @@
+// ============================================
+// 最佳实践的微基准：defer 与接收者类型
+// ============================================
+//
+// 用数字检验教程中的两条建议：
+//   - tutorial/02：避免在热路径中使用 defer
+//     对比"加锁 + defer 解锁"与"加锁 + 手动解锁"。Go 1.14 起大多数 defer
+//     被编译成内联代码（open-coded defer），两者几乎没有差别；
+//     循环中的 defer 仍要在运行时登记，每个约几十 ns 且有堆分配
+//   - tutorial/03：结构体较大时用指针接收者
+//     8 字节的 rect（与 tutorial/03 的 Rectangle 相同）与 1KB 的 bigRect，
+//     分别用值接收者和指针接收者调用。值接收者每次调用都复制整个结构体，
+//     小结构体复制几乎免费，1KB 的结构体复制成本就很明显了
+//
+// 方法标记了 //go:noinline：内联后编译器可以省掉复制，测不出调用本身的差别。
+//
+// 运行：
+//   go run ./cmd/microbench
+//   go run ./cmd/microbench -benchtime 200ms
+//   go run ./cmd/microbench -profile prof    同时采集 CPU / 堆 profile 到 prof/
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"c03/pkg/profiling"
+	"c03/pkg/unitext"
+)
+
+// rect 与 tutorial/03 的 Rectangle 相同：两个 float32，共 8 字节
+type rect struct {
+	length, width float32
+}
+
+//go:noinline
+func (r rect) AreaValue() float32 { return r.length * r.width }
+
+//go:noinline
+func (r *rect) AreaPointer() float32 { return r.length * r.width }
+
+// bigRect 在 rect 后面填充到 1KB，模拟带大数组字段的结构体
+type bigRect struct {
+	length, width float32
+	_             [1016]byte
+}
+
+//go:noinline
+func (r bigRect) AreaValue() float32 { return r.length * r.width }
+
+//go:noinline
+func (r *bigRect) AreaPointer() float32 { return r.length * r.width }
+
+// counter 被加锁保护的计数器，分别用 defer 和手动解锁
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+//go:noinline
+func (c *counter) incManual() {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+//go:noinline
+func (c *counter) incDefer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}
+
+// deferInLoop 在循环里 defer：次数在编译期未知，不能 open-coded，
+// 每个 defer 记录都要在运行时登记，到函数返回时才依次执行
+//
+//go:noinline
+func (c *counter) deferInLoop(times int) {
+	for range times {
+		defer c.release()
+	}
+}
+
+//go:noinline
+func (c *counter) manualInLoop(times int) {
+	for range times {
+		c.release()
+	}
+}
+
+func (c *counter) release() { c.n++ }
+
+// bench 一个基准
+type bench struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+// sink 防止编译器把没有用到的结果优化掉
+var sink float32
+
+func main() {
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	profileDir := flag.String("profile", "", "把 CPU / 堆 profile 写到这个目录，为空时不采集")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	small := rect{length: 10, width: 5}
+	big := bigRect{length: 10, width: 5}
+	var c counter
+
+	groups := []struct {
+		title   string
+		benches []bench
+	}{
+		{"defer（tutorial/02）", []bench{
+			{"手动 Unlock", func(b *testing.B) {
+				for b.Loop() {
+					c.incManual()
+				}
+			}},
+			{"defer Unlock", func(b *testing.B) {
+				for b.Loop() {
+					c.incDefer()
+				}
+			}},
+			{"循环中手动 10 次", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					c.manualInLoop(10)
+				}
+			}},
+			{"循环中 defer 10 次", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					c.deferInLoop(10)
+				}
+			}},
+		}},
+		{"接收者（tutorial/03）", []bench{
+			{"8B   值接收者", func(b *testing.B) {
+				for b.Loop() {
+					sink = small.AreaValue()
+				}
+			}},
+			{"8B   指针接收者", func(b *testing.B) {
+				for b.Loop() {
+					sink = small.AreaPointer()
+				}
+			}},
+			{"1KB  值接收者", func(b *testing.B) {
+				for b.Loop() {
+					sink = big.AreaValue()
+				}
+			}},
+			{"1KB  指针接收者", func(b *testing.B) {
+				for b.Loop() {
+					sink = big.AreaPointer()
+				}
+			}},
+		}},
+	}
+
+	files, err := profiling.Profile(context.Background(), *profileDir, func(context.Context) error {
+		for _, g := range groups {
+			fmt.Println(g.title)
+			fmt.Printf("  %s %10s %8s %10s\n", unitext.PadDisplayWidth("基准", 20), "ns/op", "B/op", "allocs/op")
+			for _, r := range g.benches {
+				res := testing.Benchmark(r.fn)
+				ns := float64(res.T.Nanoseconds()) / float64(res.N)
+				fmt.Printf("  %s %10.2f %8d %10d\n", unitext.PadDisplayWidth(r.name, 20), ns, res.AllocedBytesPerOp(), res.AllocsPerOp())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *profileDir != "" {
+		fmt.Printf("\nprofile: %s, %s\n  go tool pprof -top %s\n", files.CPU, files.Heap, files.CPU)
+	}
+}
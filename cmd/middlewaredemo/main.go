@@ -0,0 +1,187 @@
+// ============================================
+// HTTP 中间件链演示
+// ============================================
+//
+// 用 httptest 在进程内发请求，逐个演示每个中间件的效果，不需要监听端口。
+//
+// 运行：
+//   go run ./cmd/middlewaredemo
+// ============================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/ctxutil"
+	"c03/pkg/metrics"
+	"c03/pkg/middleware"
+)
+
+const token = "secret"
+
+func main() {
+	logger := log.New(os.Stdout, "  [log] ", 0)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hello", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "hello")
+	})
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("处理器出错了")
+	})
+
+	// 限流放在认证之前：未认证的请求同样消耗配额，防止暴力猜 token
+	reg := metrics.NewRegistry()
+	handler := middleware.Chain(
+		middleware.Recovery(log.New(io.Discard, "", 0)), // 演示中不打印堆栈
+		middleware.Logging(logger),
+		middleware.Metrics(reg),
+		middleware.RateLimit(middleware.NewTokenBucket(1, 4)),
+		middleware.Auth(token),
+	)(mux)
+
+	fmt.Println("=== 认证 ===")
+	do(handler, "/hello", "")
+	do(handler, "/hello", "wrong")
+	do(handler, "/hello", token)
+
+	fmt.Println("\n=== panic 恢复 ===")
+	do(handler, "/panic", token)
+
+	fmt.Println("\n=== 限流（容量 4，前面已用完）===")
+	for range 2 {
+		do(handler, "/hello", token)
+	}
+
+	demoPerClient()
+	demoRequestID()
+
+	// /metrics 不经过认证和限流，通常只在内网端口暴露
+	fmt.Println("\n=== 指标（Prometheus 文本格式）===")
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for line := range strings.Lines(rec.Body.String()) {
+		// 只展示请求数和进行中的请求，耗时桶太长
+		if strings.Contains(line, "http_requests_") {
+			fmt.Print(line)
+		}
+	}
+}
+
+// demoPerClient 三个 IP 并发各发 20 个请求，每个 IP 独立拥有 5 个令牌
+// 时间用假时钟冻结，令牌不会在压测途中补充，结果是确定的
+func demoPerClient() {
+	fmt.Println("\n=== 按客户端限流（每个 IP 容量 5，三个 IP 并发）===")
+	fc := clock.NewFakeClock(time.Now())
+	limiter := middleware.NewClientLimiter(1, 5,
+		middleware.WithIdleTimeout(time.Minute),
+		middleware.WithClock(fc),
+	)
+	handler := middleware.RateLimitByClient(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	}))
+
+	ips := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	var ok, limited [3]atomic.Int32
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		for range 20 {
+			wg.Go(func() {
+				req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+				req.RemoteAddr = ip + ":12345"
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code == http.StatusOK {
+					ok[i].Add(1)
+				} else {
+					limited[i].Add(1)
+				}
+			})
+		}
+	}
+	wg.Wait()
+	for i, ip := range ips {
+		fmt.Printf("%s: 放行 %d, 429 %d\n", ip, ok[i].Load(), limited[i].Load())
+	}
+
+	// 一分钟没有请求后，下一次 Allow 会顺带清理空闲客户端
+	fmt.Println("记录的客户端数:", limiter.Len())
+	fc.Advance(time.Minute)
+	limiter.Allow("10.0.0.9")
+	fmt.Println("空闲 1 分钟后:", limiter.Len())
+}
+
+// demoRequestID 请求 ID 从 HTTP 层一路带到后台 worker 的日志里
+func demoRequestID() {
+	fmt.Println("\n=== 请求 ID（HTTP 层与 worker 日志关联）===")
+	// 去掉时间字段，输出更简洁
+	logger := slog.New(middleware.NewRequestIDHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	// worker 在请求返回之后才处理任务：用 Detach 保留请求 ID，但不随请求一起被取消
+	type job struct {
+		ctx  context.Context
+		name string
+	}
+	jobs := make(chan job, 4)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for j := range jobs {
+			logger.InfoContext(j.ctx, "worker 处理任务", "job", j.name)
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "接收任务")
+		jobs <- job{ctx: ctxutil.Detach(r.Context()), name: "resize-image"}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := middleware.Chain(
+		middleware.RequestID(),
+		middleware.Logging(log.New(os.Stdout, "  [log] ", 0)),
+	)(mux)
+
+	// 第一个请求由服务端生成 ID，第二个沿用上游传来的 ID
+	for _, upstream := range []string{"", "gateway-7f3a"} {
+		req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+		if upstream != "" {
+			req.Header.Set(middleware.RequestIDHeader, upstream)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		fmt.Printf("POST /jobs %s=%q -> %d，响应头 %s=%s\n",
+			middleware.RequestIDHeader, upstream, rec.Code, middleware.RequestIDHeader, rec.Header().Get(middleware.RequestIDHeader))
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func do(h http.Handler, path, tok string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	fmt.Printf("GET %-7s token=%-6q -> %d %s\n", path, tok, rec.Code, strings.TrimSpace(rec.Body.String()))
+}
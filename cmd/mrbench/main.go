@@ -0,0 +1,256 @@
+// ============================================
+// MapReduce 词频统计与扩展性基准
+// ============================================
+//
+// 用 pkg/mapreduce 统计一批随机文档的词频：
+//   mapper：一篇文档 -> 本篇的 map[词]次数（分词和计数都在这里，并行执行）
+//   reducer：把每篇的计数合并进总表（只在一个 goroutine 中执行）
+//
+// 先检查结果与串行统计一致、错误和取消能让 MapReduce 及时返回，
+// 再在不同的 GOMAXPROCS 下跑基准（mapper 数等于 GOMAXPROCS），最后比较不同的块大小。
+// 加速比受 CPU 核数限制：GOMAXPROCS 超过核数以后不会再变快，单核机器上各行基本相同。
+//
+// 运行：
+//   go run ./cmd/mrbench
+//   go run ./cmd/mrbench -docs 5000 -procs 1,2,4,8,16 -benchtime 500ms
+// ============================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"c03/pkg/mapreduce"
+	"c03/pkg/unitext"
+)
+
+var sinkCounts map[string]int
+
+func main() {
+	docs := flag.Int("docs", 2000, "文档数")
+	words := flag.Int("words", 200, "每篇文档的词数")
+	procs := flag.String("procs", "1,2,4,8", "逗号分隔的 GOMAXPROCS 取值")
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+	var procList []int
+	for f := range strings.SplitSeq(*procs, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 1 {
+			log.Fatalf("-procs: 无效的取值 %q", f)
+		}
+		procList = append(procList, n)
+	}
+
+	corpus := makeCorpus(*docs, *words)
+
+	fmt.Println("正确性检查")
+	for _, c := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"结果与串行统计一致（各种并行度和块大小）", func() error { return checkWordCount(corpus) }},
+		{"mapper 出错时返回错误并停止", checkMapperError},
+		{"ctx 取消时及时返回", checkCancel},
+	} {
+		if err := c.fn(); err != nil {
+			log.Fatalf("  ✗ %s\n    %v", c.name, err)
+		}
+		fmt.Printf("  ✓ %s\n", c.name)
+	}
+
+	fmt.Printf("\n词频统计：%d 篇文档 × %d 词，CPU 核数 %d\n", *docs, *words, runtime.NumCPU())
+	fmt.Printf("  %s %14s %8s\n", unitext.PadDisplayWidth("GOMAXPROCS", 12), "ns/op", "加速比")
+	prev := runtime.GOMAXPROCS(0)
+	seq := testing.Benchmark(func(b *testing.B) {
+		for b.Loop() {
+			sinkCounts = countSequential(corpus)
+		}
+	})
+	fmt.Printf("  %s %14d %8s\n", unitext.PadDisplayWidth("串行", 12), seq.NsPerOp(), "1.00x")
+	for _, p := range procList {
+		runtime.GOMAXPROCS(p)
+		res := testing.Benchmark(func(b *testing.B) {
+			for b.Loop() {
+				sinkCounts, _ = wordCount(context.Background(), corpus, mapreduce.WithParallelism(p))
+			}
+		})
+		fmt.Printf("  %s %14d %7.2fx\n", unitext.PadDisplayWidth(strconv.Itoa(p), 12), res.NsPerOp(), speedup(seq, res))
+	}
+	runtime.GOMAXPROCS(prev)
+
+	fmt.Printf("\n块大小（GOMAXPROCS=%d）：块太小时 channel 通信占比高，太大时负载不均衡\n", prev)
+	fmt.Printf("  %s %14s\n", unitext.PadDisplayWidth("ChunkSize", 12), "ns/op")
+	for _, size := range []int{1, 16, mapreduce.DefaultChunkSize, 512} {
+		res := testing.Benchmark(func(b *testing.B) {
+			for b.Loop() {
+				sinkCounts, _ = wordCount(context.Background(), corpus, mapreduce.WithChunkSize(size))
+			}
+		})
+		fmt.Printf("  %s %14d\n", unitext.PadDisplayWidth(strconv.Itoa(size), 12), res.NsPerOp())
+	}
+}
+
+func speedup(base, r testing.BenchmarkResult) float64 {
+	return float64(base.NsPerOp()) / float64(r.NsPerOp())
+}
+
+// ============================================
+// 词频统计
+// ============================================
+
+// countWords mapper：统计一篇文档的词频，忽略大小写和标点
+func countWords(_ context.Context, doc string) (map[string]int, error) {
+	counts := make(map[string]int)
+	for w := range strings.FieldsFuncSeq(doc, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		counts[strings.ToLower(w)]++
+	}
+	return counts, nil
+}
+
+// mergeCounts reducer：acc 为 nil 时创建
+func mergeCounts(acc, m map[string]int) map[string]int {
+	if acc == nil {
+		acc = make(map[string]int, len(m))
+	}
+	for w, n := range m {
+		acc[w] += n
+	}
+	return acc
+}
+
+func wordCount(ctx context.Context, corpus []string, opts ...mapreduce.Option) (map[string]int, error) {
+	return mapreduce.MapReduce(ctx, corpus, countWords, mergeCounts, opts...)
+}
+
+func countSequential(corpus []string) map[string]int {
+	var total map[string]int
+	for _, doc := range corpus {
+		m, _ := countWords(context.Background(), doc)
+		total = mergeCounts(total, m)
+	}
+	return total
+}
+
+// makeCorpus 生成随机文档：词频服从 Zipf 分布（少数词很常见），夹杂大小写和标点
+func makeCorpus(docs, words int) []string {
+	r := rand.New(rand.NewPCG(1, 2))
+	vocab := make([]string, 5000)
+	for i := range vocab {
+		var sb strings.Builder
+		for range 3 + r.IntN(6) {
+			sb.WriteByte(byte('a' + r.IntN(26)))
+		}
+		vocab[i] = sb.String()
+	}
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(len(vocab)-1))
+	corpus := make([]string, docs)
+	for d := range corpus {
+		var sb strings.Builder
+		for i := range words {
+			w := vocab[zipf.Uint64()]
+			if i%10 == 0 {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			sb.WriteString(w)
+			if i%7 == 6 {
+				sb.WriteString(", ")
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		corpus[d] = sb.String()
+	}
+	return corpus
+}
+
+// ============================================
+// 正确性检查
+// ============================================
+
+func checkWordCount(corpus []string) error {
+	want := countSequential(corpus)
+	for _, p := range []int{1, 3, 8} {
+		for _, size := range []int{1, 7, len(corpus) + 1} {
+			got, err := wordCount(context.Background(), corpus, mapreduce.WithParallelism(p), mapreduce.WithChunkSize(size))
+			if err != nil {
+				return err
+			}
+			if !maps.Equal(got, want) {
+				return fmt.Errorf("并行度 %d、块大小 %d 时结果与串行统计不一致", p, size)
+			}
+		}
+	}
+	got, err := wordCount(context.Background(), nil)
+	if err != nil || len(got) != 0 {
+		return fmt.Errorf("空输入返回 %v, %v", got, err)
+	}
+	return nil
+}
+
+var errBadDoc = errors.New("文档损坏")
+
+func checkMapperError() error {
+	inputs := make([]int, 10000)
+	for i := range inputs {
+		inputs[i] = i
+	}
+	mapped := 0
+	_, err := mapreduce.MapReduce(context.Background(), inputs,
+		func(_ context.Context, n int) (int, error) {
+			if n == 100 {
+				return 0, errBadDoc
+			}
+			return n, nil
+		},
+		func(acc, n int) int { mapped++; return acc + n },
+		mapreduce.WithParallelism(4), mapreduce.WithChunkSize(10))
+	if !errors.Is(err, errBadDoc) || !strings.Contains(err.Error(), "第 100 项") {
+		return fmt.Errorf("返回 %v，期望包含第 100 项的 errBadDoc", err)
+	}
+	if mapped == len(inputs)-1 {
+		return errors.New("出错后仍处理完了全部输入")
+	}
+	return nil
+}
+
+func checkCancel() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := mapreduce.MapReduce(ctx, make([]int, 1000),
+		func(ctx context.Context, _ int) (int, error) {
+			select {
+			case <-time.After(10 * time.Millisecond):
+				return 1, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		},
+		func(acc, n int) int { return acc + n },
+		mapreduce.WithParallelism(2))
+	if !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("返回 %v，期望 context.Canceled", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		return fmt.Errorf("取消后 %v 才返回", d)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// ============================================
+// 性质测试
+// ============================================
+//
+// 用 pkg/prop 验证仓库中泛型算法的性质：
+//   - slices.Reverse 两次等于原切片，unitext.ReverseRunes 两次等于原字符串
+//   - tutorial/04 的 SortWith 幂等，且与 slices.Sort 结果相同
+//   - sliceutil.Dedup 幂等，结果没有重复元素
+//   - tutorial/08 的 BinarySearch 在有序切片中找得到每个元素
+//   - pkg/fake 生成的结构体 JSON 编码再解码后不变
+//
+// 最后一个是故意写错的性质，演示缩小：随机找到的反例可能有几十个元素，
+// 缩小后只剩两个相同的元素。
+//
+// 这些性质同时写在各自包的 _test.go 中（固定种子），由 go test ./... 执行：
+// tutorial/04_interface、tutorial/08_generics、pkg/sliceutil、pkg/unitext，
+// 缩小本身的测试在 pkg/prop。这里只是演示，可以换不同的种子多跑几次。
+//
+// 运行：
+//   go run ./cmd/proptest
+//   go run ./cmd/proptest -runs 2000 -seed 42
+// ============================================
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+
+	"c03/pkg/prop"
+	"c03/pkg/sliceutil"
+	"c03/pkg/unitext"
+	interfaces "c03/tutorial/04_interface"
+	generics "c03/tutorial/08_generics"
+)
+
+// user 用 pkg/fake 填充，验证 JSON 往返
+type user struct {
+	Name      string    `json:"name"`
+	Age       int       `json:"age" validate:"min=0,max=150"`
+	Email     string    `json:"email"`
+	Tags      []string  `json:"tags"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type property struct {
+	name       string
+	check      func(opts ...prop.Option) error
+	expectFail bool // 故意写错的性质，用来演示缩小
+}
+
+func main() {
+	runs := flag.Int("runs", 500, "每个性质的运行次数")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "随机数种子，用于复现")
+	flag.Parse()
+
+	ints := prop.SliceOf(prop.Int(-1000, 1000))
+	properties := []property{
+		{"Reverse(Reverse(xs)) == xs", func(opts ...prop.Option) error {
+			return prop.Check(ints, func(xs []int) bool {
+				ys := slices.Clone(xs)
+				slices.Reverse(ys)
+				slices.Reverse(ys)
+				return slices.Equal(xs, ys)
+			}, opts...)
+		}, false},
+		{"ReverseRunes(ReverseRunes(s)) == s", func(opts ...prop.Option) error {
+			return prop.Check(prop.String("abc 中文é😀́"), func(s string) bool {
+				return unitext.ReverseRunes(unitext.ReverseRunes(s)) == s
+			}, opts...)
+		}, false},
+		{"SortWith 幂等且与 slices.Sort 一致", func(opts ...prop.Option) error {
+			less := func(a, b int) bool { return a < b }
+			return prop.Check(ints, func(xs []int) bool {
+				once := interfaces.SortWith(interfaces.QuickSorter{}, xs, less)
+				twice := interfaces.SortWith(interfaces.QuickSorter{}, once, less)
+				return slices.Equal(once, twice) && slices.Equal(once, slices.Sorted(slices.Values(xs)))
+			}, opts...)
+		}, false},
+		{"Dedup 幂等且无重复", func(opts ...prop.Option) error {
+			return prop.Check(prop.SliceOf(prop.Int(0, 9)), func(xs []int) bool {
+				once := sliceutil.Dedup(xs)
+				seen := map[int]bool{}
+				for _, x := range once {
+					if seen[x] {
+						return false
+					}
+					seen[x] = true
+				}
+				return slices.Equal(once, sliceutil.Dedup(once))
+			}, opts...)
+		}, false},
+		{"BinarySearch 找得到有序切片中的每个元素", func(opts ...prop.Option) error {
+			return prop.Check(ints, func(xs []int) bool {
+				sorted := slices.Sorted(slices.Values(xs))
+				for _, x := range xs {
+					if i, ok := generics.BinarySearch(sorted, x); !ok || sorted[i] != x {
+						return false
+					}
+				}
+				_, ok := generics.BinarySearch(sorted, 1001) // 范围之外
+				return !ok
+			}, opts...)
+		}, false},
+		{"fake 结构体 JSON 往返不变", func(opts ...prop.Option) error {
+			return prop.Check(prop.Struct[user](), func(u user) bool {
+				data, err := json.Marshal(u)
+				if err != nil {
+					return false
+				}
+				var back user
+				return json.Unmarshal(data, &back) == nil && reflect.DeepEqual(u, back)
+			}, opts...)
+		}, false},
+		{"（错误的性质）Dedup 不改变长度", func(opts ...prop.Option) error {
+			return prop.Check(prop.SliceOf(prop.Int(-1000, 1000)), func(xs []int) bool {
+				return len(sliceutil.Dedup(xs)) == len(xs)
+			}, opts...)
+		}, true},
+	}
+
+	fmt.Printf("seed = %d\n", *seed)
+	failed := false
+	for _, p := range properties {
+		err := p.check(prop.WithRuns(*runs), prop.WithSeed(*seed))
+		switch {
+		case err == nil && !p.expectFail:
+			fmt.Printf("✓ %s（%d 次）\n", p.name, *runs)
+		case err != nil && p.expectFail:
+			f := err.(*prop.Failure[[]int])
+			fmt.Printf("✓ %s：符合预期地失败\n    最初的反例 %v\n    缩小 %d 步后 %v\n", p.name, f.Original, f.Shrinks, f.Counterexample)
+		case err == nil:
+			failed = true
+			fmt.Printf("✗ %s：期望找到反例，但 %d 次都成立\n", p.name, *runs)
+		default:
+			failed = true
+			fmt.Printf("✗ %s\n    %v\n", p.name, err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
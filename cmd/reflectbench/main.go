@@ -0,0 +1,190 @@
+// ============================================
+// 反射元数据缓存的基准
+// ============================================
+//
+// 对比每次调用都遍历 reflect.Type 和使用 pkg/structmeta 缓存的开销：
+//   字段与标签   NumField + Field(i) + Tag.Get + strings.Split  vs  structmeta.Of 后读缓存
+//   按名字查找   reflect.Type.FieldByName                         vs  Struct.Field
+//   StructToMap  每次解析标签的朴素实现                           vs  tutorial/09 的 StructToMap
+//
+// 先检查两种 StructToMap 的结果一致，再跑基准。
+//
+// 运行：
+//   go run ./cmd/reflectbench
+//   go run ./cmd/reflectbench -benchtime 200ms
+// ============================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"c03/pkg/structmeta"
+	"c03/pkg/unitext"
+	reflection "c03/tutorial/09_reflect"
+)
+
+// Profile 测试用的结构体：12 个字段，带 json 和 validate 标签
+type Profile struct {
+	ID        int     `json:"id" validate:"required"`
+	Username  string  `json:"username" validate:"required,min=3,max=20"`
+	Email     string  `json:"email" validate:"required,email"`
+	Phone     string  `json:"phone,omitempty"`
+	Age       int     `json:"age" validate:"min=0,max=150"`
+	Score     float64 `json:"score"`
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country"`
+	Bio       string  `json:"bio,omitempty" validate:"max=200"`
+	Active    bool    `json:"active"`
+	Password  string  `json:"-"`
+	Referrer  string  `json:"referrer,omitempty"`
+	Followers int     `json:"followers"`
+}
+
+// sinks 防止编译器把没有用到的结果优化掉
+var (
+	sinkMap   map[string]interface{}
+	sinkInt   int
+	sinkField reflect.StructField
+	sinkMeta  *structmeta.Field
+)
+
+func main() {
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	flag.Parse()
+	log.SetFlags(0)
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	p := Profile{ID: 1, Username: "alice", Email: "alice@example.com", Age: 30, Score: 88.5, Country: "CN", Active: true, Password: "secret", Followers: 42}
+	if got, want := reflection.StructToMap(p), naiveStructToMap(p); !reflect.DeepEqual(got, want) {
+		log.Fatalf("StructToMap 结果不一致:\n  缓存 %v\n  朴素 %v", got, want)
+	}
+	fmt.Println("✓ 缓存版与朴素版 StructToMap 结果一致")
+
+	t := reflect.TypeFor[Profile]()
+	st, err := structmeta.Of(t)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	groups := []struct {
+		title   string
+		benches []bench
+	}{
+		{"遍历字段并解析 json、validate 标签", []bench{
+			{"每次遍历 reflect.Type", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					n := 0
+					for i := range t.NumField() {
+						f := t.Field(i)
+						name := strings.Split(f.Tag.Get("json"), ",")[0]
+						rules := strings.Split(f.Tag.Get("validate"), ",")
+						n += len(name) + len(rules)
+					}
+					sinkInt = n
+				}
+			}},
+			{"structmeta 缓存", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					st, _ := structmeta.Of(t)
+					n := 0
+					for _, f := range st.Fields {
+						n += len(f.Tag("json").Name()) + len(f.Tag("validate").Items)
+					}
+					sinkInt = n
+				}
+			}},
+		}},
+		{"按名字查找字段（Followers，最后一个）", []bench{
+			{"Type.FieldByName", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkField, _ = t.FieldByName("Followers")
+				}
+			}},
+			{"Struct.Field", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkMeta, _ = st.Field("Followers")
+				}
+			}},
+		}},
+		{"StructToMap", []bench{
+			{"每次解析标签", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkMap = naiveStructToMap(p)
+				}
+			}},
+			{"structmeta 缓存", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkMap = reflection.StructToMap(p)
+				}
+			}},
+		}},
+	}
+
+	for _, g := range groups {
+		fmt.Printf("\n%s\n", g.title)
+		fmt.Printf("  %s %12s %12s %10s %8s\n", unitext.PadDisplayWidth("方式", 22), "ns/op", "B/op", "allocs/op", "加速比")
+		var base float64
+		for _, r := range g.benches {
+			res := testing.Benchmark(r.fn)
+			ns := float64(res.T.Nanoseconds()) / float64(res.N)
+			if base == 0 {
+				base = ns
+			}
+			fmt.Printf("  %s %12.1f %12d %10d %7.1fx\n", unitext.PadDisplayWidth(r.name, 22), ns, res.AllocedBytesPerOp(), res.AllocsPerOp(), base/ns)
+		}
+	}
+}
+
+type bench struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+// naiveStructToMap 不缓存的 StructToMap：每次调用都遍历字段、拆分标签
+// 只处理平铺的结构体，足够和 Profile 上的缓存版本对比
+func naiveStructToMap(s interface{}) map[string]interface{} {
+	v := reflect.ValueOf(s)
+	t := v.Type()
+	m := make(map[string]interface{})
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		parts := strings.Split(f.Tag.Get("json"), ",")
+		name := parts[0]
+		if name == "-" && len(parts) == 1 {
+			continue
+		}
+		fv := v.Field(i)
+		omitEmpty := false
+		for _, opt := range parts[1:] {
+			omitEmpty = omitEmpty || opt == "omitempty"
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		m[name] = fv.Interface()
+	}
+	return m
+}
@@ -0,0 +1,126 @@
+// ============================================
+// 空接口栈 vs 泛型栈 基准对比
+// ============================================
+//
+// tutorial/04_interface 练习 5 的 Stack（元素为 interface{}）与
+// pkg/container 的 Stack[T]（tutorial/08 第 4 节）做相同的 Push / Pop：
+//   - int：小整数（0-255）装箱不分配，其余每个值装箱分配 8 字节
+//   - string：装箱要把 16 字节的字符串头复制到堆上
+//   - point：24 字节的结构体，装箱分配 24 字节
+// 泛型版本直接存值，没有装箱，Pop 也不需要类型断言。
+//
+// 运行：
+//   go run ./cmd/stackbench
+//   go run ./cmd/stackbench -n 10000 -benchtime 200ms
+//   go run ./cmd/stackbench -profile prof    同时采集 CPU / 堆 profile 到 prof/
+// ============================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"c03/pkg/container"
+	"c03/pkg/profiling"
+	interfaces "c03/tutorial/04_interface"
+)
+
+type point struct{ X, Y, Z float64 }
+
+func main() {
+	n := flag.Int("n", 1000, "每次迭代 Push / Pop 的元素个数")
+	benchtime := flag.Duration("benchtime", time.Second, "每个基准的运行时间")
+	profileDir := flag.String("profile", "", "把 CPU / 堆 profile 写到这个目录，为空时不采集")
+	flag.Parse()
+	log.SetFlags(0)
+	if *n <= 0 {
+		log.Fatal("-n 必须为正数")
+	}
+
+	testing.Init()
+	if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	// 值从 1000 开始，避开运行时为 0-255 预先分配好的小整数
+	ints := make([]int, *n)
+	strs := make([]string, *n)
+	points := make([]point, *n)
+	for i := range *n {
+		ints[i] = 1000 + i
+		strs[i] = fmt.Sprint("item-", i)
+		points[i] = point{float64(i), float64(i), float64(i)}
+	}
+
+	results := []struct {
+		name string
+		fn   func(b *testing.B)
+	}{
+		{"int    interface{}", benchAny(ints)},
+		{"int    Stack[T]", benchGeneric(ints)},
+		{"string interface{}", benchAny(strs)},
+		{"string Stack[T]", benchGeneric(strs)},
+		{"point  interface{}", benchAny(points)},
+		{"point  Stack[T]", benchGeneric(points)},
+	}
+
+	fmt.Printf("每次迭代 Push %d 个再全部 Pop\n", *n)
+	fmt.Printf("%-20s %12s %10s %12s\n", "基准", "ns/op", "B/op", "allocs/op")
+	files, err := profiling.Profile(context.Background(), *profileDir, func(context.Context) error {
+		for _, r := range results {
+			res := testing.Benchmark(r.fn)
+			fmt.Printf("%-20s %12d %10d %12d\n", r.name, res.NsPerOp(), res.AllocedBytesPerOp(), res.AllocsPerOp())
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *profileDir != "" {
+		// 空接口栈的装箱分配在 alloc_space 里记在 benchAny 上，泛型版本没有分配
+		fmt.Printf("\nprofile: %s, %s\n  go tool pprof -sample_index=alloc_space -top %s\n", files.CPU, files.Heap, files.Heap)
+	}
+}
+
+// benchAny 空接口栈：Push 时装箱，Pop 后类型断言
+// 栈在循环外创建，底层数组扩容后复用，测到的分配只来自装箱
+func benchAny[T any](values []T) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+		var s interfaces.Stack
+		for b.Loop() {
+			for _, v := range values {
+				s.Push(v)
+			}
+			for range values {
+				v, _ := s.Pop()
+				if _, ok := v.(T); !ok {
+					b.Fatalf("类型断言失败: %T", v)
+				}
+			}
+		}
+	}
+}
+
+// benchGeneric 泛型栈：直接存取 T
+func benchGeneric[T any](values []T) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+		s := container.NewStack[T]()
+		for b.Loop() {
+			for _, v := range values {
+				s.Push(v)
+			}
+			for range values {
+				if _, ok := s.Pop(); !ok {
+					b.Fatal("栈意外为空")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// ============================================
+// 简化版模板引擎演示
+// ============================================
+//
+// 运行：
+//   go run ./cmd/tmpldemo
+// ============================================
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"c03/pkg/minitmpl"
+)
+
+type Address struct {
+	City string
+}
+
+type Item struct {
+	Name  string
+	Price float64
+	Tags  []string
+}
+
+type Order struct {
+	Customer string
+	VIP      bool
+	Address  *Address
+	Items    []Item
+}
+
+var receipt = minitmpl.MustParse[Order](`尊敬的{{if .VIP}} VIP {{else}}{{end}}客户 {{.Customer}}：
+{{if .Address}}收货城市：{{.Address.City}}
+{{end}}订单明细：
+{{range .Items}}  - {{.Name}} ¥{{.Price}}{{if .Tags}}（{{range .Tags}}#{{.}} {{end}}）{{end}}
+{{end}}`)
+
+func main() {
+	orders := []Order{
+		{
+			Customer: "张三",
+			VIP:      true,
+			Address:  &Address{City: "北京"},
+			Items: []Item{
+				{Name: "键盘", Price: 299, Tags: []string{"外设", "新品"}},
+				{Name: "鼠标", Price: 99.5},
+			},
+		},
+		{Customer: "李四", Items: []Item{{Name: "显示器", Price: 1299}}}, // Address 为 nil
+	}
+	for _, o := range orders {
+		out, err := receipt.Render(o)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(out)
+	}
+
+	// 字段名拼错、缺少 end、range 非切片，都在解析阶段报错
+	fmt.Println("=== 解析错误 ===")
+	for _, src := range []string{
+		"你好 {{.Custmer}}",
+		"第一行\n{{range .Items}}{{.Nmae}}{{end}}",
+		"{{if .VIP}}没有结束",
+		"{{range .Customer}}{{end}}",
+		"{{end}}",
+	} {
+		_, err := minitmpl.Parse[Order](src)
+		fmt.Println(err)
+	}
+}
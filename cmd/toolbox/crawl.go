@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"c03/pkg/cli"
+	"c03/pkg/crawler"
+)
+
+func crawlCommand() *cli.Command {
+	var cfg crawler.Config
+	var timeout time.Duration
+
+	return &cli.Command{
+		Name:  "crawl",
+		Args:  "[flags] <url>",
+		Short: "从起始 URL 并发抓取网页",
+		Long: `从起始 URL 开始抓取页面并跟随其中的链接，
+深度和并发数可以限制，超时或 Ctrl+C 后等待进行中的请求返回再退出。`,
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.IntVar(&cfg.MaxDepth, "depth", 1, "最大抓取深度（起始页为 0）")
+			fs.IntVar(&cfg.Workers, "workers", 4, "并发数")
+			fs.StringVar(&cfg.OutDir, "out", "", "页面保存目录，为空则不保存")
+			fs.BoolVar(&cfg.SameHost, "same-host", true, "只跟随同域名链接")
+			fs.DurationVar(&timeout, "timeout", 30*time.Second, "整体超时")
+		},
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return cli.Usagef("需要一个起始 URL")
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			pages, err := crawler.New(cfg).Crawl(ctx, args[0])
+			for _, p := range pages {
+				if p.Err != nil {
+					fmt.Printf("[%d] %s 失败: %v\n", p.Depth, p.URL, p.Err)
+					continue
+				}
+				fmt.Printf("[%d] %s %d 个链接 %s\n", p.Depth, p.URL, len(p.Links), p.SavedAs)
+			}
+			fmt.Printf("共抓取 %d 个页面\n", len(pages))
+			return err
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"c03/pkg/cli"
+	"c03/pkg/csvutil"
+)
+
+func csvCommand() *cli.Command {
+	var from string
+
+	return &cli.Command{
+		Name:  "csv",
+		Args:  "[flags] [文件]",
+		Short: "CSV 与 JSON 互相转换",
+		Long: `CSV 转为 JSON 对象数组（自动推断列类型），或把 JSON 对象数组转回 CSV。
+按文件扩展名判断方向，读标准输入时用 -from 指定。`,
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.StringVar(&from, "from", "", "输入格式 csv 或 json，默认按扩展名判断")
+		},
+		Run: func(ctx context.Context, args []string) error {
+			var in io.Reader = os.Stdin
+			format := from
+			switch len(args) {
+			case 0:
+			case 1:
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+				if format == "" {
+					format = strings.TrimPrefix(strings.ToLower(filepath.Ext(args[0])), ".")
+				}
+			default:
+				return cli.Usagef("最多一个输入文件")
+			}
+
+			switch format {
+			case "csv":
+				return csvutil.CSVToJSON(in, os.Stdout)
+			case "json":
+				return csvutil.JSONToCSV(in, os.Stdout)
+			default:
+				return cli.Usagef("无法判断输入格式 %q，请用 -from csv 或 -from json 指定", format)
+			}
+		},
+	}
+}
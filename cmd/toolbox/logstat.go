@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"c03/pkg/cli"
+	"c03/pkg/logstat"
+)
+
+func logstatCommand() *cli.Command {
+	var from, to string
+
+	return &cli.Command{
+		Name:  "logstat",
+		Args:  "[flags] [文件...]",
+		Short: "统计日志中各级别的数量",
+		Long:  "逐行读取日志文件（没有参数时读标准输入），按时间范围过滤后输出 JSON 汇总。",
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.StringVar(&from, "from", "", "起始时间（包含），格式 "+logstat.TimeLayout)
+			fs.StringVar(&to, "to", "", "结束时间（不包含），格式 "+logstat.TimeLayout)
+		},
+		Run: func(ctx context.Context, args []string) error {
+			var filter logstat.Filter
+			var err error
+			if filter.From, err = parseLogTime(from); err != nil {
+				return cli.Usagef("-from: %v", err)
+			}
+			if filter.To, err = parseLogTime(to); err != nil {
+				return cli.Usagef("-to: %v", err)
+			}
+
+			var input io.Reader = os.Stdin
+			if len(args) > 0 {
+				readers := make([]io.Reader, 0, len(args))
+				for _, name := range args {
+					f, err := os.Open(name)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					readers = append(readers, f)
+				}
+				input = io.MultiReader(readers...)
+			}
+
+			summary, err := logstat.Analyze(input, filter)
+			if err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+}
+
+func parseLogTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(logstat.TimeLayout, s, time.Local)
+}
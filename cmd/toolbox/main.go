@@ -0,0 +1,41 @@
+// ============================================
+// 教程工具集：一个二进制，多个子命令
+// ============================================
+//
+// 把 cmd/ 下的几个工具作为 pkg/cli 的子命令组合在一起。
+//
+// 运行：
+//   go run ./cmd/toolbox help
+//   go run ./cmd/toolbox help crawl
+//   go run ./cmd/toolbox crawl -depth 1 https://go.dev/
+//   go run ./cmd/toolbox logstat cmd/logstat/sample.log
+//   go run ./cmd/toolbox csv cmd/csvtool/sample.csv
+// ============================================
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"c03/pkg/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	app := &cli.App{
+		Name:  "toolbox",
+		Short: "Go 教程练习中的命令行工具",
+		Commands: []*cli.Command{
+			crawlCommand(),
+			logstatCommand(),
+			csvCommand(),
+		},
+	}
+	code := app.Run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
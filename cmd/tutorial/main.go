@@ -0,0 +1,271 @@
+// ============================================
+// 教程运行器
+// ============================================
+//
+// tutorial/ 下的每一课都是独立的包，导入后注册到 c03/tutorial；
+// 这个命令列出并运行它们，取代原来每个文件各自 go run 的方式。
+//
+// 运行：
+//   go run ./cmd/tutorial list
+//   go run ./cmd/tutorial 05                 # 等同于 run 05
+//   go run ./cmd/tutorial run concurrency    # 也接受 5、05_concurrency
+//   go run ./cmd/tutorial check 01           # 检查练习，不指定课程时检查全部
+//   go run ./cmd/tutorial progress           # 学习进度
+//   go run ./cmd/tutorial reset [课程]        # 清除进度
+//
+// 进度保存在 ~/.go_tutorial/progress.json，可以用 GO_TUTORIAL_PROGRESS 指定其他文件。
+// ============================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"c03/pkg/cli"
+	"c03/pkg/strsim"
+	"c03/pkg/unitext"
+	"c03/tutorial"
+
+	_ "c03/tutorial/01_basic_syntax"
+	_ "c03/tutorial/02_functions"
+	_ "c03/tutorial/03_struct_method"
+	_ "c03/tutorial/04_interface"
+	_ "c03/tutorial/05_concurrency"
+	_ "c03/tutorial/06_sync_context"
+	_ "c03/tutorial/07_error_handling"
+	_ "c03/tutorial/08_generics"
+	_ "c03/tutorial/09_reflect"
+	_ "c03/tutorial/10_standard_lib"
+	_ "c03/tutorial/11_data_race"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	app := &cli.App{
+		Name:  "tutorial",
+		Short: "列出并运行 Go 教程的各课示例",
+		Commands: []*cli.Command{
+			listCommand(),
+			runCommand(),
+			checkCommand(),
+			progressCommand(),
+			resetCommand(),
+		},
+	}
+
+	// go run ./cmd/tutorial 05：第一个参数是课程时省略 run
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if _, ok := tutorial.Lookup(args[0]); ok {
+			args = append([]string{"run"}, args...)
+		}
+	}
+	code := app.Run(ctx, args)
+	stop()
+	os.Exit(code)
+}
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Short: "列出所有课程",
+		Run: func(ctx context.Context, args []string) error {
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			for _, l := range tutorial.Lessons() {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", l.ID, l.Name, l.Title)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func runCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "run",
+		Args:  "<编号或名字>",
+		Short: "运行一课的全部示例",
+		Long: `运行一课的全部示例。课程可以用编号（05、5）、目录名（05_concurrency）
+或去掉编号的名字（concurrency）指定，运行 'tutorial list' 查看所有课程。`,
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return cli.Usagef("需要一个课程")
+			}
+			l, ok := tutorial.Lookup(args[0])
+			if !ok {
+				return unknownLesson(args[0])
+			}
+			fmt.Printf("##### %s %s #####\n\n", l.ID, l.Title)
+			l.Run()
+			// 记录失败不影响本次运行，只提示
+			if store, err := openStore(); err != nil {
+				fmt.Fprintln(os.Stderr, "tutorial: 无法记录进度:", err)
+			} else if err := store.RecordRun(l.ID); err != nil {
+				fmt.Fprintln(os.Stderr, "tutorial: 无法记录进度:", err)
+			}
+			return nil
+		},
+	}
+}
+
+func checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Args:  "[编号或名字]",
+		Short: "检查练习并记录结果",
+		Long: `运行课程中注册的练习检查，把通过与否记录到学习进度。
+不指定课程时检查所有课程；有练习未通过时以状态 1 退出。`,
+		Run: func(ctx context.Context, args []string) error {
+			lessons := tutorial.Lessons()
+			switch len(args) {
+			case 0:
+			case 1:
+				l, ok := tutorial.Lookup(args[0])
+				if !ok {
+					return unknownLesson(args[0])
+				}
+				lessons = []tutorial.Lesson{l}
+			default:
+				return cli.Usagef("最多指定一个课程")
+			}
+			store, err := openStore()
+			if err != nil {
+				return err
+			}
+
+			total, failed := 0, 0
+			for _, l := range lessons {
+				for _, e := range l.Exercises {
+					total++
+					checkErr := tutorial.RunCheck(e)
+					if checkErr != nil {
+						failed++
+						fmt.Printf("✗ %s 练习 %s %s: %v\n", l.ID, e.ID, e.Title, checkErr)
+					} else {
+						fmt.Printf("✓ %s 练习 %s %s\n", l.ID, e.ID, e.Title)
+					}
+					if err := store.RecordExercise(l.ID, e.ID, checkErr); err != nil {
+						return err
+					}
+				}
+			}
+			if total == 0 {
+				fmt.Println("没有可检查的练习")
+				return nil
+			}
+			fmt.Printf("\n通过 %d/%d\n", total-failed, total)
+			if failed > 0 {
+				return fmt.Errorf("%d 道练习未通过", failed)
+			}
+			return nil
+		},
+	}
+}
+
+func progressCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "progress",
+		Short: "显示学习进度",
+		Run: func(ctx context.Context, args []string) error {
+			store, err := openStore()
+			if err != nil {
+				return err
+			}
+			p, err := store.Load()
+			if err != nil {
+				return err
+			}
+
+			// 表头是中文，tabwriter 按 rune 计算宽度会对不齐，按显示宽度补齐
+			cols := []int{4, 18, 4, 16, 4}
+			row := func(cells ...string) {
+				for i, c := range cells {
+					cells[i] = unitext.PadDisplayWidth(c, cols[i])
+				}
+				fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+			}
+			row("编号", "课程", "运行", "最近一次", "练习")
+			lessons := tutorial.Lessons()
+			ran, passed, exercises := 0, 0, 0
+			for _, l := range lessons {
+				runs, last := "-", "-"
+				if r := p.Lessons[l.ID]; r != nil {
+					ran++
+					runs = strconv.Itoa(r.Runs)
+					last = r.LastRun.Local().Format("2006-01-02 15:04")
+				}
+				ex := "-"
+				if len(l.Exercises) > 0 {
+					n := p.Passed(l)
+					passed += n
+					exercises += len(l.Exercises)
+					ex = fmt.Sprintf("%d/%d", n, len(l.Exercises))
+				}
+				row(l.ID, l.Name, runs, last, ex)
+			}
+			fmt.Printf("\n已运行 %d/%d 课，通过 %d/%d 道练习（%s）\n", ran, len(lessons), passed, exercises, store.Path())
+			return nil
+		},
+	}
+}
+
+func resetCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "reset",
+		Args:  "[编号或名字]",
+		Short: "清除学习进度",
+		Long:  "清除全部学习进度；指定课程时只清除该课的运行记录和练习记录。",
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) > 1 {
+				return cli.Usagef("最多指定一个课程")
+			}
+			store, err := openStore()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				if err := store.Reset(""); err != nil {
+					return err
+				}
+				fmt.Println("已清除全部进度")
+				return nil
+			}
+			l, ok := tutorial.Lookup(args[0])
+			if !ok {
+				return unknownLesson(args[0])
+			}
+			if err := store.Reset(l.ID); err != nil {
+				return err
+			}
+			fmt.Printf("已清除 %s %s 的进度\n", l.ID, l.Name)
+			return nil
+		},
+	}
+}
+
+func openStore() (*tutorial.ProgressStore, error) {
+	path, err := tutorial.DefaultProgressPath()
+	if err != nil {
+		return nil, err
+	}
+	return tutorial.NewProgressStore(path), nil
+}
+
+// unknownLesson 找不到课程时提示最接近的名字
+func unknownLesson(key string) error {
+	var names []string
+	for _, l := range tutorial.Lessons() {
+		names = append(names, l.Name, strings.TrimPrefix(l.Name, l.ID+"_"))
+	}
+	if best, ok := strsim.Closest(names, key); ok {
+		return cli.Usagef("没有课程 %q，你是不是想输入 %q？", key, best)
+	}
+	return cli.Usagef("没有课程 %q，运行 'tutorial list' 查看所有课程", key)
+}
@@ -0,0 +1,138 @@
+// ============================================
+// UDP 请求/响应演示
+// ============================================
+//
+// 运行：
+//   go run ./cmd/udpdemo                              # 本机自演示（含丢包重传）
+//   go run ./cmd/udpdemo -listen :9000                # 启动服务端（把请求转成大写）
+//   go run ./cmd/udpdemo -send localhost:9000 a b c   # 并发发送多个请求
+// ============================================
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c03/pkg/udpmsg"
+)
+
+func main() {
+	listen := flag.String("listen", "", "以服务端运行，监听该地址")
+	send := flag.String("send", "", "以客户端运行，向该地址发送参数中的消息")
+	timeout := flag.Duration("timeout", 500*time.Millisecond, "每次发送后等待响应的时间")
+	retries := flag.Int("retries", 3, "超时后的重传次数")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch {
+	case *listen != "":
+		conn, err := net.ListenPacket("udp", *listen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("listening on %s", conn.LocalAddr())
+		if err := udpmsg.Serve(ctx, conn, upper, nil); err != nil {
+			log.Fatal(err)
+		}
+	case *send != "":
+		c, err := udpmsg.Dial(*send)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		c.Timeout, c.Retries = *timeout, *retries
+		requestAll(ctx, c, flag.Args())
+	default:
+		demo(ctx)
+	}
+}
+
+func upper(_ net.Addr, req []byte) []byte {
+	return bytes.ToUpper(req)
+}
+
+// requestAll 并发发送所有消息，响应可能乱序到达，靠请求 ID 对上号
+func requestAll(ctx context.Context, c *udpmsg.Client, msgs []string) {
+	var wg sync.WaitGroup
+	for _, m := range msgs {
+		wg.Go(func() {
+			start := time.Now()
+			resp, err := c.Request(ctx, []byte(m))
+			if err != nil {
+				fmt.Printf("%-8s 失败: %v\n", m, err)
+				return
+			}
+			fmt.Printf("%-8s -> %-8s %v\n", m, resp, time.Since(start).Round(time.Millisecond))
+		})
+	}
+	wg.Wait()
+}
+
+// demo 在本机启动服务端，每个请求第一次到达时故意丢弃，观察客户端的重传
+func demo(ctx context.Context) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var seen sync.Map
+	var dropped atomic.Int32
+	lossy := lossyConn{PacketConn: conn, drop: func(b []byte) bool {
+		p, err := udpmsg.Unmarshal(b)
+		if err != nil {
+			return false
+		}
+		_, again := seen.LoadOrStore(p.ID, true)
+		if !again {
+			dropped.Add(1)
+		}
+		return !again
+	}}
+	go udpmsg.Serve(ctx, lossy, upper, nil)
+
+	c, err := udpmsg.Dial(conn.LocalAddr().String())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	c.Timeout = 100 * time.Millisecond
+
+	requestAll(ctx, c, []string{"alpha", "beta", "gamma", "delta"})
+	fmt.Printf("服务端丢弃了 %d 个首次到达的请求，均由重传补上\n", dropped.Load())
+
+	// 服务端停止后请求会在重传用尽后超时
+	cancel()
+	c.Retries = 1
+	if _, err := c.Request(context.Background(), []byte("lost")); err != nil {
+		fmt.Println("服务端已停止:", err)
+	}
+}
+
+// lossyConn 按 drop 的判断丢弃收到的包，模拟网络丢包
+type lossyConn struct {
+	net.PacketConn
+	drop func([]byte) bool
+}
+
+func (l lossyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := l.PacketConn.ReadFrom(b)
+		if err != nil || !l.drop(b[:n]) {
+			return n, addr, err
+		}
+	}
+}
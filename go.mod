@@ -3,6 +3,14 @@ module c03
 go 1.25.5
 
 require (
-	github.com/google/uuid v1.6.0
 	golang.org/x/exp v0.0.0-20260112195511-716be5621a96
+	golang.org/x/text v0.40.0
+	google.golang.org/grpc v1.84.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
 )
@@ -0,0 +1,266 @@
+// ============================================
+// audit 包：只追加的审计日志
+// ============================================
+//
+// 每条记录一行 JSON，追加写入文件（O_APPEND），进程重启后继续写同一个文件。
+//
+// 加上 WithPassphrase 后记录在磁盘上加密保存（pkg/cryptutil）：
+//
+//   c03-audit-v1 <base64 盐> <base64 校验>   第一行：格式、派生密钥用的盐、加密的固定串
+//   <base64(nonce + 密文)>                   之后每行一条加密的 JSON 记录
+//
+// 校验串让 Open 能立即发现口令错误，否则会用错误的密钥继续追加，之后谁也读不出来。
+// 口令只在 Open 时派生一次密钥，之后每条记录单独用 AES-GCM 加密，
+// 单独一行损坏不影响读取其他行（ReadFile 会报告出错的行号）。
+// 明文日志和加密日志不能混写：用口令打开明文日志、或不带口令打开加密日志都会返回错误。
+//
+// 用法：
+//   log, err := audit.Open("audit.log", audit.WithPassphrase(os.Getenv("AUDIT_PASSPHRASE")))
+//   handler = audit.Middleware(log, logger)(handler)   // 记录所有修改类请求
+// ============================================
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/cryptutil"
+	"c03/pkg/hashutil"
+)
+
+// header 加密日志第一行的前缀
+const header = "c03-audit-v1 "
+
+var (
+	ErrEncrypted = errors.New("audit: 日志已加密，需要口令")
+	ErrPlaintext = errors.New("audit: 日志没有加密，不能用口令打开")
+	ErrCorrupt   = errors.New("audit: 日志行损坏")
+	ErrClosed    = errors.New("audit: 日志已关闭")
+)
+
+// Entry 一条审计记录
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`           // 例如 "POST /accounts"
+	Status    int       `json:"status,omitempty"` // HTTP 状态码
+	RequestID string    `json:"request_id,omitempty"`
+	Remote    string    `json:"remote,omitempty"`
+}
+
+// Option Open / ReadFile 的选项
+type Option func(*options)
+
+type options struct {
+	passphrase string
+	clock      clock.Clock
+}
+
+// WithPassphrase 用口令加密日志；为空字符串时等同于不加密
+func WithPassphrase(passphrase string) Option {
+	return func(o *options) { o.passphrase = passphrase }
+}
+
+// WithClock Write 补全 Entry.Time 时使用的时钟，默认 clock.Real
+func WithClock(c clock.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.clock = clock.Or(o.clock)
+	return o
+}
+
+// Log 审计日志，可以并发写入
+type Log struct {
+	mu     sync.Mutex
+	f      *os.File
+	cipher *cryptutil.Cipher // nil 表示明文
+	clock  clock.Clock
+}
+
+// Open 打开（不存在时创建）path 处的审计日志，新记录追加在末尾
+func Open(path string, opts ...Option) (*Log, error) {
+	o := newOptions(opts)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{f: f, clock: o.clock}
+	if err := l.init(o.passphrase); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+	return l, nil
+}
+
+// init 读取已有日志的第一行，确定是否加密；新文件且有口令时写入头部
+func (l *Log) init(passphrase string) error {
+	first, err := bufio.NewReader(l.f).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if first == "" && passphrase != "" {
+		var line string
+		if l.cipher, line, err = newHeader(passphrase); err != nil {
+			return err
+		}
+		_, err = l.f.WriteString(line + "\n")
+		return err
+	}
+	l.cipher, _, err = openHeader(strings.TrimRight(first, "\n"), passphrase)
+	return err
+}
+
+// newHeader 生成新的盐和加密日志的头部
+func newHeader(passphrase string) (*cryptutil.Cipher, string, error) {
+	c, err := cryptutil.FromPassphrase(passphrase, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	check, err := c.Seal([]byte(header), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, header + hashutil.EncodeBase64(c.Salt()) + " " + hashutil.EncodeBase64(check), nil
+}
+
+// openHeader 根据第一行判断日志是否加密，加密时派生密钥并验证口令
+// isHeader 表示这一行是加密日志的头部（不是记录）；明文日志返回 nil Cipher
+func openHeader(line, passphrase string) (c *cryptutil.Cipher, isHeader bool, err error) {
+	rest, ok := strings.CutPrefix(line, header)
+	switch {
+	case !ok && passphrase != "" && line != "":
+		return nil, false, ErrPlaintext
+	case !ok:
+		return nil, false, nil
+	case passphrase == "":
+		return nil, true, ErrEncrypted
+	}
+	saltText, checkText, _ := strings.Cut(rest, " ")
+	salt, err := hashutil.DecodeBase64(saltText)
+	if err != nil || len(salt) != cryptutil.SaltSize {
+		return nil, true, fmt.Errorf("%w: 头部的盐无效", ErrCorrupt)
+	}
+	check, err := hashutil.DecodeBase64(checkText)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: 头部的校验串无效", ErrCorrupt)
+	}
+	if c, err = cryptutil.FromPassphrase(passphrase, salt); err != nil {
+		return nil, true, err
+	}
+	if got, err := c.Open(check, nil); err != nil || string(got) != header {
+		return nil, true, cryptutil.ErrDecrypt
+	}
+	return c, true, nil
+}
+
+// Encrypted 日志是否加密
+func (l *Log) Encrypted() bool { return l.cipher != nil }
+
+// Write 追加一条记录；e.Time 为零时使用当前时间
+func (l *Log) Write(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = l.clock.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if l.cipher != nil {
+		sealed, err := l.cipher.Seal(line, nil)
+		if err != nil {
+			return err
+		}
+		line = []byte(hashutil.EncodeBase64(sealed))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ErrClosed
+	}
+	// 一次 Write 写出整行，O_APPEND 保证多个进程同时追加时行不会交错
+	_, err = l.f.Write(append(line, '\n'))
+	return err
+}
+
+// Close 关闭日志文件，之后的 Write 返回 ErrClosed
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// ReadFile 读取 path 处的全部记录，加密日志需要 WithPassphrase
+func ReadFile(path string, opts ...Option) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := Read(bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+	return entries, nil
+}
+
+// Read 从 r 读取全部记录，加密日志需要 WithPassphrase
+// 口令错误时返回 cryptutil.ErrDecrypt；某一行无法解析时返回 ErrCorrupt，并带上行号
+func Read(r io.Reader, opts ...Option) ([]Entry, error) {
+	o := newOptions(opts)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	var c *cryptutil.Cipher
+	var entries []Entry
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		if n == 1 {
+			var isHeader bool
+			var err error
+			if c, isHeader, err = openHeader(string(line), o.passphrase); err != nil {
+				return nil, err
+			}
+			if isHeader {
+				continue
+			}
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if c != nil {
+			sealed, err := hashutil.DecodeBase64(string(line))
+			if err != nil {
+				return nil, fmt.Errorf("%w: 第 %d 行: %v", ErrCorrupt, n, err)
+			}
+			if line, err = c.Open(sealed, nil); err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", n, err)
+			}
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("%w: 第 %d 行: %v", ErrCorrupt, n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"log"
+	"net/http"
+
+	"c03/pkg/middleware"
+)
+
+// Middleware 为每个修改类请求（GET、HEAD、OPTIONS 以外）写一条审计记录
+// 记录在处理器返回后写入，带上状态码；外层有 middleware.RequestID 时带上请求 ID。
+// 写入失败不影响响应，只通过 logger 报告
+func Middleware(l *Log, logger *log.Logger) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			err := l.Write(Entry{
+				Action:    r.Method + " " + r.URL.Path,
+				Status:    rec.status,
+				RequestID: middleware.RequestIDFrom(r.Context()),
+				Remote:    r.RemoteAddr,
+			})
+			if err != nil {
+				logger.Printf("audit: %v", err)
+			}
+		})
+	}
+}
+
+// statusRecorder 记录响应状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap 让 http.ResponseController 能找到底层的 ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,110 @@
+// ============================================
+// backoff 包：指数退避
+// ============================================
+//
+// 第 n 次重试前等待 Initial × Multiplier^n，不超过 Max：
+//
+//   Initial=100ms Multiplier=2 Max=1s  ->  100ms 200ms 400ms 800ms 1s 1s ...
+//
+// 大量客户端同时失败时，相同的等待时间会让它们同时重试、再次同时失败。
+// Jitter 在计算结果上加入随机性，把重试打散：
+//
+//   NoJitter     d
+//   FullJitter   [0, d) 均匀分布，打散效果最好
+//   EqualJitter  [d/2, d) 均匀分布，保证至少等待一半
+//
+// 零值可用：未设置的字段取默认值（100ms、2、30s、不加抖动）。
+// Backoff 是值类型，复制一份就得到独立的重试状态，但同一个值不能被多个 goroutine 同时使用；
+// 只读的 Delay 方法可以并发调用。
+// ============================================
+
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+const (
+	DefaultInitial    = 100 * time.Millisecond
+	DefaultMultiplier = 2.0
+	DefaultMax        = 30 * time.Second
+)
+
+// Jitter 随机化策略
+type Jitter int
+
+const (
+	NoJitter Jitter = iota
+	FullJitter
+	EqualJitter
+)
+
+// Backoff 指数退避
+type Backoff struct {
+	Initial    time.Duration // 第一次等待时间，默认 100ms
+	Multiplier float64       // 每次的增长倍数，默认 2；1 表示固定间隔
+	Max        time.Duration // 等待时间上限（加抖动之前），默认 30s
+	Jitter     Jitter
+	Clock      clock.Clock // Sleep 使用的时钟，默认 clock.Real
+
+	attempt int
+}
+
+// Delay 第 attempt 次（从 0 开始）重试前应等待的时间，不改变状态
+func (b *Backoff) Delay(attempt int) time.Duration {
+	initial, mult, maxD := b.Initial, b.Multiplier, b.Max
+	if initial <= 0 {
+		initial = DefaultInitial
+	}
+	if mult <= 0 {
+		mult = DefaultMultiplier
+	}
+	if maxD <= 0 {
+		maxD = DefaultMax
+	}
+
+	// 先用 float64 计算再和 Max 比较，避免 attempt 很大时 Duration 溢出
+	d := maxD
+	if f := float64(initial) * math.Pow(mult, float64(attempt)); f < float64(maxD) {
+		d = time.Duration(f)
+	}
+
+	switch b.Jitter {
+	case FullJitter:
+		return rand.N(d)
+	case EqualJitter:
+		return d/2 + rand.N(d-d/2)
+	default:
+		return d
+	}
+}
+
+// Next 返回下一次的等待时间并把重试计数加一
+func (b *Backoff) Next() time.Duration {
+	d := b.Delay(b.attempt)
+	b.attempt++
+	return d
+}
+
+// Attempt 已经调用 Next 的次数
+func (b *Backoff) Attempt() int { return b.attempt }
+
+// Reset 回到初始状态，通常在一次成功之后调用
+func (b *Backoff) Reset() { b.attempt = 0 }
+
+// Sleep 等待 Next() 的时长；ctx 先结束时立即返回 ctx 的错误
+func (b *Backoff) Sleep(ctx context.Context) error {
+	t := clock.Or(b.Clock).NewTimer(b.Next())
+	defer t.Stop()
+	select {
+	case <-t.C():
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
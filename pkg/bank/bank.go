@@ -0,0 +1,279 @@
+// ============================================
+// bank 包：银行账户聚合
+// ============================================
+//
+// 在 tutorial/03_struct_method 的 BankAccount 示例基础上，
+// 用一个 Bank 聚合管理多个账户：
+// - 开户、存款、取款、转账
+// - 每个账户记录交易流水（history）
+// - 使用类型化的错误，便于上层（如 HTTP）映射状态码
+//
+// 并发安全：Bank 内部使用一把 Mutex 保护所有账户，
+// 转账在同一把锁内完成，保证两个账户的余额同时变化。
+// ============================================
+
+package bank
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"c03/pkg/idgen"
+)
+
+// TxType 交易类型
+type TxType string
+
+const (
+	TxOpen        TxType = "open"
+	TxDeposit     TxType = "deposit"
+	TxWithdraw    TxType = "withdraw"
+	TxTransferIn  TxType = "transfer_in"
+	TxTransferOut TxType = "transfer_out"
+)
+
+// Transaction 一条交易流水
+type Transaction struct {
+	ID           string    `json:"id"`
+	AccountID    string    `json:"account_id"`
+	Type         TxType    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	Counterparty string    `json:"counterparty,omitempty"` // 转账对方账户
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Account 账户快照（对外只暴露值拷贝，避免绕过 Bank 修改余额）
+type Account struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Balance   float64   `json:"balance"`
+	Closed    bool      `json:"closed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type account struct {
+	Account
+	history []Transaction
+}
+
+// Bank 银行聚合根
+type Bank struct {
+	mu       sync.Mutex
+	accounts map[string]*account
+	nextAcc  int
+	txIDs    *idgen.IDGenerator
+	now      func() time.Time
+}
+
+// NewBank 创建一个空的银行
+func NewBank() *Bank {
+	txIDs, _ := idgen.NewIDGenerator(0) // 节点号 0 总是合法的
+	return &Bank{
+		accounts: make(map[string]*account),
+		nextAcc:  10000,
+		txIDs:    txIDs,
+		now:      time.Now,
+	}
+}
+
+// Open 开户，initialBalance 可以为 0，但不能为负
+func (b *Bank) Open(owner string, initialBalance float64) (Account, error) {
+	if owner == "" {
+		return Account{}, &ValidationError{Field: "owner", Message: "不能为空"}
+	}
+	if initialBalance != 0 {
+		if err := checkAmount(initialBalance); err != nil {
+			return Account{}, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextAcc++
+	acc := &account{Account: Account{
+		ID:        strconv.Itoa(b.nextAcc),
+		Owner:     owner,
+		Balance:   initialBalance,
+		CreatedAt: b.now(),
+	}}
+	b.accounts[acc.ID] = acc
+	b.record(acc, TxOpen, initialBalance, "")
+	return acc.Account, nil
+}
+
+// Get 查询账户
+func (b *Bank) Get(id string) (Account, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acc, err := b.lookup(id)
+	if err != nil {
+		return Account{}, err
+	}
+	return acc.Account, nil
+}
+
+// Deposit 存款，返回存款后的账户快照
+func (b *Bank) Deposit(id string, amount float64) (Account, error) {
+	if err := checkAmount(amount); err != nil {
+		return Account{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acc, err := b.lookupOpen(id)
+	if err != nil {
+		return Account{}, err
+	}
+	acc.Balance += amount
+	b.record(acc, TxDeposit, amount, "")
+	return acc.Account, nil
+}
+
+// Withdraw 取款，余额不足时返回 ErrInsufficientFunds
+func (b *Bank) Withdraw(id string, amount float64) (Account, error) {
+	if err := checkAmount(amount); err != nil {
+		return Account{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acc, err := b.lookupOpen(id)
+	if err != nil {
+		return Account{}, err
+	}
+	if amount > acc.Balance {
+		return Account{}, fmt.Errorf("%w: 账户 %s 余额 %.2f，取款 %.2f", ErrInsufficientFunds, id, acc.Balance, amount)
+	}
+	acc.Balance -= amount
+	b.record(acc, TxWithdraw, amount, "")
+	return acc.Account, nil
+}
+
+// Transfer 从 from 转账到 to，两个账户在同一把锁内更新
+func (b *Bank) Transfer(from, to string, amount float64) (fromAcc, toAcc Account, err error) {
+	if err := checkAmount(amount); err != nil {
+		return Account{}, Account{}, err
+	}
+	if from == to {
+		return Account{}, Account{}, ErrSameAccount
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	src, err := b.lookupOpen(from)
+	if err != nil {
+		return Account{}, Account{}, err
+	}
+	dst, err := b.lookupOpen(to)
+	if err != nil {
+		return Account{}, Account{}, err
+	}
+	if amount > src.Balance {
+		return Account{}, Account{}, fmt.Errorf("%w: 账户 %s 余额 %.2f，转出 %.2f", ErrInsufficientFunds, from, src.Balance, amount)
+	}
+
+	src.Balance -= amount
+	dst.Balance += amount
+	b.record(src, TxTransferOut, amount, to)
+	b.record(dst, TxTransferIn, amount, from)
+	return src.Account, dst.Account, nil
+}
+
+// Close 销户，余额必须为 0
+func (b *Bank) Close(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acc, err := b.lookupOpen(id)
+	if err != nil {
+		return err
+	}
+	if acc.Balance != 0 {
+		return fmt.Errorf("%w: 账户 %s 仍有余额 %.2f", ErrNonZeroBalance, id, acc.Balance)
+	}
+	acc.Closed = true
+	return nil
+}
+
+// History 返回账户的交易流水（按时间先后），返回的是副本
+func (b *Bank) History(id string) ([]Transaction, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acc, err := b.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]Transaction, len(acc.history))
+	copy(history, acc.history)
+	return history, nil
+}
+
+// Accounts 返回所有账户快照，按 ID 排序
+func (b *Bank) Accounts() []Account {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	accounts := make([]Account, 0, len(b.accounts))
+	for _, acc := range b.accounts {
+		accounts = append(accounts, acc.Account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+	return accounts
+}
+
+// ============================================
+// 内部辅助函数（调用方必须持有 b.mu）
+// ============================================
+
+func (b *Bank) lookup(id string) (*account, error) {
+	acc, ok := b.accounts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+	}
+	return acc, nil
+}
+
+func (b *Bank) lookupOpen(id string) (*account, error) {
+	acc, err := b.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Closed {
+		return nil, fmt.Errorf("%w: %s", ErrAccountClosed, id)
+	}
+	return acc, nil
+}
+
+func (b *Bank) record(acc *account, typ TxType, amount float64, counterparty string) {
+	acc.history = append(acc.history, Transaction{
+		// 按时间递增的 ID：流水可以直接按 ID 排序，多个进程合并时也不会重复
+		ID:           "tx-" + b.txIDs.Next().String(),
+		AccountID:    acc.ID,
+		Type:         typ,
+		Amount:       amount,
+		BalanceAfter: acc.Balance,
+		Counterparty: counterparty,
+		CreatedAt:    b.now(),
+	})
+}
+
+func checkAmount(amount float64) error {
+	// NaN 与任何数比较都为 false，需要单独判断
+	if math.IsNaN(amount) || math.IsInf(amount, 0) || amount <= 0 {
+		return fmt.Errorf("%w: 金额必须大于0，实际为 %.2f", ErrInvalidAmount, amount)
+	}
+	return nil
+}
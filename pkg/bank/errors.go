@@ -0,0 +1,32 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ============================================
+// 错误定义
+// ============================================
+//
+// 哨兵错误用 %w 包装后返回，调用方用 errors.Is 判断类别；
+// ValidationError 描述请求字段不合法，用 errors.As 取出字段信息。
+
+var (
+	ErrAccountNotFound   = errors.New("账户不存在")
+	ErrAccountClosed     = errors.New("账户已关闭")
+	ErrInvalidAmount     = errors.New("无效的金额")
+	ErrInsufficientFunds = errors.New("余额不足")
+	ErrSameAccount       = errors.New("不能向同一账户转账")
+	ErrNonZeroBalance    = errors.New("账户余额不为0")
+)
+
+// ValidationError 请求参数验证错误
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("验证错误 [%s]: %s", e.Field, e.Message)
+}
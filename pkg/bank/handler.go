@@ -0,0 +1,216 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ============================================
+// REST API
+// ============================================
+//
+// 路由（使用 Go 1.22+ ServeMux 的 "方法 路径" 模式）：
+//   POST /accounts                 开户
+//   GET  /accounts/{id}            查询账户
+//   POST /accounts/{id}/deposit    存款
+//   POST /accounts/{id}/withdraw   取款
+//   GET  /accounts/{id}/history    交易流水
+//   POST /transfers                转账
+//
+// 错误到状态码的映射集中在 statusFor 中完成，
+// 业务层只返回类型化错误，不关心 HTTP。
+
+// maxBodyBytes 限制请求体大小，防止恶意的大请求
+const maxBodyBytes = 1 << 20
+
+// OpenRequest 开户请求
+type OpenRequest struct {
+	Owner          string  `json:"owner"`
+	InitialBalance float64 `json:"initial_balance"`
+}
+
+// AmountRequest 存款/取款请求
+type AmountRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// TransferRequest 转账请求
+type TransferRequest struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+// TransferResponse 转账结果，包含双方账户的最新快照
+type TransferResponse struct {
+	From Account `json:"from"`
+	To   Account `json:"to"`
+}
+
+// HistoryResponse 交易流水
+type HistoryResponse struct {
+	AccountID    string        `json:"account_id"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// ErrorResponse 统一的错误响应体
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"`
+}
+
+// Handler 把 Bank 暴露为 HTTP 服务
+type Handler struct {
+	bank *Bank
+	mux  *http.ServeMux
+}
+
+// NewHandler 创建 REST 处理器
+func NewHandler(b *Bank) *Handler {
+	h := &Handler{bank: b, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /accounts", h.open)
+	h.mux.HandleFunc("GET /accounts/{id}", h.get)
+	h.mux.HandleFunc("POST /accounts/{id}/deposit", h.deposit)
+	h.mux.HandleFunc("POST /accounts/{id}/withdraw", h.withdraw)
+	h.mux.HandleFunc("GET /accounts/{id}/history", h.history)
+	h.mux.HandleFunc("POST /transfers", h.transfer)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) open(w http.ResponseWriter, r *http.Request) {
+	var req OpenRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	acc, err := h.bank.Open(req.Owner, req.InitialBalance)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, acc)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	acc, err := h.bank.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (h *Handler) deposit(w http.ResponseWriter, r *http.Request) {
+	h.amountOp(w, r, h.bank.Deposit)
+}
+
+func (h *Handler) withdraw(w http.ResponseWriter, r *http.Request) {
+	h.amountOp(w, r, h.bank.Withdraw)
+}
+
+// amountOp 存款和取款的请求处理流程完全一样，只是调用的方法不同
+func (h *Handler) amountOp(w http.ResponseWriter, r *http.Request, op func(id string, amount float64) (Account, error)) {
+	var req AmountRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	acc, err := op(r.PathValue("id"), req.Amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	txs, err := h.bank.History(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, HistoryResponse{AccountID: id, Transactions: txs})
+}
+
+func (h *Handler) transfer(w http.ResponseWriter, r *http.Request) {
+	var req TransferRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.From == "" {
+		writeError(w, &ValidationError{Field: "from", Message: "不能为空"})
+		return
+	}
+	if req.To == "" {
+		writeError(w, &ValidationError{Field: "to", Message: "不能为空"})
+		return
+	}
+	from, to, err := h.bank.Transfer(req.From, req.To, req.Amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, TransferResponse{From: from, To: to})
+}
+
+// ============================================
+// 编解码与错误映射
+// ============================================
+
+// decodeJSON 解码请求体，拒绝未知字段和多余内容
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return &ValidationError{Field: "body", Message: fmt.Sprintf("无效的 JSON: %v", err)}
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return &ValidationError{Field: "body", Message: "请求体只能包含一个 JSON 对象"}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	resp := ErrorResponse{Error: err.Error()}
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		resp.Field = valErr.Field
+	}
+	writeJSON(w, statusFor(err), resp)
+}
+
+// statusFor 把业务错误映射为 HTTP 状态码
+func statusFor(err error) int {
+	var valErr *ValidationError
+	switch {
+	case errors.As(err, &valErr),
+		errors.Is(err, ErrInvalidAmount),
+		errors.Is(err, ErrSameAccount):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrAccountClosed),
+		errors.Is(err, ErrNonZeroBalance):
+		return http.StatusConflict
+	case errors.Is(err, ErrInsufficientFunds):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
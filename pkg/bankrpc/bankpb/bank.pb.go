@@ -0,0 +1,568 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: pkg/bankrpc/bankpb/bank.proto
+
+package bankpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OpenRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Owner          string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	InitialBalance float64                `protobuf:"fixed64,2,opt,name=initial_balance,json=initialBalance,proto3" json:"initial_balance,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OpenRequest) Reset() {
+	*x = OpenRequest{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenRequest) ProtoMessage() {}
+
+func (x *OpenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenRequest.ProtoReflect.Descriptor instead.
+func (*OpenRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OpenRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *OpenRequest) GetInitialBalance() float64 {
+	if x != nil {
+		return x.InitialBalance
+	}
+	return 0
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AmountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmountRequest) Reset() {
+	*x = AmountRequest{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmountRequest) ProtoMessage() {}
+
+func (x *AmountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmountRequest.ProtoReflect.Descriptor instead.
+func (*AmountRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AmountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AmountRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransferRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *Account               `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Account               `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransferResponse) GetFrom() *Account {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransferResponse) GetTo() *Account {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Balance       float64                `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Closed        bool                   `protobuf:"varint,4,opt,name=closed,proto3" json:"closed,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Account) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Account) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Account) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Account) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *Account) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Transaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	BalanceAfter  float64                `protobuf:"fixed64,5,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
+	Counterparty  string                 `protobuf:"bytes,6,opt,name=counterparty,proto3" json:"counterparty,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_bankrpc_bankpb_bank_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Transaction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transaction) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transaction) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetBalanceAfter() float64 {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return 0
+}
+
+func (x *Transaction) GetCounterparty() string {
+	if x != nil {
+		return x.Counterparty
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_pkg_bankrpc_bankpb_bank_proto protoreflect.FileDescriptor
+
+const file_pkg_bankrpc_bankpb_bank_proto_rawDesc = "" +
+	"\n" +
+	"\x1dpkg/bankrpc/bankpb/bank.proto\x12\abank.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"L\n" +
+	"\vOpenRequest\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12'\n" +
+	"\x0finitial_balance\x18\x02 \x01(\x01R\x0einitialBalance\"\x1c\n" +
+	"\n" +
+	"GetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
+	"\rAmountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"M\n" +
+	"\x0fTransferRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\"Z\n" +
+	"\x10TransferResponse\x12$\n" +
+	"\x04from\x18\x01 \x01(\v2\x10.bank.v1.AccountR\x04from\x12 \n" +
+	"\x02to\x18\x02 \x01(\v2\x10.bank.v1.AccountR\x02to\"\x9c\x01\n" +
+	"\aAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x01R\abalance\x12\x16\n" +
+	"\x06closed\x18\x04 \x01(\bR\x06closed\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xec\x01\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12#\n" +
+	"\rbalance_after\x18\x05 \x01(\x01R\fbalanceAfter\x12\"\n" +
+	"\fcounterparty\x18\x06 \x01(\tR\fcounterparty\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xc8\x02\n" +
+	"\x04Bank\x12.\n" +
+	"\x04Open\x12\x14.bank.v1.OpenRequest\x1a\x10.bank.v1.Account\x12,\n" +
+	"\x03Get\x12\x13.bank.v1.GetRequest\x1a\x10.bank.v1.Account\x123\n" +
+	"\aDeposit\x12\x16.bank.v1.AmountRequest\x1a\x10.bank.v1.Account\x124\n" +
+	"\bWithdraw\x12\x16.bank.v1.AmountRequest\x1a\x10.bank.v1.Account\x12?\n" +
+	"\bTransfer\x12\x18.bank.v1.TransferRequest\x1a\x19.bank.v1.TransferResponse\x126\n" +
+	"\aHistory\x12\x13.bank.v1.GetRequest\x1a\x14.bank.v1.Transaction0\x01B\x18Z\x16c03/pkg/bankrpc/bankpbb\x06proto3"
+
+var (
+	file_pkg_bankrpc_bankpb_bank_proto_rawDescOnce sync.Once
+	file_pkg_bankrpc_bankpb_bank_proto_rawDescData []byte
+)
+
+func file_pkg_bankrpc_bankpb_bank_proto_rawDescGZIP() []byte {
+	file_pkg_bankrpc_bankpb_bank_proto_rawDescOnce.Do(func() {
+		file_pkg_bankrpc_bankpb_bank_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_bankrpc_bankpb_bank_proto_rawDesc), len(file_pkg_bankrpc_bankpb_bank_proto_rawDesc)))
+	})
+	return file_pkg_bankrpc_bankpb_bank_proto_rawDescData
+}
+
+var file_pkg_bankrpc_bankpb_bank_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pkg_bankrpc_bankpb_bank_proto_goTypes = []any{
+	(*OpenRequest)(nil),           // 0: bank.v1.OpenRequest
+	(*GetRequest)(nil),            // 1: bank.v1.GetRequest
+	(*AmountRequest)(nil),         // 2: bank.v1.AmountRequest
+	(*TransferRequest)(nil),       // 3: bank.v1.TransferRequest
+	(*TransferResponse)(nil),      // 4: bank.v1.TransferResponse
+	(*Account)(nil),               // 5: bank.v1.Account
+	(*Transaction)(nil),           // 6: bank.v1.Transaction
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_pkg_bankrpc_bankpb_bank_proto_depIdxs = []int32{
+	5,  // 0: bank.v1.TransferResponse.from:type_name -> bank.v1.Account
+	5,  // 1: bank.v1.TransferResponse.to:type_name -> bank.v1.Account
+	7,  // 2: bank.v1.Account.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 3: bank.v1.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: bank.v1.Bank.Open:input_type -> bank.v1.OpenRequest
+	1,  // 5: bank.v1.Bank.Get:input_type -> bank.v1.GetRequest
+	2,  // 6: bank.v1.Bank.Deposit:input_type -> bank.v1.AmountRequest
+	2,  // 7: bank.v1.Bank.Withdraw:input_type -> bank.v1.AmountRequest
+	3,  // 8: bank.v1.Bank.Transfer:input_type -> bank.v1.TransferRequest
+	1,  // 9: bank.v1.Bank.History:input_type -> bank.v1.GetRequest
+	5,  // 10: bank.v1.Bank.Open:output_type -> bank.v1.Account
+	5,  // 11: bank.v1.Bank.Get:output_type -> bank.v1.Account
+	5,  // 12: bank.v1.Bank.Deposit:output_type -> bank.v1.Account
+	5,  // 13: bank.v1.Bank.Withdraw:output_type -> bank.v1.Account
+	4,  // 14: bank.v1.Bank.Transfer:output_type -> bank.v1.TransferResponse
+	6,  // 15: bank.v1.Bank.History:output_type -> bank.v1.Transaction
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_pkg_bankrpc_bankpb_bank_proto_init() }
+func file_pkg_bankrpc_bankpb_bank_proto_init() {
+	if File_pkg_bankrpc_bankpb_bank_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_bankrpc_bankpb_bank_proto_rawDesc), len(file_pkg_bankrpc_bankpb_bank_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_bankrpc_bankpb_bank_proto_goTypes,
+		DependencyIndexes: file_pkg_bankrpc_bankpb_bank_proto_depIdxs,
+		MessageInfos:      file_pkg_bankrpc_bankpb_bank_proto_msgTypes,
+	}.Build()
+	File_pkg_bankrpc_bankpb_bank_proto = out.File
+	file_pkg_bankrpc_bankpb_bank_proto_goTypes = nil
+	file_pkg_bankrpc_bankpb_bank_proto_depIdxs = nil
+}
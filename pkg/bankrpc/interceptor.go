@@ -0,0 +1,76 @@
+package bankrpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================
+// 拦截器：gRPC 版的中间件
+// ============================================
+//
+// 与 pkg/middleware 的 func(http.Handler) http.Handler 作用相同，
+// 只是一元调用和流式调用各有一种签名。
+// grpc.ChainUnaryInterceptor 按参数顺序嵌套，第一个在最外层。
+
+// UnaryLogging 记录每次一元调用的方法、状态码和耗时
+func UnaryLogging(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Printf("%s %s %v", info.FullMethod, status.Code(err), time.Since(start).Round(time.Microsecond))
+		return resp, err
+	}
+}
+
+// StreamLogging 记录每次流式调用的方法、发送的消息数、状态码和耗时
+func StreamLogging(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		cs := &countingStream{ServerStream: ss}
+		err := handler(srv, cs)
+		logger.Printf("%s %s sent=%d %v", info.FullMethod, status.Code(err), cs.sent, time.Since(start).Round(time.Microsecond))
+		return err
+	}
+}
+
+// countingStream 包装 ServerStream，统计发送的消息数
+type countingStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+// UnaryDefaultTimeout 客户端没有设置截止时间时加上 d，避免请求无限期占用服务端
+// 客户端自己设置的截止时间更早时保持不变
+func UnaryDefaultTimeout(d time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientLogging 客户端拦截器：记录调用耗时和状态码
+func UnaryClientLogging(logger *log.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logger.Printf("%s %s %v", method, status.Code(err), time.Since(start).Round(time.Microsecond))
+		return err
+	}
+}
@@ -0,0 +1,161 @@
+// ============================================
+// bankrpc 包：银行服务的 gRPC 实现
+// ============================================
+//
+// 对应 tutorial/09_reflect 练习 5（用反射实现 RPC 调用器）的"真实版本"：
+// 接口定义在 bankpb/bank.proto，由 protoc 生成编解码和服务桩代码，
+// 这里只需要实现 bankpb.BankServer，把请求转给 pkg/bank。
+//
+// - 业务错误按 errors.Is 映射为 gRPC 状态码，客户端用 status.Code(err) 判断
+// - History 是服务端流：每条流水一个消息，客户端取消或超时时停止发送
+// - 日志、默认超时等横切逻辑放在拦截器（interceptor.go）
+// ============================================
+
+package bankrpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"c03/pkg/bank"
+	"c03/pkg/bankrpc/bankpb"
+)
+
+// defaultTimeout 客户端没有设置截止时间的一元调用最多执行多久
+const defaultTimeout = 5 * time.Second
+
+// Server 把 *bank.Bank 暴露为 bankpb.BankServer
+type Server struct {
+	bankpb.UnimplementedBankServer
+	bank *bank.Bank
+
+	// HistoryDelay 每条流水之间的间隔，演示流式推送和客户端取消时使用
+	HistoryDelay time.Duration
+}
+
+// NewServer 创建服务实现
+func NewServer(b *bank.Bank) *Server {
+	return &Server{bank: b}
+}
+
+// NewGRPCServer 创建注册好 Bank 服务和日志、默认超时拦截器的 grpc.Server
+func NewGRPCServer(srv *Server, logger *log.Logger) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryLogging(logger), UnaryDefaultTimeout(defaultTimeout)),
+		grpc.ChainStreamInterceptor(StreamLogging(logger)),
+	)
+	bankpb.RegisterBankServer(s, srv)
+	return s
+}
+
+func (s *Server) Open(ctx context.Context, req *bankpb.OpenRequest) (*bankpb.Account, error) {
+	acc, err := s.bank.Open(req.GetOwner(), req.GetInitialBalance())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(acc), nil
+}
+
+func (s *Server) Get(ctx context.Context, req *bankpb.GetRequest) (*bankpb.Account, error) {
+	acc, err := s.bank.Get(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(acc), nil
+}
+
+func (s *Server) Deposit(ctx context.Context, req *bankpb.AmountRequest) (*bankpb.Account, error) {
+	acc, err := s.bank.Deposit(req.GetId(), req.GetAmount())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(acc), nil
+}
+
+func (s *Server) Withdraw(ctx context.Context, req *bankpb.AmountRequest) (*bankpb.Account, error) {
+	acc, err := s.bank.Withdraw(req.GetId(), req.GetAmount())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(acc), nil
+}
+
+func (s *Server) Transfer(ctx context.Context, req *bankpb.TransferRequest) (*bankpb.TransferResponse, error) {
+	from, to, err := s.bank.Transfer(req.GetFrom(), req.GetTo(), req.GetAmount())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &bankpb.TransferResponse{From: toAccount(from), To: toAccount(to)}, nil
+}
+
+// History 逐条发送流水；ctx 在客户端取消、超时或断开时结束
+func (s *Server) History(req *bankpb.GetRequest, stream grpc.ServerStreamingServer[bankpb.Transaction]) error {
+	txs, err := s.bank.History(req.GetId())
+	if err != nil {
+		return toStatus(err)
+	}
+	ctx := stream.Context()
+	for _, tx := range txs {
+		if s.HistoryDelay > 0 {
+			select {
+			case <-time.After(s.HistoryDelay):
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if err := stream.Send(toTransaction(tx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toStatus 把业务错误映射为 gRPC 状态码，对应 bank.Handler 中的 HTTP 状态码映射
+func toStatus(err error) error {
+	var valErr *bank.ValidationError
+	var code codes.Code
+	switch {
+	case errors.As(err, &valErr),
+		errors.Is(err, bank.ErrInvalidAmount),
+		errors.Is(err, bank.ErrSameAccount):
+		code = codes.InvalidArgument
+	case errors.Is(err, bank.ErrAccountNotFound):
+		code = codes.NotFound
+	case errors.Is(err, bank.ErrAccountClosed),
+		errors.Is(err, bank.ErrNonZeroBalance),
+		errors.Is(err, bank.ErrInsufficientFunds):
+		code = codes.FailedPrecondition
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
+
+func toAccount(a bank.Account) *bankpb.Account {
+	return &bankpb.Account{
+		Id:        a.ID,
+		Owner:     a.Owner,
+		Balance:   a.Balance,
+		Closed:    a.Closed,
+		CreatedAt: timestamppb.New(a.CreatedAt),
+	}
+}
+
+func toTransaction(tx bank.Transaction) *bankpb.Transaction {
+	return &bankpb.Transaction{
+		Id:           tx.ID,
+		AccountId:    tx.AccountID,
+		Type:         string(tx.Type),
+		Amount:       tx.Amount,
+		BalanceAfter: tx.BalanceAfter,
+		Counterparty: tx.Counterparty,
+		CreatedAt:    timestamppb.New(tx.CreatedAt),
+	}
+}
@@ -0,0 +1,114 @@
+// ============================================
+// bufpool 包：按大小分级的字节缓冲池
+// ============================================
+//
+// 每个连接、每次拷贝都 make 一块缓冲区，高并发时会产生大量短命的垃圾，
+// GC 压力随 QPS 线性增长。sync.Pool 可以复用它们，但一个 Pool 里的
+// 缓冲区大小不一：要 512 字节却拿到 1MB 会浪费内存，反过来又不够用。
+//
+// BufPool 按 2 的幂分级，每级一个 sync.Pool：
+//
+//   Get(3000)  -> 从 4KB 级取出，返回 len=3000、cap=4096 的切片
+//   Put(b)     -> 按 cap 放回对应的级；cap 不是某一级的大小（不是从池里拿的）就丢弃
+//
+// 超过最大级别的请求直接 make，Put 时也不回收，避免池里囤积巨型缓冲区。
+//
+// 注意：Put 之后不能再使用该切片，也不要 Put 两次——它可能已经被别人拿走。
+// ============================================
+
+package bufpool
+
+import (
+	"io"
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+const (
+	minShift = 9  // 最小级 512B
+	maxShift = 20 // 最大级 1MB
+)
+
+// 池里存的是底层数组的首地址（*byte），取出时按该级的大小还原成切片。
+// 直接存 []byte 的话，切片头（3 个字）装进 any 时每次 Put 都要分配一次，
+// 存 *[]byte 也一样要为新的切片头分配；指针装进 any 则不需要分配。
+
+// BufPool 并发安全的分级缓冲池，零值不可用，使用 New 创建
+type BufPool struct {
+	pools [maxShift - minShift + 1]sync.Pool
+}
+
+// New 创建缓冲池
+func New() *BufPool {
+	p := &BufPool{}
+	for i := range p.pools {
+		size := 1 << (minShift + i)
+		p.pools[i].New = func() any {
+			return unsafe.SliceData(make([]byte, size))
+		}
+	}
+	return p
+}
+
+// Default 进程共享的缓冲池
+var Default = New()
+
+// class 返回能容纳 size 字节的最小级别；超过最大级时返回 -1
+func class(size int) int {
+	if size <= 1<<minShift {
+		return 0
+	}
+	shift := bits.Len(uint(size - 1)) // 向上取整到 2 的幂
+	if shift > maxShift {
+		return -1
+	}
+	return shift - minShift
+}
+
+// Get 返回 len 为 size 的切片，内容未清零
+func (p *BufPool) Get(size int) []byte {
+	c := class(size)
+	if c < 0 {
+		return make([]byte, size)
+	}
+	ptr := p.pools[c].Get().(*byte)
+	return unsafe.Slice(ptr, 1<<(minShift+c))[:size]
+}
+
+// Put 归还 Get 得到的切片
+func (p *BufPool) Put(b []byte) {
+	c := class(cap(b))
+	if c < 0 || cap(b) != 1<<(minShift+c) {
+		return
+	}
+	p.pools[c].Put(unsafe.SliceData(b[:cap(b)]))
+}
+
+// copyBufSize 与 io.Copy 默认的缓冲区大小相同
+const copyBufSize = 32 << 10
+
+// Copy 与 io.Copy 相同，但总是使用池中的缓冲区
+//
+// io.Copy 优先调用 src 的 WriteTo 或 dst 的 ReadFrom，*os.File 两者都实现了，
+// 但在无法零拷贝（如文件到哈希、经过 TeeReader）时会在内部再 make 一块 32KB 的缓冲区。
+// 这里屏蔽这两个方法，直接用池里的缓冲区读写；
+// 文件到 socket 这类可以用 sendfile/splice 的场合继续用 io.Copy。
+func (p *BufPool) Copy(dst io.Writer, src io.Reader) (int64, error) {
+	buf := p.Get(copyBufSize)
+	defer p.Put(buf)
+	return io.CopyBuffer(onlyWriter{dst}, onlyReader{src}, buf)
+}
+
+// onlyWriter / onlyReader 隐藏 ReadFrom / WriteTo
+type onlyWriter struct{ io.Writer }
+type onlyReader struct{ io.Reader }
+
+// Get 从 Default 取缓冲区
+func Get(size int) []byte { return Default.Get(size) }
+
+// Put 归还到 Default
+func Put(b []byte) { Default.Put(b) }
+
+// Copy 使用 Default 的缓冲区拷贝
+func Copy(dst io.Writer, src io.Reader) (int64, error) { return Default.Copy(dst, src) }
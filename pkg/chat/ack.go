@@ -0,0 +1,114 @@
+package chat
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============================================
+// ACK 与重投递（至少一次投递语义）
+// ============================================
+//
+// 开启 WithAck 后，每条投递出去的消息都会登记到 pending 表：
+//
+//   route ──投递──> 收件箱 ──消费方处理──> Ack(userID, msgID) ──> 从 pending 删除
+//                     ^                                  |
+//                     └──── 超时未 Ack，Run 定期重投递 ────┘
+//
+// 因为可能重投递，消费方会收到重复消息，需要按 ID() 做幂等处理。
+// 路由器关闭时，仍未确认的消息随收件箱一起被丢弃。
+
+// pendingMsg 等待确认的消息
+type pendingMsg struct {
+	payload  IPayload
+	deadline time.Time
+	attempts int
+}
+
+// Ack 确认 userID 已处理完消息 msgID
+func (r *ChatRouter) Ack(userID, msgID string) error {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	pm, ok := r.pending[msgID]
+	if !ok || pm.payload.To() != userID {
+		return fmt.Errorf("%w: 用户 %s 没有待确认的消息 %s", ErrUnknownMessage, userID, msgID)
+	}
+	delete(r.pending, msgID)
+	return nil
+}
+
+// Pending 返回等待确认的消息数量
+func (r *ChatRouter) Pending() int {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	return len(r.pending)
+}
+
+// track 登记一条首次投递的消息
+func (r *ChatRouter) track(p IPayload) {
+	if r.ackTimeout <= 0 {
+		return
+	}
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	r.pending[p.ID()] = &pendingMsg{
+		payload:  p,
+		deadline: r.clock.Now().Add(r.ackBackoff.Delay(0)),
+		attempts: 1,
+	}
+}
+
+// redeliver 重投递所有已超时的消息
+// 投递时不能持有 pendingMu：消费方可能正阻塞在 Ack 上，而收件箱已满
+func (r *ChatRouter) redeliver(now time.Time) {
+	var expired []IPayload
+	var gaveUp uint64
+
+	r.pendingMu.Lock()
+	for id, pm := range r.pending {
+		if now.Before(pm.deadline) {
+			continue
+		}
+		if r.maxAttempts > 0 && pm.attempts >= r.maxAttempts {
+			delete(r.pending, id)
+			gaveUp++
+			continue
+		}
+		pm.deadline = now.Add(r.ackBackoff.Delay(pm.attempts))
+		pm.attempts++
+		expired = append(expired, pm.payload)
+	}
+	r.pendingMu.Unlock()
+
+	var redelivered uint64
+	for _, p := range expired {
+		if !r.deliver(p) {
+			r.forget(p.ID())
+			continue
+		}
+		redelivered++
+	}
+	r.stats(func(c *counters) {
+		c.expired += gaveUp
+		c.redelivered += redelivered
+	})
+}
+
+// forget 删除消息的确认记录（接收方已注销等情况）
+func (r *ChatRouter) forget(msgID string) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	delete(r.pending, msgID)
+}
+
+// forgetUser 删除某个用户的所有待确认消息
+func (r *ChatRouter) forgetUser(userID string) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	for id, pm := range r.pending {
+		if pm.payload.To() == userID {
+			delete(r.pending, id)
+		}
+	}
+}
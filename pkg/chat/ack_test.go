@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+func TestRouterRedeliversWithFakeClock(t *testing.T) {
+	const timeout = time.Second
+	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := NewChatRouter(4, WithAck(timeout, 3), WithClock(fc))
+	inbox, err := r.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	startRouter(t, r)
+	fc.BlockUntil(1) // Run 已创建重投递用的 Ticker
+
+	lost := NewChatMessage("alice", "bob", "没有被确认")
+	acked := NewChatMessage("alice", "bob", "已确认")
+	for _, m := range []ChatMessage{lost, acked} {
+		if err := r.Send(m); err != nil {
+			t.Fatal(err)
+		}
+		if got := receive(t, inbox); got.ID() != m.ID() {
+			t.Fatalf("首次投递收到 %s，期望 %s", got.ID(), m.ID())
+		}
+	}
+	if err := r.Ack("bob", acked.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 每推进一个 timeout，未确认的消息重投一次；一次推进跨过两个 tick，
+	// 多余的 tick 被丢弃，重投仍按推进后的时间判断
+	for attempt := 2; attempt <= 3; attempt++ {
+		fc.Advance(timeout)
+		if got := receive(t, inbox); got.ID() != lost.ID() {
+			t.Fatalf("第 %d 次投递收到 %s，期望 %s", attempt, got.ID(), lost.ID())
+		}
+	}
+
+	// 达到最大投递次数后放弃
+	fc.Advance(timeout)
+	waitFor(t, "消息过期", func() bool { return r.Stats().Expired == 1 })
+	if n := r.Pending(); n != 0 {
+		t.Fatalf("Pending() = %d，期望 0", n)
+	}
+	if s := r.Stats(); s.Redelivered != 2 {
+		t.Fatalf("Redelivered = %d，期望 2", s.Redelivered)
+	}
+	if n := len(inbox); n != 0 {
+		t.Fatalf("收件箱还有 %d 条消息，已确认或已过期的消息不应再投递", n)
+	}
+}
+
+func TestRouterAckUnknownMessage(t *testing.T) {
+	r := NewChatRouter(1, WithAck(time.Hour, 0), WithClock(clock.NewFakeClock(time.Unix(0, 0))))
+	inbox, err := r.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	startRouter(t, r)
+	m := NewChatMessage("alice", "bob", "hi")
+	if err := r.Send(m); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, inbox)
+
+	// 只有接收方本人可以确认，确认过的消息不能再确认
+	for _, tc := range []struct{ user, id string }{{"alice", m.ID()}, {"bob", "nope"}} {
+		if err := r.Ack(tc.user, tc.id); !errors.Is(err, ErrUnknownMessage) {
+			t.Fatalf("Ack(%q, %q) = %v，期望 ErrUnknownMessage", tc.user, tc.id, err)
+		}
+	}
+	if err := r.Ack("bob", m.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Ack("bob", m.ID()); !errors.Is(err, ErrUnknownMessage) {
+		t.Fatalf("重复 Ack = %v，期望 ErrUnknownMessage", err)
+	}
+}
@@ -0,0 +1,239 @@
+package chat
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ============================================
+// 二进制帧：长度前缀 + 字段掩码
+// ============================================
+//
+// JSON 帧每次都要带上字段名，数字和时间也按文本编码。二进制格式只写字段的值：
+//
+//   帧   = uvarint(体长度) 体
+//   体   = uvarint(掩码) 字段...
+//   字段 = 按下表的顺序，只写掩码中置位的字段
+//
+//   位  字段      编码
+//   0   Kind      uvarint(长度) 字节
+//   1   ID        同上
+//   2   From      同上
+//   3   To        同上
+//   4   Text      同上
+//   5   FileName  同上
+//   6   Size      varint
+//   7   SentAt    varint(Unix 秒) uvarint(纳秒)
+//   8   Priority  varint
+//   9   Error     uvarint(长度) 字节
+//   10  MessageID 同上
+//
+// 零值字段不写（与 JSON 的 omitempty 一致）。长度前缀让读取端不用解析内容就能分帧，
+// 也能在分配内存之前拒绝过大的帧。SentAt 只保存时刻，解码后是本地时区，比较时用 Equal。
+
+// MaxBinaryFrameSize 二进制帧体的最大长度，超过时 ReadBinaryFrame 返回 ErrFrameTooLarge
+const MaxBinaryFrameSize = 1 << 20
+
+var ErrFrameTooLarge = errors.New("帧过大")
+
+const (
+	bitKind = 1 << iota
+	bitID
+	bitFrom
+	bitTo
+	bitText
+	bitFileName
+	bitSize
+	bitSentAt
+	bitPriority
+	bitError
+	bitMessageID
+)
+
+// AppendBinary 把帧体（不含长度前缀）追加到 b
+func (f Frame) AppendBinary(b []byte) ([]byte, error) {
+	var mask uint64
+	setIf := func(bit uint64, ok bool) {
+		if ok {
+			mask |= bit
+		}
+	}
+	setIf(bitKind, f.Kind != "")
+	setIf(bitID, f.ID != "")
+	setIf(bitFrom, f.From != "")
+	setIf(bitTo, f.To != "")
+	setIf(bitText, f.Text != "")
+	setIf(bitFileName, f.FileName != "")
+	setIf(bitSize, f.Size != 0)
+	setIf(bitSentAt, !f.SentAt.IsZero())
+	setIf(bitPriority, f.Priority != 0)
+	setIf(bitError, f.Error != "")
+	setIf(bitMessageID, f.MessageID != "")
+
+	b = binary.AppendUvarint(b, mask)
+	for _, s := range []struct {
+		bit uint64
+		v   string
+	}{{bitKind, string(f.Kind)}, {bitID, f.ID}, {bitFrom, f.From}, {bitTo, f.To}, {bitText, f.Text}, {bitFileName, f.FileName}} {
+		if mask&s.bit != 0 {
+			b = appendString(b, s.v)
+		}
+	}
+	if mask&bitSize != 0 {
+		b = binary.AppendVarint(b, f.Size)
+	}
+	if mask&bitSentAt != 0 {
+		b = binary.AppendVarint(b, f.SentAt.Unix())
+		b = binary.AppendUvarint(b, uint64(f.SentAt.Nanosecond()))
+	}
+	if mask&bitPriority != 0 {
+		b = binary.AppendVarint(b, int64(f.Priority))
+	}
+	if mask&bitError != 0 {
+		b = appendString(b, f.Error)
+	}
+	if mask&bitMessageID != 0 {
+		b = appendString(b, f.MessageID)
+	}
+	return b, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// DecodeBinaryFrame 解析 AppendBinary 生成的帧体
+func DecodeBinaryFrame(data []byte) (Frame, error) {
+	d := decoder{data: data}
+	mask := d.uvarint()
+	if mask >= bitMessageID<<1 {
+		return Frame{}, fmt.Errorf("%w: 未知的字段掩码 %#x", ErrBadFrame, mask)
+	}
+
+	var f Frame
+	if mask&bitKind != 0 {
+		f.Kind = FrameKind(d.string())
+	}
+	for _, s := range []struct {
+		bit uint64
+		dst *string
+	}{{bitID, &f.ID}, {bitFrom, &f.From}, {bitTo, &f.To}, {bitText, &f.Text}, {bitFileName, &f.FileName}} {
+		if mask&s.bit != 0 {
+			*s.dst = d.string()
+		}
+	}
+	if mask&bitSize != 0 {
+		f.Size = d.varint()
+	}
+	if mask&bitSentAt != 0 {
+		sec := d.varint()
+		nsec := d.uvarint()
+		if d.err == nil && nsec >= uint64(time.Second) {
+			d.err = fmt.Errorf("%w: 纳秒 %d 超出范围", ErrBadFrame, nsec)
+		}
+		f.SentAt = time.Unix(sec, int64(nsec))
+	}
+	if mask&bitPriority != 0 {
+		f.Priority = Priority(d.varint())
+	}
+	if mask&bitError != 0 {
+		f.Error = d.string()
+	}
+	if mask&bitMessageID != 0 {
+		f.MessageID = d.string()
+	}
+	if d.err != nil {
+		return Frame{}, d.err
+	}
+	if len(d.data) != 0 {
+		return Frame{}, fmt.Errorf("%w: 帧末尾多出 %d 字节", ErrBadFrame, len(d.data))
+	}
+	return f, nil
+}
+
+// decoder 顺序读取帧体，遇到第一个错误后后续读取都返回零值，最后统一检查 err
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(what string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s 被截断", ErrBadFrame, what)
+	}
+	d.data = nil
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("uvarint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail("varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail("字符串")
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+// WriteBinaryFrame 写出带长度前缀的一帧
+func WriteBinaryFrame(w io.Writer, f Frame) error {
+	body, err := f.AppendBinary(nil)
+	if err != nil {
+		return err
+	}
+	if len(body) > MaxBinaryFrameSize {
+		return fmt.Errorf("%w: %d 字节", ErrFrameTooLarge, len(body))
+	}
+	buf := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(body)), uint64(len(body)))
+	_, err = w.Write(append(buf, body...))
+	return err
+}
+
+// ByteReader ReadBinaryFrame 的输入，*bufio.Reader 和 *bytes.Reader 都满足
+type ByteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// ReadBinaryFrame 读取一帧；在帧边界遇到 EOF 时返回 io.EOF，帧中途结束时返回 io.ErrUnexpectedEOF
+func ReadBinaryFrame(r ByteReader) (Frame, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return Frame{}, err
+	}
+	if size > MaxBinaryFrameSize {
+		return Frame{}, fmt.Errorf("%w: %d 字节", ErrFrameTooLarge, size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Frame{}, err
+	}
+	return DecodeBinaryFrame(body)
+}
@@ -0,0 +1,174 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sameFrame 比较两帧；SentAt 解码后是本地时区，用 Equal 比较时刻
+func sameFrame(a, b Frame) bool {
+	if !a.SentAt.Equal(b.SentAt) {
+		return false
+	}
+	a.SentAt, b.SentAt = time.Time{}, time.Time{}
+	return a == b
+}
+
+var testFrames = []Frame{
+	{},
+	{Kind: FrameLogin, From: "alice"},
+	{Kind: FrameOK, ID: "42", MessageID: "m42"},
+	{Kind: FrameChat, ID: "m1", From: "alice", To: "bob", Text: "你好，世界", SentAt: time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC), Priority: PriorityHigh},
+	{Kind: FrameAttachment, From: "alice", To: "bob", FileName: "report.pdf", Size: 1 << 40},
+	{Kind: FrameError, Error: "无效的帧", Size: -1},
+	{Kind: FrameChat, Text: strings.Repeat("x", 100_000), SentAt: time.Unix(-1, 0)},
+}
+
+func TestBinaryFrameRoundTrip(t *testing.T) {
+	for _, f := range testFrames {
+		body, err := f.AppendBinary(nil)
+		if err != nil {
+			t.Fatalf("AppendBinary(%+v) 失败: %v", f, err)
+		}
+		got, err := DecodeBinaryFrame(body)
+		if err != nil {
+			t.Fatalf("DecodeBinaryFrame 失败: %v", err)
+		}
+		if !sameFrame(got, f) {
+			t.Fatalf("往返后得到 %+v，期望 %+v", got, f)
+		}
+	}
+}
+
+func TestBinaryFrameStream(t *testing.T) {
+	var buf bytes.Buffer
+	for _, f := range testFrames {
+		if err := WriteBinaryFrame(&buf, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := buf.Bytes()
+
+	r := bytes.NewReader(data)
+	for i, want := range testFrames {
+		got, err := ReadBinaryFrame(r)
+		if err != nil {
+			t.Fatalf("读取第 %d 帧失败: %v", i, err)
+		}
+		if !sameFrame(got, want) {
+			t.Fatalf("第 %d 帧得到 %+v，期望 %+v", i, got, want)
+		}
+	}
+	if _, err := ReadBinaryFrame(r); err != io.EOF {
+		t.Fatalf("在帧边界结束时返回 %v，期望 io.EOF", err)
+	}
+
+	// 最后一帧少一个字节：前面的帧正常读出，最后一帧报告意外的 EOF
+	r = bytes.NewReader(data[:len(data)-1])
+	var err error
+	for err == nil {
+		_, err = ReadBinaryFrame(r)
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("帧中途结束时返回 %v，期望 io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestBinaryFrameErrors(t *testing.T) {
+	// 长度前缀超过上限：不分配内存直接拒绝
+	prefix := binary.AppendUvarint(nil, MaxBinaryFrameSize+1)
+	if _, err := ReadBinaryFrame(bytes.NewReader(prefix)); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("过大的帧返回 %v，期望 ErrFrameTooLarge", err)
+	}
+	if err := WriteBinaryFrame(io.Discard, Frame{Text: strings.Repeat("x", MaxBinaryFrameSize)}); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("写出过大的帧返回 %v，期望 ErrFrameTooLarge", err)
+	}
+
+	body, _ := Frame{Kind: FrameChat, Text: "hello"}.AppendBinary(nil)
+	tests := map[string][]byte{
+		"空":      {},
+		"字段被截断":  body[:len(body)-1],
+		"末尾多出字节": append(bytes.Clone(body), 0),
+		"未知掩码":   binary.AppendUvarint(nil, 1<<20),
+	}
+	for name, data := range tests {
+		if _, err := DecodeBinaryFrame(data); !errors.Is(err, ErrBadFrame) {
+			t.Errorf("%s: DecodeBinaryFrame 返回 %v，期望 ErrBadFrame", name, err)
+		}
+	}
+}
+
+// FuzzDecodeBinaryFrame 任意输入不会 panic；能解码的帧重新编码后再解码得到同样的帧
+// 运行：go test ./pkg/chat -fuzz FuzzDecodeBinaryFrame
+func FuzzDecodeBinaryFrame(f *testing.F) {
+	for _, fr := range testFrames[:6] {
+		body, _ := fr.AppendBinary(nil)
+		f.Add(body)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fr, err := DecodeBinaryFrame(data)
+		if err != nil {
+			return
+		}
+		body, err := fr.AppendBinary(nil)
+		if err != nil {
+			t.Fatalf("解码得到的帧 %+v 无法重新编码: %v", fr, err)
+		}
+		again, err := DecodeBinaryFrame(body)
+		if err != nil || !sameFrame(again, fr) {
+			t.Fatalf("重新编码后解码得到 %+v, %v，期望 %+v", again, err, fr)
+		}
+	})
+}
+
+// benchFrame 典型的聊天消息
+var benchFrame = Frame{
+	Kind: FrameChat, ID: "1745923840123456789", From: "alice", To: "bob",
+	Text: "明天下午三点开会，记得带上周报", SentAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+}
+
+// BenchmarkFrameEncode 对比二进制和 JSON 的编码速度，bytes/frame 是编码后的大小
+//
+//	go test -bench Frame -benchmem ./pkg/chat
+func BenchmarkFrameEncode(b *testing.B) {
+	b.Run("binary", func(b *testing.B) {
+		var buf []byte
+		for b.Loop() {
+			buf, _ = benchFrame.AppendBinary(buf[:0])
+		}
+		b.ReportMetric(float64(len(buf)), "bytes/frame")
+	})
+	b.Run("json", func(b *testing.B) {
+		var data []byte
+		for b.Loop() {
+			data, _ = json.Marshal(benchFrame)
+		}
+		b.ReportMetric(float64(len(data)), "bytes/frame")
+	})
+}
+
+func BenchmarkFrameDecode(b *testing.B) {
+	body, _ := benchFrame.AppendBinary(nil)
+	data, _ := json.Marshal(benchFrame)
+	b.Run("binary", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := DecodeBinaryFrame(body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("json", func(b *testing.B) {
+		for b.Loop() {
+			var f Frame
+			if err := json.Unmarshal(data, &f); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
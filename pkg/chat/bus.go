@@ -0,0 +1,192 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ============================================
+// Bus：基于主题的发布/订阅
+// ============================================
+//
+// 与按用户投递的 ChatRouter 不同，Bus 按主题广播：
+// 一条消息会发给所有订阅了匹配主题的订阅者。
+//
+// 主题用 "." 分段，订阅时可以使用通配符：
+//   *  匹配恰好一段        chat.*     匹配 chat.alice，不匹配 chat.alice.file
+//   #  只能放在最后，匹配剩余的零段或多段
+//                          chat.#     匹配 chat、chat.alice、chat.alice.file
+//
+// 不泄漏 goroutine 的关键：
+// - Publish 在调用方的 goroutine 中直接发送，Bus 自己不启动任何 goroutine
+// - Unsubscribe 先关闭 done 唤醒阻塞在该订阅者上的 Publish，
+//   再拿写锁移除订阅并关闭 channel，因此不会向已关闭的 channel 发送
+// - Close 同理，先关闭 stopping 唤醒所有阻塞的 Publish，再关闭全部订阅
+
+var (
+	ErrBusClosed  = errors.New("消息总线已关闭")
+	ErrBadPattern = errors.New("无效的主题")
+)
+
+// Bus 主题消息总线
+type Bus struct {
+	bufSize int
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+
+	closeOnce sync.Once
+	stopping  chan struct{}
+}
+
+// Subscription 一个订阅，通过 C() 接收消息
+type Subscription struct {
+	bus     *Bus
+	pattern []string
+	ch      chan IPayload
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewBus 创建消息总线，bufSize 是每个订阅者 channel 的缓冲大小
+func NewBus(bufSize int) *Bus {
+	return &Bus{
+		bufSize:  bufSize,
+		subs:     make(map[*Subscription]struct{}),
+		stopping: make(chan struct{}),
+	}
+}
+
+// Subscribe 订阅匹配 pattern 的主题
+func (b *Bus) Subscribe(pattern string) (*Subscription, error) {
+	segs, err := splitTopic(pattern, true)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+	s := &Subscription{
+		bus:     b,
+		pattern: segs,
+		ch:      make(chan IPayload, b.bufSize),
+		done:    make(chan struct{}),
+	}
+	b.subs[s] = struct{}{}
+	return s, nil
+}
+
+// Publish 把 msg 发给所有匹配 topic 的订阅者，返回成功投递的数量
+// 订阅者 channel 已满时阻塞等待，直到对方取走、对方取消订阅或 ctx 结束
+func (b *Bus) Publish(ctx context.Context, topic string, msg IPayload) (int, error) {
+	segs, err := splitTopic(topic, false)
+	if err != nil {
+		return 0, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, ErrBusClosed
+	}
+
+	delivered := 0
+	for s := range b.subs {
+		if !matchTopic(s.pattern, segs) {
+			continue
+		}
+		select {
+		case s.ch <- msg:
+			delivered++
+		case <-s.done:
+			// 订阅者正在退订，跳过
+		case <-b.stopping:
+			return delivered, ErrBusClosed
+		case <-ctx.Done():
+			return delivered, ctxError(ctx)
+		}
+	}
+	return delivered, nil
+}
+
+// Close 关闭总线和所有订阅，可以重复调用
+func (b *Bus) Close() {
+	b.closeOnce.Do(func() { close(b.stopping) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		close(s.ch)
+		delete(b.subs, s)
+	}
+}
+
+// C 返回接收消息的 channel，取消订阅或总线关闭后被关闭
+func (s *Subscription) C() <-chan IPayload {
+	return s.ch
+}
+
+// Unsubscribe 取消订阅，可以重复调用
+func (s *Subscription) Unsubscribe() {
+	s.stop()
+
+	b := s.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[s]; !ok {
+		return // 已经取消过，或总线已关闭
+	}
+	delete(b.subs, s)
+	close(s.ch)
+}
+
+// stop 唤醒阻塞在该订阅者上的 Publish，不需要持有锁
+func (s *Subscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// splitTopic 按 "." 分段并校验；只有订阅时允许通配符
+func splitTopic(topic string, allowWildcard bool) ([]string, error) {
+	if topic == "" {
+		return nil, ErrBadPattern
+	}
+	segs := strings.Split(topic, ".")
+	for i, seg := range segs {
+		switch {
+		case seg == "":
+			return nil, fmt.Errorf("%w: %q 含有空段", ErrBadPattern, topic)
+		case !allowWildcard && (seg == "*" || seg == "#"):
+			return nil, fmt.Errorf("%w: 发布的主题 %q 不能含通配符", ErrBadPattern, topic)
+		case seg == "#" && i != len(segs)-1:
+			return nil, fmt.Errorf("%w: %q 中的 # 只能放在最后", ErrBadPattern, topic)
+		}
+	}
+	return segs, nil
+}
+
+// matchTopic 判断主题是否匹配订阅模式
+func matchTopic(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == "#" {
+			return true
+		}
+		if i >= len(topic) {
+			return false
+		}
+		if p != "*" && p != topic[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,68 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ============================================
+// 泛型 channel 收发辅助函数
+// ============================================
+//
+// 所有阻塞的 channel 操作都应该能被取消：
+// 用 select 同时等待 channel 与 ctx.Done()，超时由 ctx 的 deadline 决定。
+//
+//   ctx, cancel := context.WithTimeout(ctx, time.Second)
+//   defer cancel()
+//   msg, err := RecvChanData(ctx, inbox)
+//   switch {
+//   case errors.Is(err, ErrChanTimeout): // 超时
+//   case errors.Is(err, ErrChanClosed):  // 对方已关闭 channel
+//   }
+
+var (
+	ErrChanTimeout = errors.New("channel 操作超时")
+	ErrChanClosed  = errors.New("channel 已关闭")
+)
+
+// SendChanData 向 ch 发送 v，直到发送成功或 ctx 结束
+// 向已关闭的 channel 发送会 panic，这里将其转换为 ErrChanClosed 返回；
+// 正确的做法仍然是由 channel 的拥有者负责关闭，这只是最后一道保险。
+func SendChanData[T any](ctx context.Context, ch chan<- T, v T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrChanClosed, r)
+		}
+	}()
+
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctxError(ctx)
+	}
+}
+
+// RecvChanData 从 ch 接收一个值，直到收到数据、channel 被关闭或 ctx 结束
+func RecvChanData[T any](ctx context.Context, ch <-chan T) (T, error) {
+	var zero T
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			return zero, ErrChanClosed
+		}
+		return v, nil
+	case <-ctx.Done():
+		return zero, ctxError(ctx)
+	}
+}
+
+// ctxError 区分超时与主动取消：超时返回 ErrChanTimeout，取消原样返回 ctx.Err()
+func ctxError(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrChanTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,234 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// ============================================
+// Client：TCP 聊天客户端
+// ============================================
+//
+// 一个后台 goroutine 负责读连接，按帧类型分流：
+// - chat / attachment 推送的消息 -> 队列 -> Messages()
+// - ok / error 请求的应答       -> replies，由等待中的请求按 ID 认领
+//
+// 消息先进入无界队列再交给 Messages()，读 goroutine 不会阻塞在消费方上。
+// 否则消费方在处理消息时调用 Ack/Send，会等待一个永远读不到的应答而死锁。
+
+var (
+	ErrServer       = errors.New("服务端返回错误")
+	ErrClientClosed = errors.New("客户端连接已关闭")
+)
+
+// Client 一个已登录用户的连接
+type Client struct {
+	userID string
+	conn   net.Conn
+	w      *connWriter
+
+	reqMu   sync.Mutex // 同一时刻只有一个请求在等待应答
+	replies chan Frame
+	msgs    chan IPayload
+
+	closeOnce sync.Once
+	closing   chan struct{} // Close 时关闭，唤醒阻塞在 msgs 上的读 goroutine
+	done      chan struct{} // 读 goroutine 退出后关闭
+}
+
+// Dial 连接服务端并以 userID 登录
+func Dial(ctx context.Context, addr, userID string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 登录阶段还没有读 goroutine，ctx 结束时直接关闭连接打断读写
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c := &Client{
+		userID:  userID,
+		conn:    conn,
+		w:       newConnWriter(conn),
+		replies: make(chan Frame, 1),
+		msgs:    make(chan IPayload),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	dec := json.NewDecoder(conn)
+	if err := c.w.write(Frame{Kind: FrameLogin, From: userID}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	var reply Frame
+	if err := dec.Decode(&reply); err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if reply.Kind == FrameError {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrServer, reply.Error)
+	}
+	if !stop() {
+		// ctx 恰好在登录成功后结束，连接已被关闭
+		return nil, ctx.Err()
+	}
+
+	queued := make(chan IPayload)
+	go c.readLoop(dec, queued)
+	go c.forward(queued)
+	return c, nil
+}
+
+// UserID 返回登录的用户 ID
+func (c *Client) UserID() string {
+	return c.userID
+}
+
+// Messages 返回推送给本用户的消息
+// 连接断开且已收到的消息全部取走后被关闭；调用 Close 后立即关闭
+func (c *Client) Messages() <-chan IPayload {
+	return c.msgs
+}
+
+// Send 发送一条消息，等待服务端确认已交给 Router，返回服务端分配的消息 ID
+// 消息的发送方和 ID 都以服务端为准，p.From() 会被忽略，p.ID() 只用于匹配应答
+func (c *Client) Send(ctx context.Context, p IPayload) (string, error) {
+	f, err := FrameFromPayload(p)
+	if err != nil {
+		return "", err
+	}
+	reply, err := c.request(ctx, f)
+	if err != nil {
+		return "", err
+	}
+	return reply.MessageID, nil
+}
+
+// SendText 发送文本消息的便捷方法
+func (c *Client) SendText(ctx context.Context, to, text string) (string, error) {
+	return c.Send(ctx, NewChatMessage(c.userID, to, text))
+}
+
+// Ack 确认已处理完消息 msgID
+func (c *Client) Ack(ctx context.Context, msgID string) error {
+	_, err := c.request(ctx, Frame{Kind: FrameAck, ID: msgID})
+	return err
+}
+
+// Close 断开连接，服务端随之注销该用户
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closing)
+		err = c.conn.Close()
+	})
+	<-c.done
+	return err
+}
+
+// request 发送一帧并等待 ID 相同的应答
+// 被取消的请求留下的过期应答 ID 不同，在这里被丢弃
+func (c *Client) request(ctx context.Context, f Frame) (Frame, error) {
+	c.reqMu.Lock()
+	defer c.reqMu.Unlock()
+
+	if err := c.w.write(f); err != nil {
+		return Frame{}, fmt.Errorf("%w: %v", ErrClientClosed, err)
+	}
+	for {
+		select {
+		case reply := <-c.replies:
+			if reply.ID != f.ID {
+				continue
+			}
+			if reply.Kind == FrameError {
+				return Frame{}, fmt.Errorf("%w: %s", ErrServer, reply.Error)
+			}
+			return reply, nil
+		case <-c.done:
+			return Frame{}, ErrClientClosed
+		case <-ctx.Done():
+			return Frame{}, ctx.Err()
+		}
+	}
+}
+
+// readLoop 读取连接上的帧直到连接断开
+func (c *Client) readLoop(dec *json.Decoder, queued chan<- IPayload) {
+	defer close(c.done)
+	defer close(queued)
+
+	for {
+		var f Frame
+		if err := dec.Decode(&f); err != nil {
+			return
+		}
+		switch f.Kind {
+		case FrameOK, FrameError:
+			c.reply(f)
+		default:
+			p, err := f.Payload()
+			if err != nil {
+				continue
+			}
+			select {
+			case queued <- p:
+			case <-c.closing:
+				return
+			}
+		}
+	}
+}
+
+// forward 把 readLoop 收到的消息排队转交给 Messages()
+func (c *Client) forward(queued <-chan IPayload) {
+	defer close(c.msgs)
+
+	var queue []IPayload
+	for queued != nil || len(queue) > 0 {
+		// 队列为空时 out 为 nil，select 不会选中发送分支
+		var out chan<- IPayload
+		var next IPayload
+		if len(queue) > 0 {
+			out, next = c.msgs, queue[0]
+		}
+		select {
+		case p, ok := <-queued:
+			if !ok {
+				queued = nil
+				continue
+			}
+			queue = append(queue, p)
+		case out <- next:
+			queue[0] = nil
+			queue = queue[1:]
+		case <-c.closing:
+			return
+		}
+	}
+}
+
+// reply 把应答交给等待中的请求，从不阻塞
+// 缓冲区已满说明里面是被取消请求的过期应答，用新的替换它
+func (c *Client) reply(f Frame) {
+	select {
+	case c.replies <- f:
+		return
+	default:
+	}
+	select {
+	case <-c.replies:
+	default:
+	}
+	c.replies <- f
+}
@@ -0,0 +1,86 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================
+// 网络帧：TCP 等传输层上的 JSON 消息格式
+// ============================================
+//
+// 每一帧是一个 JSON 对象，帧与帧之间用换行分隔（json.Encoder 自带换行），
+// 读取端用 json.Decoder 逐个解码即可完成分帧。
+//
+// 客户端 -> 服务端：login / chat / attachment / ack
+// 服务端 -> 客户端：ok / error（对每个请求的应答）以及 chat / attachment（推送的消息）
+//
+// 请求帧的 ID 只用来匹配应答；chat / attachment 的消息 ID 由服务端分配，
+// 通过 ok 应答的 MessageID 返回给发送方。
+
+// FrameKind 帧类型
+type FrameKind string
+
+const (
+	FrameLogin      FrameKind = "login"
+	FrameOK         FrameKind = "ok"
+	FrameError      FrameKind = "error"
+	FrameChat       FrameKind = "chat"
+	FrameAttachment FrameKind = "attachment"
+	FrameAck        FrameKind = "ack"
+)
+
+// Frame 传输层的一帧，不同 Kind 使用不同的字段
+type Frame struct {
+	Kind     FrameKind `json:"kind"`
+	ID       string    `json:"id,omitempty"`
+	From     string    `json:"from,omitempty"`
+	To       string    `json:"to,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	FileName string    `json:"file_name,omitempty"`
+	Size     int64     `json:"size,omitempty"`
+	SentAt   time.Time `json:"sent_at,omitzero"`
+	Priority Priority  `json:"priority,omitempty"`
+	Error    string    `json:"error,omitempty"`
+
+	MessageID string `json:"message_id,omitempty"` // ok 应答中服务端分配的消息 ID
+}
+
+var ErrBadFrame = errors.New("无效的帧")
+
+// FrameFromPayload 把消息转换为帧
+func FrameFromPayload(p IPayload) (Frame, error) {
+	f := Frame{ID: p.ID(), From: p.From(), To: p.To(), Priority: p.Priority()}
+	switch msg := p.(type) {
+	case ChatMessage:
+		f.Kind = FrameChat
+		f.Text = msg.Text
+		f.SentAt = msg.SentAt
+	case Attachment:
+		f.Kind = FrameAttachment
+		f.FileName = msg.FileName
+		f.Size = msg.Size
+		f.SentAt = msg.SentAt
+	default:
+		return Frame{}, fmt.Errorf("%w: 不支持的消息类型 %T", ErrBadFrame, p)
+	}
+	return f, nil
+}
+
+// Payload 把 chat/attachment 帧还原为消息
+func (f Frame) Payload() (IPayload, error) {
+	env := Envelope{MessageID: f.ID, FromUserID: f.From, ToUserID: f.To, SentAt: f.SentAt, Prio: f.Priority}
+	switch f.Kind {
+	case FrameChat:
+		return ChatMessage{Envelope: env, Text: f.Text}, nil
+	case FrameAttachment:
+		return Attachment{Envelope: env, FileName: f.FileName, Size: f.Size}, nil
+	default:
+		return nil, fmt.Errorf("%w: 帧类型 %q 不携带消息", ErrBadFrame, f.Kind)
+	}
+}
+
+func errorFrame(err error) Frame {
+	return Frame{Kind: FrameError, Error: err.Error()}
+}
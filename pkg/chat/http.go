@@ -0,0 +1,271 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ============================================
+// HTTP 端点：用 SSE 把浏览器接入 ChatRouter
+// ============================================
+//
+// 标准库没有 WebSocket 实现，这里用 Server-Sent Events（SSE）代替：
+// 服务端 -> 浏览器走一条长连接的事件流，浏览器 -> 服务端走普通 POST。
+//
+// 路由：
+//   GET  /                       演示页面
+//   GET  /users/{id}/events      注册用户并订阅消息（text/event-stream）
+//   POST /users/{id}/messages    以 {id} 的身份发送消息，请求体为 Frame，返回服务端生成的消息 ID
+//   POST /users/{id}/acks        确认消息，请求体 {"id":"..."}
+//   GET  /stats                  路由器指标快照
+//
+// 事件流就是每个连接的"写泵"：收件箱里的消息逐条写出，
+// 空闲时定期写一行注释作为心跳（相当于 WebSocket 的 ping），
+// 写失败或浏览器断开时注销用户。
+
+const (
+	maxBodyBytes = 1 << 20
+	sseKeepAlive = 15 * time.Second
+)
+
+// SendResponse 发送成功后返回消息 ID
+type SendResponse struct {
+	ID string `json:"id"`
+}
+
+// AckRequest 确认请求
+type AckRequest struct {
+	ID string `json:"id"`
+}
+
+// ErrorResponse 统一的错误响应体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPHandler 把 ChatRouter 暴露为 HTTP/SSE 服务
+type HTTPHandler struct {
+	router *ChatRouter
+	mux    *http.ServeMux
+}
+
+// NewHTTPHandler 创建 HTTP 处理器，router 的 Run 由调用方负责启动
+func NewHTTPHandler(router *ChatRouter) *HTTPHandler {
+	h := &HTTPHandler{router: router, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /{$}", h.index)
+	h.mux.HandleFunc("GET /users/{id}/events", h.events)
+	h.mux.HandleFunc("POST /users/{id}/messages", h.send)
+	h.mux.HandleFunc("POST /users/{id}/acks", h.ack)
+	h.mux.HandleFunc("GET /stats", h.stats)
+	return h
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// events 注册用户，把收件箱中的消息以 SSE 事件推送给浏览器
+func (h *HTTPHandler) events(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+	inbox, err := h.router.Register(userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer func() {
+		// 停止读取后 Router 可能正阻塞在向收件箱投递上，
+		// 先在后台读空收件箱，Unregister 才能拿到锁
+		go func() {
+			for range inbox {
+			}
+		}()
+		h.router.Unregister(userID)
+	}()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case p, ok := <-inbox:
+			if !ok {
+				return // 路由器已关闭
+			}
+			err = writeEvent(w, rc, p)
+		case <-ticker.C:
+			err = writeSSE(w, rc, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// send 以路径中的用户身份发送一条消息
+func (h *HTTPHandler) send(w http.ResponseWriter, r *http.Request) {
+	var f Frame
+	if err := decodeJSON(w, r, &f); err != nil {
+		writeError(w, err)
+		return
+	}
+	// 消息 ID 总是由服务端生成：ack 和重投都按 ID 查找待确认消息，
+	// 采用客户端给的 ID 会让它覆盖别人还没确认的消息
+	env := newEnvelope(r.PathValue("id"), f.To)
+	f.ID, f.From, f.SentAt = env.MessageID, env.FromUserID, env.SentAt
+
+	p, err := f.Payload()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.router.Send(p); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, SendResponse{ID: p.ID()})
+}
+
+func (h *HTTPHandler) ack(w http.ResponseWriter, r *http.Request) {
+	var req AckRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.router.Ack(r.PathValue("id"), req.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) stats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.router.Stats())
+}
+
+func (h *HTTPHandler) index(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, indexHTML)
+}
+
+// ============================================
+// SSE 编码
+// ============================================
+
+// writeEvent 把消息编码为一个 SSE 事件：
+//
+//	id: <消息 ID>
+//	event: chat
+//	data: {"kind":"chat",...}
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, p IPayload) error {
+	f, err := FrameFromPayload(p)
+	if err != nil {
+		return nil // 无法编码的消息跳过，不断开连接
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil
+	}
+	return writeSSE(w, rc, fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", f.ID, f.Kind, data))
+}
+
+// writeSSE 写出并立即刷新，每次写都带超时，防止卡死的浏览器拖住 Router
+func writeSSE(w http.ResponseWriter, rc *http.ResponseController, s string) error {
+	rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := io.WriteString(w, s); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// ============================================
+// 编解码与错误映射
+// ============================================
+
+// decodeJSON 解码请求体，拒绝未知字段和多余内容
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("%w: 无效的 JSON: %v", ErrBadFrame, err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return fmt.Errorf("%w: 请求体只能包含一个 JSON 对象", ErrBadFrame)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), ErrorResponse{Error: err.Error()})
+}
+
+// statusFor 把路由错误映射为 HTTP 状态码
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrBadFrame),
+		errors.Is(err, ErrInvalidPayload):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnknownUser),
+		errors.Is(err, ErrUnknownMessage):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUserExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrRouterClosed):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// indexHTML 最小的浏览器客户端：EventSource 收消息，fetch 发消息
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>chat</title></head>
+<body>
+<input id="me" placeholder="我的 ID"> <button onclick="join()">登录</button><br>
+<input id="to" placeholder="发给"> <input id="text" placeholder="内容">
+<button onclick="send()">发送</button>
+<pre id="log"></pre>
+<script>
+let me = "";
+const log = s => document.getElementById("log").textContent += s + "\n";
+function join() {
+  me = document.getElementById("me").value;
+  const es = new EventSource("/users/" + encodeURIComponent(me) + "/events");
+  es.addEventListener("chat", e => {
+    const m = JSON.parse(e.data);
+    log(m.from + ": " + m.text);
+    fetch("/users/" + encodeURIComponent(me) + "/acks", {method: "POST", body: JSON.stringify({id: m.id})});
+  });
+  es.onerror = () => log("连接断开");
+}
+async function send() {
+  const body = {kind: "chat", to: document.getElementById("to").value, text: document.getElementById("text").value};
+  const resp = await fetch("/users/" + encodeURIComponent(me) + "/messages", {method: "POST", body: JSON.stringify(body)});
+  if (!resp.ok) log("发送失败: " + (await resp.json()).error);
+}
+</script>
+</body>
+</html>
+`
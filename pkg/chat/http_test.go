@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSendIgnoresClientID(t *testing.T) {
+	router := NewChatRouter(8)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		<-router.Done()
+	}()
+	go router.Run(ctx)
+
+	inbox, err := router.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHTTPHandler(router)
+
+	// 两次都带同一个客户端 ID，服务端必须各自生成新的 ID
+	seen := map[string]bool{}
+	for range 2 {
+		body := `{"kind":"chat","id":"dup","to":"bob","text":"hi"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/alice/messages", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("状态码 %d，期望 %d：%s", rec.Code, http.StatusAccepted, rec.Body)
+		}
+		var resp SendResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ID == "" || resp.ID == "dup" || seen[resp.ID] {
+			t.Fatalf("返回的消息 ID %q 不是服务端新生成的", resp.ID)
+		}
+		seen[resp.ID] = true
+	}
+
+	for range 2 {
+		select {
+		case p := <-inbox:
+			if !seen[p.ID()] {
+				t.Fatalf("收到的消息 ID %q 与发送响应不一致", p.ID())
+			}
+			if p.From() != "alice" {
+				t.Fatalf("From = %q，期望 alice", p.From())
+			}
+		case <-time.After(time.Second):
+			t.Fatal("等待消息超时")
+		}
+	}
+}
+
+func TestHTTPStatusMapping(t *testing.T) {
+	router := NewChatRouter(8)
+	h := NewHTTPHandler(router)
+	tests := []struct {
+		name, path, body string
+		want             int
+	}{
+		{"无效 JSON", "/users/alice/messages", `{`, http.StatusBadRequest},
+		{"未知字段", "/users/alice/messages", `{"kind":"chat","nope":1}`, http.StatusBadRequest},
+		{"接收方未注册", "/users/alice/messages", `{"kind":"chat","to":"nobody","text":"hi"}`, http.StatusNotFound},
+		{"确认不存在的消息", "/users/alice/acks", `{"id":"missing"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("状态码 %d，期望 %d：%s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
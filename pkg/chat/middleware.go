@@ -0,0 +1,122 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ============================================
+// 中间件：在 Send 与路由之间插入横切逻辑
+// ============================================
+//
+// 与 HTTP 中间件的写法一致：每个中间件接收下一个 Handler，返回包装后的 Handler。
+//
+//   Send(p) ──> Logging ──> Validation ──> ProfanityFilter ──> 入队路由
+//
+// 中间件可以：
+// - 直接返回错误拒绝消息（不再调用 next）
+// - 构造新的消息交给 next（例如替换文本）
+// - 在 next 前后做记录（例如日志、耗时）
+
+var ErrInvalidPayload = errors.New("消息校验失败")
+
+// Handler 处理一条提交给路由器的消息
+type Handler func(p IPayload) error
+
+// Middleware 包装 Handler
+type Middleware func(next Handler) Handler
+
+// WithMiddleware 为 Send 添加中间件，先添加的在最外层
+func WithMiddleware(mws ...Middleware) Option {
+	return func(r *ChatRouter) {
+		r.middlewares = append(r.middlewares, mws...)
+	}
+}
+
+// chain 从后往前包装，使第一个中间件最先执行
+func chain(h Handler, mws []Middleware) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// LoggingMiddleware 记录每条消息的发送结果与耗时
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(p IPayload) error {
+			start := time.Now()
+			err := next(p)
+			if err != nil {
+				logger.Printf("%s %s -> %s %s 失败: %v", p.Type(), p.From(), p.To(), p.ID(), err)
+				return err
+			}
+			logger.Printf("%s %s -> %s %s (%v)", p.Type(), p.From(), p.To(), p.ID(), time.Since(start))
+			return nil
+		}
+	}
+}
+
+// ValidationMiddleware 拒绝缺少字段或内容为空的消息
+func ValidationMiddleware(maxTextLen int) Middleware {
+	return func(next Handler) Handler {
+		return func(p IPayload) error {
+			if err := validate(p, maxTextLen); err != nil {
+				return err
+			}
+			return next(p)
+		}
+	}
+}
+
+func validate(p IPayload, maxTextLen int) error {
+	switch {
+	case p.ID() == "":
+		return fmt.Errorf("%w: 缺少消息 ID", ErrInvalidPayload)
+	case p.From() == "":
+		return fmt.Errorf("%w: 缺少发送方", ErrInvalidPayload)
+	case p.From() == p.To():
+		return fmt.Errorf("%w: 不能给自己发消息", ErrInvalidPayload)
+	}
+	switch msg := p.(type) {
+	case ChatMessage:
+		if strings.TrimSpace(msg.Text) == "" {
+			return fmt.Errorf("%w: 消息内容为空", ErrInvalidPayload)
+		}
+		if maxTextLen > 0 && utf8.RuneCountInString(msg.Text) > maxTextLen {
+			return fmt.Errorf("%w: 消息超过 %d 个字符", ErrInvalidPayload, maxTextLen)
+		}
+	case Attachment:
+		if msg.FileName == "" || msg.Size < 0 {
+			return fmt.Errorf("%w: 无效的附件 %q (%d 字节)", ErrInvalidPayload, msg.FileName, msg.Size)
+		}
+	}
+	return nil
+}
+
+// ProfanityFilterMiddleware 把文本消息中的敏感词替换为等长的 *
+func ProfanityFilterMiddleware(words ...string) Middleware {
+	pairs := make([]string, 0, 2*len(words))
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		pairs = append(pairs, w, strings.Repeat("*", utf8.RuneCountInString(w)))
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return func(next Handler) Handler {
+		return func(p IPayload) error {
+			if msg, ok := p.(ChatMessage); ok {
+				// ChatMessage 是值类型，修改的是副本，不影响调用方持有的消息
+				msg.Text = replacer.Replace(msg.Text)
+				p = msg
+			}
+			return next(p)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"time"
+
+	"c03/pkg/backoff"
+	"c03/pkg/clock"
+)
+
+// Option 配置 ChatRouter 的函数式选项
+type Option func(*ChatRouter)
+
+// WithAck 开启 ACK 与重投递：
+// 消息投递后 timeout 内未被 Ack，会重新投递到接收方收件箱，
+// 最多投递 maxAttempts 次（<= 0 表示不限次数），之后丢弃。
+func WithAck(timeout time.Duration, maxAttempts int) Option {
+	return func(r *ChatRouter) {
+		r.ackTimeout = timeout
+		r.maxAttempts = maxAttempts
+	}
+}
+
+// WithAckBackoff 让重投递的等待时间指数增长，需要同时使用 WithAck：
+// 第 n 次投递后等待 b.Delay(n-1) 再重投，接收方持续处理不过来时不会被重复消息淹没。
+// 超时检查仍然每隔 WithAck 的 timeout/2 进行一次，b.Initial 通常取同一个 timeout。
+func WithAckBackoff(b backoff.Backoff) Option {
+	return func(r *ChatRouter) {
+		r.ackBackoff = &b
+	}
+}
+
+// WithClock 替换重投递使用的时钟，测试中传入 clock.FakeClock，
+// 用 Advance 触发超时而不必真的等待 ackTimeout
+func WithClock(c clock.Clock) Option {
+	return func(r *ChatRouter) {
+		r.clock = clock.Or(c)
+	}
+}
@@ -0,0 +1,113 @@
+package chat
+
+import (
+	"maps"
+	"time"
+)
+
+// ============================================
+// 收件箱溢出策略
+// ============================================
+//
+// 每个收件箱都是有界的（容量 inboxSize）。消费方太慢、收件箱已满时：
+//
+//   OverflowBlock      阻塞等待消费方取走（默认）；设置了超时则超时后丢弃新消息
+//   OverflowDropNewest 立即丢弃新消息
+//   OverflowDropOldest 丢弃收件箱中最旧的一条，为新消息腾出位置
+//
+// 无限期阻塞时，一个不读收件箱的消费方会卡住整个 Run 循环，
+// 其他用户也收不到消息；另外两种策略以丢消息为代价保证路由不被卡住。
+// 被丢弃的消息不会再被重投递，丢弃次数可以通过 Drops() 查看。
+
+// OverflowPolicy 收件箱已满时的处理方式
+type OverflowPolicy int
+
+const (
+	OverflowBlock OverflowPolicy = iota
+	OverflowDropNewest
+	OverflowDropOldest
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// WithOverflow 设置收件箱溢出策略
+// blockTimeout 只对 OverflowBlock 生效，<= 0 表示无限期等待
+func WithOverflow(policy OverflowPolicy, blockTimeout time.Duration) Option {
+	return func(r *ChatRouter) {
+		r.overflow = policy
+		r.blockTimeout = blockTimeout
+	}
+}
+
+// Drops 返回每个用户被丢弃的消息数（快照）
+func (r *ChatRouter) Drops() map[string]uint64 {
+	r.dropMu.Lock()
+	defer r.dropMu.Unlock()
+	return maps.Clone(r.drops)
+}
+
+// push 按溢出策略把消息放入收件箱，p 被丢弃时返回 false
+// 调用方持有 usersMu 读锁，保证 inbox 不会在此期间被关闭
+func (r *ChatRouter) push(inbox chan IPayload, p IPayload) bool {
+	select {
+	case inbox <- p:
+		return true
+	default:
+	}
+
+	switch r.overflow {
+	case OverflowDropNewest:
+		r.drop(p)
+		return false
+
+	case OverflowDropOldest:
+		if cap(inbox) == 0 {
+			// 无缓冲的收件箱里没有可以丢弃的旧消息
+			r.drop(p)
+			return false
+		}
+		// 只有 Run 向收件箱发送，腾出一个位置后再发送一定成功；
+		// 但消费方可能恰好先取走了消息，所以腾位置时也不能阻塞
+		select {
+		case old := <-inbox:
+			r.drop(old)
+			r.forget(old.ID())
+		default:
+		}
+		inbox <- p
+		return true
+
+	default:
+		if r.blockTimeout <= 0 {
+			inbox <- p
+			return true
+		}
+		timer := time.NewTimer(r.blockTimeout)
+		defer timer.Stop()
+		select {
+		case inbox <- p:
+			return true
+		case <-timer.C:
+			r.stats(func(c *counters) { c.timeouts++ })
+			r.drop(p)
+			return false
+		}
+	}
+}
+
+func (r *ChatRouter) drop(p IPayload) {
+	r.dropMu.Lock()
+	defer r.dropMu.Unlock()
+	r.drops[p.To()]++
+}
@@ -0,0 +1,124 @@
+// ============================================
+// chat 包：基于 channel 的多用户聊天路由
+// ============================================
+//
+// 本包演示如何用 channel 组织一个消息系统：
+// - IPayload：所有消息的公共接口（聊天文本、附件……）
+// - ChatRouter：维护用户注册表，每个用户一个收件箱 channel，
+//   按 ToUserID 把消息投递到对应收件箱
+//
+// 设计原则：
+// 1. 不要通过共享内存来通信，而要通过通信来共享内存
+// 2. 收件箱 channel 由 Router 创建，也由 Router 关闭（拥有者负责关闭）
+// 3. 消费方只拿到只读 channel（<-chan），无法误关闭
+// ============================================
+
+package chat
+
+import (
+	"time"
+
+	"c03/pkg/idgen"
+)
+
+// PayloadType 消息类型
+type PayloadType string
+
+const (
+	PayloadChat       PayloadType = "chat"
+	PayloadAttachment PayloadType = "attachment"
+)
+
+// Priority 消息优先级，Router 总是先投递高优先级的消息
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityHigh
+)
+
+// IPayload 所有可路由消息的接口
+type IPayload interface {
+	ID() string // 消息 ID，ACK 与去重都依赖它
+	Type() PayloadType
+	From() string // 发送方用户 ID
+	To() string   // 接收方用户 ID，Router 据此路由
+	Priority() Priority
+}
+
+// Envelope 消息的公共头部，嵌入到具体消息类型中即可获得 ID/From/To/Priority 方法
+type Envelope struct {
+	MessageID  string    `json:"id"`
+	FromUserID string    `json:"from"`
+	ToUserID   string    `json:"to"`
+	SentAt     time.Time `json:"sent_at"`
+	Prio       Priority  `json:"priority,omitempty"`
+}
+
+func (e Envelope) ID() string {
+	return e.MessageID
+}
+
+func (e Envelope) From() string {
+	return e.FromUserID
+}
+
+func (e Envelope) To() string {
+	return e.ToUserID
+}
+
+func (e Envelope) Priority() Priority {
+	return e.Prio
+}
+
+// ChatMessage 文本聊天消息
+type ChatMessage struct {
+	Envelope
+	Text string `json:"text"`
+}
+
+func (m ChatMessage) Type() PayloadType {
+	return PayloadChat
+}
+
+// Attachment 附件消息（只携带元信息，不携带文件内容）
+type Attachment struct {
+	Envelope
+	FileName string `json:"file_name"`
+	Size     int64  `json:"size"`
+}
+
+func (a Attachment) Type() PayloadType {
+	return PayloadAttachment
+}
+
+// NewChatMessage 创建文本消息
+func NewChatMessage(from, to, text string) ChatMessage {
+	return ChatMessage{
+		Envelope: newEnvelope(from, to),
+		Text:     text,
+	}
+}
+
+// NewAttachment 创建附件消息
+func NewAttachment(from, to, fileName string, size int64) Attachment {
+	return Attachment{
+		Envelope: newEnvelope(from, to),
+		FileName: fileName,
+		Size:     size,
+	}
+}
+
+func newEnvelope(from, to string) Envelope {
+	return Envelope{
+		MessageID:  idgen.NewUUID(),
+		FromUserID: from,
+		ToUserID:   to,
+		SentAt:     time.Now(),
+	}
+}
+
+var (
+	_ IPayload = ChatMessage{}
+	_ IPayload = Attachment{}
+)
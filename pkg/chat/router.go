@@ -0,0 +1,266 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"c03/pkg/backoff"
+	"c03/pkg/clock"
+)
+
+// ============================================
+// ChatRouter：多用户消息路由
+// ============================================
+//
+//   Send(p) ──> high / in ──> Run() ──route by To()──> users[to] (收件箱)
+//
+// 优先级：高优先级消息走单独的 high 通道。Run 每轮先非阻塞地检查 high，
+// 只有 high 为空时才在两个通道上一起 select（select 在多个就绪分支间是随机的，
+// 不先检查 high 就无法保证偏向）。积压时高优先级消息总是先被投递。
+//
+// 锁的划分：
+// - sendMu 保护 high/in 与 closed，保证关闭之后不会再向它们发送（向已关闭 channel 发送会 panic）
+// - usersMu 保护用户注册表，Run 投递时只持有读锁
+// 两把锁分开，避免 Send 阻塞在 in 上时把 Run 也一起卡住。
+//
+// 生命周期由调用方传给 Run 的 context 控制：
+// ctx 取消后不再接收新消息，已进入 in 的消息会被投递完（drain），
+// 随后关闭所有收件箱并关闭 Done() 返回的 channel。
+
+var (
+	ErrUserExists     = errors.New("用户已注册")
+	ErrUnknownUser    = errors.New("用户未注册")
+	ErrRouterClosed   = errors.New("路由器已关闭")
+	ErrUnknownMessage = errors.New("消息不存在或已确认")
+)
+
+// ChatRouter 按 ToUserID 路由消息
+type ChatRouter struct {
+	inboxSize int
+
+	sendMu   sync.RWMutex
+	in       chan IPayload
+	high     chan IPayload // 高优先级通道
+	closed   bool
+	stopping chan struct{} // ctx 取消时关闭，唤醒阻塞在 Send 中的调用方
+	done     chan struct{} // Run 完全退出后关闭
+
+	usersMu sync.RWMutex
+	users   map[string]chan IPayload
+
+	middlewares []Middleware
+	handler     Handler // 中间件包装后的 enqueue
+
+	overflow     OverflowPolicy
+	blockTimeout time.Duration
+	dropMu       sync.Mutex
+	drops        map[string]uint64
+
+	statsMu  sync.Mutex
+	counters counters
+
+	ackTimeout  time.Duration
+	ackBackoff  *backoff.Backoff // 每次投递后的确认等待时间，默认固定为 ackTimeout
+	maxAttempts int
+	pendingMu   sync.Mutex
+	pending     map[string]*pendingMsg
+
+	clock clock.Clock // 重投递计时，默认 clock.Real
+}
+
+// NewChatRouter 创建路由器，inboxSize 是每个用户收件箱的缓冲大小
+func NewChatRouter(inboxSize int, opts ...Option) *ChatRouter {
+	r := &ChatRouter{
+		inboxSize: inboxSize,
+		in:        make(chan IPayload, inboxSize),
+		high:      make(chan IPayload, inboxSize),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+		users:     make(map[string]chan IPayload),
+		pending:   make(map[string]*pendingMsg),
+		drops:     make(map[string]uint64),
+		counters:  counters{routed: make(map[PayloadType]uint64)},
+		clock:     clock.Real,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.ackBackoff == nil {
+		r.ackBackoff = &backoff.Backoff{Initial: r.ackTimeout, Multiplier: 1}
+	}
+	r.handler = chain(r.enqueue, r.middlewares)
+	return r
+}
+
+// Register 注册用户，返回该用户的只读收件箱
+// 路由器关闭或用户被注销时，收件箱会被关闭，消费方可以直接 range
+func (r *ChatRouter) Register(userID string) (<-chan IPayload, error) {
+	r.sendMu.RLock()
+	defer r.sendMu.RUnlock()
+	if r.closed {
+		return nil, ErrRouterClosed
+	}
+
+	r.usersMu.Lock()
+	defer r.usersMu.Unlock()
+	if _, ok := r.users[userID]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrUserExists, userID)
+	}
+	inbox := make(chan IPayload, r.inboxSize)
+	r.users[userID] = inbox
+	return inbox, nil
+}
+
+// Unregister 注销用户并关闭其收件箱
+func (r *ChatRouter) Unregister(userID string) error {
+	r.usersMu.Lock()
+	defer r.usersMu.Unlock()
+
+	inbox, ok := r.users[userID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownUser, userID)
+	}
+	delete(r.users, userID)
+	close(inbox)
+	r.forgetUser(userID)
+	return nil
+}
+
+// Users 返回当前在线用户数
+func (r *ChatRouter) Users() int {
+	r.usersMu.RLock()
+	defer r.usersMu.RUnlock()
+	return len(r.users)
+}
+
+// Send 提交一条消息，接收方必须已注册
+// 消息先经过 WithMiddleware 配置的中间件，任一中间件返回错误则不会入队
+func (r *ChatRouter) Send(p IPayload) error {
+	return r.handler(p)
+}
+
+// enqueue 把消息放入对应优先级的通道，是中间件链的终点
+func (r *ChatRouter) enqueue(p IPayload) error {
+	r.sendMu.RLock()
+	defer r.sendMu.RUnlock()
+	if r.closed {
+		return ErrRouterClosed
+	}
+	if !r.registered(p.To()) {
+		return fmt.Errorf("%w: %s", ErrUnknownUser, p.To())
+	}
+	lane := r.in
+	if p.Priority() >= PriorityHigh {
+		lane = r.high
+	}
+	select {
+	case lane <- p:
+		return nil
+	case <-r.stopping:
+		return ErrRouterClosed
+	}
+}
+
+// Done 返回一个 channel，Run 完全退出（消息已 drain、收件箱已关闭）后被关闭
+func (r *ChatRouter) Done() <-chan struct{} {
+	return r.done
+}
+
+// Run 路由循环，阻塞直到 ctx 被取消且 high/in 中的消息全部投递完毕
+// 退出前关闭所有收件箱，通知消费方不会再有新消息
+func (r *ChatRouter) Run(ctx context.Context) {
+	defer close(r.done)
+
+	// 未开启 ACK 时 redeliverC 为 nil，select 永远不会选中它
+	var redeliverC <-chan time.Time
+	if r.ackTimeout > 0 {
+		ticker := r.clock.NewTicker(r.ackTimeout / 2)
+		defer ticker.Stop()
+		redeliverC = ticker.C()
+	}
+
+	for {
+		select {
+		case p := <-r.high:
+			r.route(p)
+			continue
+		case <-ctx.Done():
+			r.shutdown()
+			return
+		default:
+		}
+
+		select {
+		case p := <-r.high:
+			r.route(p)
+		case p := <-r.in:
+			r.route(p)
+		case <-redeliverC:
+			// 用时钟的当前时间而不是 tick 携带的时间：
+			// 假时钟一次推进多个周期时，积压的 tick 仍然按推进后的时间判断超时
+			r.redeliver(r.clock.Now())
+		case <-ctx.Done():
+			r.shutdown()
+			return
+		}
+	}
+}
+
+// shutdown 停止接收新消息，投递完剩余消息后关闭所有收件箱
+func (r *ChatRouter) shutdown() {
+	// 先唤醒阻塞在 Send 中的调用方，它们释放读锁后这里才能拿到写锁
+	close(r.stopping)
+
+	r.sendMu.Lock()
+	r.closed = true
+	close(r.high)
+	close(r.in)
+	r.sendMu.Unlock()
+
+	for p := range r.high {
+		r.route(p)
+	}
+	for p := range r.in {
+		r.route(p)
+	}
+
+	r.usersMu.Lock()
+	defer r.usersMu.Unlock()
+	for id, inbox := range r.users {
+		close(inbox)
+		delete(r.users, id)
+	}
+}
+
+// route 首次投递一条消息；接收方在投递前注销、或被溢出策略丢弃的消息不再跟踪
+// 先登记再投递，避免消费方在登记之前就 Ack
+func (r *ChatRouter) route(p IPayload) {
+	r.track(p)
+	if !r.deliver(p) {
+		r.forget(p.ID())
+		return
+	}
+	r.stats(func(c *counters) { c.routed[p.Type()]++ })
+}
+
+// deliver 把消息放入接收方收件箱，接收方不存在或消息按溢出策略被丢弃时返回 false
+func (r *ChatRouter) deliver(p IPayload) bool {
+	r.usersMu.RLock()
+	defer r.usersMu.RUnlock()
+
+	inbox, ok := r.users[p.To()]
+	if !ok {
+		return false
+	}
+	return r.push(inbox, p)
+}
+
+func (r *ChatRouter) registered(userID string) bool {
+	r.usersMu.RLock()
+	defer r.usersMu.RUnlock()
+	_, ok := r.users[userID]
+	return ok
+}
@@ -0,0 +1,162 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startRouter 启动 Run，测试结束时取消并等待退出
+func startRouter(t *testing.T, r *ChatRouter) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	go r.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-r.Done()
+	})
+}
+
+func newMessage(from, to, text string, prio Priority) ChatMessage {
+	m := NewChatMessage(from, to, text)
+	m.Prio = prio
+	return m
+}
+
+// waitFor 让出 CPU 直到 cond 成立（等的是其他 goroutine 的进展，不是时间流逝），超时则测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待 %s 超时", what)
+		}
+		runtime.Gosched()
+	}
+}
+
+func receive(t *testing.T, inbox <-chan IPayload) ChatMessage {
+	t.Helper()
+	select {
+	case p := <-inbox:
+		return p.(ChatMessage)
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待消息超时")
+		return ChatMessage{}
+	}
+}
+
+func TestRouterHighPriorityFirstUnderLoad(t *testing.T) {
+	const senders, perSender = 8, 200
+	total := senders * perSender
+	r := NewChatRouter(total)
+	inbox, err := r.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run 启动前多个发送方并发交替发送普通和高优先级消息，两条通道都积压着消息
+	var wg sync.WaitGroup
+	for s := range senders {
+		wg.Go(func() {
+			for i := range perSender {
+				prio := PriorityNormal
+				if i%2 == 1 {
+					prio = PriorityHigh
+				}
+				if err := r.Send(newMessage(fmt.Sprint(s), "bob", fmt.Sprint(i), prio)); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	startRouter(t, r)
+
+	// 所有高优先级消息先于任何普通消息投递；同一通道内每个发送方的消息保持顺序
+	last := map[string]int{}
+	for n := range total {
+		m := receive(t, inbox)
+		wantPrio := PriorityHigh
+		if n >= total/2 {
+			wantPrio = PriorityNormal
+		}
+		if m.Priority() != wantPrio {
+			t.Fatalf("第 %d 条消息优先级 %v，期望 %v", n, m.Priority(), wantPrio)
+		}
+		var seq int
+		fmt.Sscan(m.Text, &seq)
+		key := fmt.Sprint(m.From(), m.Priority())
+		if prev, ok := last[key]; ok && seq <= prev {
+			t.Fatalf("发送方 %s 的消息 %d 排在 %d 之后", m.From(), seq, prev)
+		}
+		last[key] = seq
+	}
+}
+
+func TestRouterHighPriorityOvertakesBacklog(t *testing.T) {
+	// 收件箱和通道都只能放 1 条：接收方不读时 Router 阻塞在投递上，后面的消息排队
+	r := NewChatRouter(1)
+	inbox, err := r.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	startRouter(t, r)
+
+	send := func(text string, prio Priority) {
+		t.Helper()
+		if err := r.Send(newMessage("alice", "bob", text, prio)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("m1", PriorityNormal)
+	waitFor(t, "m1 进入收件箱", func() bool { return len(inbox) == 1 })
+	send("m2", PriorityNormal)
+	waitFor(t, "Router 取走 m2 并阻塞在投递上", func() bool { return len(r.in) == 0 })
+	send("n3", PriorityNormal)
+	send("h", PriorityHigh)
+
+	var got []string
+	for range 4 {
+		got = append(got, receive(t, inbox).Text)
+	}
+	if fmt.Sprint(got) != "[m1 m2 h n3]" {
+		t.Fatalf("投递顺序 %v，期望高优先级的 h 越过排队中的 n3：[m1 m2 h n3]", got)
+	}
+}
+
+func TestRouterShutdownDrainsBothLanes(t *testing.T) {
+	r := NewChatRouter(16)
+	inbox, err := r.Register("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 4 {
+		prio := PriorityNormal
+		if i%2 == 0 {
+			prio = PriorityHigh
+		}
+		if err := r.Send(newMessage("alice", "bob", fmt.Sprint(i), prio)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ctx 已经取消：Run 仍要投递完积压的消息，高优先级在前，然后关闭收件箱
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Run(ctx)
+
+	var got []string
+	for p := range inbox {
+		got = append(got, p.(ChatMessage).Text)
+	}
+	if fmt.Sprint(got) != "[0 2 1 3]" {
+		t.Fatalf("关闭时投递 %v，期望 [0 2 1 3]", got)
+	}
+	if err := r.Send(newMessage("alice", "bob", "late", PriorityHigh)); err == nil {
+		t.Fatal("关闭后 Send 应返回错误")
+	}
+}
@@ -0,0 +1,238 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ============================================
+// Server：把 ChatRouter 暴露到 TCP 上
+// ============================================
+//
+// 每个连接对应一个用户：
+//
+//   client ──login──> 注册用户 ──ok──> client
+//   client ──chat/attachment──> Router.Send ──ok(message_id)/error──> client
+//   client ──ack──> Router.Ack ──ok/error──> client
+//   收件箱 ──writer goroutine──> client
+//
+// 连接断开时注销用户，收件箱随之关闭，writer goroutine 退出。
+// 两个 goroutine 都会写连接，写操作用 connWriter 串行化。
+
+const (
+	loginTimeout = 5 * time.Second
+	writeTimeout = 5 * time.Second // 客户端长时间不读时放弃，避免拖住 Router
+)
+
+// Server TCP 聊天服务端
+type Server struct {
+	router *ChatRouter
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewServer 创建服务端，router 的 Run 由调用方负责启动
+func NewServer(router *ChatRouter) *Server {
+	return &Server{
+		router: router,
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe 监听 addr 并处理连接，直到 ctx 被取消
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在 ln 上接受连接，直到 ctx 被取消
+// 返回前关闭监听器与所有连接，并等待连接处理 goroutine 退出
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() {
+		ln.Close()
+		s.closeConns()
+	})
+	defer stop()
+
+	var err error
+	for {
+		conn, acceptErr := ln.Accept()
+		if acceptErr != nil {
+			if ctx.Err() == nil {
+				err = acceptErr
+			}
+			break
+		}
+		if !s.track(conn) {
+			conn.Close()
+			continue
+		}
+		go s.handle(conn)
+	}
+
+	ln.Close()
+	s.closeConns()
+	s.wg.Wait()
+	return err
+}
+
+// track 登记连接；ctx 已取消（closeConns 之后）时返回 false
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	s.wg.Done()
+}
+
+// closeConns 关闭所有连接，此后不再接受新连接
+func (s *Server) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// handle 处理一个连接的完整生命周期
+func (s *Server) handle(conn net.Conn) {
+	defer s.untrack(conn)
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	w := newConnWriter(conn)
+
+	userID, inbox, err := s.login(conn, dec)
+	if err != nil {
+		w.write(errorFrame(err))
+		return
+	}
+	w.write(Frame{Kind: FrameOK})
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		s.pump(conn, w, inbox)
+	}()
+
+	for {
+		var f Frame
+		if err := dec.Decode(&f); err != nil {
+			break
+		}
+		// 应答带上请求的 ID，客户端据此匹配请求与应答
+		reply := Frame{Kind: FrameOK, ID: f.ID}
+		msgID, err := s.dispatch(userID, f)
+		if err != nil {
+			reply = errorFrame(err)
+			reply.ID = f.ID
+		}
+		reply.MessageID = msgID
+		w.write(reply)
+	}
+
+	// 路由器关闭时用户可能已被注销，忽略错误
+	s.router.Unregister(userID)
+	<-writerDone
+}
+
+// login 读取第一帧并注册用户
+func (s *Server) login(conn net.Conn, dec *json.Decoder) (string, <-chan IPayload, error) {
+	conn.SetReadDeadline(time.Now().Add(loginTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var f Frame
+	if err := dec.Decode(&f); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrBadFrame, err)
+	}
+	if f.Kind != FrameLogin || f.From == "" {
+		return "", nil, fmt.Errorf("%w: 第一帧必须是带 from 的 login", ErrBadFrame)
+	}
+	inbox, err := s.router.Register(f.From)
+	if err != nil {
+		return "", nil, err
+	}
+	return f.From, inbox, nil
+}
+
+// dispatch 处理客户端发来的一帧，发送消息时返回服务端分配的消息 ID
+func (s *Server) dispatch(userID string, f Frame) (string, error) {
+	switch f.Kind {
+	case FrameChat, FrameAttachment:
+		// 消息 ID 与发送方都由服务端决定：ack 和重投都按 ID 查找待确认消息，
+		// 采用客户端给的 ID 会让它覆盖别人还没确认的消息；发送方以登录用户为准，防止冒名。
+		// 帧上原来的 ID 只用于匹配应答，由调用方保留
+		env := newEnvelope(userID, f.To)
+		f.ID, f.From, f.SentAt = env.MessageID, env.FromUserID, env.SentAt
+		p, err := f.Payload()
+		if err != nil {
+			return "", err
+		}
+		if err := s.router.Send(p); err != nil {
+			return "", err
+		}
+		return p.ID(), nil
+	case FrameAck:
+		return "", s.router.Ack(userID, f.ID)
+	default:
+		return "", fmt.Errorf("%w: 不支持的帧类型 %q", ErrBadFrame, f.Kind)
+	}
+}
+
+// pump 把收件箱中的消息写到连接上，直到收件箱被关闭
+// 写失败后关闭连接让读循环退出，但仍要继续读空收件箱，否则 Router 会阻塞在投递上
+func (s *Server) pump(conn net.Conn, w *connWriter, inbox <-chan IPayload) {
+	broken := false
+	for p := range inbox {
+		if broken {
+			continue
+		}
+		f, err := FrameFromPayload(p)
+		if err != nil {
+			continue
+		}
+		if err := w.write(f); err != nil {
+			broken = true
+			conn.Close()
+		}
+	}
+}
+
+// connWriter 串行化对同一连接的写操作，每次写都带超时
+type connWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+	enc  *json.Encoder
+}
+
+func newConnWriter(conn net.Conn) *connWriter {
+	return &connWriter{conn: conn, enc: json.NewEncoder(conn)}
+}
+
+func (w *connWriter) write(f Frame) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return w.enc.Encode(f)
+}
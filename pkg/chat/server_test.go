@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// startServer 在回环地址上启动 Server，测试结束时关闭
+func startServer(t *testing.T, r *ChatRouter) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(r).Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve 返回错误: %v", err)
+		}
+	})
+	return ln.Addr().String()
+}
+
+func dial(t *testing.T, addr, userID string) *Client {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, addr, userID)
+	if err != nil {
+		t.Fatalf("%s 登录失败: %v", userID, err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestServerIgnoresClientMessageID(t *testing.T) {
+	r := NewChatRouter(4, WithAck(time.Hour, 0))
+	startRouter(t, r)
+	addr := startServer(t, r)
+
+	bob := dial(t, addr, "bob")
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// 两个发送方使用同一个消息 ID，后一条不能覆盖前一条的待确认记录
+	ids := map[string]string{}
+	for _, from := range []string{"alice", "carol"} {
+		msg := NewChatMessage(from, "bob", "来自 "+from)
+		msg.MessageID = "dup"
+		id, err := dial(t, addr, from).Send(ctx, msg)
+		if err != nil {
+			t.Fatalf("%s 发送失败: %v", from, err)
+		}
+		if id == "" || id == "dup" {
+			t.Fatalf("%s 的消息 ID = %q，期望服务端分配的新 ID", from, id)
+		}
+		ids[from] = id
+	}
+	if ids["alice"] == ids["carol"] {
+		t.Fatalf("两条消息得到相同的 ID %q", ids["alice"])
+	}
+
+	for range 2 {
+		m := receive(t, bob.Messages())
+		if m.ID() != ids[m.From()] {
+			t.Fatalf("收到 %s 的消息 ID = %q，期望 %q", m.From(), m.ID(), ids[m.From()])
+		}
+	}
+	if n := r.Pending(); n != 2 {
+		t.Fatalf("Pending() = %d，期望 2", n)
+	}
+
+	if err := bob.Ack(ctx, "dup"); !errors.Is(err, ErrServer) {
+		t.Fatalf("确认客户端给的 ID: err = %v，期望 ErrServer", err)
+	}
+	for _, from := range []string{"alice", "carol"} {
+		if err := bob.Ack(ctx, ids[from]); err != nil {
+			t.Fatalf("确认 %s 的消息失败: %v", from, err)
+		}
+	}
+	if n := r.Pending(); n != 0 {
+		t.Fatalf("全部确认后 Pending() = %d，期望 0", n)
+	}
+}
@@ -0,0 +1,70 @@
+package chat
+
+import (
+	"expvar"
+	"maps"
+)
+
+// ============================================
+// 运行指标
+// ============================================
+//
+// 计数器在路由的各个环节累加，Stats() 返回某一时刻的快照：
+//   route      首次投递成功 -> Routed[类型]++
+//   redeliver  重投递成功   -> Redelivered++；超过最大次数 -> Expired++
+//   push       阻塞超时     -> Timeouts++；按策略丢弃 -> Dropped（见 Drops）
+//
+// 快照可以通过 HTTPHandler 的 GET /stats 查看，
+// 也可以用 PublishExpvar 注册到 expvar，与 /debug/vars 中的其他变量一起导出。
+
+// Stats 路由器指标快照
+type Stats struct {
+	ActiveUsers int                    `json:"active_users"`
+	Pending     int                    `json:"pending"`
+	Routed      map[PayloadType]uint64 `json:"routed"`
+	Redelivered uint64                 `json:"redelivered"`
+	Expired     uint64                 `json:"expired"`
+	Timeouts    uint64                 `json:"timeouts"`
+	Dropped     uint64                 `json:"dropped"`
+}
+
+// counters 路由器内部的累计计数
+type counters struct {
+	routed      map[PayloadType]uint64
+	redelivered uint64
+	expired     uint64
+	timeouts    uint64
+}
+
+// Stats 返回当前指标快照
+func (r *ChatRouter) Stats() Stats {
+	s := Stats{
+		ActiveUsers: r.Users(),
+		Pending:     r.Pending(),
+	}
+
+	r.statsMu.Lock()
+	s.Routed = maps.Clone(r.counters.routed)
+	s.Redelivered = r.counters.redelivered
+	s.Expired = r.counters.expired
+	s.Timeouts = r.counters.timeouts
+	r.statsMu.Unlock()
+
+	for _, n := range r.Drops() {
+		s.Dropped += n
+	}
+	return s
+}
+
+// PublishExpvar 把指标注册为 expvar 变量 name，每次读取时实时计算
+// 与 expvar.Publish 一样，重复注册同一个 name 会 panic
+func (r *ChatRouter) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return r.Stats() }))
+}
+
+// stats 修改计数器
+func (r *ChatRouter) stats(update func(c *counters)) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	update(&r.counters)
+}
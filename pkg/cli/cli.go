@@ -0,0 +1,200 @@
+// ============================================
+// cli 包：子命令式命令行框架
+// ============================================
+//
+// 像 go build / git commit 一样，一个程序提供多个子命令：
+//
+//   app := &cli.App{Name: "toolbox", Commands: []*cli.Command{crawl, logstat}}
+//   os.Exit(app.Run(ctx, os.Args[1:]))
+//
+// - 每个子命令有独立的 flag.FlagSet，flag 只在该子命令后面解析
+// - 自动生成帮助：app help、app help <cmd>、app <cmd> -h
+// - 退出码约定：0 成功，1 运行出错，2 用法错误（未知命令、flag 错误、ErrUsage）
+// - 命令名拼错时提示最接近的命令（见 pkg/strsim）
+//
+// 终端进度条见 progress.go。
+// ============================================
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"c03/pkg/strsim"
+)
+
+// 退出码
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// ErrUsage Run 返回包装了它的错误时，打印该命令的用法并以 ExitUsage 退出
+var ErrUsage = errors.New("用法错误")
+
+// Command 一个子命令
+type Command struct {
+	Name  string
+	Args  string // 用法中 flag 之后的部分，如 "[flags] <url>"
+	Short string // 一行说明，显示在命令列表中
+	Long  string // 详细说明，显示在 help <cmd> 中
+
+	// SetFlags 在解析前注册 flag，可以为 nil
+	SetFlags func(fs *flag.FlagSet)
+	// Run 执行命令，args 是解析 flag 之后剩余的参数
+	Run func(ctx context.Context, args []string) error
+}
+
+// App 一组子命令
+type App struct {
+	Name     string
+	Short    string
+	Commands []*Command
+
+	Stdout io.Writer // 为 nil 时使用 os.Stdout
+	Stderr io.Writer // 为 nil 时使用 os.Stderr
+}
+
+// Usagef 生成包装 ErrUsage 的错误
+func Usagef(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUsage, fmt.Sprintf(format, args...))
+}
+
+// Run 根据 args[0] 选择子命令并执行，返回退出码
+func (a *App) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		a.printUsage(a.stderr())
+		return ExitUsage
+	}
+
+	name, rest := args[0], args[1:]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if name == "help" && len(rest) > 0 {
+			cmd := a.find(rest[0])
+			if cmd == nil {
+				fmt.Fprintf(a.stderr(), "%s: 未知命令 %q\n", a.Name, rest[0])
+				a.suggest(rest[0])
+				return ExitUsage
+			}
+			cmd.printHelp(a.Name, a.newFlagSet(cmd), a.stdout())
+			return ExitOK
+		}
+		a.printUsage(a.stdout())
+		return ExitOK
+	}
+
+	cmd := a.find(name)
+	if cmd == nil {
+		fmt.Fprintf(a.stderr(), "%s: 未知命令 %q\n", a.Name, name)
+		a.suggest(name)
+		fmt.Fprintf(a.stderr(), "运行 '%s help' 查看可用命令\n", a.Name)
+		return ExitUsage
+	}
+
+	fs := a.newFlagSet(cmd)
+	if err := fs.Parse(rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK // -h 已经由 fs.Usage 打印了帮助
+		}
+		return ExitUsage // 错误信息和用法已经由 FlagSet 打印
+	}
+
+	if err := cmd.Run(ctx, fs.Args()); err != nil {
+		fmt.Fprintf(a.stderr(), "%s %s: %v\n", a.Name, cmd.Name, err)
+		if errors.Is(err, ErrUsage) {
+			fmt.Fprintf(a.stderr(), "用法: %s %s %s\n", a.Name, cmd.Name, cmd.Args)
+			return ExitUsage
+		}
+		return ExitError
+	}
+	return ExitOK
+}
+
+func (a *App) find(name string) *Command {
+	i := slices.IndexFunc(a.Commands, func(c *Command) bool { return c.Name == name })
+	if i < 0 {
+		return nil
+	}
+	return a.Commands[i]
+}
+
+// suggest 命令名拼错时提示最接近的命令，如 "crwal" -> "crawl"
+func (a *App) suggest(name string) {
+	names := make([]string, 0, len(a.Commands)+1)
+	for _, c := range a.Commands {
+		names = append(names, c.Name)
+	}
+	names = append(names, "help")
+	if best, ok := strsim.Closest(names, name); ok {
+		fmt.Fprintf(a.stderr(), "你是不是想输入 '%s %s'？\n", a.Name, best)
+	}
+}
+
+// newFlagSet 为 cmd 创建 FlagSet；ContinueOnError 让 Run 能返回退出码而不是直接 os.Exit
+func (a *App) newFlagSet(cmd *Command) *flag.FlagSet {
+	fs := flag.NewFlagSet(a.Name+" "+cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr())
+	if cmd.SetFlags != nil {
+		cmd.SetFlags(fs)
+	}
+	fs.Usage = func() { cmd.printHelp(a.Name, fs, fs.Output()) }
+	return fs
+}
+
+func (a *App) printUsage(w io.Writer) {
+	if a.Short != "" {
+		fmt.Fprintf(w, "%s - %s\n\n", a.Name, a.Short)
+	}
+	fmt.Fprintf(w, "用法: %s <命令> [参数]\n\n命令:\n", a.Name)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range a.Commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.Name, c.Short)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n运行 '%s help <命令>' 查看命令的详细说明\n", a.Name)
+}
+
+func (c *Command) printHelp(app string, fs *flag.FlagSet, w io.Writer) {
+	fmt.Fprintf(w, "用法: %s %s %s\n", app, c.Name, c.Args)
+	desc := c.Long
+	if desc == "" {
+		desc = c.Short
+	}
+	if desc != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(desc))
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(w, "\n选项:")
+		out := fs.Output()
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+		fs.SetOutput(out)
+	}
+}
+
+func (a *App) stdout() io.Writer {
+	if a.Stdout != nil {
+		return a.Stdout
+	}
+	return os.Stdout
+}
+
+func (a *App) stderr() io.Writer {
+	if a.Stderr != nil {
+		return a.Stderr
+	}
+	return os.Stderr
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================
+// ProgressBar：终端进度条
+// ============================================
+//
+//   bar := cli.NewProgressBar(os.Stderr, size)
+//   io.Copy(dst, bar.Reader(src))   // 或者 io.Copy(io.MultiWriter(dst, bar), src)
+//   bar.Finish()
+//
+// 输出是终端（TTY）时用 \r 在同一行原地刷新：
+//   [=========>          ]  48.2%  12.0 MiB/25.0 MiB  3.1 MiB/s  ETA 4s
+// 输出被重定向到文件或管道时，\r 会产生一堆乱码，改为每隔几秒打印一行。
+//
+// 不启动后台 goroutine：每次 Add 时检查距上次绘制的时间，超过间隔才重绘。
+
+const (
+	ttyInterval  = 100 * time.Millisecond
+	lineInterval = 2 * time.Second
+	barWidth     = 20
+)
+
+// ProgressBar 进度条，可以并发调用 Add / Write
+type ProgressBar struct {
+	mu       sync.Mutex
+	out      io.Writer
+	total    int64 // <= 0 表示总量未知
+	current  int64
+	unit     string // 为空表示按字节显示
+	tty      bool
+	interval time.Duration
+	start    time.Time
+	lastDraw time.Time
+	done     bool
+	now      func() time.Time
+}
+
+// NewProgressBar 创建进度条，total <= 0 表示总量未知（不显示百分比和 ETA）
+func NewProgressBar(out io.Writer, total int64) *ProgressBar {
+	tty := isTerminal(out)
+	interval := lineInterval
+	if tty {
+		interval = ttyInterval
+	}
+	return &ProgressBar{
+		out:      out,
+		total:    total,
+		tty:      tty,
+		interval: interval,
+		start:    time.Now(),
+		now:      time.Now,
+	}
+}
+
+// SetUnit 按个数而不是字节显示，如 SetUnit("页")
+func (b *ProgressBar) SetUnit(unit string) *ProgressBar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unit = unit
+	return b
+}
+
+// Add 增加进度
+func (b *ProgressBar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current += n
+	if now := b.now(); now.Sub(b.lastDraw) >= b.interval {
+		b.lastDraw = now
+		b.draw(now)
+	}
+}
+
+// Write 实现 io.Writer，只计数不保存数据
+func (b *ProgressBar) Write(p []byte) (int, error) {
+	b.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// Reader 包装 r，读出的字节计入进度
+func (b *ProgressBar) Reader(r io.Reader) io.Reader {
+	return io.TeeReader(r, b)
+}
+
+// Finish 绘制最终状态并换行，可以重复调用
+func (b *ProgressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done {
+		return
+	}
+	b.done = true
+	b.draw(b.now())
+	if b.tty {
+		fmt.Fprintln(b.out)
+	}
+}
+
+// draw 调用方持有锁
+func (b *ProgressBar) draw(now time.Time) {
+	elapsed := now.Sub(b.start)
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(b.current) / elapsed.Seconds()
+	}
+
+	var sb strings.Builder
+	if b.total > 0 {
+		frac := min(float64(b.current)/float64(b.total), 1)
+		filled := int(frac * barWidth)
+		sb.WriteString("[")
+		sb.WriteString(strings.Repeat("=", filled))
+		if filled < barWidth {
+			sb.WriteString(">")
+			sb.WriteString(strings.Repeat(" ", barWidth-filled-1))
+		}
+		fmt.Fprintf(&sb, "] %5.1f%%  %s/%s", frac*100, b.format(float64(b.current)), b.format(float64(b.total)))
+	} else {
+		sb.WriteString(b.format(float64(b.current)))
+	}
+	fmt.Fprintf(&sb, "  %s/s", b.format(rate))
+
+	switch {
+	case b.done:
+		fmt.Fprintf(&sb, "  用时 %v", elapsed.Round(time.Second/10))
+	case b.total > 0 && rate > 0:
+		eta := time.Duration(float64(b.total-b.current) / rate * float64(time.Second))
+		fmt.Fprintf(&sb, "  ETA %v", max(eta, 0).Round(time.Second))
+	}
+
+	if b.tty {
+		// \033[K 清除光标到行尾，防止新的一行比上一次短时残留旧字符
+		fmt.Fprintf(b.out, "\r%s\033[K", sb.String())
+	} else {
+		fmt.Fprintln(b.out, sb.String())
+	}
+}
+
+// format 字节用 KiB/MiB 等二进制单位，个数直接加单位
+func (b *ProgressBar) format(n float64) string {
+	if b.unit != "" {
+		return fmt.Sprintf("%.0f %s", n, b.unit)
+	}
+	return FormatBytes(n)
+}
+
+// FormatBytes 把字节数格式化为 "12.3 MiB" 这样的形式
+func FormatBytes(n float64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	exp := 0
+	for n >= unit && exp < 5 {
+		n /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", n, "KMGTP"[exp-1])
+}
+
+// isTerminal 判断 w 是否为终端：字符设备（/dev/tty、控制台）而不是文件或管道
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
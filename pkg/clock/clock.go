@@ -0,0 +1,71 @@
+// ============================================
+// clock 包：可注入的时钟
+// ============================================
+//
+// 直接调用 time.Now / time.After 的代码只能用真实的 time.Sleep 来验证：
+// 测 2 秒过期的缓存就要等 2 秒以上，而且机器一忙结果就不稳定。
+// 把"时间从哪来"抽成 Clock 接口后：
+//
+//   生产代码：clock.Real，行为与 time 包完全相同
+//   测试/演示：clock.NewFakeClock(t)，时间只在调用 Advance 时前进，
+//              到期的定时器按到期顺序同步触发
+//
+// 约定：需要时间的类型持有一个 Clock 字段（或 WithClock 选项），
+// 默认是 clock.Real，零值可用。
+// ============================================
+
+package clock
+
+import "time"
+
+// Clock 时间来源
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer 对应 *time.Timer，通道通过方法取得以便替换实现
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker 对应 *time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real 使用 time 包的真实时钟
+var Real Clock = realClock{}
+
+// Or 返回 c，c 为 nil 时返回 Real，用于给可选的 Clock 字段取默认值
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time        { return r.t.C }
+func (r realTimer) Stop() bool                 { return r.t.Stop() }
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time   { return r.t.C }
+func (r realTicker) Stop()                 { r.t.Stop() }
+func (r realTicker) Reset(d time.Duration) { r.t.Reset(d) }
@@ -0,0 +1,207 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock 手动推进的时钟，并发安全
+//
+//	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	timer := fc.NewTimer(time.Second)
+//	fc.Advance(time.Second) // timer.C() 立即收到值，不需要真的等待
+//
+// 被测代码通常在另一个 goroutine 里创建定时器，
+// 先用 BlockUntil 等它登记好，再 Advance，否则时间可能在定时器创建之前就推进了。
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeTimer
+	changed chan struct{} // 登记的定时器变化时关闭并替换，唤醒 BlockUntil
+}
+
+// NewFakeClock 创建停在 start 的时钟
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start, changed: make(chan struct{})}
+}
+
+// Now 当前的假时间
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After 等价于 NewTimer(d).C()
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer 创建在 Now()+d 触发一次的定时器，d <= 0 时立即触发
+func (f *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+	f.mu.Lock()
+	f.schedule(t, d)
+	f.mu.Unlock()
+	return t
+}
+
+// NewTicker 创建每隔 d 触发的 Ticker，d <= 0 时 panic（与 time.NewTicker 一致）
+func (f *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: NewTicker 的间隔必须为正数")
+	}
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1), period: d}
+	f.mu.Lock()
+	f.schedule(t, d)
+	f.mu.Unlock()
+	return fakeTicker{t}
+}
+
+// Advance 把时间向前推进 d，期间到期的定时器按到期时间顺序触发
+// Ticker 在一次 Advance 中可能到期多次，与真实 Ticker 一样，消费方来不及读时多余的触发被丢弃
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advanceTo(f.now.Add(d))
+}
+
+// Set 把时间设置为 t，t 早于当前时间时只修改时间，不触发任何定时器
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.Before(f.now) {
+		f.now = t
+		return
+	}
+	f.advanceTo(t)
+}
+
+// Waiters 返回尚未触发（或周期性）的定时器数量
+func (f *FakeClock) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil 阻塞直到至少有 n 个定时器在等待
+func (f *FakeClock) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) >= n {
+			f.mu.Unlock()
+			return
+		}
+		ch := f.changed
+		f.mu.Unlock()
+		<-ch
+	}
+}
+
+// advanceTo 逐个触发 <= target 的定时器，调用方持有 mu
+func (f *FakeClock) advanceTo(target time.Time) {
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool { return f.waiters[i].when.Before(f.waiters[j].when) })
+		if len(f.waiters) == 0 || f.waiters[0].when.After(target) {
+			break
+		}
+		t := f.waiters[0]
+		f.now = t.when
+		t.fire(t.when)
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			f.remove(t)
+		}
+	}
+	f.now = target
+}
+
+// schedule 登记 t 在 now+d 触发，调用方持有 mu
+func (f *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	t.when = f.now.Add(d)
+	if d <= 0 && t.period == 0 {
+		t.fire(f.now)
+		return
+	}
+	f.waiters = append(f.waiters, t)
+	f.notify()
+}
+
+// remove 取消登记，返回 t 之前是否在等待，调用方持有 mu
+func (f *FakeClock) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.notify()
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeClock) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// fakeTimer 实现 Timer；period > 0 时是 Ticker 的底层实现
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+// fire 非阻塞发送，通道里已有未读的值时丢弃本次
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+// drain 丢弃未读的值：Go 1.23 起 Stop/Reset 之后不会再收到旧的触发
+func (t *fakeTimer) drain() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
+// Stop 停止定时器，返回它是否还在等待（Timer 语义）
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.drain()
+	return t.clock.remove(t)
+}
+
+// Reset 让定时器从现在起 d 后触发，返回它之前是否还在等待；Ticker 同时修改周期
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.drain()
+	active := t.clock.remove(t)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.clock.schedule(t, d)
+	return active
+}
+
+// fakeTicker 把 fakeTimer 适配为 Ticker（Stop/Reset 没有返回值）
+type fakeTicker struct{ t *fakeTimer }
+
+func (k fakeTicker) C() <-chan time.Time { return k.t.c }
+func (k fakeTicker) Stop()               { k.t.Stop() }
+
+func (k fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: Ticker 的间隔必须为正数")
+	}
+	k.t.Reset(d)
+}
@@ -0,0 +1,231 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// recv 非阻塞读取一次触发；FakeClock 在 Advance 返回前已经同步发送，不需要等待
+func recv(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case v := <-c:
+		return v, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClockTimersFireInDeadlineOrder(t *testing.T) {
+	fc := NewFakeClock(epoch)
+	// 故意不按到期时间的顺序创建
+	delays := []time.Duration{3 * time.Second, time.Second, 2 * time.Second, 5 * time.Second}
+	timers := make([]Timer, len(delays))
+	for i, d := range delays {
+		timers[i] = fc.NewTimer(d)
+	}
+	if n := fc.Waiters(); n != len(delays) {
+		t.Fatalf("Waiters() = %d，期望 %d", n, len(delays))
+	}
+
+	fc.Advance(4 * time.Second)
+	// 每个定时器收到的是自己的到期时间，而不是推进后的时间
+	for i, d := range delays {
+		v, ok := recv(timers[i].C())
+		if d > 4*time.Second {
+			if ok {
+				t.Fatalf("%v 的定时器在 4s 时触发了", d)
+			}
+			continue
+		}
+		if !ok || !v.Equal(epoch.Add(d)) {
+			t.Fatalf("%v 的定时器收到 %v (%v)，期望 %v", d, v, ok, epoch.Add(d))
+		}
+	}
+	if now := fc.Now(); !now.Equal(epoch.Add(4 * time.Second)) {
+		t.Fatalf("Now() = %v，期望推进到 4s", now)
+	}
+	if n := fc.Waiters(); n != 1 {
+		t.Fatalf("触发后 Waiters() = %d，期望 1", n)
+	}
+
+	// 往回 Set 只改时间，不触发
+	fc.Set(epoch)
+	if _, ok := recv(timers[3].C()); ok {
+		t.Fatal("Set 到过去的时间触发了定时器")
+	}
+	fc.Set(epoch.Add(5 * time.Second))
+	if v, ok := recv(timers[3].C()); !ok || !v.Equal(epoch.Add(5*time.Second)) {
+		t.Fatalf("Set 到 5s 后收到 %v (%v)", v, ok)
+	}
+}
+
+func TestFakeClockTimerZeroDelay(t *testing.T) {
+	fc := NewFakeClock(epoch)
+	for _, d := range []time.Duration{0, -time.Second} {
+		if v, ok := recv(fc.After(d)); !ok || !v.Equal(epoch) {
+			t.Fatalf("After(%v) 收到 %v (%v)，期望立即触发", d, v, ok)
+		}
+	}
+	if n := fc.Waiters(); n != 0 {
+		t.Fatalf("Waiters() = %d，立即触发的定时器不应登记", n)
+	}
+}
+
+func TestFakeClockTickerDropsMissedTicks(t *testing.T) {
+	fc := NewFakeClock(epoch)
+	tk := fc.NewTicker(time.Second)
+
+	// 一次推进跨过 3 个周期：通道只缓冲第一次，后两次被丢弃
+	fc.Advance(3500 * time.Millisecond)
+	if v, ok := recv(tk.C()); !ok || !v.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("第一次读取 %v (%v)，期望 1s 的 tick", v, ok)
+	}
+	if v, ok := recv(tk.C()); ok {
+		t.Fatalf("多余的 tick %v 没有被丢弃", v)
+	}
+
+	// 下一次仍按原来的节奏在 4s 触发，而不是从 3.5s 重新计时
+	fc.Advance(400 * time.Millisecond)
+	if _, ok := recv(tk.C()); ok {
+		t.Fatal("3.9s 时不应触发")
+	}
+	fc.Advance(100 * time.Millisecond)
+	if v, ok := recv(tk.C()); !ok || !v.Equal(epoch.Add(4*time.Second)) {
+		t.Fatalf("4s 时收到 %v (%v)", v, ok)
+	}
+
+	// 每个周期都读走时不丢任何 tick
+	for i := 5; i <= 7; i++ {
+		fc.Advance(time.Second)
+		if v, ok := recv(tk.C()); !ok || !v.Equal(epoch.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("%ds 时收到 %v (%v)", i, v, ok)
+		}
+	}
+
+	tk.Stop()
+	fc.Advance(time.Minute)
+	if _, ok := recv(tk.C()); ok {
+		t.Fatal("Stop 之后仍然触发")
+	}
+}
+
+func TestFakeClockStopReset(t *testing.T) {
+	fc := NewFakeClock(epoch)
+
+	tm := fc.NewTimer(time.Second)
+	if !tm.Stop() {
+		t.Fatal("等待中的定时器 Stop 应返回 true")
+	}
+	if tm.Stop() {
+		t.Fatal("已停止的定时器再次 Stop 应返回 false")
+	}
+	fc.Advance(time.Second)
+	if _, ok := recv(tm.C()); ok {
+		t.Fatal("已停止的定时器触发了")
+	}
+
+	// 已触发但没读走的值在 Stop 时被丢弃
+	tm = fc.NewTimer(time.Second)
+	fc.Advance(time.Second)
+	if tm.Stop() {
+		t.Fatal("已触发的定时器 Stop 应返回 false")
+	}
+	if _, ok := recv(tm.C()); ok {
+		t.Fatal("Stop 之后还能读到旧的触发")
+	}
+
+	// Reset 等待中的定时器：返回 true，旧的到期时间不再触发
+	tm = fc.NewTimer(time.Second)
+	if !tm.Reset(3 * time.Second) {
+		t.Fatal("等待中的定时器 Reset 应返回 true")
+	}
+	fc.Advance(2 * time.Second)
+	if _, ok := recv(tm.C()); ok {
+		t.Fatal("Reset 之后旧的到期时间仍然触发")
+	}
+	fc.Advance(time.Second)
+	if v, ok := recv(tm.C()); !ok || !v.Equal(fc.Now()) {
+		t.Fatalf("Reset 后的到期时间收到 %v (%v)", v, ok)
+	}
+
+	// Reset 已触发的定时器：返回 false，可以再次触发
+	if tm.Reset(time.Second) {
+		t.Fatal("已触发的定时器 Reset 应返回 false")
+	}
+	fc.Advance(time.Second)
+	if _, ok := recv(tm.C()); !ok {
+		t.Fatal("Reset 已触发的定时器后没有再次触发")
+	}
+
+	// Ticker 的 Reset 同时修改周期
+	tk := fc.NewTicker(time.Second)
+	tk.Reset(2 * time.Second)
+	fc.Advance(time.Second)
+	if _, ok := recv(tk.C()); ok {
+		t.Fatal("Reset 为 2s 后 1s 时不应触发")
+	}
+	for range 2 {
+		fc.Advance(2 * time.Second)
+		if _, ok := recv(tk.C()); !ok {
+			t.Fatal("Reset 后没有按新的周期触发")
+		}
+	}
+	tk.Stop()
+}
+
+func TestFakeClockTickerPanicsOnNonPositive(t *testing.T) {
+	fc := NewFakeClock(epoch)
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s 没有 panic", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("NewTicker(0)", func() { fc.NewTicker(0) })
+	tk := fc.NewTicker(time.Second)
+	mustPanic("Reset(-1)", func() { tk.Reset(-1) })
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	fc := NewFakeClock(epoch)
+	fc.BlockUntil(0) // 条件已满足时立即返回
+
+	// 被测 goroutine 创建定时器的时机不确定，BlockUntil 等它登记好再 Advance
+	got := make(chan time.Time)
+	go func() { got <- <-fc.After(time.Second) }()
+	fc.BlockUntil(1)
+	fc.Advance(time.Second)
+	select {
+	case v := <-got:
+		if !v.Equal(epoch.Add(time.Second)) {
+			t.Fatalf("收到 %v，期望 1s", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("BlockUntil 之后 Advance 没有唤醒等待的 goroutine")
+	}
+
+	// 等待多个定时器时，每次登记都会唤醒 BlockUntil 重新检查
+	done := make(chan struct{})
+	go func() {
+		fc.BlockUntil(2)
+		close(done)
+	}()
+	tm := fc.NewTimer(time.Second)
+	select {
+	case <-done:
+		t.Fatal("只有 1 个定时器时 BlockUntil(2) 返回了")
+	default:
+	}
+	fc.NewTimer(time.Second)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("登记第 2 个定时器后 BlockUntil(2) 没有返回")
+	}
+	tm.Stop()
+}
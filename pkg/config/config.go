@@ -0,0 +1,236 @@
+// ============================================
+// config 包：带环境变量替换的 JSON 配置加载
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 3：
+// - 配置文件是 JSON，加载到任意结构体
+// - ${VAR} 替换为环境变量，变量不存在时报错
+// - ${VAR:-default} 变量不存在或为空时使用默认值
+// - $$ 表示字面量 $
+// - 带 `config:"required"` 标签的字段加载后不能是零值
+//
+// 替换在解析 JSON 之前对原始文本进行，替换进来的值会按 JSON 字符串规则转义，
+// 所以既可以写在引号内（"host": "${HOST}"），
+// 也可以不加引号用于数字和布尔值（"port": ${PORT:-8080}）。
+//
+// INI 格式的解析见 ini.go，文件变化时自动重新加载的 Config[T] 见 reload.go。
+// ============================================
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrMissingVar = errors.New("环境变量未设置")
+	ErrSyntax     = errors.New("配置语法错误")
+	ErrRequired   = errors.New("缺少必填字段")
+)
+
+// LookupFunc 查找变量，与 os.LookupEnv 签名相同
+type LookupFunc func(name string) (string, bool)
+
+// Load 读取 path 指定的 JSON 文件，用进程环境变量替换后加载到 dst
+func Load(path string, dst any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := Decode(f, dst, os.LookupEnv); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Decode 从 r 读取 JSON，用 lookup 替换变量后加载到 dst，并检查必填字段
+// dst 必须是指向结构体的非 nil 指针
+func Decode(r io.Reader, dst any, lookup LookupFunc) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: dst 必须是指向结构体的指针，实际是 %T", dst)
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	expanded, err := expand(string(raw), lookup, true)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(expanded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			line, col := position(expanded, se.Offset)
+			return fmt.Errorf("%w: 第 %d 行第 %d 列: %v", ErrSyntax, line, col, se)
+		}
+		return fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+
+	var missing []string
+	checkRequired(rv.Elem(), "", "json", &missing)
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Expand 替换 s 中的 ${VAR} 和 ${VAR:-default}，不做 JSON 转义
+// 所有缺失的变量会在同一个错误中列出
+func Expand(s string, lookup LookupFunc) (string, error) {
+	return expand(s, lookup, false)
+}
+
+// expand 逐字符扫描，jsonEscape 为 true 时替换值按 JSON 字符串规则转义
+func expand(s string, lookup LookupFunc, jsonEscape bool) (string, error) {
+	var b strings.Builder
+	var errs []error
+
+	for i := 0; i < len(s); {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i += 2
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			line, col := position(s, int64(i+1))
+			return "", fmt.Errorf("%w: 第 %d 行第 %d 列: ${ 没有闭合", ErrSyntax, line, col)
+		}
+		expr := s[i+2 : i+end]
+		name, def, hasDef := strings.Cut(expr, ":-")
+		if !validName(name) {
+			line, col := position(s, int64(i+1))
+			return "", fmt.Errorf("%w: 第 %d 行第 %d 列: 无效的变量名 %q", ErrSyntax, line, col, name)
+		}
+
+		val, ok := lookup(name)
+		switch {
+		case hasDef && (!ok || val == ""):
+			val = def
+		case !ok:
+			line, _ := position(s, int64(i+1))
+			errs = append(errs, fmt.Errorf("%w: %s（第 %d 行）", ErrMissingVar, name, line))
+		}
+		if jsonEscape {
+			val = escapeJSON(val)
+		}
+		b.WriteString(val)
+		i += end + 1
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return b.String(), nil
+}
+
+// validName 变量名由字母、数字和下划线组成，不能以数字开头
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// escapeJSON 转义引号、反斜杠和控制字符，结果不含两侧的引号
+func escapeJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s) // 编码字符串不会失败
+	out := strings.TrimSuffix(buf.String(), "\n")
+	return out[1 : len(out)-1]
+}
+
+// position 把字节偏移换算成行号和列号（都从 1 开始）
+func position(s string, offset int64) (line, col int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	before := s[:offset]
+	line = strings.Count(before, "\n") + 1
+	col = len(before) - strings.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// checkRequired 递归检查带 required 标签的字段，path 使用 tagKey 标签（json、ini）中的名字
+func checkRequired(v reflect.Value, prefix, tagKey string, missing *[]string) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := tagName(field, tagKey)
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if isRequired(field) && fv.IsZero() {
+			*missing = append(*missing, path)
+			continue
+		}
+		switch {
+		case fv.Kind() == reflect.Struct:
+			checkRequired(fv, path, tagKey, missing)
+		case fv.Kind() == reflect.Pointer && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct:
+			checkRequired(fv.Elem(), path, tagKey, missing)
+		}
+	}
+}
+
+// tagName 返回字段在 tagKey 标签中的名字，没有标签时使用字段名
+func tagName(f reflect.StructField, tagKey string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(tagKey), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+func isRequired(f reflect.StructField) bool {
+	for opt := range strings.SplitSeq(f.Tag.Get("config"), ",") {
+		if strings.TrimSpace(opt) == "required" {
+			return true
+		}
+	}
+	return false
+}
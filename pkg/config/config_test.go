@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzDecode 把任意字符串作为变量值替换进 JSON 字符串，解码后必须得到原值；
+// 原始文本本身也当作配置解析一次，只要求不 panic
+// 运行：go test ./pkg/config -fuzz FuzzDecode
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{"localhost", `a"b\c`, "多字节", "\n\t\x01", "${X}", "$$", "${V:-x}"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		lookup := func(name string) (string, bool) { return in, name == "V" }
+		var dst struct {
+			Value string `json:"value"`
+		}
+		_ = Decode(strings.NewReader(in), &dst, lookup)
+		_, _ = Expand(in, lookup)
+
+		if !utf8.ValidString(in) {
+			return // JSON 会把非法 UTF-8 替换成 U+FFFD，不可能还原
+		}
+		dst.Value = ""
+		if err := Decode(strings.NewReader(`{"value": "${V}"}`), &dst, lookup); err != nil {
+			t.Fatalf("替换 %q 后解码失败: %v", in, err)
+		}
+		if dst.Value != in {
+			t.Fatalf("替换后解码得到 %q，期望 %q", dst.Value, in)
+		}
+	})
+}
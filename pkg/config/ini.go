@@ -0,0 +1,331 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// INI 格式
+// ============================================
+//
+//   ; 注释以 ; 或 # 开头
+//   name = demo              ; 第一个 [section] 之前的键属于全局节 ""
+//
+//   [server]
+//   host = 0.0.0.0
+//   port = 8080
+//   timeout = 5s             ; 可以用 Duration 读取
+//   greeting = "hello ; world"  ; 双引号内的 ; 不是注释，支持 \n 等转义
+//
+// 未加引号的值中，前面有空白的 ; 或 # 开始行内注释。
+// 同一节中重复的键以最后一个为准，重复的节会合并。
+//
+// Unmarshal 的映射规则（字段名来自 `ini:"..."` 标签，没有标签时使用字段名）：
+//   - 顶层的普通字段对应全局节中的键
+//   - 顶层的结构体字段对应同名的节，其字段对应节中的键
+//   - 支持 string、bool、整数、浮点数、time.Duration 和 []string（逗号分隔）
+//   - 同样支持 `config:"required"`
+
+var (
+	ErrNoKey    = errors.New("配置项不存在")
+	ErrBadValue = errors.New("配置值类型不匹配")
+)
+
+// INI 解析后的 INI 文档
+type INI struct {
+	sections map[string]map[string]string
+	order    []string // 节出现的顺序，全局节 "" 总是第一个
+}
+
+// ParseINI 解析 INI 文档
+func ParseINI(r io.Reader) (*INI, error) {
+	ini := &INI{sections: map[string]map[string]string{"": {}}, order: []string{""}}
+	section := ""
+
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+
+		case line[0] == '[':
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: 第 %d 行: 节名缺少 ]", ErrSyntax, lineNo)
+			}
+			if rest := strings.TrimSpace(line[end+1:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return nil, fmt.Errorf("%w: 第 %d 行: ] 之后有多余内容 %q", ErrSyntax, lineNo, rest)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if section == "" {
+				return nil, fmt.Errorf("%w: 第 %d 行: 节名为空", ErrSyntax, lineNo)
+			}
+			if _, ok := ini.sections[section]; !ok {
+				ini.sections[section] = map[string]string{}
+				ini.order = append(ini.order, section)
+			}
+
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return nil, fmt.Errorf("%w: 第 %d 行: 应为 key = value，实际是 %q", ErrSyntax, lineNo, line)
+			}
+			v, err := parseINIValue(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("%w: 第 %d 行: %v", ErrSyntax, lineNo, err)
+			}
+			ini.sections[section][key] = v
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return ini, nil
+}
+
+// LoadINI 读取 INI 文件并加载到 dst
+func LoadINI(path string, dst any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ini, err := ParseINI(f)
+	if err == nil {
+		err = ini.Unmarshal(dst)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// parseINIValue 处理引号和行内注释
+func parseINIValue(v string) (string, error) {
+	if strings.HasPrefix(v, `"`) {
+		// 找到与开头配对的引号，之后只允许注释
+		end := 1
+		for end < len(v) && v[end] != '"' {
+			if v[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(v) {
+			return "", errors.New("引号没有闭合")
+		}
+		if rest := strings.TrimSpace(v[end+1:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+			return "", fmt.Errorf("引号之后有多余内容 %q", rest)
+		}
+		return strconv.Unquote(v[:end+1])
+	}
+	for i := 1; i < len(v); i++ {
+		if (v[i] == ';' || v[i] == '#') && (v[i-1] == ' ' || v[i-1] == '\t') {
+			return strings.TrimSpace(v[:i]), nil
+		}
+	}
+	return v, nil
+}
+
+// Sections 按出现顺序返回所有节名，第一个总是全局节 ""
+func (ini *INI) Sections() []string {
+	return slices.Clone(ini.order)
+}
+
+// Keys 返回节中的键（排序后）
+func (ini *INI) Keys(section string) []string {
+	keys := make([]string, 0, len(ini.sections[section]))
+	for k := range ini.sections[section] {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Get 返回原始字符串值
+func (ini *INI) Get(section, key string) (string, bool) {
+	v, ok := ini.sections[section][key]
+	return v, ok
+}
+
+// String 返回字符串值，不存在时返回 def
+func (ini *INI) String(section, key, def string) string {
+	if v, ok := ini.Get(section, key); ok {
+		return v
+	}
+	return def
+}
+
+// Int 读取整数
+func (ini *INI) Int(section, key string) (int, error) {
+	return getAs(ini, section, key, strconv.Atoi)
+}
+
+// Float 读取浮点数
+func (ini *INI) Float(section, key string) (float64, error) {
+	return getAs(ini, section, key, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Bool 读取布尔值，接受 strconv.ParseBool 支持的写法以及 yes/no、on/off
+func (ini *INI) Bool(section, key string) (bool, error) {
+	return getAs(ini, section, key, parseBool)
+}
+
+// Duration 读取时间间隔，如 "1m30s"
+func (ini *INI) Duration(section, key string) (time.Duration, error) {
+	return getAs(ini, section, key, time.ParseDuration)
+}
+
+func getAs[T any](ini *INI, section, key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	s, ok := ini.Get(section, key)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrNoKey, qualified(section, key))
+	}
+	v, err := parse(s)
+	if err != nil {
+		return zero, fmt.Errorf("%w: %s = %q 不是 %T", ErrBadValue, qualified(section, key), s, zero)
+	}
+	return v, nil
+}
+
+func qualified(section, key string) string {
+	if section == "" {
+		return key
+	}
+	return section + "." + key
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// Unmarshal 把文档加载到 dst，dst 必须是指向结构体的非 nil 指针
+// 文档中多出的节和键被忽略
+func (ini *INI) Unmarshal(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: dst 必须是指向结构体的指针，实际是 %T", dst)
+	}
+	v := rv.Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name := tagName(f, "ini")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		var err error
+		if fv.Kind() == reflect.Struct {
+			err = ini.unmarshalSection(name, fv)
+		} else {
+			err = ini.setField(fv, "", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	checkRequired(v, "", "ini", &missing)
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (ini *INI) unmarshalSection(section string, v reflect.Value) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name := tagName(f, "ini")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if err := ini.setField(v.Field(i), section, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// setField 把 section.key 的值转换后写入 v，键不存在时保持原值
+func (ini *INI) setField(v reflect.Value, section, key string) error {
+	s, ok := ini.Get(section, key)
+	if !ok {
+		return nil
+	}
+	bad := func() error {
+		return fmt.Errorf("%w: %s = %q 不是 %v", ErrBadValue, qualified(section, key), s, v.Type())
+	}
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return bad()
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return bad()
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return bad()
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return bad()
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return bad()
+		}
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for item := range strings.SplitSeq(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	default:
+		return fmt.Errorf("%w: %s 的字段类型 %v 不受支持", ErrBadValue, qualified(section, key), v.Type())
+	}
+	return nil
+}
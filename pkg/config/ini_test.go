@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// FuzzParseINI 把任意字符串用 strconv.Quote 写成 key = "..." 后解析，必须得到原值；
+// 原始文本本身也当作 INI 解析一次，只要求不 panic
+// 运行：go test ./pkg/config -fuzz FuzzParseINI
+func FuzzParseINI(f *testing.F) {
+	for _, seed := range []string{"plain", `with "quotes"`, `back\slash`, "; not a comment", "tab\there", "中文", "[s]\nport = 80"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		if ini, err := ParseINI(strings.NewReader(in)); err == nil {
+			var dst struct {
+				Name string
+				Port int
+			}
+			_ = ini.Unmarshal(&dst)
+		}
+
+		doc := "[s]\nkey = " + strconv.Quote(in) + " ; 注释\n"
+		ini, err := ParseINI(strings.NewReader(doc))
+		if err != nil {
+			t.Fatalf("解析 %q 失败: %v", doc, err)
+		}
+		if got, _ := ini.Get("s", "key"); got != in {
+			t.Fatalf("解析 %q 得到 %q，期望 %q", doc, got, in)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// ============================================
+// 热加载
+// ============================================
+//
+// Load 只在启动时读一次；长时间运行的服务改了日志级别、限流阈值就要重启。
+// Config[T] 把加载、监视、校验组合起来：
+//
+//   cfg, err := config.New[AppConfig]("app.json")   // 首次加载失败直接返回错误
+//   go cfg.Watch(ctx)                                // 文件变化时自动 Reload
+//   cfg.Subscribe(func(old, cur *AppConfig) { ... }) // 换上新配置后通知
+//   port := cfg.Get().Server.Port                    // 任意 goroutine 随时读取
+//
+// 一次 Reload：读文件 → 按扩展名解析（.ini 用 INI，其他按 JSON 并替换环境变量）
+// → 检查必填字段 → T 实现了 Validator 时调用 Validate → 用 atomic.Pointer 整体替换 → 通知订阅者。
+// 任何一步失败都保留旧配置：写了一半的文件、填错的端口不会让正在运行的服务崩掉。
+//
+// Get 返回的 *T 是共享的，只读；要修改就复制一份。
+// 监视用轮询（比较修改时间和大小），不依赖 inotify 等平台接口；间隔默认 1 秒。
+
+// ErrInvalid 新配置没有通过 Validate，旧配置继续生效
+var ErrInvalid = errors.New("配置校验失败")
+
+// Validator 配置类型实现它时，每次加载后调用 Validate，返回错误则拒绝这份配置
+type Validator interface {
+	Validate() error
+}
+
+// Option New 的选项
+type Option func(*reloadOptions)
+
+type reloadOptions struct {
+	interval time.Duration
+	lookup   LookupFunc
+	clock    clock.Clock
+	logger   *log.Logger
+}
+
+// WithPollInterval Watch 检查文件变化的间隔，默认 1 秒
+func WithPollInterval(d time.Duration) Option {
+	return func(o *reloadOptions) { o.interval = d }
+}
+
+// WithLookup 替换 ${VAR} 时查找变量的函数，默认 os.LookupEnv
+func WithLookup(fn LookupFunc) Option { return func(o *reloadOptions) { o.lookup = fn } }
+
+// WithClock Watch 计时用的时钟，测试时传入 clock.FakeClock
+func WithClock(c clock.Clock) Option { return func(o *reloadOptions) { o.clock = c } }
+
+// WithLogger Watch 中重新加载成功或失败时的日志，默认 log.Default()
+func WithLogger(l *log.Logger) Option { return func(o *reloadOptions) { o.logger = l } }
+
+// Config 可以热加载的配置，并发安全
+type Config[T any] struct {
+	path string
+	opts reloadOptions
+
+	cur     atomic.Pointer[T]
+	version atomic.Uint64
+
+	reloadMu sync.Mutex // 同一时刻只有一次 Reload，订阅者按版本顺序收到通知
+	stamp    fileStamp  // 上次加载的文件状态，持有 reloadMu 时读写
+
+	subMu  sync.Mutex
+	subs   []*subscriber[T]
+	nextID int
+}
+
+type subscriber[T any] struct {
+	id int
+	fn func(old, cur *T)
+}
+
+// fileStamp 判断文件是否变化：修改时间或大小不同就重新加载
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// New 从 path 加载配置，加载或校验失败时返回错误
+func New[T any](path string, opts ...Option) (*Config[T], error) {
+	o := reloadOptions{interval: time.Second, lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.clock = clock.Or(o.clock)
+	if o.logger == nil {
+		o.logger = log.Default()
+	}
+	if o.interval <= 0 {
+		return nil, errors.New("config: 轮询间隔必须为正数")
+	}
+	c := &Config[T]{path: path, opts: o}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get 当前配置，只读
+func (c *Config[T]) Get() *T { return c.cur.Load() }
+
+// Version 成功加载的次数，首次加载后为 1
+func (c *Config[T]) Version() uint64 { return c.version.Load() }
+
+// Subscribe 每次换上新配置后调用 fn，返回取消订阅的函数
+// 订阅者按订阅顺序在 Reload 的调用方 goroutine 中执行，不要在 fn 里调用 Reload
+func (c *Config[T]) Subscribe(fn func(old, cur *T)) (cancel func()) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	c.nextID++
+	id := c.nextID
+	c.subs = append(c.subs, &subscriber[T]{id: id, fn: fn})
+	return func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+		c.subs = slices.DeleteFunc(c.subs, func(s *subscriber[T]) bool { return s.id == id })
+	}
+}
+
+// Reload 立即重新加载；失败时保留旧配置并返回错误
+// 文件内容没变时也会加载并通知订阅者，Watch 只在文件变化时调用它
+func (c *Config[T]) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	st, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	// 先记下文件状态：加载失败的文件不用每个间隔都重试一遍，等它再次变化
+	c.stamp = fileStamp{modTime: st.ModTime(), size: st.Size()}
+
+	next := new(T)
+	if err := c.decode(raw, next); err != nil {
+		return fmt.Errorf("%s: %w", c.path, err)
+	}
+	if v, ok := any(next).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%s: %w: %w", c.path, ErrInvalid, err)
+		}
+	}
+
+	old := c.cur.Swap(next)
+	c.version.Add(1)
+	if old != nil {
+		c.notify(old, next)
+	}
+	return nil
+}
+
+func (c *Config[T]) decode(raw []byte, dst *T) error {
+	if filepath.Ext(c.path) == ".ini" {
+		ini, err := ParseINI(bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		return ini.Unmarshal(dst)
+	}
+	return Decode(bytes.NewReader(raw), dst, c.opts.lookup)
+}
+
+func (c *Config[T]) notify(old, cur *T) {
+	c.subMu.Lock()
+	subs := slices.Clone(c.subs)
+	c.subMu.Unlock()
+	for _, s := range subs {
+		s.fn(old, cur)
+	}
+}
+
+// Watch 每个间隔检查一次文件，修改时间或大小变化时 Reload，直到 ctx 取消
+// 文件暂时不存在（编辑器先删后写）时跳过这一次；新内容无效时记日志，继续使用旧配置
+func (c *Config[T]) Watch(ctx context.Context) error {
+	ticker := c.opts.clock.NewTicker(c.opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+		if !c.changed() {
+			continue
+		}
+		if err := c.Reload(); err != nil {
+			c.opts.logger.Printf("config: 重新加载失败，继续使用版本 %d: %v", c.Version(), err)
+			continue
+		}
+		c.opts.logger.Printf("config: 已重新加载 %s，版本 %d", c.path, c.Version())
+	}
+}
+
+func (c *Config[T]) changed() bool {
+	st, err := os.Stat(c.path)
+	if err != nil {
+		return false
+	}
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	return !st.ModTime().Equal(c.stamp.modTime) || st.Size() != c.stamp.size
+}
@@ -0,0 +1,64 @@
+// ============================================
+// container 包：泛型容器
+// ============================================
+//
+// 来自 tutorial/08_generics 第 4 节的泛型类型，单独成包后其他课程和 cmd 可以直接导入：
+//
+//   Stack[T]          后进先出，基于切片
+//   Queue[T]          先进先出，基于可扩容的环形缓冲区，入队出队都是 O(1)
+//   BlockingQueue[T]  并发安全的队列，Pop 在队列为空时等待（生产者/消费者）
+//   Set[T]            基于 map[T]struct{} 的集合
+//   LinkedList[T]     单向链表
+//
+// 除 BlockingQueue 外都不是并发安全的，多个 goroutine 共享时自己加锁。
+// 空容器的 Pop / Dequeue / Peek 返回零值和 false，而不是 panic。
+// ============================================
+
+package container
+
+// Stack 泛型栈
+type Stack[T any] struct {
+	items []T
+}
+
+// NewStack 创建空栈；类型参数无法从参数推导，需要显式写出：NewStack[int]()
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{items: make([]T, 0)}
+}
+
+// Push 压栈
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+// Pop 弹出栈顶元素，栈为空时返回零值和 false
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	item := s.items[len(s.items)-1]
+	// 弹出的位置清零，T 包含指针时不再引用已弹出的元素
+	s.items[len(s.items)-1] = zero
+	s.items = s.items[:len(s.items)-1]
+	return item, true
+}
+
+// Peek 返回栈顶元素但不弹出
+func (s *Stack[T]) Peek() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// IsEmpty 栈是否为空
+func (s *Stack[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+// Size 元素个数
+func (s *Stack[T]) Size() int {
+	return len(s.items)
+}
@@ -0,0 +1,49 @@
+package container
+
+import "iter"
+
+// ListNode 链表节点
+type ListNode[T any] struct {
+	Value T
+	Next  *ListNode[T]
+}
+
+// LinkedList 泛型单向链表，记录尾节点，Append 是 O(1)
+type LinkedList[T any] struct {
+	head, tail *ListNode[T]
+	size       int
+}
+
+// NewLinkedList 创建空链表
+func NewLinkedList[T any]() *LinkedList[T] {
+	return &LinkedList[T]{}
+}
+
+// Append 在末尾追加
+func (l *LinkedList[T]) Append(value T) {
+	node := &ListNode[T]{Value: value}
+	if l.head == nil {
+		l.head = node
+	} else {
+		l.tail.Next = node
+	}
+	l.tail = node
+	l.size++
+}
+
+// Front 第一个节点，链表为空时为 nil；沿 Next 遍历
+func (l *LinkedList[T]) Front() *ListNode[T] { return l.head }
+
+// Len 元素个数
+func (l *LinkedList[T]) Len() int { return l.size }
+
+// All 从头到尾遍历元素值
+func (l *LinkedList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := l.head; n != nil; n = n.Next {
+			if !yield(n.Value) {
+				return
+			}
+		}
+	}
+}
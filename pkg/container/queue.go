@@ -0,0 +1,172 @@
+package container
+
+import (
+	"context"
+	"sync"
+)
+
+// Queue 泛型队列：基于可扩容的环形缓冲区
+//
+//	buf:  [ d e . . . a b c ]
+//	                  ^head      size = 5，队尾写满后绕回数组开头
+//
+// 入队写到 (head+size) % len(buf)，出队只移动 head，都是 O(1)，
+// 不像 items = items[1:] 那样不断丢掉数组前部、迫使 append 反复重新分配。
+// 只有缓冲区满时才扩容为两倍，扩容时把元素按顺序搬到新数组开头。
+type Queue[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// NewQueue 创建空队列，第一次入队时分配缓冲区
+func NewQueue[T any]() *Queue[T] {
+	return &Queue[T]{}
+}
+
+// Enqueue 入队，缓冲区满时扩容为两倍
+func (q *Queue[T]) Enqueue(item T) {
+	if q.size == len(q.buf) {
+		q.grow()
+	}
+	q.buf[(q.head+q.size)%len(q.buf)] = item
+	q.size++
+}
+
+// Dequeue 出队，队列为空时返回零值和 false
+func (q *Queue[T]) Dequeue() (T, bool) {
+	var zero T
+	if q.size == 0 {
+		return zero, false
+	}
+	item := q.buf[q.head]
+	// 出队的位置要清零：否则 T 是指针或包含指针时，
+	// 缓冲区会一直引用已出队的元素，使其无法被 GC 回收
+	q.buf[q.head] = zero
+	q.head = (q.head + 1) % len(q.buf)
+	q.size--
+	return item, true
+}
+
+// Peek 返回队首元素但不出队，队列为空时返回零值和 false
+func (q *Queue[T]) Peek() (T, bool) {
+	var zero T
+	if q.size == 0 {
+		return zero, false
+	}
+	return q.buf[q.head], true
+}
+
+// IsEmpty 队列是否为空
+func (q *Queue[T]) IsEmpty() bool {
+	return q.size == 0
+}
+
+// Len 元素个数
+func (q *Queue[T]) Len() int {
+	return q.size
+}
+
+// Clear 清空队列，保留已分配的缓冲区
+func (q *Queue[T]) Clear() {
+	clear(q.buf)
+	q.head = 0
+	q.size = 0
+}
+
+// Range 从队首到队尾依次调用 fn，fn 返回 false 时提前结束，不修改队列
+func (q *Queue[T]) Range(fn func(item T) bool) {
+	for i := 0; i < q.size; i++ {
+		if !fn(q.buf[(q.head+i)%len(q.buf)]) {
+			return
+		}
+	}
+}
+
+// ToSlice 按队列顺序返回内容的副本，修改返回值不影响队列
+func (q *Queue[T]) ToSlice() []T {
+	result := make([]T, q.size)
+	q.copyTo(result)
+	return result
+}
+
+// Drain 取出所有元素并清空队列
+func (q *Queue[T]) Drain() []T {
+	result := q.ToSlice()
+	q.Clear()
+	return result
+}
+
+// grow 把容量扩大为两倍（至少 8）
+func (q *Queue[T]) grow() {
+	buf := make([]T, max(2*len(q.buf), 8))
+	q.copyTo(buf)
+	q.buf = buf
+	q.head = 0
+}
+
+// copyTo 把元素按队列顺序复制到 dst 开头：先复制 head 到数组末尾，再复制绕回的部分
+func (q *Queue[T]) copyTo(dst []T) {
+	if q.size == 0 {
+		return
+	}
+	n := copy(dst, q.buf[q.head:min(q.head+q.size, len(q.buf))])
+	copy(dst[n:], q.buf[:q.size-n])
+}
+
+// BlockingQueue 泛型阻塞队列：生产者/消费者模型
+//
+// Pop 在队列为空时等待，直到有元素入队或 ctx 结束。
+// sync.Cond 本身不支持 context，这里用 context.AfterFunc 在 ctx 结束时
+// Broadcast 一次，唤醒所有等待者，让它们检查 ctx.Err() 后返回
+type BlockingQueue[T any] struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue *Queue[T]
+}
+
+// NewBlockingQueue 创建空的阻塞队列
+func NewBlockingQueue[T any]() *BlockingQueue[T] {
+	q := &BlockingQueue[T]{queue: NewQueue[T]()}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Push 入队并唤醒一个等待的消费者
+func (q *BlockingQueue[T]) Push(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queue.Enqueue(item)
+	q.cond.Signal() // 只唤醒一个等待者，一个元素只够一个消费者取
+}
+
+// Pop 出队；队列为空时等待，ctx 结束时返回 ctx.Err()
+func (q *BlockingQueue[T]) Pop(ctx context.Context) (T, error) {
+	stop := context.AfterFunc(ctx, func() {
+		// 先拿锁再 Broadcast：保证等待者已经进入 Wait，不会错过这次唤醒
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.cond.Broadcast()
+	})
+	defer stop()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// Wait 返回不代表条件成立（可能是 Broadcast 或被别的消费者抢先），必须循环检查
+	for q.queue.IsEmpty() {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		q.cond.Wait()
+	}
+	item, _ := q.queue.Dequeue()
+	return item, nil
+}
+
+// Len 当前元素个数
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Len()
+}
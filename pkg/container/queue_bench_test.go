@@ -0,0 +1,76 @@
+package container
+
+import "testing"
+
+// sliceQueue 最初基于切片的实现，出队时 items = items[1:]，仅用于和环形缓冲区对比
+type sliceQueue[T any] struct {
+	items []T
+}
+
+func (q *sliceQueue[T]) Enqueue(item T) {
+	q.items = append(q.items, item)
+}
+
+func (q *sliceQueue[T]) Dequeue() (T, bool) {
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false
+	}
+	item := q.items[0]
+	q.items[0] = zero
+	q.items = q.items[1:]
+	return item, true
+}
+
+// fifo 两种队列实现共有的方法，方便用同一段基准代码测试
+type fifo[T any] interface {
+	Enqueue(item T)
+	Dequeue() (T, bool)
+}
+
+// BenchmarkQueue 对比环形缓冲区 Queue 与切片实现：
+//
+//	go test -bench Queue -benchmem ./pkg/container
+func BenchmarkQueue(b *testing.B) {
+	workloads := []struct {
+		name string
+		run  func(q fifo[int], i int)
+	}{
+		// 稳态：队列长度保持 1000，入队一个出队一个
+		{"steady", func(q fifo[int], i int) {
+			q.Enqueue(i)
+			q.Dequeue()
+		}},
+		// 突发：先积压 100 个，再全部取出
+		{"burst100", func(q fifo[int], i int) {
+			for j := 0; j < 100; j++ {
+				q.Enqueue(j)
+			}
+			for j := 0; j < 100; j++ {
+				q.Dequeue()
+			}
+		}},
+	}
+	impls := []struct {
+		name string
+		new  func() fifo[int]
+	}{
+		{"ring", func() fifo[int] { return NewQueue[int]() }},
+		{"slice", func() fifo[int] { return &sliceQueue[int]{} }},
+	}
+
+	for _, w := range workloads {
+		for _, impl := range impls {
+			b.Run(w.name+"/"+impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				q := impl.new()
+				for i := 0; i < 1000; i++ {
+					q.Enqueue(i)
+				}
+				for i := 0; b.Loop(); i++ {
+					w.run(q, i)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(q *Queue[int])
+		wantPeek int
+		wantOK   bool
+		want     []int // 队列从头到尾的内容
+	}{
+		{
+			name:  "新建的空队列",
+			build: func(q *Queue[int]) {},
+		},
+		{
+			name: "入队后全部出队",
+			build: func(q *Queue[int]) {
+				q.Enqueue(1)
+				q.Enqueue(2)
+				q.Dequeue()
+				q.Dequeue()
+			},
+		},
+		{
+			name: "Clear 后为空",
+			build: func(q *Queue[int]) {
+				for i := range 10 {
+					q.Enqueue(i)
+				}
+				q.Clear()
+			},
+		},
+		{
+			name:     "单个元素",
+			build:    func(q *Queue[int]) { q.Enqueue(7) },
+			wantPeek: 7, wantOK: true,
+			want: []int{7},
+		},
+		{
+			name: "先进先出",
+			build: func(q *Queue[int]) {
+				for i := 1; i <= 3; i++ {
+					q.Enqueue(i)
+				}
+				q.Dequeue()
+			},
+			wantPeek: 2, wantOK: true,
+			want: []int{2, 3},
+		},
+		{
+			// 容量 8：出队 6 个后再入队，队尾绕回数组开头，之后扩容要保持顺序
+			name: "绕回后扩容",
+			build: func(q *Queue[int]) {
+				for i := range 8 {
+					q.Enqueue(i)
+				}
+				for range 6 {
+					q.Dequeue()
+				}
+				for i := 8; i < 20; i++ {
+					q.Enqueue(i)
+				}
+			},
+			wantPeek: 6, wantOK: true,
+			want: []int{6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		{
+			name: "Clear 后可以继续使用",
+			build: func(q *Queue[int]) {
+				q.Enqueue(1)
+				q.Enqueue(2)
+				q.Clear()
+				q.Enqueue(3)
+			},
+			wantPeek: 3, wantOK: true,
+			want: []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueue[int]()
+			tt.build(q)
+
+			if got, ok := q.Peek(); got != tt.wantPeek || ok != tt.wantOK {
+				t.Errorf("Peek() = %d, %v，期望 %d, %v", got, ok, tt.wantPeek, tt.wantOK)
+			}
+			if got := q.Len(); got != len(tt.want) {
+				t.Errorf("Len() = %d，期望 %d", got, len(tt.want))
+			}
+			if got := q.IsEmpty(); got != (len(tt.want) == 0) {
+				t.Errorf("IsEmpty() = %v，期望 %v", got, len(tt.want) == 0)
+			}
+			if got := q.ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("ToSlice() = %v，期望 %v", got, tt.want)
+			}
+
+			// Peek 不出队，之后按顺序 Dequeue 得到同样的内容
+			var drained []int
+			for {
+				v, ok := q.Dequeue()
+				if !ok {
+					break
+				}
+				drained = append(drained, v)
+			}
+			if !slices.Equal(drained, tt.want) {
+				t.Errorf("依次出队得到 %v，期望 %v", drained, tt.want)
+			}
+			if v, ok := q.Dequeue(); ok || v != 0 {
+				t.Errorf("空队列 Dequeue() = %d, %v，期望 0, false", v, ok)
+			}
+		})
+	}
+}
+
+func TestQueueClearReleasesReferences(t *testing.T) {
+	q := NewQueue[*int]()
+	for i := range 4 {
+		q.Enqueue(&i)
+	}
+	q.Dequeue()
+	q.Clear()
+	// 出队和 Clear 都要清零缓冲区，否则已移出的元素无法被 GC 回收
+	for i, p := range q.buf {
+		if p != nil {
+			t.Fatalf("Clear 后 buf[%d] 仍引用元素", i)
+		}
+	}
+}
+
+func TestBlockingQueuePopContext(t *testing.T) {
+	q := NewBlockingQueue[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Pop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("空队列 Pop 返回 %v，期望 context.DeadlineExceeded", err)
+	}
+
+	go q.Push(42)
+	if v, err := q.Pop(context.Background()); v != 42 || err != nil {
+		t.Fatalf("Pop() = %d, %v，期望 42, nil", v, err)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("Len() = %d，期望 0", q.Len())
+	}
+}
@@ -0,0 +1,55 @@
+package container
+
+import (
+	"iter"
+	"maps"
+)
+
+// Set 泛型集合（基于 map），值类型用 struct{} 不占空间
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet 创建集合并加入 items
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add 加入元素，已存在时不变
+func (s *Set[T]) Add(item T) {
+	s.items[item] = struct{}{}
+}
+
+// Remove 删除元素，不存在时不变
+func (s *Set[T]) Remove(item T) {
+	delete(s.items, item)
+}
+
+// Contains 是否包含 item
+func (s *Set[T]) Contains(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+// Size 元素个数
+func (s *Set[T]) Size() int {
+	return len(s.items)
+}
+
+// All 遍历所有元素，顺序不确定
+func (s *Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s.items)
+}
+
+// ToSlice 以切片返回所有元素，顺序不确定
+func (s *Set[T]) ToSlice() []T {
+	result := make([]T, 0, len(s.items))
+	for item := range s.items {
+		result = append(result, item)
+	}
+	return result
+}
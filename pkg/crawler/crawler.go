@@ -0,0 +1,234 @@
+// ============================================
+// crawler 包：并发网页爬虫
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 2。
+//
+//   Crawl ──jobs──> worker × N ──results──> Crawl（协调者）
+//     ^                                        |
+//     └──── 新链接（未访问且未超过深度）入队 ────┘
+//
+// - 所有 worker 共享一个 http.Client（复用连接池）
+// - visited 与待抓取队列只由协调者 goroutine 访问，不需要加锁
+// - ctx 结束后不再派发新任务，等待进行中的请求返回后退出
+// ============================================
+
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes 单个页面最多读取的字节数
+const maxBodyBytes = 2 << 20
+
+var hrefPattern = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*["']([^"']+)["']`)
+
+// Config 爬虫配置
+type Config struct {
+	MaxDepth int          // 最大深度，起始页为 0
+	Workers  int          // 并发 worker 数，<= 0 时为 4
+	OutDir   string       // 页面保存目录，为空则不保存
+	SameHost bool         // 只跟随与起始页同域名的链接
+	Client   *http.Client // 为空时使用带 10 秒超时的默认 Client
+}
+
+// Page 一个页面的抓取结果
+type Page struct {
+	URL     string
+	Depth   int
+	Status  int
+	Links   []string // 页面中解析出的绝对链接（已去掉 #fragment）
+	SavedAs string   // 保存路径，未保存时为空
+	Err     error
+}
+
+type task struct {
+	url   string
+	depth int
+}
+
+// Crawler 并发爬虫
+type Crawler struct {
+	cfg    Config
+	client *http.Client
+}
+
+// New 创建爬虫
+func New(cfg Config) *Crawler {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Crawler{cfg: cfg, client: client}
+}
+
+// Crawl 从 start 开始抓取，返回所有抓取过的页面（按完成顺序）
+// ctx 结束时返回已完成的页面和 ctx.Err()
+func (c *Crawler) Crawl(ctx context.Context, start string) ([]Page, error) {
+	startURL, err := url.Parse(start)
+	if err != nil {
+		return nil, err
+	}
+	if startURL.Scheme != "http" && startURL.Scheme != "https" {
+		return nil, fmt.Errorf("不支持的协议: %q", startURL.Scheme)
+	}
+	startURL.Fragment = ""
+
+	jobs := make(chan task)
+	results := make(chan Page)
+	for range c.cfg.Workers {
+		go c.worker(ctx, jobs, results)
+	}
+
+	visited := map[string]bool{startURL.String(): true}
+	queue := []task{{url: startURL.String(), depth: 0}}
+	inFlight := 0
+	var pages []Page
+
+	ctxDone := ctx.Done()
+	for len(queue) > 0 || inFlight > 0 {
+		// 队列为空时 out 为 nil，select 不会选中发送分支
+		var out chan<- task
+		var next task
+		if len(queue) > 0 {
+			out, next = jobs, queue[0]
+		}
+
+		select {
+		case out <- next:
+			queue = queue[1:]
+			inFlight++
+		case page := <-results:
+			inFlight--
+			pages = append(pages, page)
+			if ctxDone == nil || page.Depth >= c.cfg.MaxDepth {
+				continue
+			}
+			for _, link := range page.Links {
+				if visited[link] || !c.follow(startURL, link) {
+					continue
+				}
+				visited[link] = true
+				queue = append(queue, task{url: link, depth: page.Depth + 1})
+			}
+		case <-ctxDone:
+			// 丢弃尚未派发的任务，继续循环等待进行中的请求返回；
+			// 置为 nil 避免已关闭的 Done 一直就绪导致空转
+			queue = nil
+			ctxDone = nil
+		}
+	}
+	close(jobs)
+	return pages, ctx.Err()
+}
+
+// follow 判断是否跟随链接
+func (c *Crawler) follow(start *url.URL, link string) bool {
+	if !c.cfg.SameHost {
+		return true
+	}
+	u, err := url.Parse(link)
+	return err == nil && u.Host == start.Host
+}
+
+func (c *Crawler) worker(ctx context.Context, jobs <-chan task, results chan<- Page) {
+	for t := range jobs {
+		results <- c.fetch(ctx, t)
+	}
+}
+
+// fetch 抓取一个页面，解析链接并按需保存
+func (c *Crawler) fetch(ctx context.Context, t task) Page {
+	page := Page{URL: t.url, Depth: t.depth}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		page.Err = err
+		return page
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		page.Err = err
+		return page
+	}
+	defer resp.Body.Close()
+
+	page.Status = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		page.Err = fmt.Errorf("HTTP %s", resp.Status)
+		return page
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		page.Err = err
+		return page
+	}
+
+	if isHTML(resp.Header.Get("Content-Type")) {
+		page.Links = extractLinks(resp.Request.URL, body)
+	}
+	if c.cfg.OutDir != "" {
+		page.SavedAs, page.Err = save(c.cfg.OutDir, resp.Request.URL, body)
+	}
+	return page
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
+
+// extractLinks 提取页面中的 http/https 链接，相对链接基于 base 解析，结果去重
+func extractLinks(base *url.URL, body []byte) []string {
+	seen := make(map[string]bool)
+	var links []string
+	for _, m := range hrefPattern.FindAllSubmatch(body, -1) {
+		u, err := base.Parse(strings.TrimSpace(string(m[1])))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		u.Fragment = ""
+		link := u.String()
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// save 把页面保存到 dir/<host>/<path>，目录形式的路径保存为 index.html
+func save(dir string, u *url.URL, body []byte) (string, error) {
+	// path.Clean 以 "/" 开头时会消除所有 ".."，保证文件不会写到 dir 之外
+	p := path.Clean("/" + u.Path)
+	if strings.HasSuffix(u.Path, "/") || p == "/" {
+		p = path.Join(p, "index.html")
+	}
+	if u.RawQuery != "" {
+		p += "_" + url.PathEscape(u.RawQuery)
+	}
+	name := filepath.Join(dir, u.Host, filepath.FromSlash(p))
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
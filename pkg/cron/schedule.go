@@ -0,0 +1,216 @@
+// ============================================
+// cron 包：cron 表达式与定时任务调度
+// ============================================
+//
+// 标准 5 段表达式：
+//
+//   ┌───────── 分钟 0-59
+//   │ ┌─────── 小时 0-23
+//   │ │ ┌───── 日   1-31
+//   │ │ │ ┌─── 月   1-12 或 JAN-DEC
+//   │ │ │ │ ┌─ 星期 0-6 或 SUN-SAT（7 也表示周日）
+//   * * * * *
+//
+// 每段支持 *、数字、范围 a-b、列表 a,b,c、步长 */n 和 a-b/n，
+// 另有 @yearly @monthly @weekly @daily @hourly 简写。
+//
+// 日和星期都被限定（都不是 *）时，两者满足其一即可，与 Vixie cron 一致：
+// "0 0 1 * MON" 表示每月 1 号以及每个周一。
+// ============================================
+
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrSyntax = errors.New("cron: 表达式语法错误")
+
+// field 一段的取值范围
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "分钟", min: 0, max: 59}
+	hourField   = field{name: "小时", min: 0, max: 23}
+	domField    = field{name: "日", min: 1, max: 31}
+	monthField  = field{name: "月", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	dowField = field{name: "星期", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule 解析后的表达式，每段用位图表示允许的取值
+type Schedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+// Parse 解析 5 段 cron 表达式或 @ 简写
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("%w: %q 需要 5 段，实际 %d 段", ErrSyntax, expr, len(parts))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	fields := []struct {
+		dst *uint64
+		f   field
+	}{{&s.minute, minuteField}, {&s.hour, hourField}, {&s.dom, domField}, {&s.month, monthField}, {&s.dow, dowField}}
+	for i, fd := range fields {
+		if *fd.dst, err = parseField(parts[i], fd.f); err != nil {
+			return nil, fmt.Errorf("%w（表达式 %q）", err, expr)
+		}
+	}
+	// 星期 7 与 0 都表示周日
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domRestricted = parts[2] != "*" && !strings.HasPrefix(parts[2], "*/")
+	s.dowRestricted = parts[4] != "*" && !strings.HasPrefix(parts[4], "*/")
+	return s, nil
+}
+
+// MustParse 与 Parse 相同，出错时 panic，用于包级变量初始化
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String 返回原始表达式
+func (s *Schedule) String() string { return s.expr }
+
+// parseField 解析一段，返回允许取值的位图
+func parseField(text string, f field) (uint64, error) {
+	var set uint64
+	for item := range strings.SplitSeq(text, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: %s段的步长 %q 无效", ErrSyntax, f.name, stepPart)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+			if f.max == 7 {
+				hi = 6 // 星期的 * 不重复包含 7
+			}
+		default:
+			loText, hiText, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(loText); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiText); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max // "5/15" 表示从 5 开始每 15 个
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("%w: %s段的范围 %q 起点大于终点", ErrSyntax, f.name, rangePart)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value 解析一个数字或名字并检查范围
+func (f field) value(text string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(text)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s段的值 %q 无效", ErrSyntax, f.name, text)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: %s段的值 %d 超出范围 %d-%d", ErrSyntax, f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// maxSearch 找不到下一次执行时间时放弃的年限（如 "0 0 30 2 *" 永远不会发生）
+const maxSearch = 5
+
+// Next 返回严格晚于 t 的下一次执行时间，使用 t 的时区；不存在时返回零值
+//
+// 从 t 的下一分钟开始逐段调整：月不匹配就跳到下个月 1 号 0 点，
+// 日不匹配就跳到明天 0 点，依此类推，每次跳转后重新检查更高的段。
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearch, 0, 0)
+	loc := t.Location()
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 日和星期的组合规则
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
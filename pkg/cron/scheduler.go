@@ -0,0 +1,292 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// ============================================
+// Scheduler：按 cron 表达式执行任务
+// ============================================
+//
+// Run 循环只做一件事：睡到最早的下一次执行时间，醒来后启动所有到期的任务。
+// 任务在各自的 goroutine 中执行，慢任务不会推迟其他任务。
+//
+// 同一个任务上一次还没跑完、下一次又到期时，按 OverlapPolicy 处理：
+//   Allow  直接再启动一个
+//   Skip   放弃这一次（默认，最不容易把机器拖垮）
+//   Queue  等上一次结束后立即执行，最多积压一次
+//
+// 任务 panic 会被恢复并记录日志，不影响调度器和其他任务。
+// 时间来自 clock.Clock，测试中用 FakeClock 可以在毫秒内"跑完"一整天。
+
+// Job 一次任务执行，ctx 在调度器停止时取消
+type Job func(ctx context.Context) error
+
+// OverlapPolicy 上一次执行未结束时的处理方式
+type OverlapPolicy int
+
+const (
+	Skip OverlapPolicy = iota
+	Allow
+	Queue
+)
+
+func (p OverlapPolicy) String() string {
+	switch p {
+	case Skip:
+		return "skip"
+	case Allow:
+		return "allow"
+	case Queue:
+		return "queue"
+	default:
+		return fmt.Sprintf("OverlapPolicy(%d)", int(p))
+	}
+}
+
+var (
+	ErrDuplicateJob = errors.New("cron: 任务名已存在")
+	ErrNeverRuns    = errors.New("cron: 表达式永远不会触发")
+)
+
+// Stats 一个任务的执行统计
+type Stats struct {
+	Runs    int // 开始执行的次数
+	Skipped int // 因上一次未结束而放弃的次数
+	Failed  int // 返回错误或 panic 的次数
+	Panics  int
+}
+
+// Entry 任务的当前状态快照
+type Entry struct {
+	Name     string
+	Schedule *Schedule
+	Policy   OverlapPolicy
+	Next     time.Time
+	Prev     time.Time // 上一次到期的时间，从未到期时为零值
+	Running  int       // 正在执行的次数
+	Stats    Stats
+}
+
+type entry struct {
+	name     string
+	schedule *Schedule
+	policy   OverlapPolicy
+	job      Job
+	next     time.Time
+	prev     time.Time
+
+	// running 当前正在执行的次数，queued 是否已有一次在排队（Queue 策略）
+	running int
+	queued  bool
+	stats   Stats
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	clock  clock.Clock
+	logger *log.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	wake    chan struct{} // Add 之后唤醒 Run 重新计算最早的执行时间
+	wg      sync.WaitGroup
+}
+
+// Option 配置 Scheduler
+type Option func(*Scheduler)
+
+// WithClock 替换时钟，默认 clock.Real
+func WithClock(c clock.Clock) Option {
+	return func(s *Scheduler) { s.clock = clock.Or(c) }
+}
+
+// WithLogger 设置记录任务错误和 panic 的日志，默认 log.Default()
+func WithLogger(l *log.Logger) Option {
+	return func(s *Scheduler) { s.logger = l }
+}
+
+// New 创建调度器
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		clock:   clock.Real,
+		logger:  log.Default(),
+		entries: make(map[string]*entry),
+		wake:    make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add 注册任务，Run 之前或运行中都可以调用
+func (s *Scheduler) Add(name, spec string, policy OverlapPolicy, job Job) error {
+	sched, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, name)
+	}
+	next := sched.Next(s.clock.Now())
+	if next.IsZero() {
+		return fmt.Errorf("%w: %q", ErrNeverRuns, spec)
+	}
+	s.entries[name] = &entry{name: name, schedule: sched, policy: policy, job: job, next: next}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Remove 删除任务，正在执行的那一次不受影响
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	delete(s.entries, name)
+	s.mu.Unlock()
+}
+
+// Entries 按下一次执行时间排序返回所有任务
+func (s *Scheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		out = append(out, Entry{Name: e.name, Schedule: e.schedule, Policy: e.policy, Next: e.next, Prev: e.prev, Running: e.running, Stats: e.stats})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Next.Equal(out[j].Next) {
+			return out[i].Next.Before(out[j].Next)
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Run 调度任务直到 ctx 取消，然后等待正在执行的任务返回
+// 任务收到的 ctx 就是这里的 ctx，可以据此提前结束
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wg.Wait()
+
+	for {
+		timer := s.clock.NewTimer(s.untilNext())
+		select {
+		case <-timer.C():
+			s.runDue(ctx)
+		case <-s.wake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// idleWait 没有任何任务时的等待时间，Add 会提前唤醒
+const idleWait = time.Hour
+
+// untilNext 距最早的下一次执行还有多久
+func (s *Scheduler) untilNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var earliest time.Time
+	for _, e := range s.entries {
+		if earliest.IsZero() || e.next.Before(earliest) {
+			earliest = e.next
+		}
+	}
+	if earliest.IsZero() {
+		return idleWait
+	}
+	return max(earliest.Sub(s.clock.Now()), 0)
+}
+
+// runDue 启动所有到期的任务并计算它们的下一次执行时间
+func (s *Scheduler) runDue(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	for _, e := range s.entries {
+		if e.next.After(now) {
+			continue
+		}
+		e.prev = e.next
+		// 从 now 而不是 e.next 计算下一次：调度器被阻塞过久时不补跑错过的多次
+		e.next = e.schedule.Next(now)
+		s.dispatch(ctx, e)
+	}
+}
+
+// dispatch 按重叠策略决定是否启动一次执行，调用方持有 mu
+func (s *Scheduler) dispatch(ctx context.Context, e *entry) {
+	if e.running > 0 {
+		switch e.policy {
+		case Skip:
+			e.stats.Skipped++
+			return
+		case Queue:
+			if e.queued {
+				e.stats.Skipped++
+			} else {
+				e.queued = true
+			}
+			return
+		}
+	}
+	s.start(ctx, e)
+}
+
+// start 在新的 goroutine 中执行一次任务，调用方持有 mu
+func (s *Scheduler) start(ctx context.Context, e *entry) {
+	e.running++
+	e.stats.Runs++
+	s.wg.Go(func() {
+		err := s.invoke(ctx, e)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			e.stats.Failed++
+		}
+		e.running--
+		// 排队的一次在上一次结束后立即执行；调度器已停止时放弃
+		if e.queued && e.running == 0 {
+			e.queued = false
+			if ctx.Err() == nil {
+				s.start(ctx, e)
+			}
+		}
+	})
+}
+
+// invoke 执行任务，把 panic 转换为错误并记录
+func (s *Scheduler) invoke(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.mu.Lock()
+			e.stats.Panics++
+			s.mu.Unlock()
+			err = fmt.Errorf("panic: %v", v)
+			s.logger.Printf("cron: 任务 %s panic: %v\n%s", e.name, v, debug.Stack())
+		}
+	}()
+	if err := e.job(ctx); err != nil {
+		s.logger.Printf("cron: 任务 %s 失败: %v", e.name, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cryptutil
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// 密码哈希
+// ============================================
+//
+// 保存密码时只保存慢哈希，不保存明文，也不能用 SHA-256 这类快哈希：
+// 快哈希每秒能算几十亿次，泄露后很快会被暴力破解。
+// PBKDF2 迭代 60 万次，单次验证约 0.1 秒，对登录无感，对暴力破解是 60 万倍的代价。
+//
+// 存储格式（与 PHC 字符串格式一致，参数和盐都在字符串里，不需要额外的列）：
+//
+//   $pbkdf2-sha256$i=600000$<base64 盐>$<base64 哈希>
+//
+// 以后提高迭代次数时，旧的哈希仍然可以验证；登录成功后用 NeedsRehash 判断，
+// 需要时用新参数重新计算并保存。
+
+const (
+	// passwordScheme 存储格式中的算法名
+	passwordScheme = "pbkdf2-sha256"
+	// maxPasswordIterations 解析时允许的最大迭代次数，防止被篡改的哈希让一次验证耗时几分钟
+	maxPasswordIterations = 100 * Iterations
+)
+
+var (
+	ErrPasswordMismatch = errors.New("cryptutil: 密码不正确")
+	ErrPasswordHash     = errors.New("cryptutil: 无法识别的密码哈希")
+)
+
+// PasswordHasher 密码哈希的参数；零值使用 Iterations
+type PasswordHasher struct {
+	Iterations int
+}
+
+func (h PasswordHasher) iterations() int {
+	if h.Iterations <= 0 {
+		return Iterations
+	}
+	return h.Iterations
+}
+
+// Hash 用随机盐计算 password 的哈希，返回带参数的存储字符串
+func (h PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, SaltSize)
+	rand.Read(salt)
+	iter := h.iterations()
+	key, err := pbkdf2.Key(sha256.New, password, salt, iter, KeySize)
+	if err != nil {
+		return "", err
+	}
+	b64 := base64.RawStdEncoding
+	return fmt.Sprintf("$%s$i=%d$%s$%s", passwordScheme, iter, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// NeedsRehash 哈希的迭代次数低于当前参数时返回 true
+func (h PasswordHasher) NeedsRehash(encoded string) bool {
+	p, err := parsePasswordHash(encoded)
+	return err != nil || p.iter < h.iterations()
+}
+
+// HashPassword 用默认参数计算密码哈希
+func HashPassword(password string) (string, error) {
+	return PasswordHasher{}.Hash(password)
+}
+
+// VerifyPassword 检查 password 是否与 HashPassword 的结果匹配
+// 不匹配时返回 ErrPasswordMismatch，encoded 格式不对时返回 ErrPasswordHash；
+// 使用 encoded 中记录的参数，与当前的默认参数无关
+func VerifyPassword(password, encoded string) error {
+	p, err := parsePasswordHash(encoded)
+	if err != nil {
+		return err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, p.salt, p.iter, len(p.key))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key, p.key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+type passwordHash struct {
+	iter      int
+	salt, key []byte
+}
+
+// parsePasswordHash 解析 $pbkdf2-sha256$i=N$salt$key
+func parsePasswordHash(encoded string) (passwordHash, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 5 || fields[0] != "" || fields[1] != passwordScheme {
+		return passwordHash{}, ErrPasswordHash
+	}
+	iterText, ok := strings.CutPrefix(fields[2], "i=")
+	iter, err := strconv.Atoi(iterText)
+	if !ok || err != nil || iter <= 0 || iter > maxPasswordIterations {
+		return passwordHash{}, fmt.Errorf("%w: 迭代次数 %q", ErrPasswordHash, fields[2])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(fields[3])
+	if err != nil {
+		return passwordHash{}, fmt.Errorf("%w: 盐: %v", ErrPasswordHash, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil || len(key) == 0 {
+		return passwordHash{}, fmt.Errorf("%w: 哈希值", ErrPasswordHash)
+	}
+	return passwordHash{iter: iter, salt: salt, key: key}, nil
+}
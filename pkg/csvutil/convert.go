@@ -0,0 +1,280 @@
+package csvutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ============================================
+// CSV <-> JSON 流式转换
+// ============================================
+//
+// CSVToJSON 把带表头的 CSV 转为 JSON 对象数组，键的顺序与表头一致。
+// 列类型由前 sampleRows 行推断：全部是整数为 number，全部是数字为 number，
+// 全部是 true/false 为 boolean，否则为 string；空单元格输出 null。
+// 样本之后的行不能再改变列类型，转换失败的值按字符串输出。
+//
+// JSONToCSV 反向转换：表头取第一个对象的键（保持文档中的顺序），
+// 之后的对象缺少的键输出空单元格，多出的键报错。
+//
+// 两个方向都逐行读写，内存占用与文件大小无关（只缓存样本行）。
+
+var (
+	ErrSchema  = errors.New("记录与表头不一致")
+	ErrNotList = errors.New("JSON 必须是对象数组")
+)
+
+// sampleRows 推断列类型时查看的行数
+const sampleRows = 100
+
+// colType 推断出的列类型，按从窄到宽排列
+type colType int
+
+const (
+	typeUnknown colType = iota // 样本中全为空
+	typeInt
+	typeFloat
+	typeBool
+	typeString
+)
+
+// CSVToJSON 从 r 读取 CSV，把 JSON 数组写入 w
+func CSVToJSON(r io.Reader, w io.Writer) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// 先读入样本行推断类型
+	var sample [][]string
+	for len(sample) < sampleRows {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		sample = append(sample, record)
+	}
+	types := make([]colType, len(header))
+	for _, record := range sample {
+		for i, v := range record {
+			types[i] = widen(types[i], v)
+		}
+	}
+
+	// 键只需要编码一次
+	keys := make([][]byte, len(header))
+	for i, h := range header {
+		if keys[i], err = json.Marshal(h); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	n := 0
+	writeRow := func(record []string) error {
+		if n > 0 {
+			bw.WriteString(",")
+		}
+		n++
+		bw.WriteString("\n  {")
+		for i, v := range record {
+			if i > 0 {
+				bw.WriteString(", ")
+			}
+			bw.Write(keys[i])
+			bw.WriteString(": ")
+			if err := writeValue(bw, types[i], v); err != nil {
+				return err
+			}
+		}
+		bw.WriteString("}")
+		return nil
+	}
+
+	for _, record := range sample {
+		if err := writeRow(record); err != nil {
+			return err
+		}
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeRow(record); err != nil {
+			return err
+		}
+	}
+	if n > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// widen 根据新值 v 放宽列类型
+func widen(t colType, v string) colType {
+	if v == "" || t == typeString {
+		return t
+	}
+	switch {
+	case (t == typeUnknown || t == typeInt) && isInt(v):
+		return typeInt
+	case (t == typeUnknown || t == typeInt || t == typeFloat) && isFloat(v):
+		return typeFloat
+	case (t == typeUnknown || t == typeBool) && isBool(v):
+		return typeBool
+	}
+	return typeString
+}
+
+// isInt 和 isFloat 还要求 v 本身是合法的 JSON 数字：
+// "007"、"+5"、"NaN" 能被 strconv 解析，但直接写进 JSON 不合法，按字符串处理
+func isInt(v string) bool {
+	_, err := strconv.ParseInt(v, 10, 64)
+	return err == nil && json.Valid([]byte(v))
+}
+
+func isFloat(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil && json.Valid([]byte(v))
+}
+
+func isBool(v string) bool {
+	return v == "true" || v == "false"
+}
+
+func writeValue(w *bufio.Writer, t colType, v string) error {
+	switch {
+	case v == "":
+		_, err := w.WriteString("null")
+		return err
+	case t == typeInt && isInt(v),
+		t == typeFloat && isFloat(v),
+		t == typeBool && isBool(v):
+		_, err := w.WriteString(v)
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// JSONToCSV 从 r 读取 JSON 对象数组，把 CSV 写入 w
+func JSONToCSV(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	var header []string
+	var index map[string]int
+	for row := 1; dec.More(); row++ {
+		keys, values, err := decodeObject(dec)
+		if err != nil {
+			return fmt.Errorf("第 %d 个对象: %w", row, err)
+		}
+
+		if header == nil {
+			header = keys
+			index = make(map[string]int, len(keys))
+			for i, k := range keys {
+				index[k] = i
+			}
+			if err := cw.Write(header); err != nil {
+				return err
+			}
+		}
+
+		record := make([]string, len(header))
+		for i, k := range keys {
+			col, ok := index[k]
+			if !ok {
+				return fmt.Errorf("第 %d 个对象: %w: 多出的键 %q", row, ErrSchema, k)
+			}
+			record[col] = values[i]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// decodeObject 读取一个对象，按文档顺序返回键和转换为 CSV 单元格的值
+func decodeObject(dec *json.Decoder) (keys, values []string, err error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string) // 对象中 More 为 true 时下一个 token 一定是键
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, cellOf(raw))
+	}
+	return keys, values, expectDelim(dec, '}')
+}
+
+// cellOf 字符串去掉引号，null 为空，数字和布尔值原样，嵌套的对象和数组保留紧凑 JSON
+func cellOf(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case string(raw) == "null":
+		return ""
+	case raw[0] == '"':
+		var s string
+		json.Unmarshal(raw, &s)
+		return s
+	case raw[0] == '{' || raw[0] == '[':
+		var buf bytes.Buffer
+		json.Compact(&buf, raw)
+		return buf.String()
+	}
+	return string(raw)
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("%w: 期望 %v，实际是 %v", ErrNotList, want, tok)
+	}
+	return nil
+}
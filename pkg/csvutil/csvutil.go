@@ -0,0 +1,252 @@
+// ============================================
+// csvutil 包：CSV 与结构体切片互转
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 4：
+// - Read 按表头把每行记录解析为 T，字段用 `csv:"列名"` 标签对应
+// - 用 strconv 做类型转换，支持字符串、整数、无符号整数、浮点数和布尔值，
+//   以及实现了 encoding.TextMarshaler / TextUnmarshaler 的类型（time.Time、jsontype.Date 等）
+// - Write 把 []T 写回 CSV，第一行是表头
+// - Filter / SortBy 接收调用方注入的谓词和比较函数
+//
+// 没有 csv 标签的导出字段使用字段名作为列名，标签为 "-" 的字段被忽略。
+// CSV 中多出来的列会被忽略，结构体中有但 CSV 中缺少的列会报错。
+// ============================================
+
+package csvutil
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"c03/pkg/structmeta"
+)
+
+var (
+	ErrUnsupportedType = errors.New("不支持的字段类型")
+	ErrMissingColumn   = errors.New("CSV 缺少列")
+	ErrBadValue        = errors.New("无法转换的值")
+)
+
+// column 一个结构体字段与 CSV 列的对应关系
+type column struct {
+	name  string
+	index int // 字段在结构体中的下标
+}
+
+// Read 读取带表头的 CSV，把每条记录解析为 T
+func Read[T any](r io.Reader) ([]T, error) {
+	cols, err := columnsOf[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// pos[i] 是 cols[i] 在 CSV 记录中的位置
+	pos := make([]int, len(cols))
+	for i, c := range cols {
+		pos[i] = slices.Index(header, c.name)
+		if pos[i] < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, c.name)
+		}
+	}
+
+	var rows []T
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		var row T
+		v := reflect.ValueOf(&row).Elem()
+		for i, c := range cols {
+			if err := setField(v.Field(c.index), record[pos[i]]); err != nil {
+				return nil, fmt.Errorf("第 %d 行 %s 列: %w", line, c.name, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// Write 把 rows 写为带表头的 CSV
+func Write[T any](w io.Writer, rows []T) error {
+	cols, err := columnsOf[T]()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(cols))
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		for i, c := range cols {
+			if record[i], err = formatField(v.Field(c.index)); err != nil {
+				return fmt.Errorf("%s 列: %w", c.name, err)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Filter 返回满足 keep 的记录组成的新切片，不修改 rows
+func Filter[T any](rows []T, keep func(T) bool) []T {
+	var out []T
+	for _, row := range rows {
+		if keep(row) {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// SortBy 返回按 cmp 稳定排序后的新切片，不修改 rows
+// cmp 的约定与 slices.SortFunc 相同：a < b 返回负数
+func SortBy[T any](rows []T, cmp func(a, b T) int) []T {
+	out := slices.Clone(rows)
+	slices.SortStableFunc(out, cmp)
+	return out
+}
+
+// columnsOf 解析 T 的字段标签，T 必须是结构体
+// 字段和标签来自 structmeta 的缓存，同一个类型反复读写时不会重新解析
+func columnsOf[T any]() ([]column, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %v 不是结构体", ErrUnsupportedType, t)
+	}
+	st, err := structmeta.Of(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []column
+	for _, f := range st.Fields {
+		name := f.Tag("csv").Raw
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if !supported(f.Type) {
+			return nil, fmt.Errorf("%w: %s %v", ErrUnsupportedType, f.Name, f.Type)
+		}
+		cols = append(cols, column{name: name, index: f.Index[0]})
+	}
+	return cols, nil
+}
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isText 值可以编码为文本、指针可以从文本解码
+func isText(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) && reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func supported(t reflect.Type) bool {
+	if isText(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setField 用 strconv 把 s 转换为字段的类型，位数按字段类型检查溢出
+func setField(v reflect.Value, s string) error {
+	if isText(v.Type()) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("%w: %v", ErrBadValue, err)
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%w: %q 不是布尔值", ErrBadValue, s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q 不是 %v", ErrBadValue, s, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q 不是 %v", ErrBadValue, s, v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q 不是 %v", ErrBadValue, s, v.Type())
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+func formatField(v reflect.Value) (string, error) {
+	if isText(v.Type()) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,133 @@
+// ============================================
+// ctxutil 包：Context 辅助工具
+// ============================================
+//
+// 1. Key[T]：带类型的 context 键
+//    context.WithValue 的键和值都是 any，取值时要自己做类型断言，
+//    键也容易和别的包冲突。Key[T] 用指针身份区分键，值的类型由 T 固定：
+//
+//      var UserKey = ctxutil.NewKey[string]("user")
+//      ctx = UserKey.With(ctx, "alice")
+//      user, ok := UserKey.From(ctx)   // user 是 string，不需要断言
+//
+// 2. Merge(a, b)：a 或 b 任意一个结束，合并后的 ctx 就结束
+//    典型场景：请求的 ctx 与服务关闭的 ctx 同时生效。
+//
+// 3. Detach(ctx)：保留值，去掉取消和截止时间
+//    典型场景：请求返回后还要在后台写审计日志，又需要 ctx 中的请求 ID。
+// ============================================
+
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Key 类型为 T 的 context 键，必须通过 NewKey 创建并以指针使用
+type Key[T any] struct {
+	name string
+}
+
+// NewKey 创建新的键；name 只用于调试输出，同名的两个键互不相同
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// With 返回携带 v 的子 context
+func (k *Key[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// From 取出 k 对应的值；没有设置时返回零值和 false
+func (k *Key[T]) From(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// Get 与 From 相同，但只返回值，适合零值即默认值的场合
+func (k *Key[T]) Get(ctx context.Context) T {
+	v, _ := k.From(ctx)
+	return v
+}
+
+// String 便于在 fmt 输出中区分不同的键
+func (k *Key[T]) String() string { return "ctxutil.Key(" + k.name + ")" }
+
+// ============================================
+// Merge
+// ============================================
+
+// merged 以 a 的取消链为主体，另外监听 b
+type merged struct {
+	context.Context // context.WithCancel(a) 的结果
+	a, b            context.Context
+}
+
+// Merge 返回在 a 或 b 结束时结束的 context
+//
+//   - Deadline 取两者中较早的一个
+//   - Value 先查 a，找不到再查 b
+//   - Err 返回先结束的那一个的错误，context.Cause 同理
+//
+// 与 context.WithCancel 一样，用完后必须调用返回的 cancel 释放对 b 的监听。
+func Merge(a, b context.Context) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancelCause(a)
+	stop := context.AfterFunc(b, func() { cancel(context.Cause(b)) })
+	ctx := &merged{Context: inner, a: a, b: b}
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+func (m *merged) Deadline() (time.Time, bool) {
+	da, okA := m.a.Deadline()
+	db, okB := m.b.Deadline()
+	switch {
+	case !okA:
+		return db, okB
+	case !okB:
+		return da, okA
+	case db.Before(da):
+		return db, true
+	default:
+		return da, true
+	}
+}
+
+func (m *merged) Err() error {
+	if m.Context.Err() == nil {
+		return nil
+	}
+	// 因 b 结束而取消时，inner 只会报告 Canceled，这里换成 b 的真实原因
+	if err := m.a.Err(); err != nil {
+		return err
+	}
+	if err := m.b.Err(); err != nil {
+		return err
+	}
+	return context.Canceled
+}
+
+func (m *merged) Value(key any) any {
+	if v := m.Context.Value(key); v != nil {
+		return v
+	}
+	return m.b.Value(key)
+}
+
+// ============================================
+// Detach
+// ============================================
+
+// Detach 返回保留 ctx 所有值、但永远不会被取消也没有截止时间的 context
+//
+// 行为与标准库 context.WithoutCancel 相同，这里取一个更直白的名字，
+// 和 Merge 放在一起说明"值"与"生命周期"是可以分开处理的。
+// 需要新的超时时在结果上再套一层：
+//
+//	bg, cancel := context.WithTimeout(ctxutil.Detach(ctx), 5*time.Second)
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
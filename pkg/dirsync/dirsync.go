@@ -0,0 +1,235 @@
+// ============================================
+// dirsync 包：目录同步
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 5：
+// - 用 fsutil.WalkFiltered 分别遍历两个目录，按相对路径比较
+// - 根据修改时间决定是否复制：目标不存在或比源旧时复制
+// - 方向可以是 A -> B、B -> A 或双向（双向时较新的一方覆盖较旧的一方）
+// - 支持排除模式（path.Match 语法），同时匹配相对路径和文件名，无效模式返回 fsutil.ErrBadPattern
+// - DryRun 只生成计划，不修改任何文件
+//
+// 只同步普通文件，符号链接等特殊文件被跳过；不会删除任何文件。
+// 复制时先写临时文件再重命名，并保留修改时间，所以重复运行是幂等的。
+// ============================================
+
+package dirsync
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"c03/pkg/bufpool"
+	"c03/pkg/fsutil"
+)
+
+// Direction 同步方向
+type Direction int
+
+const (
+	AToB Direction = iota // 只把 A 中较新的文件复制到 B
+	BToA                  // 只把 B 中较新的文件复制到 A
+	Both                  // 双向，较新的一方覆盖较旧的一方
+)
+
+func (d Direction) String() string {
+	switch d {
+	case AToB:
+		return "a->b"
+	case BToA:
+		return "b->a"
+	case Both:
+		return "both"
+	default:
+		return "unknown"
+	}
+}
+
+// Options 同步选项
+type Options struct {
+	Direction Direction
+	Exclude   []string // 排除模式，如 "*.tmp"、".git"、"build/*"
+	DryRun    bool
+
+	// Progress 不为 nil 时，在开始复制前以待复制的总字节数调用一次，
+	// 复制的数据同时写入返回的 Writer（例如 cli.ProgressBar）
+	Progress func(total int64) io.Writer
+}
+
+// Action 一次复制（或计划中的复制）
+type Action struct {
+	Rel   string // 相对路径，使用 "/" 分隔
+	From  string
+	To    string
+	Bytes int64
+	Err   error
+}
+
+// Summary 同步结果汇总
+type Summary struct {
+	Copied   []Action // 已复制（DryRun 时为计划复制）的文件
+	Failed   []Action // 复制失败的文件
+	UpToDate int      // 两边一致或目标更新，无需复制
+	Bytes    int64    // 复制的总字节数
+}
+
+// fileInfo 遍历时记录的文件信息
+type fileInfo struct {
+	path    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// Sync 按 opts 同步目录 a 和 b
+// 单个文件复制失败不会中断同步，记录在 Summary.Failed 中
+func Sync(a, b string, opts Options) (Summary, error) {
+	var sum Summary
+	filesA, err := scan(a, opts.Exclude)
+	if err != nil {
+		return sum, err
+	}
+	filesB, err := scan(b, opts.Exclude)
+	if err != nil {
+		return sum, err
+	}
+
+	rels := make([]string, 0, len(filesA)+len(filesB))
+	for rel := range filesA {
+		rels = append(rels, rel)
+	}
+	for rel := range filesB {
+		if _, ok := filesA[rel]; !ok {
+			rels = append(rels, rel)
+		}
+	}
+	slices.Sort(rels)
+
+	// 先生成完整的计划，才能知道总共要复制多少字节
+	type step struct {
+		src *fileInfo
+		act Action
+	}
+	var steps []step
+	var total int64
+	for _, rel := range rels {
+		src, dst, ok := plan(rel, a, b, filesA[rel], filesB[rel], opts.Direction)
+		if !ok {
+			sum.UpToDate++
+			continue
+		}
+		steps = append(steps, step{src, Action{Rel: rel, From: src.path, To: dst, Bytes: src.size}})
+		total += src.size
+	}
+
+	progress := io.Discard
+	if opts.Progress != nil && !opts.DryRun {
+		progress = opts.Progress(total)
+	}
+	for _, st := range steps {
+		act := st.act
+		if !opts.DryRun {
+			act.Err = copyFile(st.src, act.To, progress)
+		}
+		if act.Err != nil {
+			sum.Failed = append(sum.Failed, act)
+			continue
+		}
+		sum.Copied = append(sum.Copied, act)
+		sum.Bytes += act.Bytes
+	}
+	return sum, nil
+}
+
+// plan 决定 rel 是否需要复制以及复制方向，fa / fb 为 nil 表示该侧不存在
+func plan(rel, a, b string, fa, fb *fileInfo, dir Direction) (src *fileInfo, dst string, ok bool) {
+	toB := filepath.Join(b, filepath.FromSlash(rel))
+	toA := filepath.Join(a, filepath.FromSlash(rel))
+
+	switch dir {
+	case AToB:
+		if fa != nil && newer(fa, fb) {
+			return fa, toB, true
+		}
+	case BToA:
+		if fb != nil && newer(fb, fa) {
+			return fb, toA, true
+		}
+	case Both:
+		switch {
+		case fa != nil && newer(fa, fb):
+			return fa, toB, true
+		case fb != nil && newer(fb, fa):
+			return fb, toA, true
+		}
+	}
+	return nil, "", false
+}
+
+// newer 判断 src 是否应该覆盖 dst：dst 不存在或修改时间更早
+func newer(src, dst *fileInfo) bool {
+	return dst == nil || src.modTime.After(dst.modTime)
+}
+
+// scan 遍历 root 下的普通文件，返回相对路径到文件信息的映射
+// root 不存在时视为空目录；隐藏文件也参与同步，需要时用排除模式跳过
+func scan(root string, exclude []string) (map[string]*fileInfo, error) {
+	files := make(map[string]*fileInfo)
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+
+	opts := fsutil.WalkOptions{Exclude: exclude, IncludeHidden: true}
+	for f, err := range fsutil.WalkFiltered(root, opts) {
+		if err != nil {
+			return nil, err
+		}
+		files[f.Rel] = &fileInfo{path: f.Path, size: f.Info.Size(), mode: f.Info.Mode().Perm(), modTime: f.Info.ModTime()}
+	}
+	return files, nil
+}
+
+// copyFile 复制 src 到 dst，先写同目录下的临时文件再重命名，
+// 复制中途失败不会留下写了一半的目标文件
+func copyFile(src *fileInfo, dst string, progress io.Writer) (err error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src.path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := bufpool.Copy(tmp, io.TeeReader(in, progress)); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp 创建的文件权限是 0600，改回源文件的权限
+	if err := os.Chmod(tmp.Name(), src.mode); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), src.modTime, src.modTime); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,356 @@
+// ============================================
+// download 包：支持断点续传的 HTTP 下载
+// ============================================
+//
+// 下载过程：
+//   1. HEAD 请求得到文件大小、是否支持 Range（Accept-Ranges: bytes）和 ETag
+//   2. 支持 Range 时把文件切成 Concurrency 块，每块一个 goroutine，
+//      用 Range: bytes=start-end 请求并用 WriteAt 写到各自的偏移处；
+//      不支持时退化为单连接下载
+//   3. 数据先写入 dest.part，进度记录在 dest.part.json；
+//      中断（Ctrl+C、网络错误）后再次下载同一个文件，每块从上次的位置继续
+//   4. 全部完成后校验 SHA-256（如果提供），再重命名为 dest
+//
+// 续传前会比较记录中的 URL、大小、ETag 和 Last-Modified，
+// 服务端文件变了就从头下载，不会把新旧两个版本拼在一起。
+// ============================================
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"c03/pkg/backoff"
+	"c03/pkg/bufpool"
+	"c03/pkg/hashutil"
+)
+
+var (
+	ErrChecksum = errors.New("校验和不匹配")
+	ErrStatus   = errors.New("意外的 HTTP 状态码")
+)
+
+// Options 下载选项
+type Options struct {
+	Client      *http.Client // 为空时使用 http.DefaultClient，超时由 ctx 控制
+	Concurrency int          // 分块数，<= 0 时为 4；服务端不支持 Range 时为 1
+	SHA256      string       // 期望的十六进制 SHA-256，为空则不校验
+
+	// Retries 每块遇到网络错误或 5xx/429 后的重试次数，默认 0 不重试；
+	// 重试从该块已写入的位置继续，所以只在服务端支持 Range 时生效
+	Retries int
+	Backoff backoff.Backoff // 重试前的等待时间，零值使用 backoff 包的默认值
+
+	// Progress 不为 nil 时，在开始传输前调用一次：total 是文件大小（未知时为 -1），
+	// done 是续传时已经下载的字节数；之后收到的数据同时写入返回的 Writer
+	Progress func(total, done int64) io.Writer
+}
+
+// state 续传记录，保存在 dest.part.json
+type state struct {
+	URL          string  `json:"url"`
+	Size         int64   `json:"size"`
+	Ranged       bool    `json:"ranged"`
+	ETag         string  `json:"etag,omitempty"`
+	LastModified string  `json:"last_modified,omitempty"`
+	Chunks       []chunk `json:"chunks"`
+}
+
+// chunk 闭区间 [Start, End]，Done 是已经写入的字节数
+// Done 会被下载 goroutine 并发更新，读写都使用 atomic
+type chunk struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	Done  int64 `json:"done"`
+}
+
+func (c *chunk) remaining() int64 {
+	return c.End - c.Start + 1 - atomic.LoadInt64(&c.Done)
+}
+
+// Download 把 url 下载到 dest
+func Download(ctx context.Context, url, dest string, opts Options) error {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	part, statePath := dest+".part", dest+".part.json"
+
+	remote, err := probe(ctx, opts.Client, url)
+	if err != nil {
+		return err
+	}
+
+	st := loadState(statePath)
+	if !st.matches(remote) || !partIntact(part, st) {
+		st = remote
+		st.Chunks = split(remote.Size, opts.Concurrency, remote.Ranged)
+		os.Remove(part)
+	}
+
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var done int64
+	for i := range st.Chunks {
+		done += st.Chunks[i].Done
+	}
+	progress := io.Discard
+	if opts.Progress != nil {
+		progress = opts.Progress(st.Size, done)
+	}
+
+	err = fetchChunks(ctx, opts, &st, f, progress)
+	if st.Ranged {
+		// 不支持 Range 时无法续传，不保存记录
+		if serr := saveState(statePath, &st); err == nil {
+			err = serr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if opts.SHA256 != "" {
+		sum, err := hashutil.SHA256File(part)
+		if err != nil {
+			return err
+		}
+		if !hashutil.EqualHex(sum, opts.SHA256) {
+			// 内容已经损坏，续传也无法修复，删除后下次从头下载
+			os.Remove(part)
+			os.Remove(statePath)
+			return fmt.Errorf("%w: 期望 %s，实际 %s", ErrChecksum, opts.SHA256, sum)
+		}
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return err
+	}
+	os.Remove(statePath)
+	return nil
+}
+
+// probe 用 HEAD 请求获取文件信息；服务端不支持 HEAD 时按大小未知处理
+func probe(ctx context.Context, client *http.Client, url string) (state, error) {
+	st := state{URL: url, Size: -1}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return st, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return st, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusMethodNotAllowed:
+		return st, nil
+	default:
+		return st, fmt.Errorf("%w: HEAD %s: %s", ErrStatus, url, resp.Status)
+	}
+
+	st.Size = resp.ContentLength
+	st.ETag = resp.Header.Get("ETag")
+	st.LastModified = resp.Header.Get("Last-Modified")
+	st.Ranged = st.Size > 0 && resp.Header.Get("Accept-Ranges") == "bytes"
+	return st, nil
+}
+
+// split 把 [0, size) 切成 n 块；不支持 Range 或大小未知时只有一块
+func split(size int64, n int, ranged bool) []chunk {
+	if !ranged || size <= 0 {
+		return []chunk{{Start: 0, End: size - 1}}
+	}
+	n = int(min(int64(n), size))
+	chunks := make([]chunk, n)
+	per := size / int64(n)
+	for i := range chunks {
+		chunks[i].Start = int64(i) * per
+		chunks[i].End = chunks[i].Start + per - 1
+	}
+	chunks[n-1].End = size - 1
+	return chunks
+}
+
+// fetchChunks 并发下载所有未完成的块，任意一块失败（重试用完）时取消其他块
+func fetchChunks(ctx context.Context, opts Options, st *state, f *os.File, progress io.Writer) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// 多个 goroutine 同时写 progress，加锁保证 Writer 不需要自己处理并发
+	var mu sync.Mutex
+	report := func(p []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Write(p)
+	}
+
+	retries := opts.Retries
+	if !st.Ranged {
+		retries = 0
+	}
+	var wg sync.WaitGroup
+	for i := range st.Chunks {
+		c := &st.Chunks[i]
+		if st.Ranged && c.remaining() == 0 {
+			continue
+		}
+		wg.Go(func() {
+			b := opts.Backoff // 每块一份独立的退避状态
+			for attempt := 0; ; attempt++ {
+				err := fetchChunk(ctx, opts.Client, st, c, f, report)
+				if err == nil {
+					return
+				}
+				if attempt >= retries || !retryable(err) || b.Sleep(ctx) != nil {
+					cancel(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// permanentError 重试也不会成功的错误（如 404）
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+func retryable(err error) bool {
+	var pe permanentError
+	return !errors.As(err, &pe) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// fetchChunk 下载一块剩余的部分
+func fetchChunk(ctx context.Context, client *http.Client, st *state, c *chunk, f *os.File, report func([]byte)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, st.URL, nil)
+	if err != nil {
+		return err
+	}
+	from := c.Start + atomic.LoadInt64(&c.Done)
+	if st.Ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", from, c.End))
+		// 文件在两次请求之间变了时，If-Range 让服务端返回完整的 200 而不是错位的片段
+		if v := cmpValidator(st); v != "" {
+			req.Header.Set("If-Range", v)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	want := http.StatusOK
+	if st.Ranged {
+		want = http.StatusPartialContent
+	}
+	if resp.StatusCode != want {
+		err := fmt.Errorf("%w: GET %s: %s", ErrStatus, st.URL, resp.Status)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return permanentError{err}
+		}
+		return err
+	}
+
+	w := io.NewOffsetWriter(f, from)
+	buf := bufpool.Get(32 << 10)
+	defer bufpool.Put(buf)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			// 数据写入文件之后才更新 Done，保存的记录永远不会超前于文件内容
+			atomic.AddInt64(&c.Done, int64(n))
+			report(buf[:n])
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	if st.Size >= 0 && c.remaining() != 0 {
+		// 连接提前断开，Read 也可能返回 EOF
+		return fmt.Errorf("download: 块 %d-%d 不完整，还差 %d 字节", c.Start, c.End, c.remaining())
+	}
+	return nil
+}
+
+// cmpValidator 强 ETag 优先，其次 Last-Modified；弱 ETag（W/ 开头）不能用于 If-Range
+func cmpValidator(st *state) string {
+	if st.ETag != "" && !strings.HasPrefix(st.ETag, "W/") {
+		return st.ETag
+	}
+	return st.LastModified
+}
+
+// matches 续传记录是否对应服务端上的同一个文件
+func (st state) matches(remote state) bool {
+	return st.Ranged && remote.Ranged &&
+		st.URL == remote.URL &&
+		st.Size == remote.Size &&
+		st.ETag == remote.ETag &&
+		st.LastModified == remote.LastModified &&
+		len(st.Chunks) > 0
+}
+
+// partIntact .part 文件存在，且大小足以容纳记录中已完成的数据
+func partIntact(part string, st state) bool {
+	info, err := os.Stat(part)
+	if err != nil {
+		return false
+	}
+	for _, c := range st.Chunks {
+		if c.Done > 0 && info.Size() < c.Start+c.Done {
+			return false
+		}
+	}
+	return true
+}
+
+func loadState(path string) state {
+	var st state
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &st) != nil {
+		return state{}
+	}
+	return st
+}
+
+func saveState(path string, st *state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,81 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+var (
+	ErrBusy     = errors.New("echo: 服务端连接数已满")
+	ErrMismatch = errors.New("echo: 回显内容不一致")
+)
+
+// Client 回显客户端，同一时刻只能被一个 goroutine 使用
+type Client struct {
+	conn    net.Conn
+	Timeout time.Duration // 每次 Echo 往返的最长时间，0 表示不限制
+}
+
+// Dial 连接到 addr 上的回显服务
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient 用已建立的连接创建客户端，例如 net.Pipe 的一端
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, Timeout: defaultWriteTimeout}
+}
+
+// Echo 发送 msg 并读回同样长度的数据
+// 服务端满载时返回 ErrBusy，收到的内容与发送的不同时返回 ErrMismatch
+func (c *Client) Echo(msg []byte) ([]byte, error) {
+	if c.Timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.Timeout))
+		defer c.conn.SetDeadline(time.Time{})
+	}
+
+	// net.Pipe 没有缓冲，写操作要等对方读走；服务端读一块回写一块，
+	// 所以这里边写边读，避免大消息时双方都卡在 Write 上
+	writeErr := make(chan error, 1)
+	go func() {
+		_, err := c.conn.Write(msg)
+		writeErr <- err
+	}()
+
+	got := make([]byte, len(msg))
+	n, err := io.ReadFull(c.conn, got)
+	if err != nil {
+		// 让仍在写的 goroutine 立即返回
+		c.conn.SetDeadline(time.Now())
+	}
+	werr := <-writeErr
+	// 服务端满载时回一行 BusyMessage 就关闭连接
+	if n > 0 && !bytes.Equal(got[:n], msg[:n]) && bytes.HasPrefix([]byte(BusyMessage), got[:n]) {
+		return nil, ErrBusy
+	}
+	if err != nil {
+		return nil, err
+	}
+	if werr != nil {
+		return nil, werr
+	}
+	if !bytes.Equal(got, msg) {
+		return got, fmt.Errorf("%w: 发送 %q，收到 %q", ErrMismatch, msg, got)
+	}
+	return got, nil
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,172 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.Logger = log.New(io.Discard, "", 0)
+	return s
+}
+
+// pipe 把 net.Pipe 的一端交给 s.Handle，返回另一端上的客户端和 Handle 结束的信号
+func pipe(t *testing.T, s *Server) (*Client, <-chan struct{}) {
+	t.Helper()
+	serverSide, clientSide := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		s.Handle(serverSide)
+		close(done)
+	}()
+	c := NewClient(clientSide)
+	c.Timeout = time.Second
+	t.Cleanup(func() {
+		c.Close()
+		<-done
+	})
+	return c, done
+}
+
+func TestPipeRoundTrip(t *testing.T) {
+	c, _ := pipe(t, newTestServer())
+	large := bytes.Repeat([]byte("0123456789"), 10<<10) // 100KB，大于服务端缓冲区
+	for _, msg := range [][]byte{[]byte("ping"), []byte("你好，世界"), {0}, large} {
+		got, err := c.Echo(msg)
+		if err != nil {
+			t.Fatalf("Echo(%d 字节) 失败: %v", len(msg), err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("Echo(%d 字节) 收到的内容不一致", len(msg))
+		}
+	}
+}
+
+func TestPipeIdleTimeout(t *testing.T) {
+	s := newTestServer()
+	s.IdleTimeout = 50 * time.Millisecond
+	c, done := pipe(t, s)
+	if _, err := c.Echo([]byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 客户端不再发送，服务端应在 IdleTimeout 后关闭连接
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("空闲超时后服务端没有关闭连接")
+	}
+	if _, err := c.Echo([]byte("late")); err == nil {
+		t.Fatal("连接关闭后 Echo 应返回错误")
+	}
+}
+
+func TestClientBusy(t *testing.T) {
+	s := newTestServer()
+	serverSide, clientSide := net.Pipe()
+	go s.reject(serverSide)
+
+	c := NewClient(clientSide)
+	defer c.Close()
+	if _, err := c.Echo([]byte("hello world")); !errors.Is(err, ErrBusy) {
+		t.Fatalf("Echo 返回 %v，期望 ErrBusy", err)
+	}
+}
+
+func TestClientMismatch(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	go func() {
+		defer serverSide.Close()
+		buf := make([]byte, 4)
+		io.ReadFull(serverSide, buf)
+		serverSide.Write([]byte("pong"))
+	}()
+
+	c := NewClient(clientSide)
+	defer c.Close()
+	if _, err := c.Echo([]byte("ping")); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("Echo 返回 %v，期望 ErrMismatch", err)
+	}
+}
+
+// serve 在随机端口上启动 s，返回地址和停止函数；停止函数等待 Serve 返回
+func serve(t *testing.T, s *Server) (string, func()) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听本地端口: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Serve(ctx, ln) }()
+	return ln.Addr().String(), func() {
+		cancel()
+		if err := <-errc; err != nil {
+			t.Errorf("Serve 返回 %v，期望 nil", err)
+		}
+	}
+}
+
+func TestServeMaxConns(t *testing.T) {
+	s := newTestServer()
+	s.MaxConns = 1
+	addr, stop := serve(t, s)
+	defer stop()
+
+	first, err := Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	// 往返一次，确保第一个连接已被登记
+	if _, err := first.Echo([]byte("one")); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if _, err := second.Echo([]byte("two")); !errors.Is(err, ErrBusy) {
+		t.Fatalf("超出 MaxConns 的连接 Echo 返回 %v，期望 ErrBusy", err)
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	s := newTestServer()
+	s.DrainTimeout = 50 * time.Millisecond
+	addr, stop := serve(t, s)
+
+	c, err := Dial(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.Echo([]byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.ActiveConns(); n != 1 {
+		t.Fatalf("ActiveConns() = %d，期望 1", n)
+	}
+
+	// 客户端一直不断开：超过 DrainTimeout 后被强制关闭，stop 不会永远阻塞
+	start := time.Now()
+	stop()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("关闭耗时 %v，DrainTimeout 没有生效", elapsed)
+	}
+	if n := s.ActiveConns(); n != 0 {
+		t.Fatalf("关闭后 ActiveConns() = %d，期望 0", n)
+	}
+	if _, err := Dial(context.Background(), addr); err == nil {
+		t.Fatal("关闭后仍能建立连接")
+	}
+}
@@ -0,0 +1,210 @@
+// ============================================
+// echo 包：TCP 回显服务与客户端
+// ============================================
+//
+//   Serve ──Accept──> handle × N（每个连接一个 goroutine）
+//
+// - 每次读写都设置截止时间：客户端空闲超过 IdleTimeout 或
+//   写出卡住超过 WriteTimeout 就断开，不会永远占着 goroutine
+// - 同时处理的连接数超过 MaxConns 时，新连接收到一行 "busy" 后立即关闭
+// - ctx 取消后关闭监听，等待进行中的连接结束；
+//   超过 DrainTimeout 仍未结束的连接被强制关闭
+//
+// Handle 只依赖 net.Conn，可以直接用 net.Pipe 在内存中驱动，不需要真实端口。
+// ============================================
+
+package echo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"c03/pkg/bufpool"
+)
+
+const (
+	defaultIdleTimeout  = 60 * time.Second
+	defaultWriteTimeout = 10 * time.Second
+	defaultDrainTimeout = 5 * time.Second
+	bufSize             = 4 << 10
+)
+
+// BusyMessage 连接数已满时发给新连接的内容
+const BusyMessage = "busy\n"
+
+// Server TCP 回显服务，零值不可用，使用 NewServer 创建
+type Server struct {
+	MaxConns     int           // 最大并发连接数，<= 0 表示不限制
+	IdleTimeout  time.Duration // 等待客户端数据的最长时间
+	WriteTimeout time.Duration // 单次写出的最长时间
+	DrainTimeout time.Duration // 关闭时等待进行中连接的最长时间
+	Logger       *log.Logger
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewServer 创建使用默认超时的服务
+func NewServer() *Server {
+	return &Server{
+		IdleTimeout:  defaultIdleTimeout,
+		WriteTimeout: defaultWriteTimeout,
+		DrainTimeout: defaultDrainTimeout,
+		Logger:       log.New(os.Stderr, "echo: ", log.LstdFlags),
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe 监听 addr 并提供服务，直到 ctx 取消
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在 ln 上接受连接，直到 ctx 取消；ln 由 Serve 负责关闭
+// ctx 取消导致的正常退出返回 nil
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	s.Logger.Printf("listening on %s", ln.Addr())
+
+	// Accept 不接受 ctx，只能通过关闭监听来打断它
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	var acceptErr error
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				acceptErr = err
+				ln.Close()
+			}
+			break
+		}
+		if !s.track(conn) {
+			s.reject(conn)
+			continue
+		}
+		s.wg.Go(func() {
+			defer s.untrack(conn)
+			s.Handle(conn)
+		})
+	}
+
+	s.shutdown()
+	return acceptErr
+}
+
+// Handle 回显 conn 上收到的数据，直到对方关闭、超时或出错，返回前关闭 conn
+func (s *Server) Handle(conn net.Conn) {
+	defer conn.Close()
+
+	buf := bufpool.Get(bufSize)
+	defer bufpool.Put(buf)
+	for {
+		if s.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.IdleTimeout))
+		}
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if s.WriteTimeout > 0 {
+				conn.SetWriteDeadline(time.Now().Add(s.WriteTimeout))
+			}
+			if _, werr := conn.Write(buf[:n]); werr != nil {
+				s.logErr(conn, werr)
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				s.logErr(conn, err)
+			}
+			return
+		}
+	}
+}
+
+// ActiveConns 返回正在处理的连接数
+func (s *Server) ActiveConns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// track 登记新连接，已满或已关闭时返回 false
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || (s.MaxConns > 0 && len(s.conns) >= s.MaxConns) {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+}
+
+// reject 告诉客户端服务繁忙后关闭连接，写出同样受 WriteTimeout 限制
+func (s *Server) reject(conn net.Conn) {
+	if s.WriteTimeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(s.WriteTimeout))
+	}
+	io.WriteString(conn, BusyMessage)
+	conn.Close()
+	s.Logger.Printf("%s rejected: too many connections", conn.RemoteAddr())
+}
+
+// shutdown 等待进行中的连接结束，超时后直接关闭剩余连接，
+// 阻塞在 Read/Write 上的 goroutine 会立即返回
+func (s *Server) shutdown() {
+	s.mu.Lock()
+	s.closed = true
+	n := len(s.conns)
+	s.mu.Unlock()
+	if n > 0 {
+		s.Logger.Printf("draining %d connection(s) for up to %v", n, s.DrainTimeout)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.DrainTimeout):
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		<-done
+	}
+	s.Logger.Print("stopped")
+}
+
+func (s *Server) logErr(conn net.Conn, err error) {
+	var ne net.Error
+	switch {
+	case errors.As(err, &ne) && ne.Timeout():
+		s.Logger.Printf("%s closed: timeout", conn.RemoteAddr())
+	case errors.Is(err, net.ErrClosed):
+	default:
+		s.Logger.Printf("%s closed: %v", conn.RemoteAddr(), err)
+	}
+}
@@ -0,0 +1,149 @@
+// ============================================
+// export 包：把结构体切片导出为 JSON / CSV / XML
+// ============================================
+//
+// 同一份数据常要按不同格式交给不同的使用者：前端要 JSON，表格软件要 CSV，
+// 老系统要 XML。Export / Import 用一个 Format 参数选择格式，字段映射由结构体标签决定：
+//
+//   type User struct {
+//       ID   int    `json:"id"   xml:"id,attr" csv:"id"`
+//       Name string `json:"name" xml:"name"    csv:"name"`
+//   }
+//
+//   export.Export(os.Stdout, export.XML, users, export.WithRoot("users"))
+//
+//   <users>
+//     <User id="1">
+//       <name>Alice</name>
+//     </User>
+//   </users>
+//
+// XML 的元素名取 XMLName 字段或类型名，根元素默认为 "items"，可用 WithRoot 修改。
+// CSV 只支持简单字段（见 pkg/csvutil），嵌套结构体和切片请用 JSON 或 XML。
+// ============================================
+
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"c03/pkg/csvutil"
+)
+
+var ErrUnknownFormat = errors.New("export: 未知的格式")
+
+// Format 导出格式
+type Format string
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+	XML  Format = "xml"
+)
+
+// Formats 支持的全部格式
+var Formats = []Format{JSON, CSV, XML}
+
+// ParseFormat 解析格式名，不区分大小写
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	switch f {
+	case JSON, CSV, XML:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %q（支持 json、csv、xml）", ErrUnknownFormat, s)
+}
+
+type config struct {
+	root string
+}
+
+// Option 配置 Export
+type Option func(*config)
+
+// WithRoot XML 根元素名，默认 "items"
+func WithRoot(name string) Option {
+	return func(c *config) { c.root = name }
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{root: "items"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// xmlDoc 解码 XML 文档：根元素下的每个子元素解码为一个 T
+type xmlDoc[T any] struct {
+	XMLName xml.Name
+	Items   []T `xml:",any"`
+}
+
+// Export 把 rows 按 format 写到 w
+func Export[T any](w io.Writer, format Format, rows []T, opts ...Option) error {
+	cfg := newConfig(opts)
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if rows == nil {
+			rows = []T{} // 输出 [] 而不是 null
+		}
+		return enc.Encode(rows)
+	case CSV:
+		return csvutil.Write(w, rows)
+	case XML:
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		// 逐个编码元素：元素名由 T 决定（XMLName 或类型名）
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		root := xml.StartElement{Name: xml.Name{Local: cfg.root}}
+		if err := enc.EncodeToken(root); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		if err := enc.EncodeToken(root.End()); err != nil {
+			return err
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Import 从 r 读取 Export 写出的数据
+// XML 不检查根元素名，根元素下的子元素都按 T 解码
+func Import[T any](r io.Reader, format Format) ([]T, error) {
+	switch format {
+	case JSON:
+		var rows []T
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	case CSV:
+		return csvutil.Read[T](r)
+	case XML:
+		var doc xmlDoc[T]
+		if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+			return nil, err
+		}
+		return doc.Items, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type item struct {
+	ID      int       `json:"id" xml:"id,attr" csv:"id"`
+	Name    string    `json:"name" xml:"name" csv:"name"`
+	Price   float64   `json:"price" xml:"price" csv:"price"`
+	InStock bool      `json:"in_stock" xml:"in_stock,attr" csv:"in_stock"`
+	Added   time.Time `json:"added" xml:"added" csv:"added"`
+}
+
+func sampleItems() []item {
+	added := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	return []item{
+		{ID: 1, Name: "苹果", Price: 3.5, InStock: true, Added: added},
+		{ID: 2, Name: `<逗号, "引号" & 换行` + "\n", Price: 0, Added: added.Add(time.Hour)},
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		for name, rows := range map[string][]item{"两行": sampleItems(), "空": nil} {
+			t.Run(string(format)+"/"+name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := Export(&buf, format, rows); err != nil {
+					t.Fatalf("Export 失败: %v", err)
+				}
+				back, err := Import[item](&buf, format)
+				if err != nil {
+					t.Fatalf("Import 失败: %v\n%s", err, buf.String())
+				}
+				if len(back) != len(rows) {
+					t.Fatalf("往返后有 %d 行，期望 %d 行", len(back), len(rows))
+				}
+				for i := range rows {
+					want, got := rows[i], back[i]
+					if !got.Added.Equal(want.Added) {
+						t.Fatalf("第 %d 行 Added = %v，期望 %v", i, got.Added, want.Added)
+					}
+					got.Added = want.Added
+					if got != want {
+						t.Fatalf("第 %d 行\n得到 %+v\n期望 %+v", i, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestExportXMLLayout(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, XML, sampleItems()[:1], WithRoot("inventory")); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{`<?xml version="1.0"`, "<inventory>", `<item id="1" in_stock="true">`, "<name>苹果</name>", "</inventory>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML 输出中没有 %q：\n%s", want, out)
+		}
+	}
+}
+
+func TestExportJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export[item](&buf, JSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Fatalf("空切片导出为 %q，期望 []", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"json", "CSV", "Xml"} {
+		f, err := ParseFormat(s)
+		if err != nil || !slices.Contains(Formats, f) {
+			t.Errorf("ParseFormat(%q) = %q, %v", s, f, err)
+		}
+	}
+	if _, err := ParseFormat("yaml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(\"yaml\") 返回 %v，期望 ErrUnknownFormat", err)
+	}
+	if err := Export(&bytes.Buffer{}, Format("yaml"), sampleItems()); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Export 未知格式返回 %v，期望 ErrUnknownFormat", err)
+	}
+	if _, err := Import[item](strings.NewReader(""), Format("yaml")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Import 未知格式返回 %v，期望 ErrUnknownFormat", err)
+	}
+}
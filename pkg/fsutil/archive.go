@@ -0,0 +1,259 @@
+package fsutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ============================================
+// zip / tar.gz 打包与解包
+// ============================================
+//
+// 打包：遍历 src 目录，条目名是相对 src 的路径（"/" 分隔），保留权限和修改时间。
+// 只打包目录和普通文件，符号链接等特殊文件被跳过。
+//
+// 解包时条目名来自不可信的输入，"../../etc/passwd" 或绝对路径
+// 会把文件写到目标目录之外（Zip Slip）。这里用 filepath.IsLocal 检查每个条目，
+// 不合法的条目直接报错；符号链接条目同样拒绝，防止借链接跳出目标目录。
+
+var ErrUnsafePath = errors.New("压缩包中的路径不安全")
+
+// ZipDir 把目录 src 打包为 zip 文件 dest
+func ZipDir(src, dest string) error {
+	return writeArchive(src, dest, func(w io.Writer) archiveWriter {
+		return &zipWriter{zip.NewWriter(w)}
+	})
+}
+
+// TarGzDir 把目录 src 打包为 tar.gz 文件 dest
+func TarGzDir(src, dest string) error {
+	return writeArchive(src, dest, func(w io.Writer) archiveWriter {
+		gz := gzip.NewWriter(w)
+		return &tarWriter{gz: gz, tw: tar.NewWriter(gz)}
+	})
+}
+
+// Unzip 把 zip 文件 src 解包到 destDir
+func Unzip(src, destDir string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		mode := f.Mode()
+		if mode&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s 是符号链接", ErrUnsafePath, f.Name)
+		}
+		err := func() error {
+			if f.FileInfo().IsDir() {
+				return extract(destDir, f.Name, mode, f.Modified, nil)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			return extract(destDir, f.Name, mode, f.Modified, rc)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UntarGz 把 tar.gz 文件 src 解包到 destDir
+func UntarGz(src, destDir string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = extract(destDir, hdr.Name, mode, hdr.ModTime, nil)
+		case tar.TypeReg:
+			err = extract(destDir, hdr.Name, mode, hdr.ModTime, tr)
+		case tar.TypeSymlink, tar.TypeLink:
+			err = fmt.Errorf("%w: %s 是链接", ErrUnsafePath, hdr.Name)
+		default:
+			continue // 设备文件、FIFO 等忽略
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extract 在 destDir 下创建目录（r 为 nil）或写入文件
+func extract(destDir, name string, mode fs.FileMode, modTime time.Time, r io.Reader) error {
+	name = filepath.FromSlash(name)
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+	target := filepath.Join(destDir, name)
+
+	if r == nil {
+		return os.MkdirAll(target, 0o755)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(target, modTime, modTime)
+}
+
+// ============================================
+// 打包
+// ============================================
+
+// archiveWriter 屏蔽 zip 和 tar 写入方式的差异
+type archiveWriter interface {
+	// add 写入一个条目，目录条目的 r 为 nil
+	add(name string, info fs.FileInfo, r io.Reader) error
+	Close() error
+}
+
+// writeArchive 创建 dest 并把 src 下的条目写入 newWriter 返回的归档
+func writeArchive(src, dest string, newWriter func(io.Writer) archiveWriter) (err error) {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dest) // 不留下半成品
+		}
+	}()
+
+	aw := newWriter(out)
+	absDest, _ := filepath.Abs(dest)
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == src {
+			return nil
+		}
+		if abs, _ := filepath.Abs(p); abs == absDest {
+			return nil // dest 在 src 里面时不要把自己打包进去
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return aw.add(filepath.ToSlash(rel)+"/", info, nil)
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return aw.add(filepath.ToSlash(rel), info, f)
+	})
+	if cerr := aw.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (w *zipWriter) add(name string, info fs.FileInfo, r io.Reader) error {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if r != nil {
+		hdr.Method = zip.Deflate
+	}
+	fw, err := w.zw.CreateHeader(hdr)
+	if err != nil || r == nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+func (w *zipWriter) Close() error {
+	return w.zw.Close()
+}
+
+type tarWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (w *tarWriter) add(name string, info fs.FileInfo, r io.Reader) error {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := w.tw.WriteHeader(hdr); err != nil || r == nil {
+		return err
+	}
+	_, err = io.Copy(w.tw, r)
+	return err
+}
+
+// Close 先关闭 tar 写出结尾块，再关闭 gzip 写出校验和
+func (w *tarWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		w.gz.Close()
+		return err
+	}
+	return w.gz.Close()
+}
@@ -0,0 +1,137 @@
+package fsutil
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"c03/pkg/hashutil"
+)
+
+// ============================================
+// 文件校验和与重复文件查找
+// ============================================
+//
+// FindDuplicates 分两步找内容相同的文件：
+//   1. 遍历目录按大小分组，大小唯一的文件不可能重复，不用读内容
+//   2. 剩下的文件交给固定数量的 worker 并发计算 SHA-256，再按哈希分组
+// 第一步通常能排除绝大部分文件，真正需要读取的数据量小得多。
+
+// HashFile 返回文件内容的 SHA-256（十六进制），等同于 hashutil.SHA256File
+func HashFile(path string) (string, error) {
+	return hashutil.SHA256File(path)
+}
+
+// DuplicateGroup 一组内容相同的文件
+type DuplicateGroup struct {
+	Hash  string
+	Size  int64
+	Paths []string // 按路径排序
+}
+
+// Wasted 删除多余副本后可以节省的字节数
+func (g DuplicateGroup) Wasted() int64 {
+	return g.Size * int64(len(g.Paths)-1)
+}
+
+// FindDuplicates 查找 root 下（不含隐藏文件）内容相同的文件
+// workers 是并发计算哈希的 goroutine 数，<= 0 时为 1；
+// 结果按浪费的空间从大到小排序。空文件不参与比较。
+func FindDuplicates(ctx context.Context, root string, workers int) ([]DuplicateGroup, error) {
+	bySize := make(map[int64][]string)
+	for f, err := range WalkFiltered(root, WalkOptions{}) {
+		if err != nil {
+			return nil, err
+		}
+		if size := f.Info.Size(); size > 0 {
+			bySize[size] = append(bySize[size], f.Path)
+		}
+	}
+
+	type job struct {
+		path string
+		size int64
+	}
+	type result struct {
+		job
+		hash string
+		err  error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan job)
+	results := make(chan result)
+
+	go func() {
+		defer close(jobs)
+		for size, paths := range bySize {
+			if len(paths) < 2 {
+				continue
+			}
+			for _, p := range paths {
+				select {
+				case jobs <- job{p, size}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Go(func() {
+			for j := range jobs {
+				hash, err := HashFile(j.path)
+				select {
+				case results <- result{j, hash, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	type key struct {
+		size int64
+		hash string
+	}
+	groups := make(map[key][]string)
+	for r := range results {
+		if r.err != nil {
+			// cancel 让生产者和 worker 退出，results 随后被关闭
+			cancel()
+			for range results {
+			}
+			return nil, r.err
+		}
+		k := key{r.size, r.hash}
+		groups[k] = append(groups[k], r.path)
+	}
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+
+	var dups []DuplicateGroup
+	for k, paths := range groups {
+		if len(paths) < 2 {
+			continue
+		}
+		slices.Sort(paths)
+		dups = append(dups, DuplicateGroup{Hash: k.hash, Size: k.size, Paths: paths})
+	}
+	slices.SortFunc(dups, func(a, b DuplicateGroup) int {
+		if c := cmp.Compare(b.Wasted(), a.Wasted()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Paths[0], b.Paths[0])
+	})
+	return dups, nil
+}
@@ -0,0 +1,165 @@
+// ============================================
+// fsutil 包：文件系统工具
+// ============================================
+//
+// WalkFiltered 在 filepath.WalkDir 的基础上增加常用的过滤：
+// - Include：只返回匹配的文件（为空表示全部）
+// - Exclude：匹配的文件被跳过，匹配的目录整棵跳过
+// - 默认跳过隐藏文件和隐藏目录（名字以 . 开头）
+// - MaxDepth 限制深度，FollowSymlinks 跟随符号链接
+//
+// 模式使用 path.Match 语法，同时匹配相对路径（"/" 分隔）和文件名，
+// 所以 "*.go" 匹配任意深度的 .go 文件，"build/*" 只匹配 build 下一层。
+//
+// 跟随符号链接时记录当前路径上每个目录的身份（os.SameFile），
+// 链接指回祖先目录形成环时跳过，不会无限递归。
+// ============================================
+
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+var ErrBadPattern = errors.New("无效的文件模式")
+
+// WalkOptions 遍历选项，零值表示返回 root 下所有非隐藏的普通文件
+type WalkOptions struct {
+	Include        []string
+	Exclude        []string
+	IncludeHidden  bool
+	MaxDepth       int // root 的直接子项深度为 1，<= 0 表示不限制
+	FollowSymlinks bool
+}
+
+// File 遍历得到的一个普通文件
+type File struct {
+	Path  string      // 包含 root 前缀的路径
+	Rel   string      // 相对 root 的路径，使用 "/" 分隔
+	Depth int         // root 的直接子项为 1
+	Info  fs.FileInfo // 跟随符号链接时是目标文件的信息
+}
+
+// WalkFiltered 按字典序遍历 root 下满足 opts 的普通文件
+// 读取某个目录失败时产出一个错误并跳过该目录，由调用方决定是否继续
+func WalkFiltered(root string, opts WalkOptions) iter.Seq2[File, error] {
+	return func(yield func(File, error) bool) {
+		for _, p := range append(opts.Include, opts.Exclude...) {
+			if _, err := path.Match(p, ""); err != nil {
+				yield(File{}, fmt.Errorf("%w: %q", ErrBadPattern, p))
+				return
+			}
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			yield(File{}, err)
+			return
+		}
+		if !info.IsDir() {
+			yield(File{}, fmt.Errorf("fsutil: %s 不是目录", root))
+			return
+		}
+		w := walker{opts: opts, yield: yield}
+		w.dir(root, "", 0, []fs.FileInfo{info})
+	}
+}
+
+type walker struct {
+	opts  WalkOptions
+	yield func(File, error) bool
+}
+
+// dir 遍历目录 p，ancestors 是从 root 到 p 的目录信息，用于检测符号链接环
+// 返回 false 表示调用方已停止迭代
+func (w *walker) dir(p, rel string, depth int, ancestors []fs.FileInfo) bool {
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return w.yield(File{}, err)
+	}
+
+	for _, e := range entries {
+		childPath := filepath.Join(p, e.Name())
+		childRel := e.Name()
+		if rel != "" {
+			childRel = rel + "/" + e.Name()
+		}
+		childDepth := depth + 1
+
+		if !w.opts.IncludeHidden && e.Name()[0] == '.' {
+			continue
+		}
+		if MatchAny(childRel, w.opts.Exclude) {
+			continue
+		}
+
+		info, err := w.stat(childPath, e)
+		if err != nil {
+			if !w.yield(File{}, err) {
+				return false
+			}
+			continue
+		}
+
+		switch {
+		case info.IsDir():
+			if w.opts.MaxDepth > 0 && childDepth >= w.opts.MaxDepth {
+				continue
+			}
+			if inAncestors(info, ancestors) {
+				continue // 符号链接指回了祖先目录
+			}
+			if !w.dir(childPath, childRel, childDepth, append(ancestors, info)) {
+				return false
+			}
+
+		case info.Mode().IsRegular():
+			if len(w.opts.Include) > 0 && !MatchAny(childRel, w.opts.Include) {
+				continue
+			}
+			f := File{Path: childPath, Rel: childRel, Depth: childDepth, Info: info}
+			if !w.yield(f, nil) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stat 返回条目的信息；跟随符号链接时返回目标的信息
+// 不跟随时符号链接既不是目录也不是普通文件，会被跳过
+func (w *walker) stat(p string, e fs.DirEntry) (fs.FileInfo, error) {
+	if w.opts.FollowSymlinks && e.Type()&fs.ModeSymlink != 0 {
+		return os.Stat(p)
+	}
+	return e.Info()
+}
+
+func inAncestors(info fs.FileInfo, ancestors []fs.FileInfo) bool {
+	for _, a := range ancestors {
+		if os.SameFile(info, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAny 判断相对路径 rel 或其文件名是否匹配 patterns 中的任意一个
+// 无效的模式视为不匹配
+func MatchAny(rel string, patterns []string) bool {
+	base := path.Base(rel)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, base); ok {
+			return true
+		}
+	}
+	return false
+}
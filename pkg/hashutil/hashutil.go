@@ -0,0 +1,160 @@
+// ============================================
+// hashutil 包：摘要、编码与常量时间比较
+// ============================================
+//
+// 摘要（十六进制字符串）：
+//   SHA256 / SHA256String / SHA256File / SHA256Reader
+//   MD5    / MD5String    / MD5File    / MD5Reader
+//   Sum(alg, r) 按算法名计算，算法由调用方配置时使用
+//
+// MD5 已经可以人为构造碰撞，只用于兼容旧系统的校验和（Content-MD5、部分 ETag），
+// 校验下载内容、签名等与安全有关的场景使用 SHA-256。
+//
+// 编码：EncodeHex / DecodeHex、EncodeBase64 / DecodeBase64
+// DecodeBase64 同时接受标准和 URL 安全两种字母表、带不带 "=" 填充都可以。
+//
+// 比较：
+//   Equal     比较两个秘密（token、签名），耗时与内容无关，也不泄露长度
+//   EqualHex  比较两个十六进制摘要，不区分大小写，常量时间
+// ============================================
+
+package hashutil
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"c03/pkg/bufpool"
+)
+
+var ErrUnknownAlgorithm = errors.New("hashutil: 未知的摘要算法")
+
+// Algorithm 摘要算法名
+type Algorithm string
+
+const (
+	AlgSHA256 Algorithm = "sha256"
+	AlgMD5    Algorithm = "md5"
+)
+
+// New 返回算法对应的 hash.Hash
+func (a Algorithm) New() (hash.Hash, error) {
+	switch a {
+	case AlgSHA256:
+		return sha256.New(), nil
+	case AlgMD5:
+		return md5.New(), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, string(a))
+}
+
+// Sum 读完 r，返回十六进制摘要
+func Sum(alg Algorithm, r io.Reader) (string, error) {
+	h, err := alg.New()
+	if err != nil {
+		return "", err
+	}
+	if _, err := bufpool.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SumFile 文件内容的十六进制摘要
+func SumFile(alg Algorithm, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return Sum(alg, f)
+}
+
+// SHA256 b 的 SHA-256（十六进制）
+func SHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SHA256String s 的 SHA-256（十六进制）
+func SHA256String(s string) string { return SHA256([]byte(s)) }
+
+// SHA256Reader 读完 r，返回 SHA-256（十六进制）
+func SHA256Reader(r io.Reader) (string, error) { return Sum(AlgSHA256, r) }
+
+// SHA256File 文件内容的 SHA-256（十六进制）
+func SHA256File(path string) (string, error) { return SumFile(AlgSHA256, path) }
+
+// MD5 b 的 MD5（十六进制），不要用于安全场景
+func MD5(b []byte) string {
+	sum := md5.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// MD5String s 的 MD5（十六进制）
+func MD5String(s string) string { return MD5([]byte(s)) }
+
+// MD5Reader 读完 r，返回 MD5（十六进制）
+func MD5Reader(r io.Reader) (string, error) { return Sum(AlgMD5, r) }
+
+// MD5File 文件内容的 MD5（十六进制）
+func MD5File(path string) (string, error) { return SumFile(AlgMD5, path) }
+
+// ============================================
+// 编码
+// ============================================
+
+// EncodeHex 小写十六进制
+func EncodeHex(b []byte) string { return hex.EncodeToString(b) }
+
+// DecodeHex 解码十六进制，大小写均可
+func DecodeHex(s string) ([]byte, error) { return hex.DecodeString(s) }
+
+// EncodeBase64 标准字母表、带填充的 base64
+func EncodeBase64(b []byte) string { return base64.StdEncoding.EncodeToString(b) }
+
+// EncodeBase64URL URL 安全字母表（- 和 _）、不带填充，可以直接放进 URL 和 JWT
+func EncodeBase64URL(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// DecodeBase64 解码 base64：标准或 URL 安全字母表，有无填充均可
+func DecodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// ============================================
+// 常量时间比较
+// ============================================
+
+// Equal 常量时间比较两个秘密
+// subtle.ConstantTimeCompare 在长度不同时立即返回，会泄露秘密的长度；
+// 先各自取 SHA-256 再比较，比较的总是两个 32 字节的值
+func Equal(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// EqualHex 比较两个十六进制摘要，不区分大小写；任意一个不是合法的十六进制时返回 false
+func EqualHex(a, b string) bool {
+	ba, err := hex.DecodeString(a)
+	if err != nil {
+		return false
+	}
+	bb, err := hex.DecodeString(b)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(ba, bb) == 1
+}
@@ -0,0 +1,187 @@
+// ============================================
+// httpserver 包：带优雅关闭的 HTTP 服务
+// ============================================
+//
+// http.ListenAndServe 会一直阻塞，进程被 Ctrl+C 杀掉时正在处理的请求直接中断。
+// Serve 管理服务的完整生命周期：
+//
+//   1. 监听地址并在后台处理请求
+//   2. 等待 ctx 取消或收到 SIGINT / SIGTERM
+//   3. 调用 Shutdown：停止接受新连接，等待进行中的请求完成
+//   4. 超过排空时间仍未完成则强制关闭
+//
+// 正常关闭返回 nil；监听失败或强制关闭时返回错误。
+//
+// WithSignals 把信号交给 signals.Registry 统一处理：Serve 不再自己监听 SIGINT / SIGTERM，
+// 轮到名为 "http" 的关闭回调时才开始排空，与其他组件的关闭顺序由注册顺序决定。
+//
+// WithPprof 在 /debug/pprof/ 下挂载 net/http/pprof，可以在线采集 profile：
+//
+//   go tool pprof http://localhost:8080/debug/pprof/profile?seconds=10
+//   go tool pprof http://localhost:8080/debug/pprof/heap
+//
+// profile 会暴露内部实现，只应在内网或 127.0.0.1 上开启。
+// ============================================
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"c03/pkg/signals"
+)
+
+const (
+	defaultDrainTimeout      = 10 * time.Second
+	defaultReadHeaderTimeout = 10 * time.Second
+)
+
+// Option 配置 Serve
+type Option func(*config)
+
+type config struct {
+	drainTimeout time.Duration
+	logger       *log.Logger
+	pprof        bool
+	signals      *signalHook
+}
+
+// WithDrainTimeout 设置关闭时等待进行中请求的最长时间，默认 10 秒
+func WithDrainTimeout(d time.Duration) Option {
+	return func(c *config) { c.drainTimeout = d }
+}
+
+// WithLogger 设置记录启动和关闭的日志，默认 log.Default()
+func WithLogger(l *log.Logger) Option {
+	return func(c *config) { c.logger = l }
+}
+
+// WithPprof 在 /debug/pprof/ 下提供 net/http/pprof 的处理器，其他路径交给原 handler
+func WithPprof() Option {
+	return func(c *config) { c.pprof = true }
+}
+
+// WithSignals 由 reg 处理退出信号，Serve 自己不再监听 SIGINT / SIGTERM
+//
+// 调用 WithSignals 时（而不是 Serve 开始时）就注册名为 "http" 的关闭回调：
+// 先创建选项，再打开处理器依赖的存储等组件，HTTP 服务就会在它们之前关闭。
+// 回调的超时是 reg 的默认值，应当不小于排空时间。一个 WithSignals 只能用于一次 Serve
+func WithSignals(reg *signals.Registry) Option {
+	h := &signalHook{shutdown: make(chan struct{}), stopped: make(chan struct{})}
+	var once sync.Once
+	reg.OnShutdown("http", 0, func(ctx context.Context) error {
+		once.Do(func() { close(h.shutdown) })
+		select {
+		case <-h.stopped:
+			return h.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	return func(c *config) { c.signals = h }
+}
+
+// signalHook 连接关闭回调和 ServeListener
+type signalHook struct {
+	shutdown chan struct{} // 回调要求开始排空
+	stopped  chan struct{} // ServeListener 返回后关闭，之后 err 是它的返回值
+	err      error
+}
+
+// withPprof 不使用 http.DefaultServeMux：导入 net/http/pprof 会往上面注册处理器，
+// 这里显式挂载，只有开启选项的服务才会暴露
+func withPprof(handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/", handler)
+	return mux
+}
+
+// Serve 在 addr 上提供 handler 服务，直到 ctx 取消或收到退出信号
+func Serve(ctx context.Context, addr string, handler http.Handler, opts ...Option) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return ServeListener(ctx, ln, handler, opts...)
+}
+
+// ServeListener 与 Serve 相同，使用已经打开的 ln
+// 监听 "127.0.0.1:0" 这类随机端口时，可以先从 ln.Addr() 得到实际地址
+func ServeListener(ctx context.Context, ln net.Listener, handler http.Handler, opts ...Option) (err error) {
+	cfg := config{drainTimeout: defaultDrainTimeout, logger: log.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.pprof {
+		handler = withPprof(handler)
+	}
+
+	var shutdownReq <-chan struct{} // 为 nil 时永远不会就绪
+	if h := cfg.signals; h != nil {
+		shutdownReq = h.shutdown
+		defer func() {
+			h.err = err
+			close(h.stopped)
+		}()
+	} else {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ErrorLog:          cfg.logger,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+	cfg.logger.Printf("http server listening on %s", ln.Addr())
+	if cfg.pprof {
+		cfg.logger.Printf("pprof available at http://%s/debug/pprof/", ln.Addr())
+	}
+
+	select {
+	case err := <-serveErr:
+		// 没有调用 Shutdown，Serve 不会返回 ErrServerClosed
+		return err
+	case <-ctx.Done():
+	case <-shutdownReq:
+	}
+
+	cfg.logger.Printf("http server shutting down, draining for up to %v", cfg.drainTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.drainTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(drainCtx); err != nil {
+		// 排空超时：强制关闭剩余连接
+		srv.Close()
+		<-serveErr
+		return fmt.Errorf("httpserver: 优雅关闭失败: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	cfg.logger.Print("http server stopped")
+	return nil
+}
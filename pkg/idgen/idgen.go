@@ -0,0 +1,176 @@
+// ============================================
+// idgen 包：ID 生成
+// ============================================
+//
+// IDGenerator 生成 snowflake 风格的 64 位 ID：
+//
+//   ┌─ 0 ─┬──────── 41 位毫秒时间戳 ────────┬─ 10 位节点 ─┬─ 12 位序号 ─┐
+//
+//   - 按时间递增：ID 越大生成得越晚，可以直接用来排序、分页
+//   - 节点号区分不同进程，多个节点同时生成也不会重复
+//   - 同一毫秒内最多 4096 个，用完后借用下一毫秒，不会阻塞
+//   - 时钟回拨时继续使用上次的时间戳，保证单调递增
+//   - 时间从 2024-01-01 算起，41 位可以用到 2093 年
+//
+// ID.String() 是 13 位 Crockford Base32，字符串顺序与数值顺序一致。
+//
+// NewUUID 生成随机的 UUIDv4，适合不需要排序、但要求不可猜测的场合。
+// ============================================
+
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+const (
+	nodeBits = 10
+	seqBits  = 12
+
+	MaxNode = 1<<nodeBits - 1
+	maxSeq  = 1<<seqBits - 1
+)
+
+// Epoch 时间戳的起点
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	ErrInvalidNode = errors.New("idgen: 节点号超出范围")
+	ErrInvalidID   = errors.New("idgen: ID 格式错误")
+)
+
+// ID 一个生成的 ID
+type ID int64
+
+// Time ID 生成时的时间戳（毫秒精度）
+func (id ID) Time() time.Time {
+	return Epoch.Add(time.Duration(int64(id)>>(nodeBits+seqBits)) * time.Millisecond)
+}
+
+// Node 生成该 ID 的节点号
+func (id ID) Node() int { return int(int64(id)>>seqBits) & MaxNode }
+
+// Seq 同一毫秒内的序号
+func (id ID) Seq() int { return int(id) & maxSeq }
+
+// crockford Crockford Base32 字母表，去掉了容易混淆的 I L O U，且按 ASCII 升序排列
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// idLen 64 位需要 13 个 5 位字符
+const idLen = 13
+
+// String 13 位 Crockford Base32，定长保证字符串比较与数值比较一致
+func (id ID) String() string {
+	var buf [idLen]byte
+	v := uint64(id)
+	for i := idLen - 1; i >= 0; i-- {
+		buf[i] = crockford[v&31]
+		v >>= 5
+	}
+	return string(buf[:])
+}
+
+// ParseID 解析 String 的输出，不区分大小写
+func ParseID(s string) (ID, error) {
+	if len(s) != idLen {
+		return 0, fmt.Errorf("%w: %q 长度应为 %d", ErrInvalidID, s, idLen)
+	}
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(crockford, upper(s[i]))
+		if d < 0 {
+			return 0, fmt.Errorf("%w: %q 含有非法字符 %q", ErrInvalidID, s, s[i])
+		}
+		v = v<<5 | uint64(d)
+	}
+	// 13×5 = 65 位，比 int64 多出 2 位（含符号位），第一个字符只能是 0-7
+	if s[0] > '7' {
+		return 0, fmt.Errorf("%w: %q 超出范围", ErrInvalidID, s)
+	}
+	return ID(v), nil
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// IDGenerator 并发安全的 ID 生成器，每个进程（节点）使用不同的节点号
+type IDGenerator struct {
+	node  int64
+	clock clock.Clock
+
+	mu   sync.Mutex
+	last int64 // 上一个 ID 使用的毫秒时间戳
+	seq  int64
+}
+
+// Option 配置 IDGenerator
+type Option func(*IDGenerator)
+
+// WithClock 替换时钟，默认 clock.Real
+func WithClock(c clock.Clock) Option {
+	return func(g *IDGenerator) { g.clock = clock.Or(c) }
+}
+
+// NewIDGenerator 创建节点号为 node（0-1023）的生成器
+func NewIDGenerator(node int, opts ...Option) (*IDGenerator, error) {
+	if node < 0 || node > MaxNode {
+		return nil, fmt.Errorf("%w: %d（允许 0-%d）", ErrInvalidNode, node, MaxNode)
+	}
+	g := &IDGenerator{node: int64(node), clock: clock.Real, last: -1}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Next 生成下一个 ID，保证严格大于同一生成器之前返回的所有 ID
+func (g *IDGenerator) Next() ID {
+	now := g.clock.Now().Sub(Epoch).Milliseconds()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch {
+	case now > g.last:
+		g.last, g.seq = now, 0
+	case g.seq < maxSeq:
+		// 同一毫秒，或时钟回拨（沿用上次的时间戳）
+		g.seq++
+	default:
+		// 本毫秒的序号用完，借用下一毫秒；时钟追上来之前 ID 的时间会略微超前
+		g.last++
+		g.seq = 0
+	}
+	return ID(g.last<<(nodeBits+seqBits) | g.node<<seqBits | g.seq)
+}
+
+// NewUUID 返回随机的 UUIDv4（RFC 9562），形如 xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // 版本 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 变体 10xx
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
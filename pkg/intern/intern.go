@@ -0,0 +1,134 @@
+// ============================================
+// intern 包：字符串驻留
+// ============================================
+//
+// 日志级别、用户 ID、HTTP 路径这类字段取值很少，却会在内存里出现成千上万份：
+// 每解析一行就分配一个新的 "ERROR"。更隐蔽的是，子串和原字符串共享底层数组，
+// 只保留一个 5 字节的级别，也会让整行（甚至整个读缓冲区）无法被回收。
+//
+// Interner 为每个不同的值只保存一份副本，之后相同的值都返回这份副本：
+//
+//   in := intern.New()
+//   level = in.Intern(level)        // 相同内容返回同一个字符串
+//   user  = in.Bytes(scanner.Bytes()[a:b])  // 已存在时不分配
+//
+// 标准库 unique.Make 提供了类似能力（并且条目不再使用时会被回收）；
+// Interner 的条目会一直保留，适合取值集合有限的字段，换来的是可查看的统计信息。
+// ============================================
+
+package intern
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Interner 并发安全的字符串驻留表，零值不可用，使用 New 创建
+type Interner struct {
+	mu sync.RWMutex
+	m  map[string]string
+
+	lookups    atomic.Int64
+	hits       atomic.Int64
+	savedBytes atomic.Int64
+	bytes      int64 // 所有唯一值的总字节数，受 mu 保护
+}
+
+// Stats 驻留表的统计信息
+type Stats struct {
+	Unique      int   // 不同值的数量
+	UniqueBytes int64 // 唯一值占用的字节数
+	Lookups     int64 // Intern / Bytes 调用次数
+	Hits        int64 // 命中已有值的次数
+	SavedBytes  int64 // 命中时少保存的字节数（不含字符串头）
+}
+
+// HitRate 命中率，没有调用过时为 0
+func (s Stats) HitRate() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Lookups)
+}
+
+// New 创建空的驻留表
+func New() *Interner {
+	return &Interner{m: make(map[string]string)}
+}
+
+// Intern 返回与 s 内容相同的共享副本
+// 首次出现时保存 s 的拷贝（strings.Clone），不会让 s 所在的大字符串一直存活
+func (in *Interner) Intern(s string) string {
+	if v, ok := in.lookup(s); ok {
+		return v
+	}
+	return in.store(s, func() string { return strings.Clone(s) })
+}
+
+// Bytes 与 Intern 相同，但参数是 []byte：命中时不分配
+// 适合直接处理 bufio.Scanner.Bytes() 返回的缓冲区
+func (in *Interner) Bytes(b []byte) string {
+	// m[string(b)] 形式的查找编译器会优化掉转换，不分配
+	in.lookups.Add(1)
+	in.mu.RLock()
+	v, ok := in.m[string(b)]
+	in.mu.RUnlock()
+	if ok {
+		in.hits.Add(1)
+		in.savedBytes.Add(int64(len(b)))
+		return v
+	}
+	return in.store(string(b), nil)
+}
+
+func (in *Interner) lookup(s string) (string, bool) {
+	in.lookups.Add(1)
+	in.mu.RLock()
+	v, ok := in.m[s]
+	in.mu.RUnlock()
+	if ok {
+		in.hits.Add(1)
+		in.savedBytes.Add(int64(len(s)))
+	}
+	return v, ok
+}
+
+// store 在写锁下再查一次（可能已被其他 goroutine 插入），没有时插入
+// clone 为 nil 表示 s 已经是独立的副本
+func (in *Interner) store(s string, clone func() string) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if v, ok := in.m[s]; ok {
+		in.hits.Add(1)
+		in.savedBytes.Add(int64(len(s)))
+		return v
+	}
+	if clone != nil {
+		s = clone()
+	}
+	in.m[s] = s
+	in.bytes += int64(len(s))
+	return s
+}
+
+// Len 不同值的数量
+func (in *Interner) Len() int {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return len(in.m)
+}
+
+// Stats 返回统计信息的快照
+func (in *Interner) Stats() Stats {
+	in.mu.RLock()
+	unique, bytes := len(in.m), in.bytes
+	in.mu.RUnlock()
+	return Stats{
+		Unique:      unique,
+		UniqueBytes: bytes,
+		Lookups:     in.lookups.Load(),
+		Hits:        in.hits.Load(),
+		SavedBytes:  in.savedBytes.Load(),
+	}
+}
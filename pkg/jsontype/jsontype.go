@@ -0,0 +1,294 @@
+// ============================================
+// jsontype 包：自定义 JSON 编解码的常用类型
+// ============================================
+//
+// 结构体标签只能改字段名、省略空值；值本身怎么编码由类型决定。
+// 实现 json.Marshaler / json.Unmarshaler 就能接管编解码：
+//
+//   Date      "2024-01-15"，只有日期，没有时分秒和时区
+//   Duration  "2h30m"，time.Duration 默认编码成纳秒数 9000000000000
+//   Null[T]   区分"字段是 null"与"字段是零值"："nickname": null vs "nickname": ""
+//
+//   type User struct {
+//       Birthday jsontype.Date           `json:"birthday,omitzero"`
+//       Timeout  jsontype.Duration       `json:"timeout"`
+//       Nickname jsontype.Null[string]   `json:"nickname"`
+//   }
+//
+// 三个类型都实现了 IsZero，可以配合 omitzero 标签在零值时省略字段。
+// 同时实现了 encoding.TextMarshaler，XML 和 pkg/csvutil 使用同样的文本格式。
+// ============================================
+
+package jsontype
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDate = errors.New("jsontype: 无效的日期")
+
+var jsonNull = []byte("null")
+
+// ============================================
+// Date
+// ============================================
+
+// DateLayout Date 的文本格式
+const DateLayout = "2006-01-02"
+
+// Date 公历日期，零值表示"没有日期"，编码为 null
+// 与 time.Time 不同，Date 没有时区：生日、出版日期在任何时区都是同一天
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf t 在其时区中的日期
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate 解析 "2006-01-02"
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+	return DateOf(t), nil
+}
+
+// IsZero 是否为零值
+func (d Date) IsZero() bool { return d == Date{} }
+
+// Time 这一天在 loc 中的零点
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// DaysSince 从 other 到 d 经过的天数，d 在 other 之前时为负数
+func (d Date) DaysSince(other Date) int {
+	// 用 UTC 计算，不受夏令时影响，每天都是 24 小时
+	return int(d.Time(time.UTC).Sub(other.Time(time.UTC)) / (24 * time.Hour))
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Time(time.UTC).Format(DateLayout)
+}
+
+// MarshalText 实现 encoding.TextMarshaler，Date 可以用作 map 的键
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText 空字符串解码为零值
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	v, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON 零值编码为 null，其余编码为 "2006-01-02"
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON 接受 null 和 "2006-01-02"
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: 需要字符串，实际是 %s", ErrInvalidDate, data)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// ============================================
+// Duration
+// ============================================
+
+// Duration 编码为 "2h30m" 这样的字符串
+// 解码时也接受数字（纳秒），兼容直接编码 time.Duration 得到的旧数据
+type Duration time.Duration
+
+// IsZero 是否为 0
+func (d Duration) IsZero() bool { return d == 0 }
+
+// String 与 time.Duration 相同，但去掉末尾多余的 0 单位："2h30m0s" -> "2h30m"，"1h0m0s" -> "1h"
+func (d Duration) String() string {
+	s := time.Duration(d).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// MarshalText 与 String 相同
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText 接受 time.ParseDuration 能解析的字符串，空字符串表示 0
+func (d *Duration) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("jsontype: 无效的时长 %q: %w", text, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON 编码为字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON 接受 time.ParseDuration 能解析的字符串，或表示纳秒的整数
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case float64:
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("jsontype: 时长 %s 不是整数纳秒", data)
+		}
+		*d = Duration(n)
+	default:
+		return fmt.Errorf("jsontype: 时长需要字符串或数字，实际是 %s", data)
+	}
+	return nil
+}
+
+// ============================================
+// Null[T]
+// ============================================
+
+// Null 可以为 null 的 T，Valid 为 false 时编码为 null
+// 与 *T 相比：是值类型，不需要分配，也不会出现空指针解引用
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NullOf 有值的 Null
+func NullOf[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// IsZero 为 null 时返回 true，配合 omitzero 标签可以省略字段
+func (n Null[T]) IsZero() bool { return !n.Valid }
+
+// Get 返回值和是否有值
+func (n Null[T]) Get() (T, bool) { return n.V, n.Valid }
+
+// Or 有值时返回值，否则返回 def
+func (n Null[T]) Or(def T) T {
+	if n.Valid {
+		return n.V
+	}
+	return def
+}
+
+func (n Null[T]) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return fmt.Sprint(n.V)
+}
+
+// MarshalJSON null 或 T 自己的编码
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON null 解码为无值，其余按 T 解码
+// 注意：字段在 JSON 中不存在时不会调用 UnmarshalJSON，同样保持无值
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		*n = Null[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullOf(v)
+	return nil
+}
+
+// MarshalText 无值时为空字符串；T 实现了 encoding.TextMarshaler 时使用它，
+// 字符串原样输出，其余类型使用 JSON 编码（数字、布尔值）
+// 文本格式中空字符串表示无值，所以 NullOf("") 编码后再解码会变成无值
+func (n Null[T]) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	switch v := any(n.V).(type) {
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	case string:
+		return []byte(v), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalText 是 MarshalText 的逆过程
+func (n *Null[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*n = Null[T]{}
+		return nil
+	}
+	var v T
+	switch p := any(&v).(type) {
+	case encoding.TextUnmarshaler:
+		if err := p.UnmarshalText(text); err != nil {
+			return err
+		}
+	case *string:
+		*p = string(text)
+	default:
+		if err := json.Unmarshal(text, &v); err != nil {
+			return err
+		}
+	}
+	*n = NullOf(v)
+	return nil
+}
@@ -0,0 +1,248 @@
+// ============================================
+// jwt 包：最小的 JWT（HS256）签发与验证
+// ============================================
+//
+// JWT = base64url(头部) "." base64url(载荷) "." base64url(签名)
+//
+//   头部   {"alg":"HS256","typ":"JWT"}
+//   载荷   Claims 的 JSON：sub、iss、exp、iat ...，时间是 Unix 秒（NumericDate）
+//   签名   HMAC-SHA256(密钥, 头部 "." 载荷)
+//
+// 服务端不保存会话：验证签名后直接信任载荷中的用户信息（无状态认证）。
+// 代价是令牌在过期前无法单独吊销，所以有效期要短（默认 15 分钟）。
+//
+// 安全要点：
+//   - 只接受 alg 为 HS256 的令牌，"none" 和其他算法一律拒绝；
+//     不能按令牌头部声明的算法去验证，否则攻击者可以自己选算法
+//   - 签名用 hmac.Equal 常量时间比较
+//   - 载荷只是 base64 编码，不是加密，不要放密码等敏感信息
+//
+// 用法：
+//   s := jwt.NewSigner(secret, jwt.WithTTL(time.Hour), jwt.WithIssuer("bank"))
+//   token, _ := s.Issue("alice")
+//   claims, err := s.Verify(token)
+//
+// HTTP 中间件见 middleware.AuthJWT。
+// ============================================
+
+package jwt
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+var (
+	ErrMalformed   = errors.New("jwt: 令牌格式错误")
+	ErrAlgorithm   = errors.New("jwt: 不支持的签名算法")
+	ErrSignature   = errors.New("jwt: 签名无效")
+	ErrExpired     = errors.New("jwt: 令牌已过期")
+	ErrNotYetValid = errors.New("jwt: 令牌尚未生效")
+	ErrIssuer      = errors.New("jwt: 签发者不匹配")
+)
+
+// DefaultTTL Issue 签发的令牌的默认有效期
+const DefaultTTL = 15 * time.Minute
+
+// b64 JWT 使用不带填充的 URL 安全 base64
+var b64 = base64.RawURLEncoding
+
+// header 本包只签发一种头部，预先编码好
+var header = b64.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// ============================================
+// Claims
+// ============================================
+
+// NumericDate JWT 的时间：JSON 中是 Unix 秒，零值省略
+type NumericDate struct {
+	time.Time
+}
+
+// At 把 t 截断到秒
+func At(t time.Time) NumericDate {
+	return NumericDate{t.Truncate(time.Second)}
+}
+
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, d.Unix(), 10), nil
+}
+
+func (d *NumericDate) UnmarshalJSON(b []byte) error {
+	// RFC 7519 允许小数秒，按浮点数解析
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return fmt.Errorf("%w: 时间 %s 不是数字", ErrMalformed, b)
+	}
+	d.Time = time.Unix(0, int64(f*float64(time.Second)))
+	return nil
+}
+
+// Claims 注册声明（RFC 7519 第 4.1 节）加上一个常用的 Role
+type Claims struct {
+	Subject   string       `json:"sub,omitempty"`
+	Issuer    string       `json:"iss,omitempty"`
+	Audience  string       `json:"aud,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	Role      string       `json:"role,omitempty"`
+}
+
+// Valid 检查 exp、nbf；leeway 容忍签发方和验证方之间的时钟偏差
+func (c Claims) Valid(now time.Time, leeway time.Duration) error {
+	if c.ExpiresAt != nil && !now.Before(c.ExpiresAt.Add(leeway)) {
+		return fmt.Errorf("%w: 过期时间 %s", ErrExpired, c.ExpiresAt.Format(time.DateTime))
+	}
+	if c.NotBefore != nil && now.Add(leeway).Before(c.NotBefore.Time) {
+		return fmt.Errorf("%w: 生效时间 %s", ErrNotYetValid, c.NotBefore.Format(time.DateTime))
+	}
+	return nil
+}
+
+// ============================================
+// Signer
+// ============================================
+
+// Signer 用同一个密钥签发和验证令牌，可以并发使用
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	issuer string
+	leeway time.Duration
+	clock  clock.Clock
+}
+
+// Option NewSigner 的选项
+type Option func(*Signer)
+
+// WithTTL Issue 签发的令牌的有效期，默认 DefaultTTL
+func WithTTL(d time.Duration) Option { return func(s *Signer) { s.ttl = d } }
+
+// WithIssuer Issue 时写入 iss；Verify 时要求 iss 与之相同
+func WithIssuer(iss string) Option { return func(s *Signer) { s.issuer = iss } }
+
+// WithLeeway 验证 exp、nbf 时容忍的时钟偏差，默认 0
+func WithLeeway(d time.Duration) Option { return func(s *Signer) { s.leeway = d } }
+
+// WithClock 签发和验证使用的时钟，默认 clock.Real
+func WithClock(c clock.Clock) Option { return func(s *Signer) { s.clock = c } }
+
+// NewSigner 创建 Signer；HS256 的密钥至少应有 32 字节随机数据
+func NewSigner(secret []byte, opts ...Option) *Signer {
+	s := &Signer{secret: bytes.Clone(secret), ttl: DefaultTTL}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.clock = clock.Or(s.clock)
+	return s
+}
+
+// Issue 为 subject 签发令牌：iat 为当前时间，exp 为当前时间加 TTL
+func (s *Signer) Issue(subject string) (string, error) {
+	return s.IssueClaims(Claims{Subject: subject})
+}
+
+// IssueClaims 补全 iss、iat、exp（已设置的字段保持不变）后签名
+func (s *Signer) IssueClaims(c Claims) (string, error) {
+	now := s.clock.Now()
+	if c.Issuer == "" {
+		c.Issuer = s.issuer
+	}
+	if c.IssuedAt == nil {
+		iat := At(now)
+		c.IssuedAt = &iat
+	}
+	if c.ExpiresAt == nil {
+		exp := At(now.Add(s.ttl))
+		c.ExpiresAt = &exp
+	}
+	return s.Sign(c)
+}
+
+// Sign 原样签名 c，不补全任何字段
+func (s *Signer) Sign(c Claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	signing := header + "." + b64.EncodeToString(payload)
+	return signing + "." + b64.EncodeToString(s.mac(signing)), nil
+}
+
+func (s *Signer) mac(signing string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(signing))
+	return h.Sum(nil)
+}
+
+// Verify 验证签名、算法、exp、nbf 和 iss，通过后返回 Claims
+// 先验证签名再解析载荷：签名不对的令牌，内容一概不看
+func (s *Signer) Verify(token string) (Claims, error) {
+	h, payload, sig, err := split(token)
+	if err != nil {
+		return Claims{}, err
+	}
+	if err := checkHeader(h); err != nil {
+		return Claims{}, err
+	}
+	if !hmac.Equal(sig, s.mac(token[:strings.LastIndexByte(token, '.')])) {
+		return Claims{}, ErrSignature
+	}
+
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Claims{}, fmt.Errorf("%w: 载荷: %v", ErrMalformed, err)
+	}
+	if err := c.Valid(s.clock.Now(), s.leeway); err != nil {
+		return Claims{}, err
+	}
+	if s.issuer != "" && c.Issuer != s.issuer {
+		return Claims{}, fmt.Errorf("%w: %q", ErrIssuer, c.Issuer)
+	}
+	return c, nil
+}
+
+// split 拆成三段并解码
+func split(token string) (header, payload, sig []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, fmt.Errorf("%w: 应有 3 段，实际 %d 段", ErrMalformed, len(parts))
+	}
+	var decoded [3][]byte
+	for i, p := range parts {
+		if decoded[i], err = b64.DecodeString(p); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: 第 %d 段: %v", ErrMalformed, i+1, err)
+		}
+	}
+	return decoded[0], decoded[1], decoded[2], nil
+}
+
+// checkHeader 只接受 HS256
+func checkHeader(b []byte) error {
+	var h struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(b, &h); err != nil {
+		return fmt.Errorf("%w: 头部: %v", ErrMalformed, err)
+	}
+	if h.Alg != "HS256" {
+		return fmt.Errorf("%w: %q", ErrAlgorithm, h.Alg)
+	}
+	if h.Typ != "" && h.Typ != "JWT" {
+		return fmt.Errorf("%w: typ %q", ErrMalformed, h.Typ)
+	}
+	return nil
+}
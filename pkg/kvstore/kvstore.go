@@ -0,0 +1,563 @@
+// ============================================
+// kvstore 包：带预写日志（WAL）的持久化键值存储
+// ============================================
+//
+// 综合练习：文件 I/O、二进制编码、并发控制、崩溃恢复。
+//
+// 目录结构：
+//   wal-0000000000000003.log   WAL 段，每次写操作先追加一条记录，再修改内存中的 map
+//   snapshot                   快照：某一时刻的全部键值，头部记录它覆盖到哪个 WAL 段
+//
+// 写入：Put / Delete 在锁内把记录追加到当前 WAL 段，成功后才修改 map。
+//   默认只写到操作系统（进程崩溃不丢数据），WithSync 时每次写入都 fsync（断电也不丢，但慢得多）。
+//
+// 压缩（Compact）：WAL 只增不减，压缩把当前内容写成快照，再删掉旧的 WAL 段：
+//   1. 持有锁：复制 map，切换到新的 WAL 段（之后的写入进入新段）
+//   2. 释放锁：把副本写入 snapshot.tmp，fsync 后重命名为 snapshot
+//   3. 删除快照已经覆盖的旧段
+//   步骤 2 写快照时不持有锁，读写照常进行。任何一步崩溃，重新打开时都能恢复：
+//   快照是原子替换的；旧段没删掉时，按快照头部的段号跳过即可。
+//   WithCompactInterval 定期压缩，WithCompactThreshold 在 WAL 超过指定大小时压缩。
+//
+// 恢复（Open）：加载快照，按顺序重放快照之后的 WAL 段。最后一段末尾的半条记录
+//   （写到一半时崩溃）会被截断；其他位置的损坏返回 ErrCorrupt，不猜测数据。
+// ============================================
+
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/signals"
+)
+
+var (
+	ErrClosed  = errors.New("kvstore: 已关闭")
+	ErrCorrupt = errors.New("kvstore: 数据文件损坏")
+)
+
+const (
+	snapshotName  = "snapshot"
+	snapshotMagic = "KVS1"
+	walPrefix     = "wal-"
+	walSuffix     = ".log"
+)
+
+// ============================================
+// 选项
+// ============================================
+
+type options struct {
+	sync             bool
+	compactInterval  time.Duration
+	compactThreshold int64
+	clock            clock.Clock
+	logger           *log.Logger
+	signals          *signals.Registry
+}
+
+// Option Open 的选项
+type Option func(*options)
+
+// WithSync 每次写入后 fsync WAL，断电也不会丢失已返回的写入
+func WithSync() Option { return func(o *options) { o.sync = true } }
+
+// WithCompactInterval 每隔 d 压缩一次；0 表示不定期压缩
+func WithCompactInterval(d time.Duration) Option {
+	return func(o *options) { o.compactInterval = d }
+}
+
+// WithCompactThreshold 当前 WAL 段超过 n 字节时在后台压缩；0 表示不按大小压缩
+func WithCompactThreshold(n int64) Option {
+	return func(o *options) { o.compactThreshold = n }
+}
+
+// WithClock 定期压缩使用的时钟，默认 clock.Real
+func WithClock(c clock.Clock) Option { return func(o *options) { o.clock = c } }
+
+// WithLogger 后台压缩失败时的日志，默认 log.Default()
+func WithLogger(l *log.Logger) Option { return func(o *options) { o.logger = l } }
+
+// WithSignals 在 reg 中注册名为 "kvstore" 的关闭回调：收到退出信号时 Close，WAL 刷盘后再退出
+// 注册顺序决定关闭顺序，存储通常最后打开、最后关闭：先停掉还会写入它的组件
+func WithSignals(reg *signals.Registry) Option { return func(o *options) { o.signals = reg } }
+
+// ============================================
+// KVStore
+// ============================================
+
+// Recovery Open 时的恢复情况
+type Recovery struct {
+	SnapshotKeys   int   // 从快照加载的键数
+	Replayed       int   // 重放的 WAL 记录数
+	TruncatedBytes int64 // 最后一个 WAL 段末尾被截断的字节数（半条记录）
+}
+
+// Stats 运行时统计
+type Stats struct {
+	Keys        int
+	WALSegment  uint64 // 当前 WAL 段号
+	WALBytes    int64  // 当前 WAL 段的大小
+	Compactions int
+	Recovery    Recovery
+}
+
+// KVStore 持久化键值存储，可以并发使用
+type KVStore struct {
+	dir  string
+	opts options
+
+	mu      sync.RWMutex
+	data    map[string][]byte // 值在存入后不再修改，可以在 map 副本之间共享
+	wal     *os.File
+	walSeq  uint64
+	walSize int64
+	err     error // WAL 写入失败且无法回滚后，拒绝之后的所有写入
+	closed  bool
+
+	compactMu   sync.Mutex // 同一时刻只有一次压缩
+	compactions int        // 持有 mu 时读写
+	recovery    Recovery
+
+	trigger  chan struct{} // WAL 超过阈值时通知后台压缩
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Open 打开 dir 下的存储，不存在时创建；打开时完成崩溃恢复
+func Open(dir string, opts ...Option) (*KVStore, error) {
+	o := options{logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.clock = clock.Or(o.clock)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &KVStore{dir: dir, opts: o, data: make(map[string][]byte)}
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+
+	if o.compactInterval > 0 || o.compactThreshold > 0 {
+		s.trigger = make(chan struct{}, 1)
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.background()
+	}
+	if o.signals != nil {
+		o.signals.OnShutdown("kvstore", 0, func(context.Context) error { return s.Close() })
+	}
+	return s, nil
+}
+
+// Get 返回 key 的值（副本）
+func (s *KVStore) Get(key string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	return bytes.Clone(v), ok
+}
+
+// Put 写入 key；返回 nil 时记录已经写入 WAL
+func (s *KVStore) Put(key string, value []byte) error {
+	return s.apply(record{op: opPut, key: key, value: bytes.Clone(value)})
+}
+
+// Delete 删除 key；key 不存在时也会写一条记录，不返回错误
+func (s *KVStore) Delete(key string) error {
+	return s.apply(record{op: opDelete, key: key})
+}
+
+func (s *KVStore) apply(r record) error {
+	buf := appendRecord(nil, r)
+	if len(buf)-recordHeaderSize > MaxRecordSize {
+		return fmt.Errorf("kvstore: 记录 %d 字节，超过上限 %d", len(buf), MaxRecordSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.closed:
+		return ErrClosed
+	case s.err != nil:
+		return s.err
+	}
+	if err := s.appendWAL(buf); err != nil {
+		return err
+	}
+	switch r.op {
+	case opPut:
+		s.data[r.key] = r.value
+	case opDelete:
+		delete(s.data, r.key)
+	}
+	if s.opts.compactThreshold > 0 && s.walSize >= s.opts.compactThreshold {
+		select {
+		case s.trigger <- struct{}{}:
+		default: // 已经通知过，后台还没处理
+		}
+	}
+	return nil
+}
+
+// appendWAL 追加一条记录；调用方持有 s.mu
+// 写入失败时把文件截断回写入前的大小，否则半条记录后面的写入在恢复时都会被丢掉
+func (s *KVStore) appendWAL(buf []byte) error {
+	_, err := s.wal.Write(buf)
+	if err == nil && s.opts.sync {
+		err = s.wal.Sync()
+	}
+	if err != nil {
+		if terr := s.wal.Truncate(s.walSize); terr != nil {
+			s.err = fmt.Errorf("kvstore: WAL 写入失败且无法回滚，存储变为只读: %w", errors.Join(err, terr))
+			return s.err
+		}
+		return fmt.Errorf("kvstore: 写 WAL: %w", err)
+	}
+	s.walSize += int64(len(buf))
+	return nil
+}
+
+// Len 键的数量
+func (s *KVStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+// Keys 所有键，已排序
+func (s *KVStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.data))
+}
+
+// Stats 当前统计
+func (s *KVStore) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Stats{
+		Keys:        len(s.data),
+		WALSegment:  s.walSeq,
+		WALBytes:    s.walSize,
+		Compactions: s.compactions,
+		Recovery:    s.recovery,
+	}
+}
+
+// Close 停止后台压缩，关闭 WAL；之后的写入返回 ErrClosed
+func (s *KVStore) Close() error {
+	if s.stop != nil {
+		s.stopOnce.Do(func() { close(s.stop) })
+		<-s.done
+	}
+	s.compactMu.Lock() // 等待进行中的压缩完成
+	defer s.compactMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return errors.Join(s.wal.Sync(), s.wal.Close())
+}
+
+// background 定期压缩或在 WAL 过大时压缩
+func (s *KVStore) background() {
+	defer close(s.done)
+	var tick <-chan time.Time
+	if s.opts.compactInterval > 0 {
+		t := s.opts.clock.NewTicker(s.opts.compactInterval)
+		defer t.Stop()
+		tick = t.C()
+	}
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-tick:
+		case <-s.trigger:
+		}
+		if err := s.Compact(); err != nil && !errors.Is(err, ErrClosed) {
+			s.opts.logger.Printf("kvstore: 后台压缩: %v", err)
+		}
+	}
+}
+
+// ============================================
+// 压缩
+// ============================================
+
+// Compact 把当前内容写成快照，删除快照覆盖的 WAL 段
+func (s *KVStore) Compact() error {
+	s.compactMu.Lock()
+	defer s.compactMu.Unlock()
+
+	// 1. 持有锁：复制 map，切换到新的 WAL 段
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	snap := maps.Clone(s.data)
+	covered := s.walSeq
+	next, err := s.createWAL(covered + 1)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	old := s.wal
+	s.wal, s.walSeq, s.walSize = next, covered+1, 0
+	s.mu.Unlock()
+
+	// 旧段已经不会再被写入；它的内容都在 snap 里
+	if err := errors.Join(old.Sync(), old.Close()); err != nil {
+		return err
+	}
+
+	// 2. 不持有锁：写快照
+	if err := s.writeSnapshot(snap, covered); err != nil {
+		return err
+	}
+
+	// 3. 删除快照覆盖的旧段；删除失败不影响正确性，下次打开时会跳过
+	segs, err := s.walSegments()
+	if err != nil {
+		return err
+	}
+	for _, seq := range segs {
+		if seq <= covered {
+			os.Remove(s.walPath(seq))
+		}
+	}
+
+	s.mu.Lock()
+	s.compactions++
+	s.mu.Unlock()
+	return nil
+}
+
+// writeSnapshot 原子地写入快照：临时文件 + fsync + 重命名 + fsync 目录
+func (s *KVStore) writeSnapshot(data map[string][]byte, covered uint64) error {
+	tmp, err := os.CreateTemp(s.dir, snapshotName+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // 重命名成功后删除会失败，忽略
+
+	w := bufio.NewWriter(tmp)
+	hdr := binary.LittleEndian.AppendUint64([]byte(snapshotMagic), covered)
+	w.Write(hdr)
+	var buf []byte
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		buf = appendRecord(buf[:0], record{op: opPut, key: k, value: data[k]})
+		w.Write(buf) // bufio.Writer 记住第一个错误，Flush 时返回
+	}
+	if err := errors.Join(w.Flush(), tmp.Sync()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, snapshotName)); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// syncDir fsync 目录，让重命名、创建文件本身也落盘
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// ============================================
+// 恢复
+// ============================================
+
+// recover 加载快照、重放 WAL、打开当前 WAL 段
+func (s *KVStore) recover() error {
+	// 上次写快照时崩溃留下的临时文件
+	if tmps, _ := filepath.Glob(filepath.Join(s.dir, snapshotName+".tmp*")); len(tmps) > 0 {
+		for _, t := range tmps {
+			os.Remove(t)
+		}
+	}
+
+	covered, err := s.loadSnapshot()
+	if err != nil {
+		return err
+	}
+	s.recovery.SnapshotKeys = len(s.data)
+
+	segs, err := s.walSegments()
+	if err != nil {
+		return err
+	}
+	segs = slices.DeleteFunc(segs, func(seq uint64) bool {
+		if seq <= covered {
+			os.Remove(s.walPath(seq)) // 压缩时删除失败的旧段
+			return true
+		}
+		return false
+	})
+
+	for i, seq := range segs {
+		last := i == len(segs)-1
+		size, err := s.replay(seq, last)
+		if err != nil {
+			return err
+		}
+		if last {
+			s.walSeq, s.walSize = seq, size
+		}
+	}
+
+	if len(segs) == 0 {
+		s.walSeq = covered + 1
+		s.wal, err = s.createWAL(s.walSeq)
+		return err
+	}
+	s.wal, err = os.OpenFile(s.walPath(s.walSeq), os.O_WRONLY|os.O_APPEND, 0)
+	return err
+}
+
+// loadSnapshot 加载快照，返回它覆盖到的 WAL 段号；没有快照时返回 0
+func (s *KVStore) loadSnapshot() (uint64, error) {
+	f, err := os.Open(filepath.Join(s.dir, snapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	hdr := make([]byte, len(snapshotMagic)+8)
+	if _, err := io.ReadFull(r, hdr); err != nil || string(hdr[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, fmt.Errorf("%w: 快照头部无效", ErrCorrupt)
+	}
+	covered := binary.LittleEndian.Uint64(hdr[len(snapshotMagic):])
+	for {
+		rec, _, err := readRecord(r)
+		if err == io.EOF {
+			return covered, nil
+		}
+		if err != nil {
+			// 快照是原子替换的，不会只写了一半
+			return 0, fmt.Errorf("%w: 快照: %v", ErrCorrupt, err)
+		}
+		s.data[rec.key] = rec.value
+	}
+}
+
+// replay 重放一个 WAL 段，返回有效内容的字节数
+// last 为 true 时末尾的半条记录被截断，否则返回 ErrCorrupt
+func (s *KVStore) replay(seq uint64, last bool) (int64, error) {
+	path := s.walPath(seq)
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		rec, n, err := readRecord(r)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if errors.Is(err, errTorn) {
+			if !last {
+				return 0, fmt.Errorf("%w: %s 偏移 %d: %v", ErrCorrupt, filepath.Base(path), offset, err)
+			}
+			if err := os.Truncate(path, offset); err != nil {
+				return 0, err
+			}
+			s.recovery.TruncatedBytes = info.Size() - offset
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch rec.op {
+		case opPut:
+			s.data[rec.key] = rec.value
+		case opDelete:
+			delete(s.data, rec.key)
+		}
+		s.recovery.Replayed++
+		offset += n
+	}
+}
+
+func (s *KVStore) walPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%016d%s", walPrefix, seq, walSuffix))
+}
+
+// createWAL 创建新的 WAL 段并 fsync 目录
+func (s *KVStore) createWAL(seq uint64) (*os.File, error) {
+	f, err := os.OpenFile(s.walPath(seq), os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncDir(s.dir); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// walSegments 目录中所有 WAL 段的段号，从小到大
+func (s *KVStore) walSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segs []uint64
+	for _, e := range entries {
+		name, ok := strings.CutPrefix(e.Name(), walPrefix)
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, walSuffix)
+		if !ok {
+			continue
+		}
+		if seq, err := strconv.ParseUint(name, 10, 64); err == nil {
+			segs = append(segs, seq)
+		}
+	}
+	slices.Sort(segs)
+	return segs, nil
+}
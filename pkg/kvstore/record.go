@@ -0,0 +1,89 @@
+package kvstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// ============================================
+// 记录格式（WAL 和快照共用）
+// ============================================
+//
+//   记录 = 长度(4 字节 LE) CRC(4 字节 LE) 内容
+//   内容 = 操作(1 字节) uvarint(键长度) 键 值
+//
+// CRC 是内容的 CRC-32C。进程在写一条记录的中途崩溃时，文件末尾会留下半条记录：
+// 长度不够或者 CRC 对不上，恢复时从这里截断即可。
+
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+
+	recordHeaderSize = 8
+	// MaxRecordSize 一条记录内容的最大长度，读到更大的长度说明数据已损坏
+	MaxRecordSize = 64 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errTorn 文件末尾的记录不完整或校验失败
+var errTorn = errors.New("记录不完整")
+
+type record struct {
+	op    byte
+	key   string
+	value []byte
+}
+
+// appendRecord 把 r 编码后追加到 b
+func appendRecord(b []byte, r record) []byte {
+	start := len(b)
+	b = append(b, make([]byte, recordHeaderSize)...)
+	b = append(b, r.op)
+	b = binary.AppendUvarint(b, uint64(len(r.key)))
+	b = append(b, r.key...)
+	b = append(b, r.value...)
+	body := b[start+recordHeaderSize:]
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(body)))
+	binary.LittleEndian.PutUint32(b[start+4:], crc32.Checksum(body, crcTable))
+	return b
+}
+
+// readRecord 读取一条记录；在记录边界遇到 EOF 时返回 io.EOF，
+// 记录不完整或 CRC 不对时返回 errTorn
+func readRecord(r io.Reader) (record, int64, error) {
+	var hdr [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errTorn
+		}
+		return record{}, 0, err
+	}
+	size := binary.LittleEndian.Uint32(hdr[:4])
+	if size < 2 || size > MaxRecordSize {
+		return record{}, 0, fmt.Errorf("%w: 长度 %d", errTorn, size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = errTorn
+		}
+		return record{}, 0, err
+	}
+	if crc32.Checksum(body, crcTable) != binary.LittleEndian.Uint32(hdr[4:]) {
+		return record{}, 0, fmt.Errorf("%w: CRC 不匹配", errTorn)
+	}
+
+	rec := record{op: body[0]}
+	klen, n := binary.Uvarint(body[1:])
+	if n <= 0 || klen > uint64(len(body)-1-n) || (rec.op != opPut && rec.op != opDelete) {
+		return record{}, 0, fmt.Errorf("%w: 内容格式错误", errTorn)
+	}
+	rest := body[1+n:]
+	rec.key = string(rest[:klen])
+	rec.value = rest[klen:]
+	return rec, recordHeaderSize + int64(size), nil
+}
@@ -0,0 +1,153 @@
+// ============================================
+// logstat 包：日志分析
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 1：
+// - 用 bufio.Scanner 逐行流式读取，不会把整个文件读入内存
+// - 用 regexp 解析时间、级别和消息
+// - 统计各级别数量，按时间范围过滤
+// - 汇总结果可以直接编码为 JSON
+//
+// 支持的日志格式（级别两侧的方括号可选）：
+//   2024-01-15 10:30:00 [INFO] server started
+//   2024-01-15 10:30:05 WARN disk usage 85%
+// ============================================
+
+package logstat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"c03/pkg/intern"
+)
+
+// TimeLayout 日志中的时间格式
+const TimeLayout = time.DateTime
+
+// maxLineBytes 单行最大长度，bufio.Scanner 默认只有 64KB
+const maxLineBytes = 1 << 20
+
+var ErrMalformedLine = errors.New("无法解析的日志行")
+
+var linePattern = regexp.MustCompile(
+	`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\s+\[?(DEBUG|INFO|WARN|WARNING|ERROR|FATAL)\]?\s+(.*)$`)
+
+// Entry 一条解析后的日志
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+}
+
+// ParseLine 解析一行日志
+func ParseLine(line string) (Entry, error) {
+	m := linePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Entry{}, fmt.Errorf("%w: %q", ErrMalformedLine, line)
+	}
+	t, err := time.ParseInLocation(TimeLayout, m[1], time.Local)
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: %v", ErrMalformedLine, err)
+	}
+	level := m[2]
+	if level == "WARNING" {
+		level = "WARN"
+	}
+	return Entry{Time: t, Level: level, Message: m[3]}, nil
+}
+
+// Filter 过滤条件，零值表示不过滤
+type Filter struct {
+	From time.Time // 包含
+	To   time.Time // 不包含
+}
+
+// Match 判断日志是否落在时间范围内
+func (f Filter) Match(e Entry) bool {
+	if !f.From.IsZero() && e.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Summary 分析结果
+type Summary struct {
+	Lines     int            `json:"lines"`     // 读取的总行数（不含空行）
+	Matched   int            `json:"matched"`   // 通过过滤的日志数
+	Malformed int            `json:"malformed"` // 无法解析的行数
+	ByLevel   map[string]int `json:"by_level"`  // 通过过滤的日志按级别计数
+	First     *time.Time     `json:"first,omitempty"`
+	Last      *time.Time     `json:"last,omitempty"`
+}
+
+// Analyze 流式读取 r 中的日志并汇总
+// 无法解析的行只计数不中断；读取出错时返回已汇总的部分和错误
+func Analyze(r io.Reader, f Filter) (Summary, error) {
+	s := Summary{ByLevel: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.Lines++
+
+		e, err := ParseLine(line)
+		if err != nil {
+			s.Malformed++
+			continue
+		}
+		if !f.Match(e) {
+			continue
+		}
+		s.add(e)
+	}
+	return s, scanner.Err()
+}
+
+// ReadAll 把通过过滤的日志全部读入内存，供需要多次遍历的分析（排序、分组）使用
+//
+// ParseLine 得到的 Level 和 Message 是整行的子串，每条 Entry 都会让整行一直存活。
+// in 不为 nil 时对两者做字符串驻留：重复的级别和消息只保存一份，行本身可以被回收。
+// 消息大多各不相同的日志只会让驻留表越来越大，这时应传 nil。
+func ReadAll(r io.Reader, f Filter, in *intern.Interner) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for scanner.Scan() {
+		e, err := ParseLine(scanner.Text())
+		if err != nil || !f.Match(e) {
+			continue
+		}
+		if in != nil {
+			e.Level = in.Intern(e.Level)
+			e.Message = in.Intern(e.Message)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func (s *Summary) add(e Entry) {
+	s.Matched++
+	s.ByLevel[e.Level]++
+	if s.First == nil || e.Time.Before(*s.First) {
+		t := e.Time
+		s.First = &t
+	}
+	if s.Last == nil || e.Time.After(*s.Last) {
+		t := e.Time
+		s.Last = &t
+	}
+}
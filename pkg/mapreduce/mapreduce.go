@@ -0,0 +1,116 @@
+// ============================================
+// mapreduce 包：并行 map、串行 reduce
+// ============================================
+//
+//   inputs ──分块──> [mapper × Parallelism] ──[]M──> reducer（调用方的 goroutine）──> R
+//
+//   - 输入按 ChunkSize 分块，每个 worker 一次领取一块，对块内每一项调用 mapper，
+//     整块结果一起发给 reducer：块越大，channel 通信的开销占比越小，但负载越不均衡
+//   - reducer 只在一个 goroutine 中运行，不需要加锁；
+//     各块到达的顺序不确定，reducer 应当与顺序无关（计数、求和、合并 map）
+//   - R 从零值开始，reducer 负责初始化（例如 acc 为 nil 时创建 map）
+//   - mapper 返回错误或 ctx 取消时，停止分发剩余的块，返回第一个错误
+//
+// 词频统计示例和随 GOMAXPROCS 的扩展性基准见 cmd/mrbench。
+// ============================================
+
+package mapreduce
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Mapper 处理一个输入
+type Mapper[I, M any] func(ctx context.Context, in I) (M, error)
+
+// Reducer 把一个 map 结果合并进累积值
+type Reducer[M, R any] func(acc R, m M) R
+
+type options struct {
+	parallelism int
+	chunkSize   int
+}
+
+// Option MapReduce 的选项
+type Option func(*options)
+
+// WithParallelism 同时运行的 mapper 数，默认 runtime.GOMAXPROCS(0)
+func WithParallelism(n int) Option { return func(o *options) { o.parallelism = n } }
+
+// WithChunkSize 每块的输入数，默认 64
+func WithChunkSize(n int) Option { return func(o *options) { o.chunkSize = n } }
+
+// DefaultChunkSize WithChunkSize 的默认值
+const DefaultChunkSize = 64
+
+// MapReduce 对每个输入并行调用 mapper，结果依次交给 reducer，返回最终的累积值
+// 出错时返回目前为止的累积值和错误
+func MapReduce[I, M, R any](ctx context.Context, inputs []I, mapper Mapper[I, M], reducer Reducer[M, R], opts ...Option) (R, error) {
+	o := options{parallelism: runtime.GOMAXPROCS(0), chunkSize: DefaultChunkSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.parallelism = max(o.parallelism, 1)
+	o.chunkSize = max(o.chunkSize, 1)
+
+	var acc R
+	chunks := (len(inputs) + o.chunkSize - 1) / o.chunkSize
+	if chunks == 0 {
+		return acc, context.Cause(ctx)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var next atomic.Int64
+	results := make(chan []M, o.parallelism)
+	var wg sync.WaitGroup
+	for range min(o.parallelism, chunks) {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				c := int(next.Add(1) - 1)
+				if c >= chunks {
+					return
+				}
+				start := c * o.chunkSize
+				end := min(start+o.chunkSize, len(inputs))
+				ms := make([]M, 0, end-start)
+				for i := start; i < end; i++ {
+					m, err := mapper(ctx, inputs[i])
+					if err != nil {
+						cancel(fmt.Errorf("mapreduce: 第 %d 项: %w", i, err))
+						return
+					}
+					ms = append(ms, m)
+				}
+				select {
+				case results <- ms:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// reduce 在当前 goroutine 中进行；出错后继续读完 results，让 worker 都能退出
+	for ms := range results {
+		if ctx.Err() != nil {
+			continue
+		}
+		for _, m := range ms {
+			acc = reducer(acc, m)
+		}
+	}
+	if ctx.Err() != nil {
+		return acc, context.Cause(ctx)
+	}
+	return acc, nil
+}
@@ -0,0 +1,165 @@
+// ============================================
+// memo 包：并发安全的函数记忆化
+// ============================================
+//
+// 来自 tutorial/02_functions 的练习 5，把只支持 func(int) int 的 memFunc
+// 推广到任意可比较的参数：
+//
+//   square := memo.Memo(func(n int) int { return n * n })
+//   binom  := memo.Memo2(choose)                       // func(n, k int) int
+//   dist   := memo.Memo3(levenshteinWithCost)          // func(a, b string, cost int) int
+//
+// 多个参数组合成 Key2 / Key3 结构体作为 map 的键：结构体的所有字段都是
+// comparable 时，结构体本身也是 comparable，不需要拼字符串或计算哈希。
+//
+// 可选配置：
+//   WithMaxEntries(n)   最多缓存 n 个结果，超出时淘汰最久未使用的（LRU）
+//   WithOnEvict(f)      淘汰时回调，f 的参数类型必须与缓存的键、值一致
+//
+// 并发：多个 goroutine 可以同时调用返回的函数。计算在锁外进行，同一个键
+// 并发未命中时 f 可能被执行多次，缓存中保留最先写入的结果，所以 f 应当是纯函数。
+// ============================================
+
+package memo
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// Key2 两个参数组成的缓存键
+type Key2[A, B comparable] struct {
+	A A
+	B B
+}
+
+// Key3 三个参数组成的缓存键
+type Key3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
+
+type config struct {
+	maxEntries int
+	onEvict    any // func(K, V)，在 newCache 中检查类型
+}
+
+// Option 记忆化的可选配置
+type Option func(*config)
+
+// WithMaxEntries 限制缓存条数，n <= 0 表示不限（默认）
+func WithMaxEntries(n int) Option {
+	return func(c *config) { c.maxEntries = n }
+}
+
+// WithOnEvict 条目因超出 WithMaxEntries 被淘汰时调用 f
+// Memo 的键是参数本身，Memo2 / Memo3 的键是 Key2 / Key3；
+// 类型不匹配时 Memo / Memo2 / Memo3 在创建时 panic
+// f 在锁外调用，可以再调用被记忆化的函数
+func WithOnEvict[K comparable, V any](f func(key K, value V)) Option {
+	return func(c *config) { c.onEvict = f }
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// cache 带 LRU 淘汰的并发安全缓存
+type cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	max     int
+	items   map[K]*list.Element // 值为 *entry[K, V]
+	order   *list.List          // 最近使用的在前
+	onEvict func(K, V)
+}
+
+func newCache[K comparable, V any](opts []Option) *cache[K, V] {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c := &cache[K, V]{max: cfg.maxEntries, items: make(map[K]*list.Element), order: list.New()}
+	if cfg.onEvict != nil {
+		f, ok := cfg.onEvict.(func(K, V))
+		if !ok {
+			var k K
+			var v V
+			panic(fmt.Sprintf("memo: WithOnEvict 的参数类型为 %T，期望 func(%T, %T)", cfg.onEvict, k, v))
+		}
+		c.onEvict = f
+	}
+	return c
+}
+
+func (c *cache[K, V]) get(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[k]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*entry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// add 写入结果；其他 goroutine 已经写入时返回已有的值
+func (c *cache[K, V]) add(k K, v V) V {
+	c.mu.Lock()
+	if el, ok := c.items[k]; ok {
+		c.order.MoveToFront(el)
+		v = el.Value.(*entry[K, V]).value
+		c.mu.Unlock()
+		return v
+	}
+	c.items[k] = c.order.PushFront(&entry[K, V]{k, v})
+	var evicted []*entry[K, V]
+	for c.max > 0 && c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		e := oldest.Value.(*entry[K, V])
+		delete(c.items, e.key)
+		evicted = append(evicted, e)
+	}
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for _, e := range evicted {
+			c.onEvict(e.key, e.value)
+		}
+	}
+	return v
+}
+
+func (c *cache[K, V]) call(k K, f func() V) V {
+	if v, ok := c.get(k); ok {
+		return v
+	}
+	return c.add(k, f())
+}
+
+// Memo 记忆化单参数函数
+func Memo[K comparable, V any](f func(K) V, opts ...Option) func(K) V {
+	c := newCache[K, V](opts)
+	return func(k K) V {
+		return c.call(k, func() V { return f(k) })
+	}
+}
+
+// Memo2 记忆化两个参数的函数，缓存键为 Key2{a, b}
+func Memo2[A, B comparable, V any](f func(A, B) V, opts ...Option) func(A, B) V {
+	c := newCache[Key2[A, B], V](opts)
+	return func(a A, b B) V {
+		return c.call(Key2[A, B]{a, b}, func() V { return f(a, b) })
+	}
+}
+
+// Memo3 记忆化三个参数的函数，缓存键为 Key3{a, b, c}
+func Memo3[A, B, C comparable, V any](f func(A, B, C) V, opts ...Option) func(A, B, C) V {
+	cc := newCache[Key3[A, B, C], V](opts)
+	return func(a A, b B, c C) V {
+		return cc.call(Key3[A, B, C]{a, b, c}, func() V { return f(a, b, c) })
+	}
+}
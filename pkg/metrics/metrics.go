@@ -0,0 +1,233 @@
+// ============================================
+// metrics 包：进程内指标与 Prometheus 文本格式输出
+// ============================================
+//
+// 三种基本指标，更新路径上只有原子操作，不加锁：
+//   Counter   只增不减的计数（请求数、命中数）
+//   Gauge     可增可减的当前值（进行中的任务数、队列长度）
+//   Histogram 按桶统计分布（耗时），同时记录总数和总和
+//
+// 指标注册到 Registry，Handler 把它们渲染成 Prometheus 文本格式：
+//
+//   # HELP http_requests_total HTTP 请求数
+//   # TYPE http_requests_total counter
+//   http_requests_total{code="200",method="GET"} 3
+//
+// 需要按标签区分时使用 CounterVec / HistogramVec，With 按标签值取出子指标。
+// ============================================
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrDuplicate   = errors.New("metrics: 指标名已注册")
+	ErrInvalidName = errors.New("metrics: 无效的指标名")
+	ErrLabelCount  = errors.New("metrics: 标签值数量不匹配")
+)
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// DefBuckets 默认的耗时桶（秒），与 Prometheus 客户端库一致
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ============================================
+// Counter / Gauge
+// ============================================
+
+// atomicFloat 用 uint64 保存 float64 的位模式，CAS 循环实现原子加法
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) add(v float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// Counter 只增不减的计数器
+type Counter struct {
+	v atomicFloat
+}
+
+// Inc 加 1
+func (c *Counter) Inc() { c.v.add(1) }
+
+// Add 加 v，v 为负数时 panic（计数器只能增加）
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: Counter 不能减少")
+	}
+	c.v.add(v)
+}
+
+// Value 当前值
+func (c *Counter) Value() float64 { return c.v.load() }
+
+// Gauge 可以任意设置的当前值
+type Gauge struct {
+	v atomicFloat
+}
+
+// Set 设置为 v
+func (g *Gauge) Set(v float64) { g.v.bits.Store(math.Float64bits(v)) }
+
+// Inc 加 1
+func (g *Gauge) Inc() { g.v.add(1) }
+
+// Dec 减 1
+func (g *Gauge) Dec() { g.v.add(-1) }
+
+// Add 加 v，v 可以为负
+func (g *Gauge) Add(v float64) { g.v.add(v) }
+
+// Value 当前值
+func (g *Gauge) Value() float64 { return g.v.load() }
+
+// ============================================
+// Histogram
+// ============================================
+
+// Histogram 按上界统计观测值的分布
+// 每个桶只计落在 (上一个上界, 本上界] 的次数，输出时再累加成 Prometheus 的累积桶
+type Histogram struct {
+	upper  []float64
+	counts []atomic.Uint64 // 最后一个是 +Inf 桶
+	sum    atomicFloat
+	count  atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	upper := slices.Clone(buckets)
+	sort.Float64s(upper)
+	upper = slices.Compact(upper)
+	return &Histogram{upper: upper, counts: make([]atomic.Uint64, len(upper)+1)}
+}
+
+// Observe 记录一个观测值
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v) // 第一个 >= v 的上界
+	h.counts[i].Add(1)
+	h.sum.add(v)
+	h.count.Add(1)
+}
+
+// Count 观测次数
+func (h *Histogram) Count() uint64 { return h.count.Load() }
+
+// Sum 观测值之和
+func (h *Histogram) Sum() float64 { return h.sum.load() }
+
+// ============================================
+// 带标签的指标
+// ============================================
+
+// vec 按标签值组合保存子指标，第一次 With 时创建
+type vec[M any] struct {
+	labels []string
+	newM   func() M
+	mu     sync.RWMutex
+	m      map[string]M // key 为标签值用 \xff 连接
+}
+
+func (v *vec[M]) with(values []string) M {
+	if len(values) != len(v.labels) {
+		panic(fmt.Errorf("%w: 需要 %d 个，实际 %d 个", ErrLabelCount, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.RLock()
+	m, ok := v.m[key]
+	v.mu.RUnlock()
+	if ok {
+		return m
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if m, ok := v.m[key]; ok {
+		return m
+	}
+	m = v.newM()
+	v.m[key] = m
+	return m
+}
+
+// each 按标签值排序遍历子指标，保证输出稳定
+func (v *vec[M]) each(fn func(labels string, m M)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.m))
+	for k := range v.m {
+		keys = append(keys, k)
+	}
+	v.mu.RUnlock()
+	sort.Strings(keys)
+	for _, k := range keys {
+		v.mu.RLock()
+		m := v.m[k]
+		v.mu.RUnlock()
+		fn(formatLabels(v.labels, strings.Split(k, "\xff")), m)
+	}
+}
+
+// CounterVec 按标签区分的一组 Counter
+type CounterVec struct {
+	v vec[*Counter]
+}
+
+// With 返回标签值对应的 Counter，值的顺序与注册时的标签名一致
+func (c *CounterVec) With(values ...string) *Counter { return c.v.with(values) }
+
+// HistogramVec 按标签区分的一组 Histogram
+type HistogramVec struct {
+	v vec[*Histogram]
+}
+
+// With 返回标签值对应的 Histogram
+func (h *HistogramVec) With(values ...string) *Histogram { return h.v.with(values) }
+
+// formatLabels 生成 {a="1",b="2"}，标签按名字排序
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	idx := make([]int, len(names))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return names[idx[a]] < names[idx[b]] })
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for n, i := range idx {
+		if n > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `%s="%s"`, names[i], escapeLabel(values[i]))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ============================================
+// Registry：注册与输出
+// ============================================
+
+// Registry 保存一组指标，并发安全
+type Registry struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	name, help, typ string
+	write           func(w io.Writer, name string)
+}
+
+// Default 进程级的默认 Registry，供不方便传递 Registry 的工具函数使用（如 timing.Timed）
+var Default = NewRegistry()
+
+// NewRegistry 创建空的 Registry
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]*entry)}
+}
+
+// NewCounter 注册一个 Counter；名字不合法或重复时 panic，与 http.Handle 的约定相同
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	r.register(name, help, "counter", func(w io.Writer, name string) {
+		writeSample(w, name, "", c.Value())
+	})
+	return c
+}
+
+// NewGauge 注册一个 Gauge
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{}
+	r.register(name, help, "gauge", func(w io.Writer, name string) {
+		writeSample(w, name, "", g.Value())
+	})
+	return g
+}
+
+// NewHistogram 注册一个 Histogram，buckets 为空时使用 DefBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(buckets)
+	r.register(name, help, "histogram", func(w io.Writer, name string) {
+		writeHistogram(w, name, "", h)
+	})
+	return h
+}
+
+// NewCounterVec 注册一组带标签的 Counter
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	cv := &CounterVec{v: vec[*Counter]{labels: labels, newM: func() *Counter { return &Counter{} }, m: make(map[string]*Counter)}}
+	r.register(name, help, "counter", func(w io.Writer, name string) {
+		cv.v.each(func(labels string, c *Counter) {
+			writeSample(w, name, labels, c.Value())
+		})
+	})
+	return cv
+}
+
+// NewHistogramVec 注册一组带标签的 Histogram
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	hv := &HistogramVec{v: vec[*Histogram]{labels: labels, newM: func() *Histogram { return newHistogram(buckets) }, m: make(map[string]*Histogram)}}
+	r.register(name, help, "histogram", func(w io.Writer, name string) {
+		hv.v.each(func(labels string, h *Histogram) {
+			writeHistogram(w, name, labels, h)
+		})
+	})
+	return hv
+}
+
+func (r *Registry) register(name, help, typ string, write func(io.Writer, string)) {
+	if !namePattern.MatchString(name) {
+		panic(fmt.Errorf("%w: %q", ErrInvalidName, name))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		panic(fmt.Errorf("%w: %s", ErrDuplicate, name))
+	}
+	r.entries[name] = &entry{name: name, help: help, typ: typ, write: write}
+}
+
+// WriteText 按名字顺序以 Prometheus 文本格式写出所有指标
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		if e.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", e.name, escapeHelp(e.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", e.name, e.typ)
+		e.write(bw, e.name)
+	}
+	return bw.Flush()
+}
+
+// Handler 返回输出所有指标的 HTTP 处理器，通常挂在 /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+func writeSample(w io.Writer, name, labels string, v float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(v))
+}
+
+// writeHistogram 输出累积桶、_sum 和 _count
+// le 标签要和已有标签合并到同一对花括号里
+func writeHistogram(w io.Writer, name, labels string, h *Histogram) {
+	withLE := func(le string) string {
+		if labels == "" {
+			return `{le="` + le + `"}`
+		}
+		return labels[:len(labels)-1] + `,le="` + le + `"}`
+	}
+	var cum uint64
+	for i, upper := range h.upper {
+		cum += h.counts[i].Load()
+		writeSample(w, name+"_bucket", withLE(formatFloat(upper)), float64(cum))
+	}
+	cum += h.counts[len(h.upper)].Load()
+	writeSample(w, name+"_bucket", withLE("+Inf"), float64(cum))
+	writeSample(w, name+"_sum", labels, h.Sum())
+	// _count 与 +Inf 桶使用同一次读取的值，避免并发 Observe 时两者不一致
+	writeSample(w, name+"_count", labels, float64(cum))
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
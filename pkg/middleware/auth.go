@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"c03/pkg/ctxutil"
+	"c03/pkg/hashutil"
+	"c03/pkg/jwt"
+)
+
+// Auth 要求请求头 Authorization: Bearer <token>
+// 用常量时间比较，避免通过响应耗时逐字节猜出 token，也不会泄露 token 的长度
+func Auth(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := bearerToken(r)
+			if !ok || !hashutil.Equal(got, token) {
+				unauthorized(w, "")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ============================================
+// JWT 认证
+// ============================================
+//
+// Auth 比较的是所有客户端共用的一个固定 token；AuthJWT 接受 jwt.Signer 签发的令牌，
+// 令牌里带着用户是谁（sub）、什么角色（role），服务端不用查会话表：
+//
+//   POST /login（账号密码）──> signer.Issue(user) ──> 客户端保存令牌
+//   GET /me  Authorization: Bearer <令牌> ──> AuthJWT 验证 ──> ClaimsFrom(ctx)
+
+var claimsKey = ctxutil.NewKey[jwt.Claims]("jwtClaims")
+
+// AuthJWT 验证 Bearer 令牌，通过后把 Claims 放进 context
+// 失败时返回 401，WWW-Authenticate 头中带上 error="invalid_token"（RFC 6750）
+func AuthJWT(s *jwt.Signer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tok, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, "")
+				return
+			}
+			claims, err := s.Verify(tok)
+			if err != nil {
+				unauthorized(w, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// WithClaims 返回携带 Claims 的 context
+func WithClaims(ctx context.Context, c jwt.Claims) context.Context {
+	return claimsKey.With(ctx, c)
+}
+
+// ClaimsFrom 取出 AuthJWT 验证过的 Claims；没有经过 AuthJWT 时 ok 为 false
+func ClaimsFrom(ctx context.Context) (jwt.Claims, bool) {
+	return claimsKey.From(ctx)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	return strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// unauthorized 返回 401；reason 非空时作为 invalid_token 的描述，也作为响应正文
+func unauthorized(w http.ResponseWriter, reason string) {
+	challenge := `Bearer realm="api"`
+	body := "unauthorized"
+	if reason != "" {
+		challenge += `, error="invalid_token"`
+		body += ": " + reason
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, body, http.StatusUnauthorized)
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// ============================================
+// 按客户端限流
+// ============================================
+//
+// RateLimit 用一个令牌桶限制所有请求，一个刷接口的客户端就能把配额用光。
+// ClientLimiter 为每个客户端（默认按 IP）分配独立的令牌桶，
+// 长时间没有请求的客户端会被清理，内存占用只与活跃客户端数有关。
+// 清理在 Allow 中顺带进行（每隔 idleTimeout 扫描一次），同样不需要后台 goroutine。
+
+// ClientLimiter 每个客户端一个 TokenBucket，并发安全
+type ClientLimiter struct {
+	rate        float64
+	burst       int
+	keyFunc     func(*http.Request) string
+	idleTimeout time.Duration
+	clock       clock.Clock
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	bucket   *TokenBucket
+	lastSeen time.Time
+}
+
+// ClientLimitOption 配置 ClientLimiter
+type ClientLimitOption func(*ClientLimiter)
+
+// WithKeyFunc 指定如何从请求得到客户端标识，默认 ClientIP
+// 例如按 API key 限流：func(r *http.Request) string { return r.Header.Get("X-API-Key") }
+func WithKeyFunc(fn func(*http.Request) string) ClientLimitOption {
+	return func(l *ClientLimiter) { l.keyFunc = fn }
+}
+
+// WithIdleTimeout 客户端超过 d 没有请求就丢弃它的令牌桶，默认 10 分钟
+// 丢弃后再来的请求拿到的是满桶，所以 d 至少应为 burst/rate 秒
+// d 为 0 表示从不清理；d 为负数时忽略，保留默认值
+func WithIdleTimeout(d time.Duration) ClientLimitOption {
+	return func(l *ClientLimiter) {
+		if d >= 0 {
+			l.idleTimeout = d
+		}
+	}
+}
+
+// WithClock 替换时钟，默认 clock.Real
+func WithClock(c clock.Clock) ClientLimitOption {
+	return func(l *ClientLimiter) { l.clock = clock.Or(c) }
+}
+
+// NewClientLimiter 创建按客户端限流的限流器，每个客户端每秒 rate 个请求，突发 burst 个
+func NewClientLimiter(rate float64, burst int, opts ...ClientLimitOption) *ClientLimiter {
+	l := &ClientLimiter{
+		rate:        rate,
+		burst:       burst,
+		keyFunc:     ClientIP,
+		idleTimeout: 10 * time.Minute,
+		clock:       clock.Real,
+		clients:     make(map[string]*clientBucket),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.lastSweep = l.clock.Now()
+	return l
+}
+
+// Allow 为 key 对应的客户端取走一个令牌
+func (l *ClientLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	now := l.clock.Now()
+	if l.idleTimeout > 0 && now.Sub(l.lastSweep) >= l.idleTimeout {
+		l.sweep(now)
+	}
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientBucket{bucket: newTokenBucket(l.rate, l.burst, l.clock.Now)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	l.mu.Unlock()
+
+	// 桶有自己的锁，不同客户端之间不必互相等待
+	return c.bucket.Allow()
+}
+
+// sweep 删除空闲过久的客户端，调用方持有 mu
+func (l *ClientLimiter) sweep(now time.Time) {
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) >= l.idleTimeout {
+			delete(l.clients, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Len 当前记录的客户端数
+func (l *ClientLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// RateLimitByClient 按 l 的 keyFunc 区分客户端，超出配额的请求返回 429
+func RateLimitByClient(l *ClientLimiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.Allow(l.keyFunc(r)) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP 返回 RemoteAddr 中的 IP
+// 不读取 X-Forwarded-For：它可以被客户端随意伪造，只有在可信代理之后才应使用
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+func TestClientLimiterIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		advance time.Duration
+		want    int // 推进时间后再来一个新客户端，记录中的客户端数
+	}{
+		{"默认 10 分钟，未到期", -1, 5 * time.Minute, 3},
+		{"默认 10 分钟，已到期", -1, 10 * time.Minute, 1},
+		{"自定义 1 秒", time.Second, 2 * time.Second, 1},
+		{"0 表示从不清理", 0, 24 * time.Hour, 3},
+		{"负数被忽略", -time.Second, time.Second, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFakeClock(time.Unix(0, 0))
+			opts := []ClientLimitOption{WithClock(clk)}
+			if tt.timeout != -1 {
+				opts = append(opts, WithIdleTimeout(tt.timeout))
+			}
+			l := NewClientLimiter(1, 1, opts...)
+			l.Allow("a")
+			l.Allow("b")
+			clk.Advance(tt.advance)
+			l.Allow("c")
+			if got := l.Len(); got != tt.want {
+				t.Fatalf("Len() = %d，期望 %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientLimiterZeroTimeoutKeepsBuckets(t *testing.T) {
+	// idleTimeout 为 0 时若每次都清理，客户端每个请求都会拿到满桶，限流失效
+	clk := clock.NewFakeClock(time.Unix(0, 0))
+	l := NewClientLimiter(1, 2, WithClock(clk), WithIdleTimeout(0))
+	for i, want := range []bool{true, true, false, false} {
+		if got := l.Allow("a"); got != want {
+			t.Fatalf("第 %d 次 Allow = %v，期望 %v", i+1, got, want)
+		}
+	}
+}
+
+func TestRateLimitByClientConcurrent(t *testing.T) {
+	const (
+		clients  = 8
+		requests = 50
+		burst    = 10
+	)
+	clk := clock.NewFakeClock(time.Unix(0, 0))
+	l := NewClientLimiter(1, burst, WithClock(clk),
+		WithKeyFunc(func(r *http.Request) string { return r.Header.Get("X-Client") }))
+	h := RateLimitByClient(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	var ok, limited [clients]atomic.Int64
+	var wg sync.WaitGroup
+	for c := range clients {
+		for range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Client", string(rune('a'+c)))
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				switch rec.Code {
+				case http.StatusOK:
+					ok[c].Add(1)
+				case http.StatusTooManyRequests:
+					limited[c].Add(1)
+					if rec.Header().Get("Retry-After") == "" {
+						t.Error("429 响应缺少 Retry-After")
+					}
+				default:
+					t.Errorf("意外的状态码 %d", rec.Code)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// 时钟不走，每个客户端恰好放行 burst 个，互不影响
+	for c := range clients {
+		if got := ok[c].Load(); got != burst {
+			t.Errorf("客户端 %d 放行 %d 个，期望 %d", c, got, burst)
+		}
+		if got := limited[c].Load(); got != requests-burst {
+			t.Errorf("客户端 %d 拒绝 %d 个，期望 %d", c, got, requests-burst)
+		}
+	}
+	if got := l.Len(); got != clients {
+		t.Errorf("Len() = %d，期望 %d", got, clients)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct{ remote, want string }{
+		{"192.0.2.1:1234", "192.0.2.1"},
+		{"[2001:db8::1]:80", "2001:db8::1"},
+		{"no-port", "no-port"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remote
+		r.Header.Set("X-Forwarded-For", "203.0.113.9")
+		if got := ClientIP(r); got != tt.want {
+			t.Errorf("ClientIP(%q) = %q，期望 %q", tt.remote, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Logging 每个请求结束后记录一行：方法、路径、状态码、字节数和耗时
+// 处理器 panic 时同样记录，状态码显示为 panic，panic 继续向外传播
+// 外层有 RequestID 时，行尾附加 request_id=<id>
+func Logging(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			completed := false
+			defer func() {
+				status := "panic"
+				switch {
+				case !completed:
+				case rec.status == 0:
+					status = "200" // 处理器什么都没写，net/http 会补 200
+				default:
+					status = strconv.Itoa(rec.status)
+				}
+				line := fmt.Sprintf("%s %s %s %dB %v", r.Method, r.URL.RequestURI(), status, rec.bytes, time.Since(start))
+				if id := RequestIDFrom(r.Context()); id != "" {
+					line += " request_id=" + id
+				}
+				logger.Print(line)
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+		})
+	}
+}
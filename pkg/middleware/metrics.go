@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"c03/pkg/metrics"
+)
+
+// Metrics 在 reg 中注册 HTTP 指标并在每个请求结束后更新：
+//
+//	http_requests_total{method,code}         请求数
+//	http_request_duration_seconds{method}    耗时分布
+//	http_requests_in_flight                  正在处理的请求数
+//
+// 同一个 reg 只能调用一次（指标名重复会 panic）
+func Metrics(reg *metrics.Registry) Middleware {
+	requests := reg.NewCounterVec("http_requests_total", "HTTP 请求数", "method", "code")
+	duration := reg.NewHistogramVec("http_request_duration_seconds", "HTTP 请求耗时（秒）", nil, "method")
+	inFlight := reg.NewGauge("http_requests_in_flight", "正在处理的 HTTP 请求数")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			inFlight.Inc()
+			rec := &statusRecorder{ResponseWriter: w}
+			completed := false
+			defer func() {
+				inFlight.Dec()
+				code := "500" // panic 时由外层的 Recovery 返回 500
+				switch {
+				case !completed:
+				case rec.status == 0:
+					code = "200"
+				default:
+					code = strconv.Itoa(rec.status)
+				}
+				requests.With(r.Method, code).Inc()
+				duration.With(r.Method).Observe(time.Since(start).Seconds())
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+		})
+	}
+}
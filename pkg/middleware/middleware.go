@@ -0,0 +1,69 @@
+// ============================================
+// middleware 包：HTTP 中间件链
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 6。
+// 中间件就是"接收一个 Handler、返回一个新 Handler"的函数，
+// 用 Chain 组合后，请求按参数顺序从外到内依次经过：
+//
+//   handler := middleware.Chain(
+//       middleware.RequestID(),        // 最先分配请求 ID，后面的日志都能带上
+//       middleware.Recovery(logger),   // 兜住后面所有中间件的 panic
+//       middleware.Logging(logger),
+//       middleware.Metrics(registry),
+//       middleware.RateLimit(limiter),  // 或 RateLimitByClient(clientLimiter) 按 IP 限流
+//       middleware.Auth(token),        // 或 AuthJWT(signer)：验证 pkg/jwt 签发的令牌
+//   )(mux)
+// ============================================
+
+package middleware
+
+import (
+	"net/http"
+)
+
+// Middleware 包装一个 http.Handler
+type Middleware func(next http.Handler) http.Handler
+
+// Chain 把多个中间件组合成一个，第一个参数是最外层
+func Chain(mws ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// statusRecorder 记录响应状态码和写出的字节数
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap 让 http.ResponseController 能找到底层的 ResponseWriter（Flush 等）
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// wroteHeader 响应头是否已经发出
+func (r *statusRecorder) wroteHeader() bool {
+	return r.status != 0
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve 用 httptest 把请求交给 h，返回响应记录
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+})
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name+">")
+				next.ServeHTTP(w, r)
+				order = append(order, "<"+name)
+			})
+		}
+	}
+	h := Chain(mark("a"), mark("b"), mark("c"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+	serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := "a> b> c> handler <c <b <a"
+	if got := strings.Join(order, " "); got != want {
+		t.Fatalf("执行顺序 %q，期望 %q", got, want)
+	}
+
+	// 没有中间件时原样返回处理器
+	if rec := serve(Chain()(okHandler), httptest.NewRequest(http.MethodGet, "/", nil)); rec.Body.String() != "ok" {
+		t.Fatalf("Chain() 响应 %q，期望 ok", rec.Body)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"默认 200", func(w http.ResponseWriter, r *http.Request) {}, "GET /items?q=1 200 0B"},
+		{"写出正文", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("hello")) }, "GET /items?q=1 200 5B"},
+		{"显式状态码", func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }, "GET /items?q=1 404 19B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := Logging(log.New(&buf, "", 0))(tt.handler)
+			serve(h, httptest.NewRequest(http.MethodGet, "/items?q=1", nil))
+			if !strings.HasPrefix(buf.String(), tt.want+" ") {
+				t.Fatalf("日志 %q，期望以 %q 开头", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingPanic(t *testing.T) {
+	var buf bytes.Buffer
+	h := Logging(log.New(&buf, "", 0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Logging 不应吞掉 panic")
+			}
+		}()
+		serve(h, httptest.NewRequest(http.MethodGet, "/p", nil))
+	}()
+	if !strings.HasPrefix(buf.String(), "GET /p panic ") {
+		t.Fatalf("日志 %q，期望记录为 panic", buf.String())
+	}
+}
+
+func TestLoggingRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	h := Chain(RequestID(), Logging(log.New(&buf, "", 0)))(okHandler)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "abc-123")
+	serve(h, r)
+	if !strings.Contains(buf.String(), "request_id=abc-123") {
+		t.Fatalf("日志 %q 中没有请求 ID", buf.String())
+	}
+}
+
+func TestAuth(t *testing.T) {
+	h := Auth("secret")(okHandler)
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"正确的 token", "Bearer secret", http.StatusOK},
+		{"没有 Authorization", "", http.StatusUnauthorized},
+		{"错误的 token", "Bearer nope", http.StatusUnauthorized},
+		{"token 的前缀", "Bearer secre", http.StatusUnauthorized},
+		{"不是 Bearer", "Basic secret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			rec := serve(h, r)
+			if rec.Code != tt.want {
+				t.Fatalf("状态码 %d，期望 %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("401 响应缺少 WWW-Authenticate")
+			}
+		})
+	}
+}
+
+// fixedLimiter 按预设序列放行或拒绝
+type fixedLimiter struct{ allow []bool }
+
+func (l *fixedLimiter) Allow() bool {
+	ok := l.allow[0]
+	l.allow = l.allow[1:]
+	return ok
+}
+
+func TestRateLimit(t *testing.T) {
+	h := RateLimit(&fixedLimiter{allow: []bool{true, false, true}})(okHandler)
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK} {
+		rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != want {
+			t.Fatalf("第 %d 个请求状态码 %d，期望 %d", i+1, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Fatal("429 响应缺少 Retry-After")
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(2, 3, func() time.Time { return now })
+
+	// 初始是满的：连续 3 个放行，第 4 个拒绝
+	for i, want := range []bool{true, true, true, false} {
+		if got := b.Allow(); got != want {
+			t.Fatalf("第 %d 次 Allow = %v，期望 %v", i+1, got, want)
+		}
+	}
+	// 每秒补 2 个：0.5 秒后补 1 个
+	now = now.Add(500 * time.Millisecond)
+	if !b.Allow() || b.Allow() {
+		t.Fatal("0.5 秒后应恰好补充 1 个令牌")
+	}
+	// 很久之后也不超过 burst
+	now = now.Add(time.Hour)
+	for i := range 3 {
+		if !b.Allow() {
+			t.Fatalf("第 %d 次 Allow 被拒绝，桶应是满的", i+1)
+		}
+	}
+	if b.Allow() {
+		t.Fatal("令牌数超过了 burst")
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	t.Run("panic 返回 500", func(t *testing.T) {
+		buf.Reset()
+		h := Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		rec := serve(h, httptest.NewRequest(http.MethodGet, "/x", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("状态码 %d，期望 500", rec.Code)
+		}
+		if !strings.Contains(buf.String(), "panic: GET /x: boom") {
+			t.Fatalf("日志 %q 中没有 panic 信息", buf.String())
+		}
+	})
+
+	t.Run("不 panic 时透传", func(t *testing.T) {
+		rec := serve(Recovery(logger)(okHandler), httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+			t.Fatalf("响应 %d %q，期望 200 ok", rec.Code, rec.Body)
+		}
+	})
+
+	// 响应已经写出一部分或处理器主动中止时，改为 panic(http.ErrAbortHandler) 让 net/http 断开连接
+	for name, handler := range map[string]http.HandlerFunc{
+		"写出后 panic": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("partial"))
+			panic("boom")
+		},
+		"ErrAbortHandler": func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if v := recover(); v != http.ErrAbortHandler {
+					t.Fatalf("recover() = %v，期望 http.ErrAbortHandler", v)
+				}
+			}()
+			serve(Recovery(logger)(handler), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter 决定当前请求是否放行
+type Limiter interface {
+	Allow() bool
+}
+
+// RateLimit 被 limiter 拒绝的请求直接返回 429
+func RateLimit(limiter Limiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Allow() {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TokenBucket 令牌桶限流器
+// 桶里最多 burst 个令牌，每秒补充 rate 个；每个请求消耗一个令牌。
+// 令牌按距上次补充的时间惰性计算，不需要后台 goroutine。
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket 创建令牌桶，初始是满的
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return newTokenBucket(rate, burst, time.Now)
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+		now:    now,
+	}
+}
+
+// Allow 取走一个令牌，桶空时返回 false
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery 捕获处理器中的 panic，记录堆栈并返回 500
+// 应当放在中间件链的最外层（只有不会 panic 的 RequestID 可以在它之前）
+func Recovery(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v) // 约定的中止信号，交给 net/http 断开连接
+				}
+				where := r.Method + " " + r.URL.Path
+				if id := RequestIDFrom(r.Context()); id != "" {
+					where += " request_id=" + id
+				}
+				logger.Printf("panic: %s: %v\n%s", where, v, debug.Stack())
+				if rec.wroteHeader() {
+					// 响应已经写出一部分，无法再改成 500，
+					// 中止连接让客户端知道响应不完整
+					panic(http.ErrAbortHandler)
+				}
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
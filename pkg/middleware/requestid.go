@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"c03/pkg/ctxutil"
+)
+
+// ============================================
+// 请求 ID
+// ============================================
+//
+// 一个请求往往会经过多个中间件、处理器，再交给后台 worker 异步处理。
+// 给每个请求分配一个 ID 并放进 context，所有日志都带上它，
+// 出问题时按 ID 一搜就能串起整条链路：
+//
+//   客户端 ──X-Request-ID──> RequestID ──ctx──> 处理器 ──ctx──> worker
+//      <──── 响应头回显 ────┘           日志: request_id=...
+//
+// 上游（网关、其他服务）已经带了 X-Request-ID 时沿用它，整条调用链使用同一个 ID。
+
+// RequestIDHeader 传递请求 ID 的 HTTP 头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen 沿用上游 ID 时的最大长度，防止客户端塞入超长的值污染日志
+const maxRequestIDLen = 64
+
+var requestIDKey = ctxutil.NewKey[string]("requestID")
+
+// RequestID 为每个请求确定 ID，写入 context 和响应头
+// 应放在中间件链的最前面，后面的 Recovery、Logging 才能记录到 ID
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// WithRequestID 返回携带请求 ID 的 context，用于不经过 HTTP 的入口（如定时任务）
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return requestIDKey.With(ctx, id)
+}
+
+// RequestIDFrom 取出请求 ID，没有时返回空字符串
+func RequestIDFrom(ctx context.Context) string {
+	return requestIDKey.Get(ctx)
+}
+
+// newRequestID 16 个十六进制字符的随机 ID
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID 只接受长度合理、由字母数字和 -_. 组成的 ID
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================
+// 结构化日志
+// ============================================
+
+// requestIDHandler 给每条日志加上 ctx 中的 request_id 字段
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// NewRequestIDHandler 包装 h：使用 slog.InfoContext(ctx, ...) 等带 ctx 的方法记录日志时，
+// 自动附加 request_id 字段。worker 只要拿到请求的 ctx，日志就能和 HTTP 层对上
+//
+//	logger := slog.New(middleware.NewRequestIDHandler(slog.NewTextHandler(os.Stderr, nil)))
+func NewRequestIDHandler(h slog.Handler) slog.Handler {
+	return requestIDHandler{h}
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFrom(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
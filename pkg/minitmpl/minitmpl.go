@@ -0,0 +1,350 @@
+// ============================================
+// minitmpl 包：简化版模板引擎
+// ============================================
+//
+// 对应 tutorial/10_standard_lib 练习 7，支持：
+//   {{.Name}}                  字段替换，可以嵌套 {{.Address.City}}
+//   {{.}}                      当前值本身（常用于 range 字符串切片）
+//   {{if .Cond}}...{{end}}     条件，值不是零值时成立，可带 {{else}}
+//   {{range .Items}}...{{end}} 遍历切片或数组，块内的 . 是当前元素
+//
+// Parse 是泛型的：解析时就根据数据类型 T 检查字段是否存在，
+// 拼错字段名会在 Parse 阶段报错并给出行号，而不是执行时才输出空字符串。
+// 字段路径在解析时解析为字段下标，执行时直接用 FieldByIndex 取值。
+// ============================================
+
+package minitmpl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrSyntax       = errors.New("模板语法错误")
+	ErrUnknownField = errors.New("未知字段")
+	ErrNotRangeable = errors.New("只能 range 切片或数组")
+)
+
+// actionPattern 匹配 {{ ... }}，动作两侧的空白会被忽略
+var actionPattern = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+
+// Template 解析好的模板，可以并发执行
+type Template[T any] struct {
+	nodes []node
+}
+
+// Parse 解析模板，并按 T 的结构检查所有字段引用
+func Parse[T any](src string) (*Template[T], error) {
+	p := &parser{src: src}
+	p.tokenize()
+	nodes, end, err := p.parseList(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	if end != "" {
+		return nil, p.errorf(ErrSyntax, "多余的 {{%s}}", end)
+	}
+	return &Template[T]{nodes: nodes}, nil
+}
+
+// MustParse 与 Parse 相同，出错时 panic，用于包级变量初始化
+func MustParse[T any](src string) *Template[T] {
+	t, err := Parse[T](src)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Execute 用 data 渲染模板，写入 w
+func (t *Template[T]) Execute(w io.Writer, data T) error {
+	return execList(w, t.nodes, reflect.ValueOf(&data).Elem())
+}
+
+// Render 渲染为字符串
+func (t *Template[T]) Render(data T) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ============================================
+// 语法树
+// ============================================
+
+type node interface{ isNode() }
+
+type textNode struct{ text string }
+
+// fieldNode {{.A.B}}，path 为空表示 {{.}}
+type fieldNode struct{ path fieldPath }
+
+type ifNode struct {
+	cond      fieldPath
+	then, els []node
+}
+
+type rangeNode struct {
+	over fieldPath
+	body []node
+}
+
+func (textNode) isNode()  {}
+func (fieldNode) isNode() {}
+func (ifNode) isNode()    {}
+func (rangeNode) isNode() {}
+
+// fieldPath 每一段是一个结构体字段的下标路径（匿名嵌入字段会有多个下标）
+type fieldPath [][]int
+
+// ============================================
+// 解析
+// ============================================
+
+// token 文本或动作；动作保存 {{ }} 内去掉空白后的内容
+type token struct {
+	text   string
+	action bool
+	offset int // 在源码中的字节偏移，用于报告行号
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) tokenize() {
+	last := 0
+	for _, m := range actionPattern.FindAllStringSubmatchIndex(p.src, -1) {
+		if m[0] > last {
+			p.tokens = append(p.tokens, token{text: p.src[last:m[0]], offset: last})
+		}
+		p.tokens = append(p.tokens, token{text: p.src[m[2]:m[3]], action: true, offset: m[0]})
+		last = m[1]
+	}
+	if last < len(p.src) {
+		p.tokens = append(p.tokens, token{text: p.src[last:], offset: last})
+	}
+}
+
+// parseList 解析到 {{end}}、{{else}} 或输入结束，返回遇到的结束动作（输入结束时为 ""）
+func (p *parser) parseList(dot reflect.Type) ([]node, string, error) {
+	var nodes []node
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		p.pos++
+		if !tok.action {
+			nodes = append(nodes, textNode{tok.text})
+			continue
+		}
+
+		keyword, arg, _ := strings.Cut(tok.text, " ")
+		arg = strings.TrimSpace(arg)
+		switch keyword {
+		case "end", "else":
+			if arg != "" {
+				return nil, "", p.errorf(ErrSyntax, "{{%s}} 不接受参数", keyword)
+			}
+			return nodes, keyword, nil
+
+		case "if":
+			n, err := p.parseIf(dot, arg)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, n)
+
+		case "range":
+			n, err := p.parseRange(dot, arg)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, n)
+
+		default:
+			path, _, err := p.resolve(dot, tok.text)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, fieldNode{path})
+		}
+	}
+	return nodes, "", nil
+}
+
+func (p *parser) parseIf(dot reflect.Type, arg string) (node, error) {
+	start := p.pos - 1
+	cond, _, err := p.resolve(dot, arg)
+	if err != nil {
+		return nil, err
+	}
+	n := ifNode{cond: cond}
+	var end string
+	if n.then, end, err = p.parseList(dot); err != nil {
+		return nil, err
+	}
+	if end == "else" {
+		if n.els, end, err = p.parseList(dot); err != nil {
+			return nil, err
+		}
+	}
+	if end != "end" {
+		return nil, p.errorAt(start, ErrSyntax, "{{if %s}} 缺少 {{end}}", arg)
+	}
+	return n, nil
+}
+
+func (p *parser) parseRange(dot reflect.Type, arg string) (node, error) {
+	start := p.pos - 1
+	over, t, err := p.resolve(dot, arg)
+	if err != nil {
+		return nil, err
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, p.errorf(ErrNotRangeable, "%s 的类型是 %v", arg, t)
+	}
+	body, end, err := p.parseList(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	if end != "end" {
+		return nil, p.errorAt(start, ErrSyntax, "{{range %s}} 缺少 {{end}}", arg)
+	}
+	return rangeNode{over: over, body: body}, nil
+}
+
+// resolve 在类型 dot 上查找 .A.B 形式的字段路径，返回路径和最终类型
+func (p *parser) resolve(dot reflect.Type, expr string) (fieldPath, reflect.Type, error) {
+	if expr == "" || expr[0] != '.' {
+		return nil, nil, p.errorf(ErrSyntax, "无法识别的动作 {{%s}}", expr)
+	}
+	if expr == "." {
+		return nil, dot, nil
+	}
+
+	var path fieldPath
+	t := dot
+	for name := range strings.SplitSeq(expr[1:], ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, nil, p.errorf(ErrUnknownField, "%s：%v 不是结构体", expr, t)
+		}
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return nil, nil, p.errorf(ErrUnknownField, "%s（类型 %v 没有导出字段 %s）", expr, t, name)
+		}
+		path = append(path, f.Index)
+		t = f.Type
+	}
+	return path, t, nil
+}
+
+// errorf 生成带当前动作行号的错误
+func (p *parser) errorf(kind error, format string, args ...any) error {
+	return p.errorAt(p.pos-1, kind, format, args...)
+}
+
+// errorAt 生成带第 i 个 token 行号的错误
+func (p *parser) errorAt(i int, kind error, format string, args ...any) error {
+	offset := 0
+	if i >= 0 {
+		offset = p.tokens[i].offset
+	}
+	line := strings.Count(p.src[:offset], "\n") + 1
+	return fmt.Errorf("%w: 第 %d 行: %s", kind, line, fmt.Sprintf(format, args...))
+}
+
+// ============================================
+// 执行
+// ============================================
+
+func execList(w io.Writer, nodes []node, dot reflect.Value) error {
+	for _, n := range nodes {
+		if err := execNode(w, n, dot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execNode(w io.Writer, n node, dot reflect.Value) error {
+	switch n := n.(type) {
+	case textNode:
+		_, err := io.WriteString(w, n.text)
+		return err
+
+	case fieldNode:
+		v := lookup(dot, n.path)
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return nil // 路径上有 nil 指针，输出空
+		}
+		_, err := fmt.Fprint(w, v.Interface())
+		return err
+
+	case ifNode:
+		if truth(lookup(dot, n.cond)) {
+			return execList(w, n.then, dot)
+		}
+		return execList(w, n.els, dot)
+
+	case rangeNode:
+		v := lookup(dot, n.over)
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if !v.IsValid() || v.Kind() == reflect.Pointer {
+			return nil
+		}
+		for i := range v.Len() {
+			if err := execList(w, n.body, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookup 沿字段路径取值，途中遇到 nil 指针时返回无效的 Value
+func lookup(v reflect.Value, path fieldPath) reflect.Value {
+	for _, index := range path {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		var err error
+		if v, err = v.FieldByIndexErr(index); err != nil {
+			return reflect.Value{} // 嵌入的结构体指针为 nil
+		}
+	}
+	return v
+}
+
+// truth 零值为假，空切片和空 map 也为假
+func truth(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	}
+	return !v.IsZero()
+}
@@ -0,0 +1,45 @@
+package minitmpl
+
+import (
+	"errors"
+	"testing"
+)
+
+type fuzzData struct {
+	Name  string
+	Admin bool
+	Tags  []string
+	Addr  struct{ City string }
+}
+
+// FuzzParse 检查任意模板文本：解析失败时错误必须包装哨兵错误，
+// 解析成功时必须能正常渲染
+// 运行：go test ./pkg/minitmpl -fuzz FuzzParse
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"Hello {{.Name}}!",
+		"{{if .Admin}}管理员{{else}}用户{{end}}",
+		"{{range .Tags}}[{{.}}]{{end}}",
+		"{{.Addr.City}}",
+		"{{if .Name}}",
+		"{{.Missing}}",
+		"{{range .Name}}{{end}}",
+		"}}{{",
+	} {
+		f.Add(seed)
+	}
+	data := fuzzData{Name: "Go", Admin: true, Tags: []string{"a", "b"}}
+	data.Addr.City = "Beijing"
+	f.Fuzz(func(t *testing.T, src string) {
+		tmpl, err := Parse[fuzzData](src)
+		if err != nil {
+			if !errors.Is(err, ErrSyntax) && !errors.Is(err, ErrUnknownField) && !errors.Is(err, ErrNotRangeable) {
+				t.Fatalf("Parse(%q) 返回的错误没有包装哨兵错误: %v", src, err)
+			}
+			return
+		}
+		if _, err := tmpl.Render(data); err != nil {
+			t.Fatalf("Parse(%q) 成功但 Render 失败: %v", src, err)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// ============================================
+// optional 包：Option 与 Result
+// ============================================
+//
+// 来自 tutorial/08_generics 第 7 节，仿照 Rust 的 Option / Result：
+//
+//   port := optional.None[int]()
+//   port.UnwrapOr(8080)                  // 8080
+//
+//   r := optional.Of(strconv.Atoi("42"))  // 把 (值, error) 包成 Result
+//   r.UnwrapOr(0)                         // 42
+//
+// Go 的惯用写法仍然是 (T, bool) 和 (T, error)：函数签名里直接返回它们，
+// 调用方一眼就知道要检查什么。Option / Result 适合把"可能没有的值"存进结构体字段、
+// 切片或 channel，例如一批并发任务的结果 []Result[T]。
+// ============================================
+
+package optional
+
+import "fmt"
+
+// Option 可能不存在的值；零值是 None
+type Option[T any] struct {
+	value   T
+	present bool
+}
+
+// Some 包含 v 的 Option
+func Some[T any](v T) Option[T] {
+	return Option[T]{value: v, present: true}
+}
+
+// None 不包含值的 Option
+func None[T any]() Option[T] {
+	return Option[T]{present: false}
+}
+
+// IsSome 是否有值
+func (o Option[T]) IsSome() bool {
+	return o.present
+}
+
+// IsNone 是否没有值
+func (o Option[T]) IsNone() bool {
+	return !o.present
+}
+
+// Get 以 Go 惯用的 (值, ok) 形式返回
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Unwrap 返回值，None 时 panic
+func (o Option[T]) Unwrap() T {
+	if !o.present {
+		panic("called Unwrap on None")
+	}
+	return o.value
+}
+
+// UnwrapOr 返回值，None 时返回 defaultValue
+func (o Option[T]) UnwrapOr(defaultValue T) T {
+	if o.present {
+		return o.value
+	}
+	return defaultValue
+}
+
+func (o Option[T]) String() string {
+	if !o.present {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// Result 值或错误；零值是值为零值的 Ok
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok 成功的结果
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v, err: nil}
+}
+
+// Err 失败的结果
+func Err[T any](e error) Result[T] {
+	var zero T
+	return Result[T]{value: zero, err: e}
+}
+
+// Of 把 (值, error) 形式的返回值包成 Result：optional.Of(strconv.Atoi(s))
+func Of[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// IsOk 是否成功
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr 是否失败
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get 以 Go 惯用的 (值, error) 形式返回
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Unwrap 返回值，失败时以错误 panic
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// UnwrapOr 返回值，失败时返回 defaultValue
+func (r Result[T]) UnwrapOr(defaultValue T) T {
+	if r.err == nil {
+		return r.value
+	}
+	return defaultValue
+}
+
+// Error 失败时的错误，成功时为 nil
+func (r Result[T]) Error() error {
+	return r.err
+}
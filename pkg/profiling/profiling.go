@@ -0,0 +1,85 @@
+// ============================================
+// profiling 包：在一次函数调用前后采集 pprof
+// ============================================
+//
+// 基准只告诉你"慢了多少"，profile 告诉你"慢在哪里"。Profile 在 fn 运行期间
+// 采集 CPU profile，结束后写一份堆 profile：
+//
+//   files, err := profiling.Profile(ctx, "prof", func(ctx context.Context) error {
+//       runBenchmarks()
+//       return nil
+//   })
+//   // go tool pprof -top prof/cpu.pprof
+//   // go tool pprof -sample_index=alloc_space -top prof/heap.pprof
+//
+// dir 为空时直接调用 fn，不采集，命令行程序可以把 -profile 参数直接传进来。
+// 长期运行的服务用 httpserver.WithPprof 按需在线采集。
+// ============================================
+
+package profiling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Files 写出的 profile 文件路径
+type Files struct {
+	CPU  string
+	Heap string
+}
+
+// Profile 在 dir 下写出 fn 运行期间的 cpu.pprof 和结束时的 heap.pprof
+// fn 返回错误时 profile 照常写出，错误原样返回；
+// 同一时间只能有一个 CPU profile，其他地方已经在采集时返回错误且不调用 fn
+func Profile(ctx context.Context, dir string, fn func(ctx context.Context) error) (Files, error) {
+	if err := ctx.Err(); err != nil {
+		return Files{}, err
+	}
+	if dir == "" {
+		return Files{}, fn(ctx)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Files{}, err
+	}
+	files := Files{
+		CPU:  filepath.Join(dir, "cpu.pprof"),
+		Heap: filepath.Join(dir, "heap.pprof"),
+	}
+
+	cpu, err := os.Create(files.CPU)
+	if err != nil {
+		return Files{}, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		os.Remove(files.CPU)
+		return Files{}, fmt.Errorf("profiling: %w", err)
+	}
+	fnErr := fn(ctx)
+	pprof.StopCPUProfile()
+	if err := cpu.Close(); err != nil {
+		return files, errors.Join(fnErr, err)
+	}
+
+	return files, errors.Join(fnErr, writeHeap(files.Heap))
+}
+
+// writeHeap 先 GC，让 inuse 数据反映存活对象；alloc_* 数据是程序启动以来的累计值
+func writeHeap(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := pprof.Lookup("heap").WriteTo(f, 0); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,251 @@
+// ============================================
+// prop 包：基于性质的测试（property-based testing）
+// ============================================
+//
+// 普通测试检查几个手写的例子，性质测试描述"对所有输入都成立"的规律，
+// 由生成器产生大量随机输入去验证：
+//
+//   err := prop.Check(prop.SliceOf(prop.Int(-100, 100)), func(xs []int) bool {
+//       ys := slices.Clone(xs)
+//       slices.Reverse(ys)
+//       slices.Reverse(ys)
+//       return slices.Equal(xs, ys)           // Reverse(Reverse(xs)) == xs
+//   })
+//
+// 找到反例后会缩小（shrink）：反复尝试更简单的输入（更短的切片、更接近 0 的数），
+// 只要性质仍然不成立就接受，最后报告的是最小反例，如 [0 -1] 而不是 37 个随机数。
+//
+// 生成器：Int、String、SliceOf、Struct（用 pkg/fake 按字段填充，不缩小）、
+// 以及自定义的 Gen{Generate, Shrink}。size 从 0 逐渐增大到 MaxSize，
+// 先试小输入，简单的反例更早被发现。
+// ============================================
+
+package prop
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"c03/pkg/fake"
+)
+
+// Gen 类型 T 的生成器
+type Gen[T any] struct {
+	// Generate 生成一个值，size 控制规模（切片长度、字符串长度、整数范围）
+	Generate func(r *rand.Rand, size int) T
+	// Shrink 返回比 v 更简单的候选值，越简单的越靠前；nil 表示不缩小
+	Shrink func(v T) []T
+}
+
+// Failure 性质不成立
+type Failure[T any] struct {
+	Counterexample T // 缩小后的反例
+	Original       T // 最初找到的反例
+	Runs           int
+	Shrinks        int // 成功缩小的步数
+	Seed           uint64
+}
+
+func (f *Failure[T]) Error() string {
+	return fmt.Sprintf("第 %d 次运行失败（seed=%d），缩小 %d 步后的反例: %#v", f.Runs, f.Seed, f.Shrinks, f.Counterexample)
+}
+
+type config struct {
+	runs    int
+	maxSize int
+	seed    uint64
+}
+
+// Option Check 的可选配置
+type Option func(*config)
+
+// WithRuns 运行次数，默认 200
+func WithRuns(n int) Option { return func(c *config) { c.runs = n } }
+
+// WithMaxSize 生成规模的上限，默认 50
+func WithMaxSize(n int) Option { return func(c *config) { c.maxSize = n } }
+
+// WithSeed 随机数种子，默认 1；失败信息中会带上种子，便于复现
+func WithSeed(seed uint64) Option { return func(c *config) { c.seed = seed } }
+
+// maxShrinkSteps 缩小的步数上限，防止 Shrink 实现不当时死循环
+const maxShrinkSteps = 1000
+
+// Check 用 g 生成的输入验证 property，全部成立时返回 nil，否则返回 *Failure[T]
+// property 中的 panic 视为不成立
+func Check[T any](g Gen[T], property func(T) bool, opts ...Option) error {
+	cfg := config{runs: 200, maxSize: 50, seed: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	r := rand.New(rand.NewPCG(cfg.seed, cfg.seed^0x9e3779b97f4a7c15))
+	for i := range cfg.runs {
+		size := cfg.maxSize * i / max(cfg.runs-1, 1)
+		v := g.Generate(r, size)
+		if holds(property, v) {
+			continue
+		}
+		f := &Failure[T]{Counterexample: v, Original: v, Runs: i + 1, Seed: cfg.seed}
+		f.Counterexample, f.Shrinks = shrink(g, property, v)
+		return f
+	}
+	return nil
+}
+
+func holds[T any](property func(T) bool, v T) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return property(v)
+}
+
+// shrink 贪心缩小：取第一个仍然失败的候选值，从它继续，直到没有候选值失败
+func shrink[T any](g Gen[T], property func(T) bool, v T) (T, int) {
+	if g.Shrink == nil {
+		return v, 0
+	}
+	steps := 0
+	for steps < maxShrinkSteps {
+		progressed := false
+		for _, c := range g.Shrink(v) {
+			if !holds(property, c) {
+				v = c
+				steps++
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return v, steps
+}
+
+// ============================================
+// 生成器
+// ============================================
+
+// Int [lo, hi] 内的整数，size 较小时集中在 0（或最接近 0 的边界）附近
+// 缩小方向是 0（0 不在范围内时是离 0 最近的边界）
+func Int(lo, hi int) Gen[int] {
+	if lo > hi {
+		panic(fmt.Sprintf("prop: Int(%d, %d) 的范围为空", lo, hi))
+	}
+	target := min(max(0, lo), hi)
+	return Gen[int]{
+		Generate: func(r *rand.Rand, size int) int {
+			// 以 target 为中心，范围随 size 扩大
+			a, b := max(lo, target-size*size), min(hi, target+size*size)
+			return a + r.IntN(b-a+1)
+		},
+		Shrink: func(v int) []int {
+			if v == target {
+				return nil
+			}
+			out := []int{target}
+			// 逐步减半靠近 target：v-(v-t)/2, v-(v-t)/4, ..., 最后一步是 v±1
+			for d := (v - target) / 2; d != 0; d /= 2 {
+				out = append(out, v-d)
+			}
+			if step := v - sign(v-target); step != target {
+				out = append(out, step)
+			}
+			return out
+		},
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// ASCII 可打印 ASCII 字符
+const ASCII = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// String 由 alphabet 中的字符组成、长度不超过 size 的字符串，alphabet 为空时使用 ASCII
+// 缩小时删除字符，再把字符替换成 alphabet 的第一个字符
+func String(alphabet string) Gen[string] {
+	if alphabet == "" {
+		alphabet = ASCII
+	}
+	chars := []rune(alphabet)
+	runes := SliceOf(Gen[rune]{
+		Generate: func(r *rand.Rand, _ int) rune { return chars[r.IntN(len(chars))] },
+		Shrink: func(c rune) []rune {
+			if c == chars[0] {
+				return nil
+			}
+			return []rune{chars[0]}
+		},
+	})
+	return Gen[string]{
+		Generate: func(r *rand.Rand, size int) string { return string(runes.Generate(r, size)) },
+		Shrink: func(s string) []string {
+			var out []string
+			for _, c := range runes.Shrink([]rune(s)) {
+				out = append(out, string(c))
+			}
+			return out
+		},
+	}
+}
+
+// SliceOf 长度不超过 size 的切片
+// 缩小顺序：空切片、去掉前一半/后一半、逐个删除元素、逐个缩小元素
+func SliceOf[T any](elem Gen[T]) Gen[[]T] {
+	return Gen[[]T]{
+		Generate: func(r *rand.Rand, size int) []T {
+			out := make([]T, r.IntN(size+1))
+			for i := range out {
+				out[i] = elem.Generate(r, size)
+			}
+			return out
+		},
+		Shrink: func(s []T) [][]T {
+			if len(s) == 0 {
+				return nil
+			}
+			out := [][]T{{}}
+			if half := len(s) / 2; half > 0 {
+				out = append(out, slices.Clone(s[half:]), slices.Clone(s[:len(s)-half]))
+			}
+			for i := range s {
+				out = append(out, slices.Delete(slices.Clone(s), i, i+1))
+			}
+			if elem.Shrink != nil {
+				for i, v := range s {
+					for _, c := range elem.Shrink(v) {
+						t := slices.Clone(s)
+						t[i] = c
+						out = append(out, t)
+					}
+				}
+			}
+			return out
+		},
+	}
+}
+
+// Struct 用 pkg/fake 按字段类型和标签填充的结构体，T 必须是结构体类型
+// 字段之间往往有约束（如 validate 标签），无法逐字段缩小，所以不缩小
+func Struct[T any]() Gen[T] {
+	return Gen[T]{
+		Generate: func(r *rand.Rand, _ int) T {
+			var v T
+			if err := fake.New(r.Uint64()).Fake(&v); err != nil {
+				panic(err)
+			}
+			return v
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package prop
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"c03/pkg/sliceutil"
+)
+
+func TestCheckHolds(t *testing.T) {
+	// 包文档中的例子：Reverse(Reverse(xs)) == xs
+	err := Check(SliceOf(Int(-100, 100)), func(xs []int) bool {
+		ys := slices.Clone(xs)
+		slices.Reverse(ys)
+		slices.Reverse(ys)
+		return slices.Equal(xs, ys)
+	}, WithRuns(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 故意写错的性质：随机反例往往很长，缩小后只剩两个相同的元素
+func TestCheckShrinksWrongDedupProperty(t *testing.T) {
+	wrong := func(xs []int) bool { return len(sliceutil.Dedup(xs)) == len(xs) }
+	for _, seed := range []uint64{1, 2, 3, 42} {
+		err := Check(SliceOf(Int(-1000, 1000)), wrong, WithRuns(500), WithSeed(seed))
+		var f *Failure[[]int]
+		if !errors.As(err, &f) {
+			t.Fatalf("seed=%d: err = %v，期望 *Failure[[]int]", seed, err)
+		}
+		if c := f.Counterexample; len(c) != 2 || c[0] != c[1] {
+			t.Fatalf("seed=%d: 缩小后的反例 %v，期望两个相同的元素", seed, c)
+		}
+		if wrong(f.Original) || f.Seed != seed || f.Runs < 1 {
+			t.Fatalf("seed=%d: Failure 字段不正确: %+v", seed, f)
+		}
+		if len(f.Original) > 2 && f.Shrinks == 0 {
+			t.Fatalf("seed=%d: 最初的反例 %v 没有被缩小", seed, f.Original)
+		}
+
+		// 同一个种子得到同样的结果，失败可以复现
+		again := Check(SliceOf(Int(-1000, 1000)), wrong, WithRuns(500), WithSeed(seed))
+		if again.Error() != err.Error() {
+			t.Fatalf("seed=%d: 两次运行结果不同:\n%v\n%v", seed, err, again)
+		}
+	}
+}
+
+// 随机找到的反例通常已经很短，这里直接从一个长反例开始缩小
+func TestShrinkLongDedupCounterexample(t *testing.T) {
+	wrong := func(xs []int) bool { return len(sliceutil.Dedup(xs)) == len(xs) }
+	long := []int{812, -37, 5, 990, -412, 64, 7, -999, 333, 21, 64, -5, 18, 400, -77, 3}
+	got, steps := shrink(SliceOf(Int(-1000, 1000)), wrong, long)
+	if len(got) != 2 || got[0] != got[1] {
+		t.Fatalf("%v 缩小为 %v，期望两个相同的元素", long, got)
+	}
+	if steps == 0 {
+		t.Fatal("没有进行任何缩小")
+	}
+}
+
+func TestCheckShrinksInt(t *testing.T) {
+	// 最小的反例是边界 500
+	err := Check(Int(-10_000, 10_000), func(n int) bool { return n < 500 }, WithRuns(500))
+	var f *Failure[int]
+	if !errors.As(err, &f) || f.Counterexample != 500 {
+		t.Fatalf("err = %v，期望缩小到 500", err)
+	}
+
+	// 0 不在范围内时向离 0 最近的边界缩小
+	err = Check(Int(10, 100), func(n int) bool { return n%2 == 1 })
+	if !errors.As(err, &f) || f.Counterexample != 10 {
+		t.Fatalf("err = %v，期望缩小到 10", err)
+	}
+}
+
+func TestCheckPanicIsFailure(t *testing.T) {
+	err := Check(SliceOf(Int(0, 9)), func(xs []int) bool { return xs[0] >= 0 })
+	var f *Failure[[]int]
+	if !errors.As(err, &f) || len(f.Counterexample) != 0 {
+		t.Fatalf("err = %v，期望 panic 被当作失败并缩小到空切片", err)
+	}
+}
+
+func TestStringShrinks(t *testing.T) {
+	err := Check(String("ab"), func(s string) bool { return len(s) < 3 })
+	var f *Failure[string]
+	if !errors.As(err, &f) || f.Counterexample != "aaa" {
+		t.Fatalf("err = %v，期望缩小到 \"aaa\"", err)
+	}
+}
+
+// Struct 生成的值 JSON 编码再解码后不变
+func TestStructJSONRoundTrip(t *testing.T) {
+	type user struct {
+		Name      string    `json:"name"`
+		Age       int       `json:"age" validate:"min=0,max=150"`
+		Email     string    `json:"email"`
+		Tags      []string  `json:"tags"`
+		Score     float64   `json:"score"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	err := Check(Struct[user](), func(u user) bool {
+		data, err := json.Marshal(u)
+		if err != nil {
+			return false
+		}
+		var back user
+		return json.Unmarshal(data, &back) == nil && reflect.DeepEqual(u, back)
+	}, WithRuns(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,196 @@
+// ============================================
+// rtstats 包：定期采样运行时统计
+// ============================================
+//
+// 并发程序最常见的两类问题在日志里看不出来：
+//   - goroutine 泄漏：阻塞在没人发送的 channel 上，数量只增不减
+//   - 内存增长：缓存不淘汰、切片持有大数组，堆一直变大
+//
+// Reporter 每隔一段时间读取一次 runtime.MemStats 和 runtime.NumGoroutine，
+// 更新到 metrics.Registry，可选地打一行日志：
+//
+//   r := rtstats.New(reg, rtstats.WithInterval(5*time.Second), rtstats.WithLogger(log.Default()))
+//   go r.Run(ctx)
+//
+//   rtstats goroutines=12 (+3) heap_inuse=4.1 MiB heap_objects=10234 gc=7 (+2) max_pause=85µs
+//
+// 注册的指标：
+//   go_goroutines              当前 goroutine 数
+//   go_heap_inuse_bytes        堆上正在使用的字节数
+//   go_heap_objects            堆上的对象数
+//   go_gc_cycles_total         完成的 GC 次数
+//   go_gc_pause_seconds        每次 GC 的 STW 停顿（直方图）
+//
+// ReadMemStats 会短暂地 stop the world，采样间隔不要小于一秒。
+// ============================================
+
+package rtstats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"time"
+
+	"c03/pkg/cli"
+	"c03/pkg/clock"
+	"c03/pkg/metrics"
+)
+
+// pauseBuckets GC 停顿的桶（秒）：10µs 到 100ms
+var pauseBuckets = []float64{1e-5, 5e-5, 1e-4, 5e-4, 1e-3, 5e-3, 1e-2, 5e-2, 1e-1}
+
+// Sample 一次采样
+type Sample struct {
+	At          time.Time
+	Goroutines  int
+	HeapInuse   uint64
+	HeapObjects uint64
+	NumGC       uint32
+	// Pauses 距上一次采样新增的 GC 停顿，最多 256 个（runtime 只保留最近 256 次）
+	Pauses []time.Duration
+}
+
+// MaxPause Pauses 中最长的一次，没有新的 GC 时为 0
+func (s Sample) MaxPause() time.Duration {
+	var m time.Duration
+	for _, p := range s.Pauses {
+		m = max(m, p)
+	}
+	return m
+}
+
+type config struct {
+	interval time.Duration
+	logger   *log.Logger
+	clock    clock.Clock
+}
+
+// Option 配置 Reporter
+type Option func(*config)
+
+// WithInterval 采样间隔，默认 10 秒
+func WithInterval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// WithLogger 每次采样后打一行日志，默认不打
+func WithLogger(l *log.Logger) Option {
+	return func(c *config) { c.logger = l }
+}
+
+// WithClock 设置时钟，测试时用 clock.FakeClock 控制采样时机
+func WithClock(c clock.Clock) Option {
+	return func(cfg *config) { cfg.clock = c }
+}
+
+// Reporter 把运行时统计发布到 metrics
+type Reporter struct {
+	cfg config
+
+	goroutines  *metrics.Gauge
+	heapInuse   *metrics.Gauge
+	heapObjects *metrics.Gauge
+	gcCycles    *metrics.Counter
+	gcPause     *metrics.Histogram
+
+	mu   sync.Mutex
+	last Sample
+}
+
+// New 在 reg 中注册指标；同一个 reg 只能创建一个 Reporter（指标名重复时 panic）
+func New(reg *metrics.Registry, opts ...Option) *Reporter {
+	cfg := config{interval: 10 * time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.clock = clock.Or(cfg.clock)
+	if cfg.interval <= 0 {
+		panic("rtstats: 采样间隔必须为正数")
+	}
+
+	r := &Reporter{
+		cfg:         cfg,
+		goroutines:  reg.NewGauge("go_goroutines", "当前 goroutine 数"),
+		heapInuse:   reg.NewGauge("go_heap_inuse_bytes", "堆上正在使用的字节数"),
+		heapObjects: reg.NewGauge("go_heap_objects", "堆上的对象数"),
+		gcCycles:    reg.NewCounter("go_gc_cycles_total", "完成的 GC 次数"),
+		gcPause:     reg.NewHistogram("go_gc_pause_seconds", "GC 的 STW 停顿（秒）", pauseBuckets),
+	}
+	// 以创建时的状态为基线：之前的 GC 不计入计数器
+	r.last = r.read(nil)
+	return r
+}
+
+// Run 立即采样一次，之后每个间隔采样一次，直到 ctx 取消
+func (r *Reporter) Run(ctx context.Context) error {
+	ticker := r.cfg.clock.NewTicker(r.cfg.interval)
+	defer ticker.Stop()
+	for {
+		r.Sample()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// Sample 立即采样一次，更新指标并返回结果
+func (r *Reporter) Sample() Sample {
+	r.mu.Lock()
+	prev := r.last
+	s := r.read(&prev)
+	r.last = s
+	r.mu.Unlock()
+
+	r.goroutines.Set(float64(s.Goroutines))
+	r.heapInuse.Set(float64(s.HeapInuse))
+	r.heapObjects.Set(float64(s.HeapObjects))
+	r.gcCycles.Add(float64(s.NumGC - prev.NumGC))
+	for _, p := range s.Pauses {
+		r.gcPause.Observe(p.Seconds())
+	}
+	if r.cfg.logger != nil {
+		r.cfg.logger.Print(Format(prev, s))
+	}
+	return s
+}
+
+// Last 最近一次采样
+func (r *Reporter) Last() Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// read 读取当前状态；prev 不为 nil 时收集 prev 之后新增的 GC 停顿
+func (r *Reporter) read(prev *Sample) Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	s := Sample{
+		At:          r.cfg.clock.Now(),
+		Goroutines:  runtime.NumGoroutine(),
+		HeapInuse:   ms.HeapInuse,
+		HeapObjects: ms.HeapObjects,
+		NumGC:       ms.NumGC,
+	}
+	if prev == nil {
+		return s
+	}
+	// PauseNs 是长度 256 的环形缓冲，第 n 次 GC（从 1 开始）的停顿在 PauseNs[(n+255)%256]
+	from := max(prev.NumGC+1, ms.NumGC-min(ms.NumGC, uint32(len(ms.PauseNs)))+1)
+	for n := from; n <= ms.NumGC; n++ {
+		s.Pauses = append(s.Pauses, time.Duration(ms.PauseNs[(n+255)%uint32(len(ms.PauseNs))]))
+	}
+	return s
+}
+
+// Format 一行文本，括号中是相对 prev 的变化
+func Format(prev, s Sample) string {
+	return fmt.Sprintf("rtstats goroutines=%d (%+d) heap_inuse=%s heap_objects=%d gc=%d (+%d) max_pause=%v",
+		s.Goroutines, s.Goroutines-prev.Goroutines, cli.FormatBytes(float64(s.HeapInuse)), s.HeapObjects,
+		s.NumGC, s.NumGC-prev.NumGC, s.MaxPause())
+}
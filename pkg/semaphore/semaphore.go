@@ -0,0 +1,187 @@
+// ============================================
+// semaphore 包：带权重的公平信号量
+// ============================================
+//
+// tutorial/06 练习 2 的 channel 信号量每次获取一个许可；Weighted 一次可以获取 n 个，
+// 适合按资源大小限流：下载管理器按文件大小占用带宽配额，
+// bulkhead（舱壁隔离）按请求的开销占用下游的并发额度。
+//
+//   sem := semaphore.New(10)
+//   if err := sem.Acquire(ctx, 3); err != nil { return err }
+//   defer sem.Release(3)
+//
+// 公平性：等待者按到达顺序（FIFO）获得许可。队首要 5 个而只剩 3 个时，
+// 后面要 1 个的也要等，否则源源不断的小请求会让大请求永远拿不到许可（饥饿）。
+// ============================================
+
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// ErrTooLarge 请求的权重超过信号量的容量，永远无法满足
+var ErrTooLarge = errors.New("semaphore: 请求的权重超过容量")
+
+// Weighted 带权重的信号量，零值不可用，用 New 创建
+type Weighted struct {
+	size  int64
+	clock clock.Clock
+
+	mu      sync.Mutex
+	cur     int64     // 已被获取的权重
+	waiters list.List // *waiter，按到达顺序排列
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{} // 获得许可时关闭
+}
+
+// Option New 的选项
+type Option func(*Weighted)
+
+// WithClock 指定 TryAcquire 计时用的时钟，测试时传入 clock.FakeClock
+func WithClock(c clock.Clock) Option { return func(s *Weighted) { s.clock = c } }
+
+// New 创建容量为 size 的信号量
+func New(size int64, opts ...Option) *Weighted {
+	s := &Weighted{size: size}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.clock = clock.Or(s.clock)
+	return s
+}
+
+// Acquire 获取 n 个许可，不够时排队等待，直到获得许可或 ctx 结束
+// ctx 结束时返回 ctx.Err()，不占用任何许可；n 超过容量时立即返回 ErrTooLarge
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	w, elem, err := s.enqueue(n)
+	if err != nil || w == nil {
+		return err
+	}
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		if s.abandon(w, elem) {
+			return nil
+		}
+		return ctx.Err()
+	}
+}
+
+// TryAcquire 在 timeout 内获取 n 个许可，成功返回 true
+// timeout <= 0 时不等待：许可足够且没有人排队时才成功
+func (s *Weighted) TryAcquire(n int64, timeout time.Duration) bool {
+	if timeout <= 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.waiters.Len() == 0 && s.size-s.cur >= n {
+			s.cur += n
+			return true
+		}
+		return false
+	}
+
+	w, elem, err := s.enqueue(n)
+	if err != nil {
+		return false
+	}
+	if w == nil {
+		return true
+	}
+	t := s.clock.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-w.ready:
+		return true
+	case <-t.C():
+		return s.abandon(w, elem)
+	}
+}
+
+// Release 归还 n 个许可，并按顺序唤醒排队中能被满足的等待者
+// 归还的比获取的多时 panic，这是调用方的 bug
+func (s *Weighted) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: Release 的数量超过已获取的数量")
+	}
+	s.notify()
+}
+
+// Available 当前空闲的许可数（只是快照，下一刻就可能变化）
+func (s *Weighted) Available() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size - s.cur
+}
+
+// Waiting 排队中的等待者数
+func (s *Weighted) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiters.Len()
+}
+
+// enqueue 能立即获取时直接获取，返回 nil waiter；否则排到队尾
+func (s *Weighted) enqueue(n int64) (*waiter, *list.Element, error) {
+	if n > s.size {
+		return nil, nil, fmt.Errorf("%w: %d > %d", ErrTooLarge, n, s.size)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiters.Len() == 0 && s.size-s.cur >= n {
+		s.cur += n
+		return nil, nil, nil
+	}
+	w := &waiter{n: n, ready: make(chan struct{})}
+	return w, s.waiters.PushBack(w), nil
+}
+
+// abandon 放弃等待；如果在放弃之前已经获得了许可，保留许可并返回 true
+func (s *Weighted) abandon(w *waiter, elem *list.Element) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-w.ready:
+		return true
+	default:
+	}
+	front := s.waiters.Front() == elem
+	s.waiters.Remove(elem)
+	// 队首离开后，后面被它挡住的等待者可能已经能被满足
+	if front {
+		s.notify()
+	}
+	return false
+}
+
+// notify 从队首开始依次发放许可，遇到满足不了的就停下（保持 FIFO）
+// 调用方持有 s.mu
+func (s *Weighted) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
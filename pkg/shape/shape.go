@@ -0,0 +1,185 @@
+// ============================================
+// shape 包：Shape 接口与圆形、矩形、三角形
+// ============================================
+//
+// 来自 tutorial/04_interface 的练习 1。单独成包是因为第 4 课自己已经有一个
+// 用于 Stringer / IShow 演示的 Rectangle（int 宽高）；放在这里用包名区分：shape.Rectangle。
+// 第 3 课练习 1 的 Rectangle 是它的别名，两课用的是同一个类型。
+//
+//   shapes := []shape.Shape{
+//       shape.Circle{Radius: 1},
+//       shape.Rectangle{Width: 3, Height: 4},
+//       shape.Triangle{A: 3, B: 4, C: 5},
+//   }
+//   shape.TotalArea(shapes)               // π + 12 + 6
+//   shape.PrintShapeInfo(os.Stdout, s)    // Rectangle{Width=3, Height=4}  面积=12.00  周长=14.00
+//
+// 三种形状都是值接收者的小结构体，值和指针都满足 Shape 接口。
+// 修改类的方法（Rectangle 的 Scale、Rotate）也是值接收者，返回新值而不是就地修改：
+// 同一个类型混用值接收者和指针接收者时，只有指针的方法集是完整的，容易在接口赋值时出错。
+// ============================================
+
+package shape
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrInvalidShape 边长或半径不合法（负数、NaN、三角形两边之和不大于第三边）
+var ErrInvalidShape = errors.New("shape: 无效的形状")
+
+// Shape 二维图形
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// 编译期检查三种形状都实现了 Shape 和 fmt.Stringer
+var (
+	_ Shape        = Circle{}
+	_ Shape        = Rectangle{}
+	_ Shape        = Triangle{}
+	_ fmt.Stringer = Circle{}
+	_ fmt.Stringer = Rectangle{}
+	_ fmt.Stringer = Triangle{}
+)
+
+// Circle 圆
+type Circle struct {
+	Radius float64
+}
+
+// NewCircle 创建圆，半径不能为负
+func NewCircle(radius float64) (Circle, error) {
+	if !validLength(radius) {
+		return Circle{}, fmt.Errorf("%w: 半径 %v", ErrInvalidShape, radius)
+	}
+	return Circle{Radius: radius}, nil
+}
+
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+func (c Circle) String() string     { return fmt.Sprintf("Circle{Radius=%g}", c.Radius) }
+
+// Rectangle 矩形，(X, Y) 是左下角，边与坐标轴平行
+// 只关心大小时 X、Y 留零值即可：Rectangle{Width: 3, Height: 4}
+type Rectangle struct {
+	X, Y          float64
+	Width, Height float64
+}
+
+// NewRectangle 创建左下角在原点的矩形，边长不能为负
+func NewRectangle(width, height float64) (Rectangle, error) {
+	if !validLength(width) || !validLength(height) {
+		return Rectangle{}, fmt.Errorf("%w: 矩形 %v x %v", ErrInvalidShape, width, height)
+	}
+	return Rectangle{Width: width, Height: height}, nil
+}
+
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+func (r Rectangle) String() string {
+	if r.X == 0 && r.Y == 0 {
+		return fmt.Sprintf("Rectangle{Width=%g, Height=%g}", r.Width, r.Height)
+	}
+	return fmt.Sprintf("Rectangle{X=%g, Y=%g, Width=%g, Height=%g}", r.X, r.Y, r.Width, r.Height)
+}
+
+// IsSquare 宽高相等
+func (r Rectangle) IsSquare() bool { return r.Width == r.Height }
+
+// Empty 宽或高为 0（或为负），面积为 0，不包含任何点
+func (r Rectangle) Empty() bool { return r.Width <= 0 || r.Height <= 0 }
+
+// Scale 返回宽高乘以 factor 的矩形，左下角不动
+func (r Rectangle) Scale(factor float64) Rectangle {
+	r.Width *= factor
+	r.Height *= factor
+	return r
+}
+
+// Rotate 返回绕中心旋转 90° 的矩形：宽高互换，中心不变
+func (r Rectangle) Rotate() Rectangle {
+	return Rectangle{
+		X:      r.X + (r.Width-r.Height)/2,
+		Y:      r.Y + (r.Height-r.Width)/2,
+		Width:  r.Height,
+		Height: r.Width,
+	}
+}
+
+// Contains 点 (x, y) 是否在矩形内
+// 区间左闭右开 [X, X+Width) × [Y, Y+Height)：并排的两个矩形不会同时包含公共边上的点
+func (r Rectangle) Contains(x, y float64) bool {
+	return r.X <= x && x < r.X+r.Width && r.Y <= y && y < r.Y+r.Height
+}
+
+// Union 同时覆盖 r 和 other 的最小矩形
+// 空矩形不占据任何区域，与它合并得到另一个矩形本身
+func (r Rectangle) Union(other Rectangle) Rectangle {
+	if r.Empty() {
+		return other
+	}
+	if other.Empty() {
+		return r
+	}
+	x0, y0 := min(r.X, other.X), min(r.Y, other.Y)
+	x1 := max(r.X+r.Width, other.X+other.Width)
+	y1 := max(r.Y+r.Height, other.Y+other.Height)
+	return Rectangle{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Triangle 由三条边确定的三角形
+type Triangle struct {
+	A, B, C float64
+}
+
+// NewTriangle 创建三角形，三条边必须满足任意两边之和大于第三边
+func NewTriangle(a, b, c float64) (Triangle, error) {
+	if !validLength(a) || !validLength(b) || !validLength(c) ||
+		a+b <= c || a+c <= b || b+c <= a {
+		return Triangle{}, fmt.Errorf("%w: 三角形 %v, %v, %v", ErrInvalidShape, a, b, c)
+	}
+	return Triangle{A: a, B: b, C: c}, nil
+}
+
+// Area 用海伦公式计算面积：s 为半周长，面积 = √(s(s-a)(s-b)(s-c))
+// 边长不能构成三角形时根号下为负，返回 NaN
+func (t Triangle) Area() float64 {
+	s := t.Perimeter() / 2
+	return math.Sqrt(s * (s - t.A) * (s - t.B) * (s - t.C))
+}
+
+func (t Triangle) Perimeter() float64 { return t.A + t.B + t.C }
+func (t Triangle) String() string {
+	return fmt.Sprintf("Triangle{A=%g, B=%g, C=%g}", t.A, t.B, t.C)
+}
+
+// TotalArea 所有形状的面积之和，nil 元素跳过
+func TotalArea(shapes []Shape) float64 {
+	total := 0.0
+	for _, s := range shapes {
+		if s == nil {
+			continue
+		}
+		total += s.Area()
+	}
+	return total
+}
+
+// PrintShapeInfo 输出一行形状信息：名称、面积、周长
+// 实现了 fmt.Stringer 的形状用 String()，否则用 %T 输出类型名
+func PrintShapeInfo(w io.Writer, s Shape) {
+	name := fmt.Sprintf("%T", s)
+	if str, ok := s.(fmt.Stringer); ok {
+		name = str.String()
+	}
+	fmt.Fprintf(w, "%-32s 面积=%.2f  周长=%.2f\n", name, s.Area(), s.Perimeter())
+}
+
+func validLength(v float64) bool {
+	return v >= 0 && !math.IsInf(v, 0)
+}
@@ -0,0 +1,161 @@
+package shape
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestShapeAreaPerimeter(t *testing.T) {
+	tests := []struct {
+		s               Shape
+		area, perimeter float64
+	}{
+		{Circle{Radius: 1}, math.Pi, 2 * math.Pi},
+		{Circle{}, 0, 0},
+		{Rectangle{Width: 3, Height: 4}, 12, 14},
+		{&Rectangle{X: -5, Y: 7, Width: 2, Height: 2}, 4, 8}, // 指针同样满足 Shape
+		{Rectangle{}, 0, 0},
+		{Triangle{A: 3, B: 4, C: 5}, 6, 12},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Area(); math.Abs(got-tt.area) > 1e-9 {
+			t.Errorf("%v.Area() = %v，期望 %v", tt.s, got, tt.area)
+		}
+		if got := tt.s.Perimeter(); math.Abs(got-tt.perimeter) > 1e-9 {
+			t.Errorf("%v.Perimeter() = %v，期望 %v", tt.s, got, tt.perimeter)
+		}
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	if _, err := NewRectangle(3, 4); err != nil {
+		t.Errorf("NewRectangle(3, 4) 失败: %v", err)
+	}
+	if _, err := NewTriangle(3, 4, 5); err != nil {
+		t.Errorf("NewTriangle(3, 4, 5) 失败: %v", err)
+	}
+	invalid := map[string]error{}
+	_, invalid["负半径"] = NewCircle(-1)
+	_, invalid["NaN 半径"] = NewCircle(math.NaN())
+	_, invalid["无穷宽"] = NewRectangle(math.Inf(1), 1)
+	_, invalid["负高"] = NewRectangle(1, -1)
+	_, invalid["两边之和等于第三边"] = NewTriangle(1, 2, 3)
+	_, invalid["负边"] = NewTriangle(-3, 4, 5)
+	for name, err := range invalid {
+		if !errors.Is(err, ErrInvalidShape) {
+			t.Errorf("%s: 返回 %v，期望 ErrInvalidShape", name, err)
+		}
+	}
+}
+
+func TestRectangleRotate(t *testing.T) {
+	tests := []struct {
+		r, want Rectangle
+	}{
+		{Rectangle{Width: 4, Height: 2}, Rectangle{X: 1, Y: -1, Width: 2, Height: 4}},
+		{Rectangle{X: 10, Y: 10, Width: 3, Height: 3}, Rectangle{X: 10, Y: 10, Width: 3, Height: 3}},
+		{Rectangle{X: 1, Y: 2, Width: 0, Height: 6}, Rectangle{X: -2, Y: 5, Width: 6, Height: 0}},
+	}
+	for _, tt := range tests {
+		got := tt.r.Rotate()
+		if got != tt.want {
+			t.Errorf("%v.Rotate() = %v，期望 %v", tt.r, got, tt.want)
+		}
+		// 中心不变、面积不变，转两次回到原样
+		cx, cy := tt.r.X+tt.r.Width/2, tt.r.Y+tt.r.Height/2
+		if gx, gy := got.X+got.Width/2, got.Y+got.Height/2; gx != cx || gy != cy {
+			t.Errorf("%v.Rotate() 中心从 (%v, %v) 移到了 (%v, %v)", tt.r, cx, cy, gx, gy)
+		}
+		if got.Area() != tt.r.Area() {
+			t.Errorf("%v.Rotate() 面积改变", tt.r)
+		}
+		if back := got.Rotate(); back != tt.r {
+			t.Errorf("%v 旋转两次得到 %v", tt.r, back)
+		}
+	}
+}
+
+func TestRectangleContains(t *testing.T) {
+	r := Rectangle{X: 1, Y: 1, Width: 2, Height: 3}
+	tests := []struct {
+		x, y float64
+		want bool
+	}{
+		{1, 1, true},     // 左下角：闭区间
+		{2, 2.5, true},   // 内部
+		{3, 2, false},    // 右边：开区间
+		{2, 4, false},    // 上边：开区间
+		{0.99, 2, false}, // 左边之外
+		{2, 0, false},    // 下边之外
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("%v.Contains(%v, %v) = %v，期望 %v", r, tt.x, tt.y, got, tt.want)
+		}
+	}
+	if (Rectangle{X: 1, Y: 1}).Contains(1, 1) {
+		t.Error("空矩形不应包含任何点")
+	}
+
+	// 并排的两个矩形：公共边上的点只属于右边那个
+	left, right := Rectangle{Width: 1, Height: 1}, Rectangle{X: 1, Width: 1, Height: 1}
+	if left.Contains(1, 0.5) || !right.Contains(1, 0.5) {
+		t.Error("公共边上的点应只属于右边的矩形")
+	}
+}
+
+func TestRectangleUnion(t *testing.T) {
+	a := Rectangle{X: 0, Y: 0, Width: 2, Height: 2}
+	tests := []struct {
+		name  string
+		other Rectangle
+		want  Rectangle
+	}{
+		{"部分重叠", Rectangle{X: 1, Y: 1, Width: 2, Height: 2}, Rectangle{Width: 3, Height: 3}},
+		{"不相交", Rectangle{X: 5, Y: -1, Width: 1, Height: 1}, Rectangle{Y: -1, Width: 6, Height: 3}},
+		{"包含在内", Rectangle{X: 0.5, Y: 0.5, Width: 1, Height: 1}, a},
+		{"与空矩形", Rectangle{X: 100, Y: 100}, a},
+		{"与自身", a, a},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Union(tt.other); got != tt.want {
+				t.Fatalf("Union = %v，期望 %v", got, tt.want)
+			}
+			if got := tt.other.Union(a); got != tt.want {
+				t.Fatalf("Union 不满足交换律：%v", got)
+			}
+		})
+	}
+	if got := (Rectangle{}).Union(Rectangle{X: 3}); !got.Empty() {
+		t.Errorf("两个空矩形合并得到 %v，期望空矩形", got)
+	}
+}
+
+func TestRectangleScaleIsSquare(t *testing.T) {
+	r := Rectangle{X: 1, Y: 1, Width: 2, Height: 3}
+	if got, want := r.Scale(2), (Rectangle{X: 1, Y: 1, Width: 4, Height: 6}); got != want {
+		t.Errorf("Scale(2) = %v，期望 %v", got, want)
+	}
+	if r.Width != 2 {
+		t.Error("Scale 不应修改原矩形")
+	}
+	if r.IsSquare() || !(Rectangle{Width: 5, Height: 5}).IsSquare() {
+		t.Error("IsSquare 结果错误")
+	}
+}
+
+func TestTotalAreaAndPrint(t *testing.T) {
+	shapes := []Shape{Circle{Radius: 1}, Rectangle{Width: 3, Height: 4}, nil, Triangle{A: 3, B: 4, C: 5}}
+	if got, want := TotalArea(shapes), math.Pi+18; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TotalArea = %v，期望 %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	PrintShapeInfo(&buf, Rectangle{Width: 3, Height: 4})
+	if want := "Rectangle{Width=3, Height=4}"; !bytes.HasPrefix(buf.Bytes(), []byte(want)) {
+		t.Errorf("PrintShapeInfo 输出 %q，期望以 %q 开头", buf.String(), want)
+	}
+}
@@ -0,0 +1,227 @@
+// ============================================
+// signals 包：按名称注册的关闭 / 重新加载回调
+// ============================================
+//
+// 每个组件各自 signal.NotifyContext 时，收到 SIGTERM 后大家同时开始关闭，顺序不可控：
+// KV 存储可能先关掉，HTTP 服务还在处理的请求写入时就会失败。
+// Registry 统一接收信号，按注册顺序调用各组件的回调：
+//
+//   reg := signals.New(signals.WithTimeout(15 * time.Second))
+//   httpOpt := httpserver.WithSignals(reg)                    // 1. 停止接受请求，排空
+//   reg.Go("cron", 0, sched.Run)                              // 2. 停止调度，等任务结束
+//   store, _ := kvstore.Open(dir, kvstore.WithSignals(reg))   // 3. 刷盘，关闭 WAL
+//   reg.OnReload("config", 0, func(context.Context) error { return cfg.Reload() })
+//   go httpserver.ServeListener(ctx, ln, newHandler(store), httpOpt)
+//   err := reg.Run(ctx)                                        // 阻塞到关闭完成
+//
+// 关闭顺序就是注册顺序，与创建顺序无关：HTTP 处理器依赖存储，要在存储打开之后才能创建，
+// 但它的关闭回调（WithSignals 被调用时注册）排在存储前面。
+//
+// 信号与回调：
+//   SIGINT / SIGTERM  依次执行关闭回调，全部结束后 Run 返回；只执行一次
+//   SIGHUP            依次执行重新加载回调，之后继续等待信号
+//   关闭期间再收到 SIGINT / SIGTERM：不再等待，立即以状态 1 退出（卡住时按两次 Ctrl+C）
+//
+// 每个回调有自己的超时（注册时给出，0 表示用 WithTimeout 的默认值）：
+// 超时、返回错误或 panic 都只记日志，不影响后面的回调——一个组件关不掉，其他组件仍然要刷盘。
+// Run 返回所有失败回调的错误（errors.Join）。
+// ============================================
+
+package signals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrTimeout 回调没有在超时时间内返回
+var ErrTimeout = errors.New("signals: 回调超时")
+
+// Callback 关闭或重新加载回调，ctx 在超时后取消
+type Callback func(ctx context.Context) error
+
+// Option New 的选项
+type Option func(*Registry)
+
+// WithTimeout 回调的默认超时，默认 10 秒
+func WithTimeout(d time.Duration) Option { return func(r *Registry) { r.timeout = d } }
+
+// WithLogger 记录收到的信号和每个回调的结果，默认 log.Default()
+func WithLogger(l *log.Logger) Option { return func(r *Registry) { r.logger = l } }
+
+// Registry 信号回调注册表，并发安全
+type Registry struct {
+	timeout time.Duration
+	logger  *log.Logger
+	exit    func(code int) // 关闭期间再次收到退出信号时调用，默认 os.Exit
+
+	mu       sync.Mutex
+	shutdown []entry
+	reload   []entry
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+type entry struct {
+	name    string
+	timeout time.Duration
+	fn      Callback
+}
+
+// New 创建注册表
+func New(opts ...Option) *Registry {
+	r := &Registry{timeout: 10 * time.Second, logger: log.Default(), exit: os.Exit}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OnShutdown 注册关闭回调；timeout 为 0 时使用默认超时
+func (r *Registry) OnShutdown(name string, timeout time.Duration, fn Callback) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shutdown = append(r.shutdown, entry{name: name, timeout: timeout, fn: fn})
+}
+
+// OnReload 注册重新加载回调；timeout 为 0 时使用默认超时
+func (r *Registry) OnReload(name string, timeout time.Duration, fn Callback) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reload = append(r.reload, entry{name: name, timeout: timeout, fn: fn})
+}
+
+// Go 在新的 goroutine 中运行 run，直到轮到它的关闭回调：
+// 回调取消 run 的 ctx 并等待 run 返回。适合 Run(ctx) 形式的组件，如 cron.Scheduler
+func (r *Registry) Go(name string, timeout time.Duration, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+	r.OnShutdown(name, timeout, func(cbCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-cbCtx.Done():
+			return cbCtx.Err()
+		}
+	})
+}
+
+// Shutdown 按注册顺序执行关闭回调，只执行一次；之后的调用直接返回第一次的结果
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.shutdownOnce.Do(func() {
+		r.shutdownErr = r.runAll(ctx, "关闭", r.entries(&r.shutdown))
+	})
+	return r.shutdownErr
+}
+
+// Reload 按注册顺序执行重新加载回调
+func (r *Registry) Reload(ctx context.Context) error {
+	return r.runAll(ctx, "重新加载", r.entries(&r.reload))
+}
+
+func (r *Registry) entries(list *[]entry) []entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entry(nil), *list...)
+}
+
+// Run 等待信号并执行对应的回调；收到 SIGINT / SIGTERM 或 ctx 取消时执行关闭回调后返回
+func (r *Registry) Run(ctx context.Context) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Printf("signals: %v，开始关闭", context.Cause(ctx))
+			return r.shutdownWatching(sigs)
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				r.logger.Printf("signals: 收到 %v，重新加载", sig)
+				r.Reload(context.Background())
+				continue
+			}
+			r.logger.Printf("signals: 收到 %v，开始关闭", sig)
+			return r.shutdownWatching(sigs)
+		}
+	}
+}
+
+// shutdownWatching 执行关闭回调，期间再收到退出信号就强制退出
+func (r *Registry) shutdownWatching(sigs <-chan os.Signal) error {
+	done := make(chan error, 1)
+	go func() { done <- r.Shutdown(context.Background()) }()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				continue // 正在关闭，不再重新加载
+			}
+			r.logger.Printf("signals: 关闭期间再次收到 %v，强制退出", sig)
+			r.exit(1)
+		}
+	}
+}
+
+func (r *Registry) runAll(ctx context.Context, kind string, entries []entry) error {
+	var errs []error
+	for _, e := range entries {
+		start := time.Now()
+		err := r.invoke(ctx, e)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %w", kind, e.name, err)
+			r.logger.Printf("signals: %v（%v）", err, elapsed)
+			errs = append(errs, err)
+			continue
+		}
+		r.logger.Printf("signals: %s %s 完成（%v）", kind, e.name, elapsed)
+	}
+	return errors.Join(errs...)
+}
+
+// invoke 在超时内执行一个回调；回调不理会 ctx 时不再等它，让后面的回调继续
+func (r *Registry) invoke(ctx context.Context, e entry) error {
+	timeout := e.timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		done <- e.fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w（%v）", ErrTimeout, timeout)
+	}
+}
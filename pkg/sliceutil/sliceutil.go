@@ -0,0 +1,50 @@
+// ============================================
+// sliceutil 包：标准库 slices 之外的常用切片函数
+// ============================================
+//
+// 来自 tutorial/01_basic_syntax 的练习 2、4，之后的课程直接使用：
+//
+//   Dedup([]int{3, 1, 3, 2, 1})   -> [3 1 2]   保留第一次出现的顺序
+//   MinMax([]int{3, 1, 2})        -> 1, 3, nil
+//   MinMax([]int{})               -> 0, 0, ErrEmpty
+//
+// slices.Compact 只去掉相邻的重复元素，需要先排序；Dedup 不改变原有顺序。
+// ============================================
+
+package sliceutil
+
+import (
+	"cmp"
+	"errors"
+)
+
+// ErrEmpty 对空切片求最值
+var ErrEmpty = errors.New("sliceutil: 切片为空")
+
+// Dedup 返回去掉重复元素后的新切片，保留每个元素第一次出现的位置，不修改 s
+func Dedup[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// MinMax 返回最小值和最大值，s 为空时返回 ErrEmpty
+// 浮点数中有 NaN 时结果为 NaN，与内置的 min / max 一致
+func MinMax[T cmp.Ordered](s []T) (lo, hi T, err error) {
+	if len(s) == 0 {
+		return lo, hi, ErrEmpty
+	}
+	lo, hi = s[0], s[0]
+	for _, v := range s[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	return lo, hi, nil
+}
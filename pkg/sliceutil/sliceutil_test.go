@@ -0,0 +1,64 @@
+package sliceutil
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"c03/pkg/prop"
+)
+
+func TestDedup(t *testing.T) {
+	tests := []struct {
+		in, want []int
+	}{
+		{nil, []int{}},
+		{[]int{1}, []int{1}},
+		{[]int{3, 1, 3, 2, 1}, []int{3, 1, 2}},
+		{[]int{5, 5, 5}, []int{5}},
+	}
+	for _, tt := range tests {
+		orig := slices.Clone(tt.in)
+		if got := Dedup(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("Dedup(%v) = %v，期望 %v", tt.in, got, tt.want)
+		}
+		if !slices.Equal(tt.in, orig) {
+			t.Errorf("Dedup 修改了传入的切片 %v", orig)
+		}
+	}
+}
+
+// 性质：结果没有重复元素、再去重一次不变、元素按第一次出现的顺序排列
+func TestDedupProperties(t *testing.T) {
+	err := prop.Check(prop.SliceOf(prop.Int(0, 9)), func(xs []int) bool {
+		once := Dedup(xs)
+		seen := map[int]bool{}
+		var firsts []int
+		for _, x := range xs {
+			if !seen[x] {
+				seen[x] = true
+				firsts = append(firsts, x)
+			}
+		}
+		return slices.Equal(once, firsts) && slices.Equal(once, Dedup(once))
+	}, prop.WithRuns(500), prop.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	if _, _, err := MinMax([]int{}); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("MinMax(空切片) err = %v，期望 ErrEmpty", err)
+	}
+	err := prop.Check(prop.SliceOf(prop.Int(-1000, 1000)), func(xs []int) bool {
+		lo, hi, err := MinMax(xs)
+		if len(xs) == 0 {
+			return errors.Is(err, ErrEmpty)
+		}
+		return err == nil && lo == slices.Min(xs) && hi == slices.Max(xs)
+	}, prop.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,222 @@
+// ============================================
+// stats 包：描述统计
+// ============================================
+//
+// 对数值切片求均值、中位数、百分位、标准差和直方图，元素可以是任意整数或浮点类型，
+// 包括 time.Duration（底层是 int64），耗时样本可以直接传入：
+//
+//   var samples []time.Duration                    // 每次请求的耗时
+//   s := stats.Summarize(samples)
+//   time.Duration(s.P99)                           // 99% 的请求快于这个值
+//
+// 看耗时要看分布而不是均值：少数很慢的请求会把均值拉高，
+// 而中位数（P50）反映典型情况，P99 反映"慢的那部分有多慢"。
+//
+// 约定：
+//   - 结果都是 float64；空切片的结果为 NaN（没有样本时"均值"没有意义），Count 为 0
+//   - 不修改传入的切片，需要排序时先复制
+//   - Percentile 在相邻两个样本之间线性插值（与 Excel PERCENTILE.INC、numpy 默认方式相同）
+//   - StdDev 是总体标准差（除以 n），样本标准差用 SampleStdDev（除以 n-1）
+//
+// metrics.Histogram 只保留各桶的计数，适合长期运行的服务；
+// 这里的函数需要全部样本，适合基准、压测等一次性的分析。
+// ============================================
+
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Number 整数和浮点数类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum 总和，空切片为 0
+func Sum[T Number](xs []T) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += float64(x)
+	}
+	return sum
+}
+
+// Mean 算术平均值
+func Mean[T Number](xs []T) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	return Sum(xs) / float64(len(xs))
+}
+
+// Median 中位数，即 Percentile(xs, 50)
+func Median[T Number](xs []T) float64 {
+	return Percentile(xs, 50)
+}
+
+// Percentile 第 p 百分位数（0 <= p <= 100），p 超出范围时为 NaN
+// 一次要多个百分位时用 Percentiles，只排序一次
+func Percentile[T Number](xs []T, p float64) float64 {
+	return Percentiles(xs, p)[0]
+}
+
+// Percentiles 依次返回各个 p 对应的百分位数
+func Percentiles[T Number](xs []T, ps ...float64) []float64 {
+	sorted := sortedFloats(xs)
+	out := make([]float64, len(ps))
+	for i, p := range ps {
+		out[i] = percentileSorted(sorted, p)
+	}
+	return out
+}
+
+// Variance 总体方差
+// 用 Welford 算法一遍求出：先求均值再逐项求差的平方时，
+// 数值很大而差异很小的样本（如 Unix 纳秒时间戳）会因为浮点精度丢失得到负数或 0
+func Variance[T Number](xs []T) float64 {
+	n, m2 := welford(xs)
+	if n == 0 {
+		return math.NaN()
+	}
+	return m2 / float64(n)
+}
+
+// StdDev 总体标准差
+func StdDev[T Number](xs []T) float64 {
+	return math.Sqrt(Variance(xs))
+}
+
+// SampleStdDev 样本标准差（除以 n-1），少于 2 个样本时为 NaN
+// 用一部分样本估计整体的离散程度时用它
+func SampleStdDev[T Number](xs []T) float64 {
+	n, m2 := welford(xs)
+	if n < 2 {
+		return math.NaN()
+	}
+	return math.Sqrt(m2 / float64(n-1))
+}
+
+func welford[T Number](xs []T) (n int, m2 float64) {
+	var mean float64
+	for _, x := range xs {
+		n++
+		v := float64(x)
+		delta := v - mean
+		mean += delta / float64(n)
+		m2 += delta * (v - mean)
+	}
+	return n, m2
+}
+
+// ============================================
+// 汇总
+// ============================================
+
+// Summary 一组样本的概况
+type Summary struct {
+	Count         int
+	Min, Max      float64
+	Mean, StdDev  float64
+	P50, P90, P99 float64
+}
+
+// Summarize 一次求出常用的统计量，只排序一次
+func Summarize[T Number](xs []T) Summary {
+	sorted := sortedFloats(xs)
+	s := Summary{
+		Count:  len(xs),
+		Min:    math.NaN(),
+		Max:    math.NaN(),
+		Mean:   Mean(xs),
+		StdDev: StdDev(xs),
+		P50:    percentileSorted(sorted, 50),
+		P90:    percentileSorted(sorted, 90),
+		P99:    percentileSorted(sorted, 99),
+	}
+	if len(sorted) > 0 {
+		s.Min, s.Max = sorted[0], sorted[len(sorted)-1]
+	}
+	return s
+}
+
+// String 以 %g 输出各项；样本是耗时时用 Format(durationFormatter) 更好读
+func (s Summary) String() string {
+	return s.Format(func(v float64) string { return fmt.Sprintf("%.4g", v) })
+}
+
+// Format 用 f 格式化每个数值，例如耗时样本：
+//
+//	s.Format(func(v float64) string { return time.Duration(v).Round(time.Microsecond).String() })
+func (s Summary) Format(f func(float64) string) string {
+	return fmt.Sprintf("n=%d min=%s p50=%s p90=%s p99=%s max=%s mean=%s stddev=%s",
+		s.Count, f(s.Min), f(s.P50), f(s.P90), f(s.P99), f(s.Max), f(s.Mean), f(s.StdDev))
+}
+
+// ============================================
+// 直方图
+// ============================================
+
+// Bucket 直方图的一个桶：(上一个桶的 Upper, Upper] 内的样本数
+type Bucket struct {
+	Upper float64 // 最后一个桶为 +Inf
+	Count int
+}
+
+// Histogram 按上界 bounds（升序）把样本分到 len(bounds)+1 个桶里，最后一个桶收集超过所有上界的样本
+// 上界的含义与 metrics.Histogram 相同：v <= Upper 的样本落在第一个满足条件的桶
+func Histogram[T Number](xs []T, bounds []float64) []Bucket {
+	buckets := make([]Bucket, len(bounds)+1)
+	for i, b := range bounds {
+		buckets[i].Upper = b
+	}
+	buckets[len(bounds)].Upper = math.Inf(1)
+	for _, x := range xs {
+		i, _ := slices.BinarySearch(bounds, float64(x))
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// LinearBounds count 个等距的上界：start, start+width, ...
+func LinearBounds(start, width float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start + width*float64(i)
+	}
+	return bounds
+}
+
+// ExponentialBounds count 个按 factor 倍增长的上界：start, start*factor, ...
+// 耗时一般跨好几个数量级，用指数桶比等距桶更合适
+func ExponentialBounds(start, factor float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start * math.Pow(factor, float64(i))
+	}
+	return bounds
+}
+
+func sortedFloats[T Number](xs []T) []float64 {
+	out := make([]float64, len(xs))
+	for i, x := range xs {
+		out[i] = float64(x)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// percentileSorted 在已排序的样本上求百分位：位置 (n-1)*p/100，落在两个样本之间时线性插值
+func percentileSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 || math.IsNaN(p) || p < 0 || p > 100 {
+		return math.NaN()
+	}
+	pos := float64(len(sorted)-1) * p / 100
+	lo := int(math.Floor(pos))
+	hi := min(lo+1, len(sorted)-1)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
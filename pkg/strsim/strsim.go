@@ -0,0 +1,143 @@
+// ============================================
+// strsim 包：字符串相似度
+// ============================================
+//
+//   Levenshtein         编辑距离：插入、删除、替换各算 1 次
+//   DamerauLevenshtein  在上面的基础上，相邻两个字符交换也只算 1 次
+//                       （"lgo" -> "log"），更符合打字错误的规律
+//   JaroWinkler         0~1 的相似度，越大越相似，对相同前缀加分，适合短字符串
+//
+// 所有函数按 rune 比较，中文等多字节字符也按一个字符计算。
+// Closest 组合上面的函数，为"你是不是想输入..."提示找出最接近的候选。
+// ============================================
+
+package strsim
+
+// Levenshtein 返回 a 和 b 的编辑距离
+// 只保留两行动态规划表，空间 O(len(b))
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// DamerauLevenshtein 返回允许相邻字符交换的编辑距离
+//
+// 实现的是"最优字符串对齐"（OSA）版本：每个子串最多被编辑一次，
+// 所以 "ca" -> "abc" 是 3 而不是 2。对拼写纠错来说两者几乎没有区别，OSA 实现简单得多。
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// 交换需要回看两行，保留三行
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// JaroWinkler 返回 0~1 的相似度，两个空字符串视为完全相同
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	// 相同字符只有在彼此相距不超过 window 时才算匹配
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+	matchA := make([]bool, len(ra))
+	matchB := make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchB[j] && rb[j] == r {
+				matchA[i], matchB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// 按顺序比较匹配上的字符，顺序不同的对数的一半就是"换位"数
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchA[i] {
+			continue
+		}
+		for !matchB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	// Winkler 修正：共同前缀（最多 4 个字符）越长越相似
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// Closest 返回与 target 最接近的候选，没有足够接近的候选时返回 false
+//
+// "足够接近"指 Damerau-Levenshtein 距离不超过 target 长度的三分之一（至少允许 1 处），
+// 距离相同时取 Jaro-Winkler 相似度更高的一个。
+func Closest(candidates []string, target string) (string, bool) {
+	limit := max(1, len([]rune(target))/3)
+	best, bestDist, bestSim, found := "", limit, -1.0, false
+	for _, c := range candidates {
+		d := DamerauLevenshtein(c, target)
+		if d > bestDist {
+			continue
+		}
+		sim := JaroWinkler(c, target)
+		if !found || d < bestDist || sim > bestSim {
+			best, bestDist, bestSim, found = c, d, sim, true
+		}
+	}
+	return best, found
+}
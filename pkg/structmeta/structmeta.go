@@ -0,0 +1,199 @@
+// ============================================
+// structmeta 包：按 reflect.Type 缓存结构体的字段和标签
+// ============================================
+//
+// 验证器、StructToMap、CSV 映射这类代码每次调用都要遍历 reflect.Type：
+// NumField、Field(i)、Tag.Get 再 strings.Split。类型在运行时不会变，
+// 这些工作做一次就够了——encoding/json 也是这样按类型缓存编码器的。
+//
+//   st, err := structmeta.Of(reflect.TypeOf(v))   // 第一次解析，之后直接取缓存
+//   for _, f := range st.Fields {
+//       name := f.Tag("json").Name()
+//       ...v.Field(f.Index[0])
+//   }
+//   f, ok := st.Field("Email")                     // 按名字查找，包括嵌入结构体提升的字段
+//
+// 缓存是并发安全的 sync.Map，键是 reflect.Type。程序里的结构体类型是有限的，
+// 缓存不会无限增长，不需要淘汰。
+//
+// 标签按逗号拆分成项，"required,min=0,max=150" 解析为 required、min=0、max=150：
+//   Name()      第一项，json、csv 标签里的列名/键名
+//   Has(k)      是否有 k 或 k=... 这一项
+//   Value(k)    k=v 中的 v
+// ============================================
+
+package structmeta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNotStruct 传入的类型（解引用指针后）不是结构体
+var ErrNotStruct = errors.New("structmeta: 不是结构体类型")
+
+// Struct 一个结构体类型的元数据，只读，可以在 goroutine 之间共享
+type Struct struct {
+	Type reflect.Type
+	// Fields 直接声明的导出字段，按声明顺序；嵌入的结构体作为一个字段出现
+	Fields []*Field
+
+	byName map[string]*Field
+}
+
+// Field 一个导出字段
+type Field struct {
+	Name      string
+	Index     []int // 用于 reflect.Value.FieldByIndex；直接声明的字段只有一个元素
+	Type      reflect.Type
+	Anonymous bool // 嵌入字段
+
+	tags map[string]Tag
+}
+
+// Tag 解析后的一个标签值
+type Tag struct {
+	Raw   string
+	Items []string // 按逗号拆分并去掉空白的各项
+
+	values map[string]string // k=v 项，以及没有 = 的 k（值为空）
+}
+
+var cache sync.Map // reflect.Type -> *Struct
+
+// Of 返回 t 的元数据；t 是指向结构体的指针时自动解引用
+func Of(t reflect.Type) (*Struct, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %v", ErrNotStruct, t)
+	}
+	if s, ok := cache.Load(t); ok {
+		return s.(*Struct), nil
+	}
+	// 多个 goroutine 同时解析同一个类型时结果相同，保留先存进去的那个
+	s, _ := cache.LoadOrStore(t, build(t))
+	return s.(*Struct), nil
+}
+
+// For 返回类型 T 的元数据
+func For[T any]() (*Struct, error) {
+	return Of(reflect.TypeFor[T]())
+}
+
+// Field 按名字查找导出字段，包括嵌入结构体中提升上来的字段，规则与 reflect.Type.FieldByName 相同
+func (s *Struct) Field(name string) (*Field, bool) {
+	f, ok := s.byName[name]
+	return f, ok
+}
+
+func build(t reflect.Type) *Struct {
+	s := &Struct{Type: t, byName: make(map[string]*Field)}
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() {
+			continue
+		}
+		f := &Field{
+			Name:      sf.Name,
+			Index:     sf.Index,
+			Type:      sf.Type,
+			Anonymous: sf.Anonymous,
+			tags:      parseTags(sf.Tag),
+		}
+		if len(sf.Index) == 1 {
+			s.Fields = append(s.Fields, f)
+		}
+		// VisibleFields 不包含被遮蔽的字段；同名时保留层级浅的（先出现的）
+		if _, dup := s.byName[sf.Name]; !dup {
+			s.byName[sf.Name] = f
+		}
+	}
+	return s
+}
+
+// Tag 返回 key 对应的标签，没有时返回零值
+func (f *Field) Tag(key string) Tag {
+	return f.tags[key]
+}
+
+// LookupTag 与 Tag 相同，另外返回标签是否存在（`json:""` 存在但为空）
+func (f *Field) LookupTag(key string) (Tag, bool) {
+	t, ok := f.tags[key]
+	return t, ok
+}
+
+// Name 第一项；json:"-" 返回 "-"
+func (t Tag) Name() string {
+	if len(t.Items) == 0 {
+		return ""
+	}
+	return t.Items[0]
+}
+
+// Has 是否有 k 这一项（或 k=...），第一项也参与比较
+func (t Tag) Has(k string) bool {
+	_, ok := t.values[k]
+	return ok
+}
+
+// Value k=v 中的 v；只有 k 没有 = 时返回 "", true
+func (t Tag) Value(k string) (string, bool) {
+	v, ok := t.values[k]
+	return v, ok
+}
+
+// parseTags 解析 `key:"value" key2:"value2"` 格式的结构体标签，语法与 reflect.StructTag.Lookup 相同
+func parseTags(tag reflect.StructTag) map[string]Tag {
+	var tags map[string]Tag
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		key, rest, ok := strings.Cut(s, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \"") || !strings.HasPrefix(rest, `"`) {
+			return tags
+		}
+		// 找到配对的引号，跳过转义
+		i := 1
+		for i < len(rest) && rest[i] != '"' {
+			if rest[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(rest) {
+			return tags
+		}
+		value, err := strconv.Unquote(rest[:i+1])
+		if err != nil {
+			return tags
+		}
+		if tags == nil {
+			tags = make(map[string]Tag)
+		}
+		if _, dup := tags[key]; !dup { // 与 Lookup 一致：重复的键以第一个为准
+			tags[key] = parseTag(value)
+		}
+		s = rest[i+1:]
+	}
+}
+
+func parseTag(raw string) Tag {
+	t := Tag{Raw: raw, values: make(map[string]string)}
+	if raw == "" {
+		return t
+	}
+	for item := range strings.SplitSeq(raw, ",") {
+		item = strings.TrimSpace(item)
+		t.Items = append(t.Items, item)
+		k, v, _ := strings.Cut(item, "=")
+		if _, dup := t.values[k]; !dup {
+			t.values[k] = v
+		}
+	}
+	return t
+}
@@ -0,0 +1,148 @@
+package syncutil
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ============================================
+// 写时复制（copy-on-write）容器
+// ============================================
+//
+// 读多写少的共享数据（路由表、配置、黑名单、订阅者列表）用 RWMutex 保护时，
+// 每次读都要对同一个计数器做原子加减：核数一多，这个缓存行在各核之间来回传递，
+// 读反而成了瓶颈。
+//
+// COWMap / COWSlice 让读者完全不加锁：
+//   - 当前数据是一个不可变的快照，通过 atomic.Pointer 发布
+//   - 读：Load 一次指针，之后随便读，不会看到写了一半的数据
+//   - 写：加锁（写者之间互斥）→ 复制快照 → 修改副本 → 原子替换指针
+//
+// 代价是每次写都复制整个容器，O(n)。适合一秒写几次、读几十万次的场景；
+// 写得频繁或者数据很大时用 RWMutex 或 SyncMap。基准对比见 cmd/cowbench。
+//
+// 快照（Snapshot、Load 返回的切片）是共享的，只能读不能改：
+// 改了等于绕过写锁直接修改所有读者正在看的数据。
+//
+// 零值可用；第一次使用后不能复制。
+
+// COWMap 读不加锁的写时复制 map
+type COWMap[K comparable, V any] struct {
+	mu sync.Mutex // 写者之间互斥
+	p  atomic.Pointer[map[K]V]
+}
+
+// snapshot 当前快照，从未写过时为 nil（读 nil map 是安全的）
+func (m *COWMap[K, V]) snapshot() map[K]V {
+	if p := m.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Load 返回 key 对应的值
+func (m *COWMap[K, V]) Load(key K) (V, bool) {
+	v, ok := m.snapshot()[key]
+	return v, ok
+}
+
+// Len 键的个数
+func (m *COWMap[K, V]) Len() int { return len(m.snapshot()) }
+
+// Snapshot 返回当前快照，只读；之后的写入不会影响它
+func (m *COWMap[K, V]) Snapshot() map[K]V { return m.snapshot() }
+
+// All 遍历当前快照，遍历期间的写入不可见
+func (m *COWMap[K, V]) All() iter.Seq2[K, V] { return maps.All(m.snapshot()) }
+
+// Store 设置 key 的值
+func (m *COWMap[K, V]) Store(key K, value V) {
+	m.Update(func(next map[K]V) { next[key] = value })
+}
+
+// Delete 删除 key；key 不存在时不复制
+func (m *COWMap[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur := m.snapshot()
+	if _, ok := cur[key]; !ok {
+		return
+	}
+	next := maps.Clone(cur)
+	delete(next, key)
+	m.p.Store(&next)
+}
+
+// Update 在一份副本上执行 fn 后整体替换：一批修改只复制一次，读者要么看到全部修改，要么一个都看不到
+// fn 执行期间持有写锁，不要在 fn 里调用同一个 COWMap 的写方法
+func (m *COWMap[K, V]) Update(fn func(next map[K]V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next := maps.Clone(m.snapshot())
+	if next == nil {
+		next = make(map[K]V)
+	}
+	fn(next)
+	m.p.Store(&next)
+}
+
+// Replace 用 src 的副本整体替换当前内容
+func (m *COWMap[K, V]) Replace(src map[K]V) {
+	next := maps.Clone(src)
+	m.mu.Lock()
+	m.p.Store(&next)
+	m.mu.Unlock()
+}
+
+// COWSlice 读不加锁的写时复制切片
+type COWSlice[T any] struct {
+	mu sync.Mutex
+	p  atomic.Pointer[[]T]
+}
+
+// Load 返回当前快照，只读；之后的写入不会影响它
+func (s *COWSlice[T]) Load() []T {
+	if p := s.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Len 元素个数
+func (s *COWSlice[T]) Len() int { return len(s.Load()) }
+
+// At 返回第 i 个元素，越界时 panic
+func (s *COWSlice[T]) At(i int) T { return s.Load()[i] }
+
+// All 遍历当前快照
+func (s *COWSlice[T]) All() iter.Seq2[int, T] { return slices.All(s.Load()) }
+
+// Append 在末尾追加
+func (s *COWSlice[T]) Append(vs ...T) {
+	s.Update(func(cur []T) []T { return append(cur, vs...) })
+}
+
+// Set 替换第 i 个元素，越界时 panic
+func (s *COWSlice[T]) Set(i int, v T) {
+	s.Update(func(cur []T) []T {
+		cur[i] = v
+		return cur
+	})
+}
+
+// DeleteFunc 删除 del 返回 true 的元素
+func (s *COWSlice[T]) DeleteFunc(del func(T) bool) {
+	s.Update(func(cur []T) []T { return slices.DeleteFunc(cur, del) })
+}
+
+// Update 把当前内容的副本交给 fn，用 fn 的返回值替换当前内容
+// 副本的容量刚好等于长度，fn 里 append 会重新分配，不会写到旧快照上
+func (s *COWSlice[T]) Update(fn func(cur []T) []T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := fn(slices.Clip(slices.Clone(s.Load())))
+	s.p.Store(&next)
+}
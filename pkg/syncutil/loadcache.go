@@ -0,0 +1,73 @@
+package syncutil
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrLoadPanicked 加载函数 panic，等待同一个键的其他调用者收到这个错误
+var ErrLoadPanicked = errors.New("加载函数 panic")
+
+// LoadCache 并发安全的加载缓存：同一个键并发未命中时只加载一次，其他调用者等待结果
+// 加载失败不缓存，下次调用会重新加载
+type LoadCache[K comparable, V any] struct {
+	mu sync.Mutex
+	m  map[K]*loadEntry[V]
+}
+
+// loadEntry 一个键的加载状态；done 关闭后 val、err 只读
+type loadEntry[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// NewLoadCache 创建空缓存
+func NewLoadCache[K comparable, V any]() *LoadCache[K, V] {
+	return &LoadCache[K, V]{m: make(map[K]*loadEntry[V])}
+}
+
+// GetOrLoad 返回 key 的值，不存在时调用 load 加载
+// 持有锁时只做登记，load 在锁外执行，不同的键可以并行加载
+func (c *LoadCache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.m[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.val, e.err
+	}
+	e := &loadEntry[V]{done: make(chan struct{})}
+	c.m[key] = e
+	c.mu.Unlock()
+
+	// load panic 时也要唤醒等待者，否则它们会永远阻塞在 <-e.done 上
+	finished := false
+	defer func() {
+		if !finished {
+			e.err = ErrLoadPanicked
+			c.forget(key)
+			close(e.done)
+		}
+	}()
+	e.val, e.err = load()
+	finished = true
+	if e.err != nil {
+		// 失败的结果只交给正在等待的调用者，之后的调用重新加载
+		c.forget(key)
+	}
+	close(e.done)
+	return e.val, e.err
+}
+
+func (c *LoadCache[K, V]) forget(key K) {
+	c.mu.Lock()
+	delete(c.m, key)
+	c.mu.Unlock()
+}
+
+// Len 已缓存（包括正在加载）的键数
+func (c *LoadCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
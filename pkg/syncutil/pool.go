@@ -0,0 +1,210 @@
+package syncutil
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// Pool 类型安全的对象池，带 Get / Put / New 计数
+//
+//	pool := syncutil.NewPool(func() *bytes.Buffer { return new(bytes.Buffer) })
+//	buf := pool.Get()
+//	buf.Reset()              // 池不负责清理对象，取出后自己重置
+//	defer pool.Put(buf)
+//
+// 池里存的是 *T：指针装进 any 不需要分配，而且指针就是对象的身份，
+// 调试模式靠它记录"哪个对象是在哪里被取走的"。
+//
+// 计数可以回答两个问题：
+//   - 池有没有起作用：News 接近 Gets 说明几乎每次都在新建（对象太大被丢弃、Put 太少，或者 GC 太频繁）
+//   - 有没有忘记归还：Gets - Puts 持续增长就是泄漏；打开 WithDebug 后用 WriteLeaks 找到取走它们的代码
+type Pool[T any] struct {
+	pool  sync.Pool
+	debug bool
+	clock clock.Clock
+
+	gets, puts, news atomic.Int64
+
+	mu          sync.Mutex
+	outstanding map[*T]borrow // 调试模式下已取出未归还的对象
+}
+
+// borrow 一次 Get 的记录
+type borrow struct {
+	at  time.Time
+	pcs []uintptr
+}
+
+// PoolOption NewPool 的选项
+type PoolOption func(*poolOptions)
+
+type poolOptions struct {
+	debug bool
+	clock clock.Clock
+}
+
+// WithDebug 打开调试模式：记录每个未归还对象的取出时间和调用栈，
+// Put 不是从池里取出（或已经归还过）的对象时 panic
+//
+// 每次 Get 都要抓一次调用栈、加一次锁，只在排查泄漏时打开
+func WithDebug() PoolOption { return func(o *poolOptions) { o.debug = true } }
+
+// WithPoolClock 指定记录取出时间用的时钟，演示时传入 clock.FakeClock
+func WithPoolClock(c clock.Clock) PoolOption { return func(o *poolOptions) { o.clock = c } }
+
+// NewPool 创建对象池，池空时用 newFn 创建对象
+func NewPool[T any](newFn func() *T, opts ...PoolOption) *Pool[T] {
+	var o poolOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &Pool[T]{debug: o.debug, clock: clock.Or(o.clock)}
+	p.pool.New = func() any {
+		p.news.Add(1)
+		return newFn()
+	}
+	if p.debug {
+		p.outstanding = make(map[*T]borrow)
+	}
+	return p
+}
+
+// Get 取出一个对象，内容是上一个使用者留下的，用之前要重置
+func (p *Pool[T]) Get() *T {
+	p.gets.Add(1)
+	v := p.pool.Get().(*T)
+	if p.debug {
+		pcs := make([]uintptr, 32)
+		pcs = pcs[:runtime.Callers(2, pcs)] // 跳过 runtime.Callers 和 Get 自己
+		p.mu.Lock()
+		p.outstanding[v] = borrow{at: p.clock.Now(), pcs: pcs}
+		p.mu.Unlock()
+	}
+	return v
+}
+
+// Put 归还对象，之后不能再使用它；nil 被忽略
+func (p *Pool[T]) Put(v *T) {
+	if v == nil {
+		return
+	}
+	if p.debug {
+		p.mu.Lock()
+		_, ok := p.outstanding[v]
+		delete(p.outstanding, v)
+		p.mu.Unlock()
+		if !ok {
+			panic(fmt.Sprintf("syncutil: Put 的 %T 不是从这个池取出的，或者已经归还过", v))
+		}
+	}
+	p.puts.Add(1)
+	p.pool.Put(v)
+}
+
+// PoolStats 对象池的计数
+type PoolStats struct {
+	Gets int64 // Get 次数
+	Puts int64 // Put 次数
+	News int64 // 池空时调用 newFn 的次数
+}
+
+// Outstanding 已取出未归还的对象个数
+func (s PoolStats) Outstanding() int64 { return s.Gets - s.Puts }
+
+// HitRate Get 直接从池里拿到对象（没有新建）的比例，没有 Get 时为 0
+func (s PoolStats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return 1 - float64(s.News)/float64(s.Gets)
+}
+
+func (s PoolStats) String() string {
+	return fmt.Sprintf("gets=%d puts=%d news=%d 未归还=%d 命中率=%.1f%%",
+		s.Gets, s.Puts, s.News, s.Outstanding(), s.HitRate()*100)
+}
+
+// Stats 返回当前计数；三个计数分别读取，并发使用时彼此之间不是同一时刻的快照
+func (p *Pool[T]) Stats() PoolStats {
+	return PoolStats{Gets: p.gets.Load(), Puts: p.puts.Load(), News: p.news.Load()}
+}
+
+// Leak 同一处代码取走、超过阈值仍未归还的对象
+type Leak struct {
+	Count  int           // 对象个数
+	Oldest time.Duration // 其中借出最久的时长
+	Stack  string        // 调用 Get 的调用栈，每帧一行"函数\n\t文件:行号"
+}
+
+// Leaks 按调用栈汇总借出超过 olderThan 的对象，按个数从多到少排列
+// 不在调试模式时返回 nil
+func (p *Pool[T]) Leaks(olderThan time.Duration) []Leak {
+	if !p.debug {
+		return nil
+	}
+	now := p.clock.Now()
+	p.mu.Lock()
+	byStack := make(map[string]*Leak)
+	for _, b := range p.outstanding {
+		age := now.Sub(b.at)
+		if age < olderThan {
+			continue
+		}
+		// 先用程序计数器做键，格式化调用栈比较慢，每个栈只做一次
+		key := fmt.Sprint(b.pcs)
+		l, ok := byStack[key]
+		if !ok {
+			l = &Leak{Stack: formatStack(b.pcs)}
+			byStack[key] = l
+		}
+		l.Count++
+		l.Oldest = max(l.Oldest, age)
+	}
+	p.mu.Unlock()
+
+	leaks := make([]Leak, 0, len(byStack))
+	for _, l := range byStack {
+		leaks = append(leaks, *l)
+	}
+	slices.SortFunc(leaks, func(a, b Leak) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Stack, b.Stack)
+	})
+	return leaks
+}
+
+// WriteLeaks 把 Leaks 的结果写到 w，返回泄漏的对象总数
+func (p *Pool[T]) WriteLeaks(w io.Writer, olderThan time.Duration) int {
+	total := 0
+	for _, l := range p.Leaks(olderThan) {
+		total += l.Count
+		fmt.Fprintf(w, "%d 个对象未归还（最久 %v），取出于：\n%s", l.Count, l.Oldest, l.Stack)
+	}
+	return total
+}
+
+// formatStack 格式化调用栈，和 panic 输出的格式相同
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" {
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
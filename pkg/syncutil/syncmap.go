@@ -0,0 +1,130 @@
+// ============================================
+// syncutil 包：sync.Map、sync.Pool 的泛型包装与写时复制容器
+// ============================================
+//
+// sync.Map 的键和值都是 any，每次 Load 都要做类型断言，写错类型要到运行时才 panic：
+//
+//   var m sync.Map
+//   m.Store("alice", 1)
+//   v, _ := m.Load("alice")
+//   n := v.(int)                    // 断言，存进去的不是 int 时 panic
+//
+// SyncMap[K, V] 把断言收在内部，编译期检查键值类型，另外提供 sync.Map 没有的 Len：
+//
+//   var m syncutil.SyncMap[string, int]
+//   m.Store("alice", 1)
+//   n, _ := m.Load("alice")          // n 是 int
+//
+// 什么时候用 sync.Map（tutorial/06 第 6 节）：键集合基本稳定、读多写少，
+// 或者各 goroutine 读写互不相交的键。需要"查找 + 其他操作"作为一个整体时仍然要用锁：
+// chat.ChatRouter 投递消息时持有注册表的读锁，保证收件箱在发送期间不会被 Unregister 关闭，
+// 这种跨操作的不变量 sync.Map 表达不了。
+//
+// 零值可用；和 sync.Map 一样，第一次使用后不能复制。
+//
+// Pool[T]（pool.go）是 sync.Pool 的泛型包装，统计 Get / Put / New 次数；
+// 调试模式下记录每个未归还对象的调用栈，用来找出忘记 Put 的代码（tutorial/06 第 5 节）。
+//
+// COWMap / COWSlice（cow.go）是写时复制的容器：读者不加锁，写者复制后原子替换，适合读远多于写的数据。
+//
+// LoadCache（loadcache.go）来自 tutorial/11 练习 2：同一个键并发未命中时只加载一次。
+// ============================================
+
+package syncutil
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// SyncMap 类型安全的并发 map
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+	n atomic.Int64 // 键的个数，见 Len
+}
+
+// Load 返回 key 对应的值
+func (m *SyncMap[K, V]) Load(key K) (value V, ok bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		return value, false
+	}
+	return cast[V](v), true
+}
+
+// Store 设置 key 的值
+func (m *SyncMap[K, V]) Store(key K, value V) {
+	m.Swap(key, value)
+}
+
+// Swap 设置 key 的值，返回之前的值；loaded 表示之前是否存在
+func (m *SyncMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	v, loaded := m.m.Swap(key, value)
+	if !loaded {
+		m.n.Add(1)
+		return previous, false
+	}
+	return cast[V](v), true
+}
+
+// LoadOrStore key 存在时返回已有的值和 true；否则存入 value，返回 value 和 false
+// 多个 goroutine 同时为同一个 key 调用时，只有一个能存入，其余的都拿到它存入的值
+func (m *SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	v, loaded := m.m.LoadOrStore(key, value)
+	if !loaded {
+		m.n.Add(1)
+	}
+	return cast[V](v), loaded
+}
+
+// LoadAndDelete 删除 key，返回删除前的值；loaded 表示 key 是否存在
+func (m *SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	v, loaded := m.m.LoadAndDelete(key)
+	if !loaded {
+		return value, false
+	}
+	m.n.Add(-1)
+	return cast[V](v), true
+}
+
+// Delete 删除 key
+func (m *SyncMap[K, V]) Delete(key K) {
+	m.LoadAndDelete(key)
+}
+
+// Range 依次对每个键值调用 fn，fn 返回 false 时停止
+// 与 sync.Map.Range 相同：不是快照，遍历期间其他 goroutine 的修改可能看到也可能看不到
+func (m *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.m.Range(func(k, v any) bool {
+		return fn(k.(K), cast[V](v))
+	})
+}
+
+// All 以迭代器的形式遍历，语义同 Range：for k, v := range m.All() { ... }
+func (m *SyncMap[K, V]) All() iter.Seq2[K, V] {
+	return m.Range
+}
+
+// Len 键的个数
+// 增删与计数不是一个原子操作，并发修改期间读到的值可能短暂地偏离；没有并发修改时是准确的
+func (m *SyncMap[K, V]) Len() int {
+	return int(max(m.n.Load(), 0))
+}
+
+// Clear 删除所有键
+func (m *SyncMap[K, V]) Clear() {
+	m.m.Range(func(k, _ any) bool {
+		if _, loaded := m.m.LoadAndDelete(k); loaded {
+			m.n.Add(-1)
+		}
+		return true
+	})
+}
+
+// cast 把 sync.Map 中的值转换为 V
+// 用 comma-ok：V 是接口类型且存入的是 nil 时，v.(V) 会 panic，这里返回零值
+func cast[V any](v any) V {
+	out, _ := v.(V)
+	return out
+}
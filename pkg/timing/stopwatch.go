@@ -0,0 +1,142 @@
+// ============================================
+// timing 包：计时工具
+// ============================================
+//
+//   Stopwatch  秒表，支持分段计时（Lap），适合一次性地看清楚"时间花在哪"
+//   Timed      包装一次函数调用，把耗时记录到 metrics 直方图，
+//              适合长期运行的服务，按函数名统计耗时分布
+//
+// 取代 tutorial/02 里 defer timeTrack(time.Now(), name) 的写法：
+// 那种写法只能打印一个总耗时，无法分段，也无法汇总多次调用。
+// ============================================
+
+package timing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// Lap 一个分段
+type Lap struct {
+	Name  string
+	Split time.Duration // 距上一个分段（或开始）的时间
+	Total time.Duration // 距开始的时间
+}
+
+// Stopwatch 秒表，并发安全
+type Stopwatch struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	start   time.Time
+	last    time.Time
+	stopped time.Time // 零值表示仍在运行
+	laps    []Lap
+}
+
+// StartStopwatch 创建并立即启动秒表
+func StartStopwatch() *Stopwatch {
+	return StartStopwatchWithClock(clock.Real)
+}
+
+// StartStopwatchWithClock 使用指定时钟创建并启动秒表，测试中可以传入 clock.FakeClock
+func StartStopwatchWithClock(c clock.Clock) *Stopwatch {
+	c = clock.Or(c)
+	now := c.Now()
+	return &Stopwatch{clock: c, start: now, last: now}
+}
+
+// Lap 记录一个分段并返回它；秒表停止后调用只返回停止时刻的分段，不再追加
+func (s *Stopwatch) Lap(name string) Lap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.nowLocked()
+	lap := Lap{Name: name, Split: now.Sub(s.last), Total: now.Sub(s.start)}
+	if s.stopped.IsZero() {
+		s.laps = append(s.laps, lap)
+		s.last = now
+	}
+	return lap
+}
+
+// Stop 停止秒表并返回总耗时，重复调用返回同一个值
+func (s *Stopwatch) Stop() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped.IsZero() {
+		s.stopped = s.clock.Now()
+	}
+	return s.stopped.Sub(s.start)
+}
+
+// Elapsed 返回已经过的时间，停止后固定为停止时的值
+func (s *Stopwatch) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nowLocked().Sub(s.start)
+}
+
+// Laps 返回所有分段的副本
+func (s *Stopwatch) Laps() []Lap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Lap(nil), s.laps...)
+}
+
+// Splits 返回各分段的耗时，可以交给 stats.Summarize 看分布：
+//
+//	for range 100 { handle(); sw.Lap("请求") }
+//	stats.Summarize(sw.Splits())
+func (s *Stopwatch) Splits() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	splits := make([]time.Duration, len(s.laps))
+	for i, l := range s.laps {
+		splits[i] = l.Split
+	}
+	return splits
+}
+
+// String 以表格形式输出各分段和总耗时
+//
+//	解析       12ms   12ms
+//	计算      105ms  117ms
+//	总计             120ms
+func (s *Stopwatch) String() string {
+	laps := s.Laps()
+	total := s.Elapsed()
+
+	width := len("总计")
+	for _, l := range laps {
+		width = max(width, len(l.Name))
+	}
+	var sb strings.Builder
+	for _, l := range laps {
+		fmt.Fprintf(&sb, "%-*s %10v %10v\n", width, l.Name, round(l.Split), round(l.Total))
+	}
+	fmt.Fprintf(&sb, "%-*s %10s %10v", width, "总计", "", round(total))
+	return sb.String()
+}
+
+func (s *Stopwatch) nowLocked() time.Time {
+	if !s.stopped.IsZero() {
+		return s.stopped
+	}
+	return s.clock.Now()
+}
+
+// round 按量级保留 3 位左右的有效数字，便于阅读
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d
+	}
+}
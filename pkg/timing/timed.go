@@ -0,0 +1,55 @@
+package timing
+
+import (
+	"sync"
+	"time"
+
+	"c03/pkg/metrics"
+)
+
+// Recorder 把函数调用耗时按名字记录到一个 HistogramVec
+type Recorder struct {
+	hist *metrics.HistogramVec
+}
+
+// NewRecorder 在 reg 中注册名为 metricName、标签为 name 的耗时直方图
+// buckets 为空时使用 metrics.DefBuckets
+func NewRecorder(reg *metrics.Registry, metricName string, buckets []float64) *Recorder {
+	return &Recorder{hist: reg.NewHistogramVec(metricName, "函数调用耗时（秒）", buckets, "name")}
+}
+
+// Timed 调用 fn 并把耗时记录到 name 对应的直方图；fn panic 时同样记录，panic 继续传播
+func (r *Recorder) Timed(name string, fn func()) (d time.Duration) {
+	start := time.Now()
+	defer func() {
+		d = time.Since(start)
+		r.hist.With(name).Observe(d.Seconds())
+	}()
+	fn()
+	return
+}
+
+// Histogram 返回 name 对应的直方图，用于读取调用次数和总耗时
+func (r *Recorder) Histogram(name string) *metrics.Histogram {
+	return r.hist.With(name)
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultRecorder *Recorder
+)
+
+// Default 返回注册在 metrics.Default 中的 Recorder，指标名为 function_duration_seconds
+func Default() *Recorder {
+	defaultOnce.Do(func() {
+		defaultRecorder = NewRecorder(metrics.Default, "function_duration_seconds", nil)
+	})
+	return defaultRecorder
+}
+
+// Timed 使用 Default() 记录 fn 的耗时
+//
+//	timing.Timed("loadConfig", func() { cfg, err = config.Load(path, &c) })
+func Timed(name string, fn func()) time.Duration {
+	return Default().Timed(name, fn)
+}
@@ -0,0 +1,162 @@
+package udpmsg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 500 * time.Millisecond
+	defaultRetries = 3
+)
+
+var (
+	ErrTimeout      = errors.New("udpmsg: 等待响应超时")
+	ErrClientClosed = errors.New("udpmsg: 客户端已关闭")
+)
+
+// Client 向一个服务端发送请求，可被多个 goroutine 同时使用
+//
+// 后台 goroutine 读取所有响应，按 ID 交给对应的 Request 调用；
+// 找不到等待者的响应（已经超时、或者是重传导致的重复响应）直接丢弃。
+type Client struct {
+	Timeout time.Duration // 每次发送后等待响应的时间，默认 500ms
+	Retries int           // 超时后的重传次数，默认 3
+
+	conn   net.PacketConn
+	server net.Addr
+
+	mu      sync.Mutex
+	nextID  uint32
+	pending map[uint32]chan []byte
+	closed  bool
+	done    chan struct{}
+}
+
+// Dial 在随机本地端口上创建向 addr 发送请求的客户端
+func Dial(addr string) (*Client, error) {
+	server, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenPacket("udp", ":0")
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn, server), nil
+}
+
+// NewClient 用已经打开的 conn 向 server 发送请求，conn 由 Client 负责关闭
+func NewClient(conn net.PacketConn, server net.Addr) *Client {
+	c := &Client{
+		Timeout: defaultTimeout,
+		Retries: defaultRetries,
+		conn:    conn,
+		server:  server,
+		pending: make(map[uint32]chan []byte),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Request 发送 payload 并等待响应
+// 每次等待 Timeout，超时按同一个 ID 重传，共发送 Retries+1 次
+func (c *Client) Request(ctx context.Context, payload []byte) ([]byte, error) {
+	id, ch, err := c.register()
+	if err != nil {
+		return nil, err
+	}
+	defer c.unregister(id)
+
+	pkt, err := Packet{Kind: KindRequest, ID: id, Payload: payload}.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(c.Timeout)
+	defer timer.Stop()
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if _, err := c.conn.WriteTo(pkt, c.server); err != nil {
+			return nil, err
+		}
+		timer.Reset(c.Timeout)
+		select {
+		case resp := <-ch:
+			return resp, nil
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.done:
+			return nil, ErrClientClosed
+		}
+	}
+	return nil, fmt.Errorf("%w: 请求 %d 发送了 %d 次", ErrTimeout, id, c.Retries+1)
+}
+
+// Close 关闭连接，正在等待的 Request 返回 ErrClientClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+	return c.conn.Close()
+}
+
+func (c *Client) register() (uint32, chan []byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, nil, ErrClientClosed
+	}
+	c.nextID++
+	// 缓冲 1：readLoop 投递时不会因为等待者刚好超时而阻塞
+	ch := make(chan []byte, 1)
+	c.pending[c.nextID] = ch
+	return c.nextID, ch, nil
+}
+
+func (c *Client) unregister(id uint32) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// readLoop 接收响应并按 ID 分发，conn 关闭后退出
+func (c *Client) readLoop() {
+	buf := make([]byte, headerSize+MaxPayload)
+	for {
+		n, from, err := c.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		// 只接受来自服务端的响应，忽略其他来源的包
+		if from.String() != c.server.String() {
+			continue
+		}
+		p, err := Unmarshal(buf[:n])
+		if err != nil || p.Kind != KindResponse {
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[p.ID]
+		if ok {
+			// 同一个 ID 只投递一次，重复的响应会因为已删除而被丢弃
+			delete(c.pending, p.ID)
+			ch <- append([]byte(nil), p.Payload...)
+		}
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,104 @@
+// ============================================
+// udpmsg 包：基于 UDP 的请求/响应消息
+// ============================================
+//
+// UDP 与 TCP 的区别：
+//   - 没有连接，每个数据报独立收发，一次 ReadFrom 正好读到一个完整的包
+//   - 不保证送达、不保证顺序、可能重复
+//
+// 因此这里需要自己解决三件事：
+//   1. 分帧：每个包带固定包头，能识别协议、类型和长度，丢弃不认识的包
+//   2. 关联：请求带 32 位 ID，响应原样带回，客户端据此把响应交给等待者，
+//      多个请求可以同时在途，响应乱序到达也没关系
+//   3. 超时重传：等不到响应就按同一个 ID 重发，超过次数返回 ErrTimeout
+//
+// 包格式（大端序）：
+//
+//   0      2       3      4          8          10
+//   ┌──────┬───────┬──────┬──────────┬──────────┬─────────────┐
+//   │ 魔数 │ 版本  │ 类型 │ 请求 ID  │ 负载长度 │ 负载 ...    │
+//   └──────┴───────┴──────┴──────────┴──────────┴─────────────┘
+// ============================================
+
+package udpmsg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	magic      = 0x5544 // "UD"
+	version    = 1
+	headerSize = 10
+
+	// MaxPayload 单个包的最大负载，保证整个包不超过常见 MTU，避免 IP 分片
+	MaxPayload = 1200
+)
+
+// Kind 包类型
+type Kind uint8
+
+const (
+	KindRequest Kind = iota + 1
+	KindResponse
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindRequest:
+		return "request"
+	case KindResponse:
+		return "response"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+var (
+	ErrBadPacket = errors.New("udpmsg: 无效的数据包")
+	ErrTooLarge  = errors.New("udpmsg: 负载过大")
+)
+
+// Packet 一个数据报
+type Packet struct {
+	Kind    Kind
+	ID      uint32
+	Payload []byte
+}
+
+// Marshal 把包编码为字节
+func (p Packet) Marshal() ([]byte, error) {
+	if len(p.Payload) > MaxPayload {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooLarge, len(p.Payload), MaxPayload)
+	}
+	b := make([]byte, headerSize+len(p.Payload))
+	binary.BigEndian.PutUint16(b[0:], magic)
+	b[2] = version
+	b[3] = byte(p.Kind)
+	binary.BigEndian.PutUint32(b[4:], p.ID)
+	binary.BigEndian.PutUint16(b[8:], uint16(len(p.Payload)))
+	copy(b[headerSize:], p.Payload)
+	return b, nil
+}
+
+// Unmarshal 解析一个数据报，Payload 引用 b 的内存
+func Unmarshal(b []byte) (Packet, error) {
+	if len(b) < headerSize {
+		return Packet{}, fmt.Errorf("%w: 长度 %d 小于包头", ErrBadPacket, len(b))
+	}
+	if binary.BigEndian.Uint16(b[0:]) != magic || b[2] != version {
+		return Packet{}, fmt.Errorf("%w: 魔数或版本不匹配", ErrBadPacket)
+	}
+	p := Packet{Kind: Kind(b[3]), ID: binary.BigEndian.Uint32(b[4:])}
+	if p.Kind != KindRequest && p.Kind != KindResponse {
+		return Packet{}, fmt.Errorf("%w: 未知类型 %v", ErrBadPacket, p.Kind)
+	}
+	n := int(binary.BigEndian.Uint16(b[8:]))
+	if n != len(b)-headerSize {
+		return Packet{}, fmt.Errorf("%w: 声明长度 %d，实际 %d", ErrBadPacket, n, len(b)-headerSize)
+	}
+	p.Payload = b[headerSize:]
+	return p, nil
+}
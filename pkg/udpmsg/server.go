@@ -0,0 +1,51 @@
+package udpmsg
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+)
+
+// Handler 处理一个请求负载，返回响应负载
+type Handler func(from net.Addr, req []byte) []byte
+
+// Serve 在 conn 上接收请求并用 handler 的返回值回复，直到 ctx 取消
+// 无效的包直接丢弃；conn 由 Serve 负责关闭，ctx 取消导致的退出返回 nil
+//
+// 请求在读循环中逐个处理，handler 应当很快返回。
+// UDP 没有连接状态，客户端重传时同一个请求可能被处理多次，handler 需要是幂等的。
+func Serve(ctx context.Context, conn net.PacketConn, handler Handler, logger *log.Logger) error {
+	if logger == nil {
+		logger = log.Default()
+	}
+	// ReadFrom 不接受 ctx，关闭 conn 来打断它
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	buf := make([]byte, headerSize+MaxPayload)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			conn.Close()
+			return err
+		}
+		req, err := Unmarshal(buf[:n])
+		if err != nil || req.Kind != KindRequest {
+			logger.Printf("udpmsg: 丢弃来自 %s 的包: %v", from, err)
+			continue
+		}
+
+		resp, err := Packet{Kind: KindResponse, ID: req.ID, Payload: handler(from, req.Payload)}.Marshal()
+		if err != nil {
+			logger.Printf("udpmsg: 请求 %d 的响应无法编码: %v", req.ID, err)
+			continue
+		}
+		if _, err := conn.WriteTo(resp, from); err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Printf("udpmsg: 回复 %s 失败: %v", from, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// ============================================
+// unitext 包：按"字符"而不是字节处理字符串
+// ============================================
+//
+// Go 的字符串是 UTF-8 字节序列，len("世界") 是 6，s[:3] 切出的是"世"。
+// 对中文、emoji 做截断、反转、对齐时，需要分清三种"长度"：
+//
+//   字节   len(s)                    "é👍🏽世" = 2+8+3 = 13（é 用一个码点时）
+//   rune   utf8.RuneCountInString    码点个数；👍🏽 是两个码点（👍 + 肤色）
+//   字素   GraphemeCount             用户眼里的一个字符；👍🏽 算一个
+//
+// 再加上终端里的显示宽度：中文、全角符号、大多数 emoji 占两列，组合符号占零列。
+//
+//   TruncateRunes / ReverseRunes          按 rune 处理，不会切坏 UTF-8
+//   Graphemes / SubstrGraphemes / ...     按字素处理，不会拆开组合符号和 emoji 序列
+//   DisplayWidth / PadDisplayWidth / ...  按显示宽度对齐表格
+//
+// 字素切分是简化版：覆盖组合符号、变体选择符、emoji 肤色、ZWJ 序列、国旗和 CRLF，
+// 没有实现 UAX #29 的全部规则（如印度系文字的辅音簇），对中日韩和常见 emoji 足够。
+// ============================================
+
+package unitext
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ============================================
+// 按 rune
+// ============================================
+
+// TruncateRunes 保留前 n 个 rune
+func TruncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// ReverseRunes 按 rune 反转；组合符号会跑到另一个字符上，需要时用 ReverseGraphemes
+func ReverseRunes(s string) string {
+	r := []rune(s)
+	slices.Reverse(r)
+	return string(r)
+}
+
+// ============================================
+// 按字素
+// ============================================
+
+const (
+	zwj  = '\u200d' // 零宽连接符，把前后两个 emoji 连成一个（👨‍👩‍👧）
+	vs16 = '\ufe0f' // 变体选择符 16：以 emoji 样式显示前一个字符
+)
+
+// extends 附着在前一个字符上、不单独成为字素的 rune
+func extends(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r >= 0xfe00 && r <= 0xfe0f || // 变体选择符
+		r >= 0x1f3fb && r <= 0x1f3ff || // emoji 肤色
+		r >= 0xe0020 && r <= 0xe007f // 标签字符（如苏格兰旗）
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1f1e6 && r <= 0x1f1ff }
+
+// nextGrapheme 返回 s 开头第一个字素的字节长度
+func nextGrapheme(s string) int {
+	first, i := utf8.DecodeRuneInString(s)
+	if first == '\r' && strings.HasPrefix(s[i:], "\n") {
+		return i + 1
+	}
+	// 两个区域指示符组成一面国旗：🇨 + 🇳 = 🇨🇳
+	if isRegionalIndicator(first) {
+		if r, n := utf8.DecodeRuneInString(s[i:]); isRegionalIndicator(r) {
+			i += n
+		}
+	}
+	for i < len(s) {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case extends(r):
+			i += n
+		case r == zwj:
+			i += n
+			// ZWJ 后面的字符与前面连成一体
+			if i < len(s) {
+				_, n = utf8.DecodeRuneInString(s[i:])
+				i += n
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// Graphemes 把 s 切分为字素
+func Graphemes(s string) []string {
+	var out []string
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		out = append(out, s[:n])
+		s = s[n:]
+	}
+	return out
+}
+
+// GraphemeCount 字素个数
+func GraphemeCount(s string) int {
+	count := 0
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		count++
+	}
+	return count
+}
+
+// SubstrGraphemes 返回第 start 到第 end 个字素（左闭右开），越界的部分被截掉
+func SubstrGraphemes(s string, start, end int) string {
+	from, to := -1, len(s)
+	pos := 0
+	for idx := 0; ; idx++ {
+		if idx == start {
+			from = pos
+		}
+		if idx == end {
+			to = pos
+			break
+		}
+		if pos >= len(s) {
+			break
+		}
+		pos += nextGrapheme(s[pos:])
+	}
+	if from < 0 || from >= to {
+		return ""
+	}
+	return s[from:to]
+}
+
+// TruncateGraphemes 保留前 n 个字素
+func TruncateGraphemes(s string, n int) string {
+	return SubstrGraphemes(s, 0, n)
+}
+
+// ReverseGraphemes 按字素反转，"é"（e + 组合重音）和 emoji 序列保持完整
+func ReverseGraphemes(s string) string {
+	g := Graphemes(s)
+	slices.Reverse(g)
+	return strings.Join(g, "")
+}
+
+// ============================================
+// 显示宽度
+// ============================================
+
+// runeWidth 一个 rune 在等宽终端中占的列数
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || r == zwj || extends(r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case unicode.IsControl(r):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// graphemeWidth 一个字素的宽度：取第一个 rune 的宽度，
+// 带 emoji 变体选择符或是国旗时按两列计算
+func graphemeWidth(g string) int {
+	first, _ := utf8.DecodeRuneInString(g)
+	if isRegionalIndicator(first) || strings.ContainsRune(g, vs16) {
+		return 2
+	}
+	return runeWidth(first)
+}
+
+// DisplayWidth 字符串在等宽终端中占的列数
+func DisplayWidth(s string) int {
+	w := 0
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		w += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return w
+}
+
+// PadDisplayWidth 在右侧补空格到 w 列（左对齐），已经够宽时原样返回
+// fmt 的 %-10s 按 rune 计数，中文会对不齐，用它代替：
+//
+//	fmt.Printf("%s %5d\n", unitext.PadDisplayWidth(name, 10), n)
+func PadDisplayWidth(s string, w int) string {
+	if pad := w - DisplayWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// PadLeftDisplayWidth 在左侧补空格到 w 列（右对齐）
+func PadLeftDisplayWidth(s string, w int) string {
+	if pad := w - DisplayWidth(s); pad > 0 {
+		return strings.Repeat(" ", pad) + s
+	}
+	return s
+}
+
+// TruncateDisplayWidth 截断到不超过 w 列，截断时以 tail（如 "…"）结尾，
+// tail 的宽度计算在内；不会把一个宽字符或字素切成两半
+func TruncateDisplayWidth(s string, w int, tail string) string {
+	if DisplayWidth(s) <= w {
+		return s
+	}
+	limit := w - DisplayWidth(tail)
+	used, end := 0, 0
+	for end < len(s) {
+		n := nextGrapheme(s[end:])
+		gw := graphemeWidth(s[end : end+n])
+		if used+gw > limit {
+			break
+		}
+		used += gw
+		end += n
+	}
+	return s[:end] + tail
+}
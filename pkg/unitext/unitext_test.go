@@ -0,0 +1,17 @@
+package unitext
+
+import (
+	"testing"
+
+	"c03/pkg/prop"
+)
+
+// 性质：按 rune 反转两次得到原字符串，组合符号和 emoji 也不例外
+func TestReverseRunesTwice(t *testing.T) {
+	err := prop.Check(prop.String("abc 中文é😀́"), func(s string) bool {
+		return ReverseRunes(ReverseRunes(s)) == s
+	}, prop.WithRuns(500), prop.WithSeed(22))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ============================================
+// 规则
+// ============================================
+
+// Rule 一条验证规则：名字写进 FieldError.Rule，check 不通过时返回说明
+type Rule struct {
+	name  string
+	check func(v reflect.Value) error
+}
+
+// New 用函数创建规则，用于 Func 无法表达的场合（需要按 reflect.Kind 处理）
+func New(name string, check func(v reflect.Value) error) Rule {
+	return Rule{name: name, check: check}
+}
+
+// Func 把类型化的函数包装成规则；字段类型不是 T 时验证失败
+//
+//	validate.Func("adult", func(age int) error { ... })
+func Func[T any](name string, check func(T) error) Rule {
+	return New(name, func(v reflect.Value) error {
+		t, ok := v.Interface().(T)
+		if !ok {
+			return fmt.Errorf("规则 %s 需要 %T 类型，字段是 %s", name, t, v.Type())
+		}
+		return check(t)
+	})
+}
+
+// When cond 为 true 时才应用 rules，否则直接通过
+func When(cond bool, rules ...Rule) Rule {
+	return New("when", func(v reflect.Value) error {
+		if !cond {
+			return nil
+		}
+		for _, r := range rules {
+			if err := r.check(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NotEmpty 不能是零值；字符串、切片、map 的长度不能为 0
+func NotEmpty() Rule {
+	return New("not_empty", func(v reflect.Value) error {
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			if v.Len() == 0 {
+				return errors.New("不能为空")
+			}
+		default:
+			if v.IsZero() {
+				return errors.New("不能为空")
+			}
+		}
+		return nil
+	})
+}
+
+// Email 有效的邮箱地址（不含显示名，如 "张三 <a@b.c>" 不通过）；空字符串通过
+func Email() Rule {
+	return stringRule("email", func(s string) error {
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return errors.New("不是有效的邮箱地址")
+		}
+		return nil
+	})
+}
+
+// Length 字符数（不是字节数）在 [min, max] 之间；max < 0 表示不限；空字符串通过
+func Length(min, max int) Rule {
+	return stringRule("length", func(s string) error {
+		n := utf8.RuneCountInString(s)
+		switch {
+		case n < min:
+			return fmt.Errorf("至少 %d 个字符", min)
+		case max >= 0 && n > max:
+			return fmt.Errorf("最多 %d 个字符", max)
+		}
+		return nil
+	})
+}
+
+// Matches 匹配正则表达式；空字符串通过
+func Matches(re *regexp.Regexp, message string) Rule {
+	return stringRule("matches", func(s string) error {
+		if !re.MatchString(s) {
+			return errors.New(message)
+		}
+		return nil
+	})
+}
+
+// OneOf 取值必须是 values 之一；空字符串通过
+func OneOf(values ...string) Rule {
+	return stringRule("one_of", func(s string) error {
+		if !slices.Contains(values, s) {
+			return fmt.Errorf("必须是 %q 之一", values)
+		}
+		return nil
+	})
+}
+
+// Between 数值在 [min, max] 之间，适用于整数和浮点数字段
+func Between(min, max float64) Rule {
+	return numberRule("between", func(f float64) error {
+		if f < min || f > max {
+			return fmt.Errorf("必须在 %s 到 %s 之间", fmtNum(min), fmtNum(max))
+		}
+		return nil
+	})
+}
+
+// Min 数值不小于 min
+func Min(min float64) Rule {
+	return numberRule("min", func(f float64) error {
+		if f < min {
+			return fmt.Errorf("不能小于 %s", fmtNum(min))
+		}
+		return nil
+	})
+}
+
+// Max 数值不大于 max
+func Max(max float64) Rule {
+	return numberRule("max", func(f float64) error {
+		if f > max {
+			return fmt.Errorf("不能大于 %s", fmtNum(max))
+		}
+		return nil
+	})
+}
+
+// stringRule 只适用于字符串字段的规则，空字符串直接通过
+func stringRule(name string, check func(s string) error) Rule {
+	return New(name, func(v reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("规则 %s 只适用于字符串，字段是 %s", name, v.Type())
+		}
+		if v.Len() == 0 {
+			return nil
+		}
+		return check(v.String())
+	})
+}
+
+// numberRule 适用于整数和浮点数字段的规则
+func numberRule(name string, check func(f float64) error) Rule {
+	return New(name, func(v reflect.Value) error {
+		var f float64
+		switch {
+		case v.CanInt():
+			f = float64(v.Int())
+		case v.CanUint():
+			f = float64(v.Uint())
+		case v.CanFloat():
+			f = v.Float()
+		default:
+			return fmt.Errorf("规则 %s 只适用于数值，字段是 %s", name, v.Type())
+		}
+		return check(f)
+	})
+}
+
+// fmtNum 整数不带小数点
+func fmtNum(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
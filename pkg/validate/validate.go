@@ -0,0 +1,165 @@
+// ============================================
+// validate 包：流式（代码式）结构体验证
+// ============================================
+//
+// tutorial/09_reflect 的 validateStruct 把规则写在标签里：`validate:"min=0,max=150"`。
+// 标签写起来简单，但规则只能是字符串，没法依赖其他字段，也没法复用 Go 函数。
+// 这里把规则写成代码：
+//
+//   err := validate.Validate(u).
+//       Field("Email", validate.NotEmpty(), validate.Email()).
+//       Field("Age", validate.Between(0, 150)).
+//       When(u.Role == "admin", func(v *validate.Validator) {
+//           v.Field("Phone", validate.NotEmpty())   // 条件规则：只有管理员必须填手机号
+//       }).
+//       Check("Confirm", u.Confirm == u.Password, "两次输入的密码不一致").
+//       Error()
+//
+// 约定：
+//   - 每个字段按顺序检查规则，第一条不通过就停止（NotEmpty 失败时不再报"邮箱格式错误"）
+//   - 格式类规则（Email、Length、Matches、OneOf）遇到空值直接通过，"必填"交给 NotEmpty
+//   - 所有字段都会检查，Error 返回 Errors，包含全部字段的错误；
+//     errors.Is(err, ErrInvalid) 判断是不是验证错误，errors.As 取出 Errors 逐个处理
+//   - 字段名支持点号访问嵌套结构体：Field("Address.City", ...)
+// ============================================
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"c03/pkg/structmeta"
+)
+
+// ErrInvalid 所有验证错误都包装它
+var ErrInvalid = errors.New("验证失败")
+
+// FieldError 一个字段的验证错误，JSON 序列化后可以直接返回给客户端
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Message }
+
+// Unwrap 让 errors.Is(err, ErrInvalid) 成立
+func (e *FieldError) Unwrap() error { return ErrInvalid }
+
+// Errors 多个字段的验证错误，按检查顺序排列
+type Errors []*FieldError
+
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap 让 errors.Is / errors.As 能找到每一个 FieldError
+func (es Errors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// ByField 按字段分组的错误信息，适合表单逐项显示
+func (es Errors) ByField() map[string][]string {
+	m := make(map[string][]string)
+	for _, e := range es {
+		m[e.Field] = append(m[e.Field], e.Message)
+	}
+	return m
+}
+
+// Validator 对一个结构体逐个字段应用规则；不能并发使用
+type Validator struct {
+	v    reflect.Value
+	errs Errors
+}
+
+// Validate 开始验证 s（结构体或结构体指针）
+func Validate(s any) *Validator {
+	v := reflect.ValueOf(s)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return &Validator{v: v}
+}
+
+// Field 对字段 name 依次应用 rules，第一条不通过时记录错误并停止
+// 字段不存在时记录 rule 为 "field" 的错误：通常是字段改名后忘了改这里
+func (v *Validator) Field(name string, rules ...Rule) *Validator {
+	fv, err := v.lookup(name)
+	if err != nil {
+		v.errs = append(v.errs, &FieldError{Field: name, Rule: "field", Message: err.Error()})
+		return v
+	}
+	for _, r := range rules {
+		if err := r.check(fv); err != nil {
+			v.errs = append(v.errs, &FieldError{Field: name, Rule: r.name, Message: err.Error()})
+			break
+		}
+	}
+	return v
+}
+
+// When cond 为 true 时才执行 fn 中的规则
+func (v *Validator) When(cond bool, fn func(v *Validator)) *Validator {
+	if cond {
+		fn(v)
+	}
+	return v
+}
+
+// Check 记录一条跨字段的规则：ok 为 false 时把 message 记到 field 上
+func (v *Validator) Check(field string, ok bool, message string) *Validator {
+	if !ok {
+		v.errs = append(v.errs, &FieldError{Field: field, Rule: "check", Message: message})
+	}
+	return v
+}
+
+// Errors 目前为止记录的全部错误
+func (v *Validator) Errors() Errors { return v.errs }
+
+// Error 没有错误时返回 nil（注意不是 nil 的 Errors，否则 err != nil 恒成立）
+func (v *Validator) Error() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// lookup 按点号分隔的路径取字段，途经的指针自动解引用
+func (v *Validator) lookup(path string) (reflect.Value, error) {
+	cur := v.v
+	for name := range strings.SplitSeq(path, ".") {
+		for cur.Kind() == reflect.Pointer {
+			if cur.IsNil() {
+				return reflect.Value{}, fmt.Errorf("%s 之前的指针为 nil", name)
+			}
+			cur = cur.Elem()
+		}
+		if cur.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("%v 不是结构体，没有字段 %s", cur.Kind(), name)
+		}
+		// 字段下标按类型缓存，每次 Field 调用不用重新遍历结构体
+		st, err := structmeta.Of(cur.Type())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		f, ok := st.Field(name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("没有导出字段 %s", name)
+		}
+		cur = cur.FieldByIndex(f.Index)
+	}
+	return cur, nil
+}
@@ -0,0 +1,180 @@
+// ============================================
+// workpool 包：类型化的 worker pool
+// ============================================
+//
+// tutorial/05 的 Worker Pool 用 jobs / results 两个 channel，结果按完成顺序到达，
+// 出错、取消都要自己处理。Process 把这个模式做成一个泛型函数：
+//
+//   out, err := workpool.Process(ctx, urls, 5, func(ctx context.Context, url string) (int, error) {
+//       ...
+//   })
+//
+//   - out[i] 对应 inputs[i]，与完成顺序无关
+//   - 普通错误只影响自己那一项（out[i] 为零值），其他任务继续；所有错误汇总到 *Errors
+//   - 用 Fatal(err) 包装的错误、fn 中的 panic、ctx 取消会停止分发剩余的任务，
+//     正在执行的任务通过 ctx 得知取消
+//
+// 不使用 jobs channel：任务就是 inputs 的下标，worker 用原子计数器领取下一个下标，
+// 结果直接写到 out[i]（每个下标只有一个 worker 写，不需要加锁）。
+// ============================================
+
+package workpool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPanicked fn panic 时记录的错误，按致命错误处理
+var ErrPanicked = errors.New("workpool: 任务 panic")
+
+// fatalError Fatal 的包装
+type fatalError struct{ err error }
+
+func (e fatalError) Error() string { return e.err.Error() }
+func (e fatalError) Unwrap() error { return e.err }
+
+// Fatal 包装 err：Process 收到它后不再分发剩余的任务
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fatalError{err}
+}
+
+// IsFatal err 是否由 Fatal 包装（或者是任务 panic）
+func IsFatal(err error) bool {
+	var f fatalError
+	return errors.As(err, &f) || errors.Is(err, ErrPanicked)
+}
+
+// JobError 第 Index 个输入处理失败
+type JobError struct {
+	Index int
+	Err   error
+}
+
+func (e *JobError) Error() string { return fmt.Sprintf("第 %d 项: %v", e.Index, e.Err) }
+func (e *JobError) Unwrap() error { return e.Err }
+
+// Errors Process 的汇总错误
+type Errors struct {
+	Jobs    []*JobError // 失败的任务，按 Index 排序
+	Skipped int         // 因致命错误或 ctx 取消而没有执行的任务数
+	Cause   error       // 停止分发的原因：致命错误或 context.Cause(ctx)；全部执行完时为 nil
+}
+
+func (e *Errors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d 项失败", len(e.Jobs))
+	if e.Skipped > 0 {
+		fmt.Fprintf(&b, "，%d 项未执行", e.Skipped)
+	}
+	for i, j := range e.Jobs {
+		if i == 3 {
+			fmt.Fprintf(&b, "; ...（共 %d 项）", len(e.Jobs))
+			break
+		}
+		b.WriteString("; ")
+		b.WriteString(j.Error())
+	}
+	if e.Cause != nil && !e.causedByJob() {
+		fmt.Fprintf(&b, "; 停止原因: %v", e.Cause)
+	}
+	return b.String()
+}
+
+// causedByJob Cause 是否就是某个任务的错误（已经在列表里输出过）
+func (e *Errors) causedByJob() bool {
+	for _, j := range e.Jobs {
+		if j.Err == e.Cause {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap 让 errors.Is / errors.As 能找到每个任务的错误和停止原因
+func (e *Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Jobs)+1)
+	for _, j := range e.Jobs {
+		errs = append(errs, j)
+	}
+	if e.Cause != nil && !e.causedByJob() {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+// Process 用 workers 个 goroutine 对每个输入调用 fn，out[i] 是 inputs[i] 的结果
+// workers <= 0 时为 1。有错误时返回 *Errors，out 仍然包含成功的结果
+func Process[I, O any](ctx context.Context, inputs []I, workers int, fn func(ctx context.Context, in I) (O, error)) ([]O, error) {
+	out := make([]O, len(inputs))
+	if len(inputs) == 0 {
+		return out, nil
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		next    atomic.Int64 // 下一个要领取的下标
+		started atomic.Int64 // 实际执行的任务数
+		mu      sync.Mutex
+		jobErrs []*JobError
+	)
+	record := func(i int, err error) {
+		mu.Lock()
+		jobErrs = append(jobErrs, &JobError{Index: i, Err: err})
+		mu.Unlock()
+		if IsFatal(err) {
+			cancel(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), len(inputs)) {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(inputs) {
+					return
+				}
+				started.Add(1)
+				v, err := call(ctx, fn, inputs[i])
+				if err != nil {
+					record(i, err)
+					continue
+				}
+				out[i] = v
+			}
+		})
+	}
+	wg.Wait()
+
+	skipped := len(inputs) - int(started.Load())
+	if len(jobErrs) == 0 && skipped == 0 {
+		return out, nil
+	}
+	errs := &Errors{Jobs: jobErrs, Skipped: skipped}
+	if ctx.Err() != nil {
+		errs.Cause = context.Cause(ctx)
+	}
+	slices.SortFunc(errs.Jobs, func(a, b *JobError) int { return cmp.Compare(a.Index, b.Index) })
+	return out, errs
+}
+
+// call 调用 fn，把 panic 转成致命错误
+func call[I, O any](ctx context.Context, fn func(context.Context, I) (O, error), in I) (v O, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+	return fn(ctx, in)
+}
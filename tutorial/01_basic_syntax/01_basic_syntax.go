@@ -16,56 +16,98 @@
 // 4. 错误处理优先返回 error，而非使用异常机制
 // ============================================
 
-package main
+package basicsyntax
+
+//go:generate go run c03/cmd/enumgen -type=Weekday,Permission -flags=Permission
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
-	"github.com/google/uuid"
+	"c03/pkg/sliceutil"
+	"c03/tutorial"
 )
 
-type StudentID string
-type StudentInfo struct {
-	studentID StudentID
-	name      string
-	score     float32
-}
-type StudentMap map[StudentID]StudentInfo
+// Weekday 和 Permission 是带类型的 iota 枚举（见第 2 节）
+// String / MarshalJSON / ParseWeekday 等方法由 cmd/enumgen 生成在 01_basic_syntax_enum.go 中，
+// 修改常量后执行 go generate ./tutorial/01_basic_syntax 重新生成。
+type Weekday int
+
+const (
+	Monday    Weekday = iota // 0
+	Tuesday                  // 1
+	Wednesday                // 2
+	Thursday                 // 3
+	Friday                   // 4
+	Saturday                 // 5
+	Sunday                   // 6
+)
 
-func (sm StudentMap) AddStudent(id StudentID, info StudentInfo) {
-	sm[id] = info
-}
+// Permission 权限位，可以用 | 组合
+type Permission uint8
 
-func (sm StudentMap) PrintAll() {
-	for k, v := range sm {
-		fmt.Println("key:", k, ", value:", v)
-	}
+const (
+	Read    Permission = 1 << iota // 1 (0001)
+	Write                          // 2 (0010)
+	Execute                        // 4 (0100)
+)
+
+// ============================================
+// 入口
+// ============================================
+
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "01",
+		Name:  "01_basic_syntax",
+		Title: "基础语法：变量、常量与 iota、类型、控制流、切片、Map",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "1", Title: "成绩册：添加、查询、平均分、删除低分", Check: checkGradebook},
+			{ID: "2", Title: "切片去重", Check: checkDedup},
+			{ID: "3", Title: "用 iota 定义 Unix 文件权限", Check: checkFileMode},
+			{ID: "4", Title: "切片的最大值和最小值", Check: checkMinMax},
+			{ID: "5", Title: "猜数字游戏", Check: checkGuessGame},
+		},
+	})
 }
 
-func (sm StudentMap) AverageScore() float32 {
-	smNum := len(sm)
-	if smNum == 0 {
-		return 0.0
+// checkDedup 检查练习 2：保留第一次出现的顺序，不修改原切片
+func checkDedup() error {
+	in := []int{3, 1, 3, 2, 1, 3}
+	got := sliceutil.Dedup(in)
+	if !slices.Equal(got, []int{3, 1, 2}) {
+		return fmt.Errorf("Dedup(%v) = %v，期望 [3 1 2]", in, got)
 	}
-
-	// average
-	var sumSocre float32 = 0.0
-	for _, v := range sm {
-		sumSocre += v.score
+	if !slices.Equal(in, []int{3, 1, 3, 2, 1, 3}) {
+		return fmt.Errorf("Dedup 修改了原切片: %v", in)
+	}
+	if got := sliceutil.Dedup([]string{}); len(got) != 0 {
+		return fmt.Errorf("Dedup(空切片) = %v", got)
 	}
-	var avgScore float32 = sumSocre / float32(smNum)
-	return avgScore
+	return nil
 }
 
-func (sm StudentMap) RemoveUndergradeStudent() {
-	for k, v := range sm {
-		if v.score < 60.0 {
-			delete(sm, k)
-		}
+// checkMinMax 检查练习 4
+func checkMinMax() error {
+	if lo, hi, err := sliceutil.MinMax([]int{4, -2, 9, 0}); lo != -2 || hi != 9 || err != nil {
+		return fmt.Errorf("MinMax([4 -2 9 0]) = %d, %d, %v，期望 -2, 9, nil", lo, hi, err)
 	}
+	if lo, hi, err := sliceutil.MinMax([]string{"go", "c", "rust"}); lo != "c" || hi != "rust" || err != nil {
+		return fmt.Errorf("MinMax([go c rust]) = %q, %q, %v，期望 \"c\", \"rust\", nil", lo, hi, err)
+	}
+	if _, _, err := sliceutil.MinMax[int](nil); !errors.Is(err, sliceutil.ErrEmpty) {
+		return fmt.Errorf("MinMax(nil) 返回 %v，期望 ErrEmpty", err)
+	}
+	return nil
 }
 
-func main() {
+// Run 运行本课的全部示例：go run ./cmd/tutorial 01
+func Run() {
 	// ============================================
 	// 1. 变量声明
 	// ============================================
@@ -99,26 +141,35 @@ func main() {
 	// iota 是常量计数器，从 0 开始，每行递增 1
 
 	const Pi = 3.14159
-	const (
-		Monday    = iota // 0
-		Tuesday          // 1
-		Wednesday        // 2
-		Thursday         // 3
-		Friday           // 4
-		Saturday         // 5
-		Sunday           // 6
-	)
 
-	// iota 技巧：位运算定义权限
-	const (
-		Read    = 1 << iota // 1 (0001)
-		Write               // 2 (0010)
-		Execute             // 4 (0100)
-	)
+	// 星期（Weekday）和权限位（Permission）定义在文件开头：
+	// 给 iota 常量一个具名类型，才能为它定义 String 等方法
+	//   Monday = iota       -> 0, 1, 2, ...
+	//   Read   = 1 << iota  -> 1, 2, 4（iota 技巧：位运算定义权限）
 
 	fmt.Printf("Monday=%d, Sunday=%d\n", Monday, Sunday)
 	fmt.Printf("Read=%d, Write=%d, Execute=%d\n", Read, Write, Execute)
 
+	// 生成的 String 让 %v 输出名字而不是数字
+	fmt.Printf("%v %v, 未定义的值: %v\n", Monday, Sunday, Weekday(9))
+	rw := Read | Write
+	fmt.Printf("Read|Write=%d -> %v\n", rw, rw)
+
+	// JSON 中也使用名字，调整常量顺序不会改变已保存数据的含义
+	type schedule struct {
+		Day  Weekday    `json:"day"`
+		Perm Permission `json:"perm"`
+	}
+	data, _ := json.Marshal(schedule{Day: Friday, Perm: rw | Execute})
+	fmt.Printf("JSON: %s\n", data)
+	var sch schedule
+	if err := json.Unmarshal([]byte(`{"day":"saturday","perm":"Read|Execute"}`), &sch); err == nil {
+		fmt.Printf("解析 JSON: %v %v\n", sch.Day, sch.Perm)
+	}
+	if _, err := ParseWeekday("Funday"); err != nil {
+		fmt.Println("ParseWeekday:", err)
+	}
+
 	// ============================================
 	// 3. 基本数据类型
 	// ============================================
@@ -347,44 +398,29 @@ func main() {
 	//   - 查询某个学生的分数
 	//   - 计算平均分
 	//   - 删除分数低于 60 分的学生
-	var studentMap StudentMap = StudentMap{}
-
-	var id StudentID = StudentID(uuid.New().String())
-	studentMap.AddStudent(id, StudentInfo{
-		studentID: id,
-		name:      "Jim",
-		score:     80.0,
-	})
-
-	id = StudentID(uuid.New().String())
-	studentMap.AddStudent(id, StudentInfo{
-		studentID: id,
-		name:      "Jack",
-		score:     90.0,
-	})
-
-	id = StudentID(uuid.New().String())
-	studentMap.AddStudent(id, StudentInfo{
-		studentID: id,
-		name:      "Jeacy",
-		score:     98.0,
-	})
-
-	id = StudentID(uuid.New().String())
-	studentMap.AddStudent(id, StudentInfo{
-		studentID: id,
-		name:      "Junck",
-		score:     59.0,
-	})
-
-	studentMap.PrintAll()
-	avgScore := studentMap.AverageScore()
-	fmt.Println("average score:", avgScore)
-
-	studentMap.RemoveUndergradeStudent()
-	studentMap.PrintAll()
+	//
+	// 实现见 gradebook.go
+	book := NewGradebook()
+	for _, g := range []Grade{{"Jim", 80}, {"Jack", 90}, {"Jeacy", 98}, {"Junck", 59}} {
+		book.Add(g.Name, g.Score)
+	}
+	if err := book.Add("Bad", 120); err != nil {
+		fmt.Println("Add:", err)
+	}
+	if score, ok := book.Score("Jack"); ok {
+		fmt.Println("Jack:", score)
+	}
+	avgScore, _ := book.Average()
+	fmt.Printf("average score: %.2f\n", avgScore)
+	lowest, highest, _ := book.MinMax()
+	fmt.Println("lowest:", lowest, ", highest:", highest)
+	fmt.Println("dropped:", book.DropBelow(60))
+	for name, score := range book.All() {
+		fmt.Println(name, score)
+	}
 
-	var tmpMap StudentMap
+	// 读取 nil map 是安全的，返回零值；写入 nil map 会 panic
+	var tmpMap map[string]float64
 	fmt.Println("tmpMap:", len(tmpMap))
 	v, ok := tmpMap["xxx"]
 	if ok {
@@ -407,47 +443,8 @@ func main() {
 		fmt.Println("idx:", idx, " v:", v)
 	}
 	fmt.Println("====================")
-	// ContainsFunc := func(targetVal int, tmpSlice []int) bool {
-	// 	for _, v := range tmpSlice {
-	// 		if targetVal == v {
-	// 			return true
-	// 		}
-	// 	}
-	// 	return false
-	// }
-	// RemoveDumplicates := func(intSlice []int) []int {
-	// 	uniqueSlice := make([]int, 0, cap(intSlice))
-
-	// 	// early check
-	// 	if len(intSlice) == 0 {
-	// 		return uniqueSlice
-	// 	}
-
-	// 	// push back the very first
-	// 	uniqueSlice = append(uniqueSlice, intSlice[0])
-
-	// 	// from 1st to then end
-	// 	for _, v := range intSlice[1:] {
-	// 		if !ContainsFunc(v, uniqueSlice) {
-	// 			uniqueSlice = append(uniqueSlice, v)
-	// 		}
-	// 	}
-
-	// 	return uniqueSlice
-	// }
-	RemoveDumplicatesByMap := func(intSlice []int) []int {
-		intMap := make(map[int]bool, len(intSlice))
-		newSlice := make([]int, 0, len(intSlice))
-		for _, v := range intSlice {
-			if !intMap[v] {
-				intMap[v] = true
-				newSlice = append(newSlice, v)
-			}
-		}
-		return newSlice
-	}
-	// uniqueSlice := RemoveDumplicates(intSlice)
-	uniqueSlice := RemoveDumplicatesByMap(intSlice)
+	// 实现见 pkg/sliceutil：用 map 记录见过的元素，保留第一次出现的顺序
+	uniqueSlice := sliceutil.Dedup(intSlice)
 	for idx, v := range uniqueSlice {
 		fmt.Println("idx:", idx, " v:", v)
 	}
@@ -464,51 +461,33 @@ func main() {
 		WRITE                       //0010
 		READAndWrite = READ | WRITE //0011
 	)
+	// 实现见 filemode.go：九个权限位与 Unix 的八进制权限一致
+	mode := OwnerAll | GroupRead | GroupExecute | OtherRead
+	fmt.Printf("mode=%#o %v\n", uint16(mode), mode)
+	if m, err := ParseFileMode("rw-r--r--"); err == nil {
+		fmt.Printf("ParseFileMode(\"rw-r--r--\") = %#o\n", uint16(m))
+	}
+	if _, err := ParseFileMode("rwz------"); err != nil {
+		fmt.Println("ParseFileMode:", err)
+	}
 	// 练习 4：编写函数找出切片中的最大值和最小值
 	//   func findMinMax(nums []int) (min, max int)
 	//
-	findMinAndMax := func(mSlice []int) (min, max int) {
-		if len(mSlice) == 0 {
-			return 0, 0
-		}
-		max = mSlice[0]
-		min = mSlice[0]
-		for _, v := range mSlice {
-			if v > max {
-				max = v
-			}
-			if v < min {
-				min = v
-			}
-		}
-		return min, max
-	}
-	min, max := findMinAndMax(intSlice)
+	// 实现见 pkg/sliceutil：空切片没有最值，返回错误而不是 0, 0
+	min, max, err := sliceutil.MinMax(intSlice)
+	if err != nil {
+		fmt.Println("MinMax:", err)
+	}
 	fmt.Println("min:", min, ", max:", max)
 	// 练习 5：实现一个简单的猜数字游戏
 	//   - 随机生成 1-100 的数字
 	//   - 用户输入猜测，程序提示"太大"或"太小"
 	//   - 使用循环直到猜对
-	guessNum := func(targetVal int) {
-		var inputNum int
-		bingo := false
-		for !bingo {
-			fmt.Println("please guess a number:")
-			_, err := fmt.Scan(&inputNum)
-			if err != nil {
-				fmt.Println("input data error")
-				break
-			}
-			switch {
-			case inputNum == targetVal:
-				fmt.Println("bingo")
-				bingo = true
-			case inputNum > targetVal:
-				fmt.Println("too big")
-			case inputNum < targetVal:
-				fmt.Println("too small")
-			}
-		}
-	}
-	guessNum(24)
+	//
+	// 实现见 guess.go；这里用固定输入自动玩一局，交互式游戏运行：
+	//   go run ./cmd/guess -max 7
+	game := GuessGame{Lo: 1, Hi: 100}
+	input := "50 abc 25 12 18 21 23 24"
+	fmt.Println("自动输入:", input)
+	game.Play(24, strings.NewReader(strings.ReplaceAll(input, " ", "\n")), os.Stdout)
 }
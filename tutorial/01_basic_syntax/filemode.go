@@ -0,0 +1,107 @@
+// ============================================
+// 练习 3：Unix 风格的文件权限
+// ============================================
+//
+// 九个权限位从低到高依次是 other、group、owner 的 x w r，
+// 用 1 << iota 定义后正好与八进制写法一致：0o754 = rwxr-xr--
+//
+//   owner  group  other
+//   r w x  r w x  r w x
+//   4 2 1  4 2 1  4 2 1
+//
+// 与第 2 节的 Permission 不同，这里每一位同时编码了"谁"和"什么权限"，
+// 可以直接与 os.FileMode 的低 9 位互相转换。
+// ============================================
+
+package basicsyntax
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// FileMode 文件权限位
+type FileMode uint16
+
+const (
+	OtherExecute FileMode = 1 << iota // 0o001
+	OtherWrite                        // 0o002
+	OtherRead                         // 0o004
+	GroupExecute                      // 0o010
+	GroupWrite                        // 0o020
+	GroupRead                         // 0o040
+	OwnerExecute                      // 0o100
+	OwnerWrite                        // 0o200
+	OwnerRead                         // 0o400
+)
+
+// 常用组合
+const (
+	OwnerAll = OwnerRead | OwnerWrite | OwnerExecute // rwx------
+	GroupAll = GroupRead | GroupWrite | GroupExecute // ---rwx---
+	OtherAll = OtherRead | OtherWrite | OtherExecute // ------rwx
+)
+
+// ErrInvalidMode 无法解析的权限字符串
+var ErrInvalidMode = errors.New("无效的权限字符串")
+
+// modeChars 从最高位（OwnerRead）到最低位的字符
+const modeChars = "rwxrwxrwx"
+
+// String 以 ls -l 的格式输出，如 "rwxr-xr--"
+func (m FileMode) String() string {
+	var buf [9]byte
+	for i := range buf {
+		if m&(OwnerRead>>i) != 0 {
+			buf[i] = modeChars[i]
+		} else {
+			buf[i] = '-'
+		}
+	}
+	return string(buf[:])
+}
+
+// ParseFileMode 解析 "rw-r--r--" 形式的字符串
+// 也接受 ls -l 输出的 10 个字符（第一个字符是文件类型，如 "-rw-r--r--"）
+func ParseFileMode(s string) (FileMode, error) {
+	if len(s) == 10 {
+		s = s[1:]
+	}
+	if len(s) != 9 {
+		return 0, fmt.Errorf("%w %q：需要 9 个字符", ErrInvalidMode, s)
+	}
+	var m FileMode
+	for i := range 9 {
+		switch s[i] {
+		case modeChars[i]:
+			m |= OwnerRead >> i
+		case '-':
+		default:
+			return 0, fmt.Errorf("%w %q：第 %d 个字符应为 %c 或 -", ErrInvalidMode, s, i+1, modeChars[i])
+		}
+	}
+	return m, nil
+}
+
+// checkFileMode 检查练习 3：与标准库 os.FileMode 的输出逐一比较
+func checkFileMode() error {
+	if got := OwnerAll | GroupRead | GroupExecute | OtherRead; got != 0o754 || got.String() != "rwxr-xr--" {
+		return fmt.Errorf("Owner rwx、Group r-x、Other r-- = %#o %s，期望 0754 rwxr-xr--", uint16(got), got)
+	}
+	for m := FileMode(0); m <= 0o777; m++ {
+		// os.FileMode 的 String 在权限前有一个文件类型字符，普通文件是 '-'
+		if want := os.FileMode(m).String()[1:]; m.String() != want {
+			return fmt.Errorf("FileMode(%#o).String() = %s，期望 %s", uint16(m), m, want)
+		}
+		if got, err := ParseFileMode(m.String()); err != nil || got != m {
+			return fmt.Errorf("ParseFileMode(%q) = %#o, %v，期望 %#o", m.String(), uint16(got), err, uint16(m))
+		}
+	}
+	for _, bad := range []string{"", "rwx", "rwxrwxrwz", "wrxrwxrwx"} {
+		if _, err := ParseFileMode(bad); !errors.Is(err, ErrInvalidMode) {
+			return fmt.Errorf("ParseFileMode(%q) 返回 %v，期望 ErrInvalidMode", bad, err)
+		}
+	}
+	return nil
+}
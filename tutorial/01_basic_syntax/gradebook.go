@@ -0,0 +1,165 @@
+// ============================================
+// 练习 1：成绩册
+// ============================================
+//
+// 用 map[姓名]分数 保存成绩。map 的遍历顺序是随机的（Go 故意打乱，防止依赖顺序），
+// 所以需要稳定输出的方法（All、Ranked、DropBelow 的返回值）都先排序。
+// ============================================
+
+package basicsyntax
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+)
+
+var (
+	ErrInvalidScore   = errors.New("分数必须在 0-100 之间")
+	ErrEmptyGradebook = errors.New("成绩册为空")
+)
+
+// Grade 一个学生的成绩
+type Grade struct {
+	Name  string
+	Score float64
+}
+
+// Gradebook 成绩册，零值不可用，使用 NewGradebook 创建；不是并发安全的
+type Gradebook struct {
+	scores map[string]float64
+}
+
+// NewGradebook 创建空的成绩册
+func NewGradebook() *Gradebook {
+	return &Gradebook{scores: make(map[string]float64)}
+}
+
+// Add 添加学生，已存在时更新分数
+func (g *Gradebook) Add(name string, score float64) error {
+	if math.IsNaN(score) || score < 0 || score > 100 {
+		return fmt.Errorf("%w: %s 的分数为 %v", ErrInvalidScore, name, score)
+	}
+	g.scores[name] = score
+	return nil
+}
+
+// Score 查询分数，ok 为 false 表示没有这个学生
+// 与 map 的 v, ok := m[k] 写法一致：不存在时分数是零值 0，不能据此判断
+func (g *Gradebook) Score(name string) (score float64, ok bool) {
+	score, ok = g.scores[name]
+	return score, ok
+}
+
+// Len 学生人数
+func (g *Gradebook) Len() int { return len(g.scores) }
+
+// Average 平均分
+func (g *Gradebook) Average() (float64, error) {
+	if len(g.scores) == 0 {
+		return 0, ErrEmptyGradebook
+	}
+	sum := 0.0
+	for _, s := range g.scores {
+		sum += s
+	}
+	return sum / float64(len(g.scores)), nil
+}
+
+// MinMax 最低分和最高分；分数相同时取姓名排在前面的学生，保证结果稳定
+func (g *Gradebook) MinMax() (lowest, highest Grade, err error) {
+	ranked := g.Ranked()
+	if len(ranked) == 0 {
+		return Grade{}, Grade{}, ErrEmptyGradebook
+	}
+	lowest = ranked[len(ranked)-1]
+	// Ranked 中同分按姓名升序，最低分要取同分中姓名最小的
+	for i := len(ranked) - 2; i >= 0 && ranked[i].Score == lowest.Score; i-- {
+		lowest = ranked[i]
+	}
+	return lowest, ranked[0], nil
+}
+
+// DropBelow 删除分数低于 threshold 的学生，返回被删除的姓名（已排序）
+func (g *Gradebook) DropBelow(threshold float64) []string {
+	var dropped []string
+	// 遍历 map 时删除当前元素是安全的
+	for name, s := range g.scores {
+		if s < threshold {
+			delete(g.scores, name)
+			dropped = append(dropped, name)
+		}
+	}
+	slices.Sort(dropped)
+	return dropped
+}
+
+// All 按姓名顺序遍历所有学生
+func (g *Gradebook) All() iter.Seq2[string, float64] {
+	return func(yield func(string, float64) bool) {
+		for _, name := range slices.Sorted(maps.Keys(g.scores)) {
+			if !yield(name, g.scores[name]) {
+				return
+			}
+		}
+	}
+}
+
+// Ranked 按分数从高到低排列，同分按姓名升序
+func (g *Gradebook) Ranked() []Grade {
+	out := make([]Grade, 0, len(g.scores))
+	for name, s := range g.scores {
+		out = append(out, Grade{name, s})
+	}
+	slices.SortFunc(out, func(a, b Grade) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// checkGradebook 检查练习 1
+func checkGradebook() error {
+	g := NewGradebook()
+	for _, s := range []Grade{{"Jim", 80}, {"Jack", 90}, {"Jeacy", 98}, {"Junck", 59}, {"Amy", 59}} {
+		if err := g.Add(s.Name, s.Score); err != nil {
+			return err
+		}
+	}
+	if err := g.Add("Bad", 101); !errors.Is(err, ErrInvalidScore) {
+		return fmt.Errorf("Add(Bad, 101) 返回 %v，期望 ErrInvalidScore", err)
+	}
+	if s, ok := g.Score("Jack"); !ok || s != 90 {
+		return fmt.Errorf("Score(Jack) = %v, %v，期望 90, true", s, ok)
+	}
+	if _, ok := g.Score("Nobody"); ok {
+		return errors.New("Score(Nobody) 的 ok 应为 false")
+	}
+	if avg, _ := g.Average(); avg != 77.2 {
+		return fmt.Errorf("Average() = %v，期望 77.2", avg)
+	}
+	lo, hi, _ := g.MinMax()
+	if lo != (Grade{"Amy", 59}) || hi != (Grade{"Jeacy", 98}) {
+		return fmt.Errorf("MinMax() = %v, %v，期望 {Amy 59}, {Jeacy 98}", lo, hi)
+	}
+	if dropped := g.DropBelow(60); !slices.Equal(dropped, []string{"Amy", "Junck"}) {
+		return fmt.Errorf("DropBelow(60) = %v，期望 [Amy Junck]", dropped)
+	}
+	var names []string
+	for name := range g.All() {
+		names = append(names, name)
+	}
+	if !slices.Equal(names, []string{"Jack", "Jeacy", "Jim"}) {
+		return fmt.Errorf("All() 顺序为 %v，期望 [Jack Jeacy Jim]", names)
+	}
+	if _, err := NewGradebook().Average(); !errors.Is(err, ErrEmptyGradebook) {
+		return fmt.Errorf("空成绩册的 Average() 返回 %v，期望 ErrEmptyGradebook", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package basicsyntax
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func newTestGradebook(t *testing.T, grades ...Grade) *Gradebook {
+	t.Helper()
+	g := NewGradebook()
+	for _, s := range grades {
+		if err := g.Add(s.Name, s.Score); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestGradebookAddScore(t *testing.T) {
+	g := newTestGradebook(t, Grade{"Jim", 80})
+	for _, bad := range []float64{-1, 100.5, math.NaN(), math.Inf(1)} {
+		if err := g.Add("Bad", bad); !errors.Is(err, ErrInvalidScore) {
+			t.Errorf("Add(Bad, %v) 返回 %v，期望 ErrInvalidScore", bad, err)
+		}
+	}
+	for _, ok := range []float64{0, 100} {
+		if err := g.Add("Edge", ok); err != nil {
+			t.Errorf("Add(Edge, %v) 失败: %v", ok, err)
+		}
+	}
+
+	// 已存在时更新分数
+	if err := g.Add("Jim", 85); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := g.Score("Jim"); !ok || s != 85 {
+		t.Errorf("Score(Jim) = %v, %v，期望 85, true", s, ok)
+	}
+	if s, ok := g.Score("Nobody"); ok || s != 0 {
+		t.Errorf("Score(Nobody) = %v, %v，期望 0, false", s, ok)
+	}
+	if g.Len() != 2 {
+		t.Errorf("Len() = %d，期望 2", g.Len())
+	}
+}
+
+func TestGradebookStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		grades  []Grade
+		avg     float64
+		lo, hi  Grade
+		wantErr error
+	}{
+		{name: "空", wantErr: ErrEmptyGradebook},
+		{name: "一人", grades: []Grade{{"Amy", 70}}, avg: 70, lo: Grade{"Amy", 70}, hi: Grade{"Amy", 70}},
+		{
+			name:   "同分取姓名在前的",
+			grades: []Grade{{"Jim", 80}, {"Jack", 90}, {"Jeacy", 98}, {"Junck", 59}, {"Amy", 59}, {"Zed", 98}},
+			avg:    80.66666666666667, lo: Grade{"Amy", 59}, hi: Grade{"Jeacy", 98},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGradebook(t, tt.grades...)
+			avg, err := g.Average()
+			if !errors.Is(err, tt.wantErr) || math.Abs(avg-tt.avg) > 1e-9 {
+				t.Errorf("Average() = %v, %v，期望 %v, %v", avg, err, tt.avg, tt.wantErr)
+			}
+			// 多次调用结果相同：不依赖 map 的遍历顺序
+			for range 20 {
+				lo, hi, err := g.MinMax()
+				if !errors.Is(err, tt.wantErr) || lo != tt.lo || hi != tt.hi {
+					t.Fatalf("MinMax() = %v, %v, %v，期望 %v, %v, %v", lo, hi, err, tt.lo, tt.hi, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestGradebookDropBelow(t *testing.T) {
+	g := newTestGradebook(t, Grade{"Jim", 80}, Grade{"Jack", 90}, Grade{"Junck", 59}, Grade{"Amy", 59}, Grade{"Bob", 60})
+	if dropped := g.DropBelow(60); !slices.Equal(dropped, []string{"Amy", "Junck"}) {
+		t.Fatalf("DropBelow(60) = %v，期望 [Amy Junck]", dropped)
+	}
+	if _, ok := g.Score("Bob"); !ok {
+		t.Fatal("等于阈值的 Bob 不应被删除")
+	}
+	if dropped := g.DropBelow(0); dropped != nil {
+		t.Fatalf("DropBelow(0) = %v，期望不删除任何人", dropped)
+	}
+	if dropped := g.DropBelow(101); !slices.Equal(dropped, []string{"Bob", "Jack", "Jim"}) || g.Len() != 0 {
+		t.Fatalf("DropBelow(101) = %v，Len() = %d，期望删除全部", dropped, g.Len())
+	}
+}
+
+func TestGradebookSortedIteration(t *testing.T) {
+	g := newTestGradebook(t, Grade{"Cid", 70}, Grade{"Amy", 90}, Grade{"Bob", 90}, Grade{"Dan", 50})
+	for range 20 {
+		var names []string
+		for name, score := range g.All() {
+			names = append(names, name)
+			if s, _ := g.Score(name); s != score {
+				t.Fatalf("All() 给出 %s 的分数 %v，期望 %v", name, score, s)
+			}
+		}
+		if !slices.Equal(names, []string{"Amy", "Bob", "Cid", "Dan"}) {
+			t.Fatalf("All() 顺序 %v，期望按姓名排序", names)
+		}
+	}
+
+	// 提前 break 时停止遍历
+	count := 0
+	for range g.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatalf("break 后仍继续遍历了 %d 个", count)
+	}
+
+	want := []Grade{{"Amy", 90}, {"Bob", 90}, {"Cid", 70}, {"Dan", 50}}
+	if got := g.Ranked(); !slices.Equal(got, want) {
+		t.Fatalf("Ranked() = %v，期望 %v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+// ============================================
+// 练习 5：猜数字游戏
+// ============================================
+//
+// 输入输出通过 io.Reader / io.Writer 传入，而不是直接读 os.Stdin：
+// 交互式运行时传 os.Stdin / os.Stdout（见 cmd/guess），
+// 课程演示和练习检查时传 strings.Reader，用固定的输入"自动玩"一局。
+// ============================================
+
+package basicsyntax
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// ErrAborted 猜中之前输入就结束了（如 Ctrl+D）
+var ErrAborted = errors.New("游戏中止：输入已结束")
+
+// GuessGame 猜数字的规则
+type GuessGame struct {
+	Lo, Hi      int // 数字范围，包含两端
+	MaxAttempts int // 最多猜几次，0 表示不限
+}
+
+// GuessResult 一局的结果
+type GuessResult struct {
+	Target   int
+	Attempts int // 有效的猜测次数，输错的不算
+	Won      bool
+}
+
+// RandomTarget 在范围内随机选一个数
+func (g GuessGame) RandomTarget() int {
+	return g.Lo + rand.IntN(g.Hi-g.Lo+1)
+}
+
+// Play 从 in 逐行读取猜测，直到猜中、用完次数或输入结束
+// 不是整数或超出范围的输入会提示重新输入，不计入次数
+func (g GuessGame) Play(target int, in io.Reader, out io.Writer) (GuessResult, error) {
+	res := GuessResult{Target: target}
+	sc := bufio.NewScanner(in)
+	for g.MaxAttempts == 0 || res.Attempts < g.MaxAttempts {
+		if g.MaxAttempts > 0 {
+			fmt.Fprintf(out, "请输入 %d-%d 之间的数字（还剩 %d 次）: ", g.Lo, g.Hi, g.MaxAttempts-res.Attempts)
+		} else {
+			fmt.Fprintf(out, "请输入 %d-%d 之间的数字: ", g.Lo, g.Hi)
+		}
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			if err := sc.Err(); err != nil {
+				return res, err
+			}
+			return res, ErrAborted
+		}
+		line := strings.TrimSpace(sc.Text())
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			fmt.Fprintf(out, "%q 不是整数，请重新输入\n", line)
+			continue
+		}
+		if n < g.Lo || n > g.Hi {
+			fmt.Fprintf(out, "%d 超出范围，请重新输入\n", n)
+			continue
+		}
+
+		res.Attempts++
+		switch {
+		case n > target:
+			fmt.Fprintln(out, "太大了")
+		case n < target:
+			fmt.Fprintln(out, "太小了")
+		default:
+			res.Won = true
+			fmt.Fprintf(out, "猜对了！答案是 %d，共猜了 %d 次\n", target, res.Attempts)
+			return res, nil
+		}
+	}
+	fmt.Fprintf(out, "次数用完了，答案是 %d\n", target)
+	return res, nil
+}
+
+// checkGuessGame 检查练习 5：用固定输入玩两局，验证计数、重试和次数限制
+func checkGuessGame() error {
+	g := GuessGame{Lo: 1, Hi: 100}
+	// 错误输入和超出范围的输入不计入次数
+	res, err := g.Play(24, strings.NewReader("abc\n50\n0\n25\n24\n"), io.Discard)
+	if err != nil {
+		return err
+	}
+	if !res.Won || res.Attempts != 3 {
+		return fmt.Errorf("输入 abc 50 0 25 24：得到 Won=%v Attempts=%d，期望 Won=true Attempts=3", res.Won, res.Attempts)
+	}
+
+	g.MaxAttempts = 2
+	res, err = g.Play(24, strings.NewReader("50\n10\n24\n"), io.Discard)
+	if err != nil {
+		return err
+	}
+	if res.Won || res.Attempts != 2 {
+		return fmt.Errorf("最多 2 次：得到 Won=%v Attempts=%d，期望 Won=false Attempts=2", res.Won, res.Attempts)
+	}
+
+	if _, err := g.Play(24, strings.NewReader("50\n"), io.Discard); !errors.Is(err, ErrAborted) {
+		return fmt.Errorf("输入提前结束：得到错误 %v，期望 ErrAborted", err)
+	}
+	return nil
+}
@@ -18,16 +18,26 @@
 // 2. 多返回值时，error 通常作为最后一个返回值
 // 3. 使用命名返回值提高可读性，但要避免滥用
 // 4. defer 常用于资源清理（关闭文件、解锁等）
-// 5. 避免在热路径（hot path）中使用 defer（Go 1.14 后性能已改善）
+// 5. 避免在热路径（hot path）的循环中使用 defer（Go 1.14 后普通 defer 几乎没有开销，
+//    循环中的 defer 仍有分配，数据见 go run ./cmd/microbench）
 // ============================================
 
-package main
+package functions
 
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"c03/pkg/memo"
+	"c03/pkg/sliceutil"
+	"c03/pkg/stats"
+	"c03/pkg/timing"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -54,6 +64,16 @@ func add(a, b int) int {
 	return a + b
 }
 
+// Subtract 返回 a - b
+func Subtract(a, b int) int {
+	return a - b
+}
+
+// Multiply 返回 a * b，溢出时静默回绕，需要检查溢出时用 SafeMultiply
+func Multiply(a, b int) int {
+	return a * b
+}
+
 // ============================================
 // 2. 多返回值 ⭐ Go 的重要特性
 // ============================================
@@ -71,6 +91,58 @@ func divide(dividend, divisor int) (quotient, remainder int, err error) {
 	return quotient, remainder, nil // 命名返回值可以直接使用
 }
 
+// ErrOverflow 整数运算溢出
+// Go 的整数运算溢出时不会报错，而是静默回绕（wrap around）：
+// math.MaxInt + 1 == math.MinInt。需要安全运算时必须自己检查
+var ErrOverflow = errors.New("整数溢出")
+
+// SafeAdd 溢出时返回错误
+// 同号相加结果却变号，说明发生了溢出
+func SafeAdd(a, b int) (int, error) {
+	c := a + b
+	if (a > 0 && b > 0 && c < 0) || (a < 0 && b < 0 && c >= 0) {
+		return 0, fmt.Errorf("%d + %d: %w", a, b, ErrOverflow)
+	}
+	return c, nil
+}
+
+// SafeMultiply 溢出时返回错误
+// 用除法反推：没有溢出时 c / b 一定等于 a
+func SafeMultiply(a, b int) (int, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	// MinInt * -1 溢出后仍是 MinInt，且 MinInt / -1 也会溢出回 MinInt，需单独判断
+	if c/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, fmt.Errorf("%d * %d: %w", a, b, ErrOverflow)
+	}
+	return c, nil
+}
+
+// Pow 计算 base 的 exp 次方（快速幂），溢出或指数为负时返回错误
+func Pow(base, exp int) (int, error) {
+	if exp < 0 {
+		return 0, fmt.Errorf("指数不能为负数: %d", exp)
+	}
+	result := 1
+	for exp > 0 {
+		var err error
+		if exp&1 == 1 {
+			if result, err = SafeMultiply(result, base); err != nil {
+				return 0, err
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, err = SafeMultiply(base, base); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return result, nil
+}
+
 // 错误处理模式
 func findUser(id int) (string, error) {
 	if id <= 0 {
@@ -277,19 +349,53 @@ func deferAndReturn() (result int) {
 }
 
 // 计算函数执行时间
-func timeTrack(start time.Time, name string) {
-	elapsed := time.Since(start)
-	fmt.Printf("%s 耗时: %s\n", name, elapsed)
-}
-
+// defer 在函数返回时执行，正好用来打印耗时；
+// timing.Stopwatch 还能用 Lap 记录每一段各花了多少时间
 func slowFunction() {
-	defer timeTrack(time.Now(), "slowFunction")
+	sw := timing.StartStopwatch()
+	defer func() {
+		fmt.Printf("slowFunction 耗时: %v\n", sw.Stop())
+		fmt.Println(sw)
+	}()
 
 	// 模拟耗时操作
-	time.Sleep(100 * time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	sw.Lap("准备")
+	time.Sleep(70 * time.Millisecond)
+	sw.Lap("计算")
 	fmt.Println("slowFunction 执行完成")
 }
 
+// 多次调用的耗时要看分布而不是单次：timing.Timed 把每次耗时记到直方图里
+func demonstrateTimed() {
+	for i := range 5 {
+		timing.Timed("fibonacci", func() { fibonacci(20 + i) })
+	}
+	h := timing.Default().Histogram("fibonacci")
+	fmt.Printf("fibonacci 调用 %d 次，总耗时 %v\n", h.Count(), time.Duration(h.Sum()*float64(time.Second)))
+
+	// 直方图只保留计数；保留每次的耗时，就能用 pkg/stats 求中位数和百分位
+	// fibonacci 有缓存，第二次调用几乎不花时间，这里计时不带缓存的递归版本
+	var slowFib func(n int) int
+	slowFib = func(n int) int {
+		if n <= 1 {
+			return n
+		}
+		return slowFib(n-1) + slowFib(n-2)
+	}
+	sw := timing.StartStopwatch()
+	for range 200 {
+		slowFib(18)
+		sw.Lap("slowFib(18)")
+	}
+	fmt.Println("slowFib(18) 耗时分布:", stats.Summarize(sw.Splits()).Format(formatDuration))
+}
+
+// formatDuration 以 time.Duration 的格式输出纳秒数
+func formatDuration(ns float64) string {
+	return time.Duration(ns).Round(100 * time.Nanosecond).String()
+}
+
 // defer 中的参数求值
 func demonstrateDeferArgs() {
 	i := 0
@@ -343,13 +449,18 @@ func fibonacci(n int) int {
 
 var packageVar string
 
+// initLog 记录 init 的执行顺序
+// 本课现在是一个被 cmd/tutorial 导入的包，init 在程序启动时就执行了，
+// 早于 Run；直接打印会混在其他课程的输出前面，所以先记下来，由 Run 打印
+var initLog []string
+
 func init() {
 	packageVar = "initialized in init 1"
-	fmt.Println("init 1 执行")
+	initLog = append(initLog, "init 1 执行")
 }
 
 func init() {
-	fmt.Println("init 2 执行")
+	initLog = append(initLog, "init 2 执行")
 }
 
 func Separator() {
@@ -357,10 +468,90 @@ func Separator() {
 }
 
 // ============================================
-// 主函数
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "02",
+		Name:  "02_functions",
+		Title: "函数：多返回值、闭包、defer、递归、init",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "2", Title: "共享总数、并发安全的累加器", Check: checkAccumulator},
+			{ID: "4", Title: "耗时分布：中位数、百分位、标准差与直方图", Check: checkLatencyStats},
+			{ID: "5", Title: "记忆化：Memo / Memo2 / Memo3", Check: checkMemo},
+			{ID: "6", Title: "可以返回错误的函数管道 Pipeline[T]", Check: checkPipeline},
+		},
+	})
+}
+
+// checkMemo 检查练习 5：命中缓存、多参数组合键、LRU 淘汰与并发调用
+func checkMemo() error {
+	calls := 0
+	add := memo.Memo2(func(a, b int) int {
+		calls++
+		return a + b
+	})
+	if add(1, 2) != 3 || add(1, 2) != 3 || add(2, 1) != 3 || calls != 2 {
+		return fmt.Errorf("Memo2: add(1,2) 两次、add(2,1) 一次后计算了 %d 次，期望 2 次", calls)
+	}
+
+	var evicted []string
+	calls = 0
+	join := memo.Memo3(func(a, b, c string) string {
+		calls++
+		return a + b + c
+	}, memo.WithMaxEntries(2), memo.WithOnEvict(func(k memo.Key3[string, string, string], v string) {
+		evicted = append(evicted, v)
+	}))
+	join("a", "b", "c")
+	join("x", "y", "z")
+	join("a", "b", "c") // abc 变为最近使用
+	join("1", "2", "3") // 淘汰 xyz
+	join("x", "y", "z") // 重新计算，淘汰 abc
+	if calls != 4 || len(evicted) != 2 || evicted[0] != "xyz" || evicted[1] != "abc" {
+		return fmt.Errorf("Memo3 容量 2：计算 %d 次，淘汰 %v，期望 4 次、[xyz abc]", calls, evicted)
+	}
+
+	// 并发调用：结果一致，且 -race 下没有数据竞争
+	var mu sync.Mutex
+	calls = 0
+	slowSquare := memo.Memo(func(n int) int {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return n * n
+	}, memo.WithMaxEntries(8))
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := range 64 {
+		wg.Go(func() {
+			if n := i % 16; slowSquare(n) != n*n {
+				errs <- fmt.Errorf("并发调用 square(%d) 结果错误", n)
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	// 淘汰回调的类型与缓存不一致属于编程错误，创建时 panic
+	defer func() { recover() }()
+	memo.Memo(func(n int) int { return n }, memo.WithOnEvict(func(k string, v int) {}))
+	return errors.New("WithOnEvict 类型不匹配时 Memo 应当 panic")
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 02
+func Run() {
+	fmt.Println("=== init 函数（导入包时已执行） ===")
+	for _, line := range initLog {
+		fmt.Println(line)
+	}
+	fmt.Println("packageVar:", packageVar)
+
 	fmt.Println("=== 基本函数 ===")
 	sayHello()
 	var userName string = "Go开发者"
@@ -381,6 +572,31 @@ func main() {
 		fmt.Println("除以0错误:", err)
 	}
 
+	fmt.Printf("10 - 4 = %d, 6 * 7 = %d\n", Subtract(10, 4), Multiply(6, 7))
+	fmt.Printf("MaxInt + 1 = %d（静默回绕）\n", add(math.MaxInt, 1))
+
+	// 表格驱动：把输入和期望放在一张表里逐个检查，测试中也常用这种写法
+	powCases := []struct {
+		base, exp int
+		want      int
+		wantErr   bool
+	}{
+		{2, 10, 1024, false},
+		{-3, 3, -27, false},
+		{7, 0, 1, false},
+		{2, 62, 1 << 62, false},
+		{2, 63, 0, true}, // 超过 MaxInt
+		{10, -1, 0, true},
+	}
+	for _, c := range powCases {
+		got, err := Pow(c.base, c.exp)
+		ok := got == c.want && (err != nil) == c.wantErr
+		fmt.Printf("Pow(%d, %d) = %d, err = %v, 符合预期: %v\n", c.base, c.exp, got, err, ok)
+	}
+	if _, err := SafeAdd(math.MaxInt, 1); errors.Is(err, ErrOverflow) {
+		fmt.Println("SafeAdd 检测到溢出:", err)
+	}
+
 	fmt.Println("\n=== 函数作为值 ===")
 	demonstrateFuncValue()
 
@@ -392,6 +608,10 @@ func main() {
 
 	fmt.Println("\ndefer 和返回值:", deferAndReturn())
 
+	fmt.Println("\n=== 计时 ===")
+	slowFunction()
+	demonstrateTimed()
+
 	fmt.Println("\n=== 递归 ===")
 	fmt.Printf("5! = %d\n", factorial(5))
 	fmt.Printf("fib(10) = %d\n", fibonacci(10))
@@ -404,21 +624,9 @@ func main() {
 	//   func minMax(nums ...int) (min, max int, err error)
 	//   错误处理：如果没有传入参数，返回错误
 	Separator()
+	// 变长参数 nums 在函数内就是 []int，直接交给第 1 课练习 4 的 sliceutil.MinMax
 	findMinMax := func(nums ...int) (min, max int, err error) {
-		if len(nums) == 0 {
-			return 0, 0, fmt.Errorf("should at least pass in one element")
-		}
-		min = nums[0]
-		max = nums[0]
-		for _, v := range nums[1:] {
-			if v > max {
-				max = v
-			}
-			if v < min {
-				min = v
-			}
-		}
-		return min, max, nil
+		return sliceutil.MinMax(nums)
 	}
 	min, max, err := findMinMax(1, 2, 3, 4, 5, 6, 8)
 	if err != nil {
@@ -431,23 +639,17 @@ func main() {
 	//   func makeAccumulator(initial int) (add, sub func(int) int)
 	//   add(5) 表示加5，sub(3) 表示减3
 	Separator()
-	type Accumulator = func(int) int
-	accumulatorMaker := func(initVal int) (addFunc, subFunc Accumulator) {
-		addVal := initVal
-		addFunc = func(accumulation int) int {
-			addVal += accumulation
-			return addVal
-		}
-		subVal := initVal
-		subFunc = func(accumulation int) int {
-			subVal -= accumulation
-			return subVal
-		}
-		return addFunc, subFunc
+	//   实现见 accumulator.go：add 和 sub 共享同一个总数，并且并发安全
+	addFunc, subFunc := makeAccumulator(5)
+	fmt.Println("add(3):", addFunc(3)) // 8
+	fmt.Println("sub(3):", subFunc(3)) // 5，而不是 2
+	acc := NewAccumulator(0)
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Go(func() { acc.Add(i) })
 	}
-	addFunc, subFunc := accumulatorMaker(5)
-	fmt.Println("addFunc(3)", addFunc(3))
-	fmt.Println("subFunc(3)", subFunc(3))
+	wg.Wait()
+	fmt.Println("100 个 goroutine 并发 Add(1..100):", acc.Value())
 
 	// 练习 3：实现一个函数，接收一个整数切片和一个过滤函数，返回满足条件的元素
 	//   func filter(nums []int, predicate func(int) bool) []int
@@ -483,34 +685,47 @@ func main() {
 	}
 	slowFunc()
 
+	//   进阶：多次计时看分布而不是单次，pkg/stats 求中位数、百分位、标准差和直方图，
+	//   演示见第 7 节的 demonstrateTimed，检查见 latency.go
+	//
 	// 练习 5：实现一个记忆化函数，缓存任意函数的结果（进阶）
 	//   func memoize(f func(int) int) func(int) int
-	type OneFunc func(int) int
-	memFunc := func(oneFunc OneFunc) OneFunc {
-		cacheMap := map[int]int{}
-
-		return func(key int) int {
-			if v, ok := cacheMap[key]; ok {
-				fmt.Println("cache hit for val:", key)
-				return v
-			}
-
-			out := oneFunc(key)
-			cacheMap[key] = out
-			fmt.Println("no cache hit for val:", key)
-
-			return out
-		}
-	}
-	getcacheMapFunc := memFunc(func(val int) int { return val * val })
-
+	//   推广到多个参数、限制缓存大小、并发安全：见 pkg/memo 的 Memo / Memo2 / Memo3
 	Separator()
-	fmt.Println("getcacheMapFunc(1):", getcacheMapFunc(1))
-	fmt.Println("getcacheMapFunc(1):", getcacheMapFunc(1))
+	square := memo.Memo(func(val int) int {
+		fmt.Println("计算 square:", val)
+		return val * val
+	})
+	fmt.Println("square(4):", square(4))
+	fmt.Println("square(4):", square(4)) // 命中缓存，不再打印"计算"
+
+	// 组合数 C(n, k) = C(n-1, k-1) + C(n-1, k)，不记忆化时调用次数随 n 指数增长
+	calls := 0
+	var choose func(n, k int) int
+	choose = memo.Memo2(func(n, k int) int {
+		calls++
+		if k == 0 || k == n {
+			return 1
+		}
+		return choose(n-1, k-1) + choose(n-1, k)
+	})
+	fmt.Printf("C(30, 15) = %d，实际计算 %d 次\n", choose(30, 15), calls)
+
+	// 只保留最近使用的 2 个结果，淘汰时回调
+	area := memo.Memo3(func(w, h int, unit string) string {
+		return fmt.Sprint(w*h, unit, "²")
+	}, memo.WithMaxEntries(2), memo.WithOnEvict(func(k memo.Key3[int, int, string], v string) {
+		fmt.Printf("淘汰 area%v = %s\n", k, v)
+	}))
+	area(2, 3, "m")
+	area(4, 5, "m")
+	area(2, 3, "m")  // 命中，(2, 3, m) 变为最近使用
+	area(6, 7, "cm") // 淘汰最久未使用的 (4, 5, m)
 
 	// 练习 6：实现一个管道（pipeline）函数链
 	//   func pipeline(data int, funcs ...func(int) int) int
 	//   示例：pipeline(5, double, addOne, square) = ((5*2)+1)^2 = 121
+	type OneFunc func(int) int
 	pipeline := func(val int, funcs ...OneFunc) int {
 		for _, oneFunc := range funcs {
 			val = oneFunc(val)
@@ -523,5 +738,27 @@ func main() {
 		func(val int) int { return val * 2 },
 		func(val int) int { return val + 1 },
 		func(val int) int { return val * val })
-	fmt.Println("pipeline:",res)
+	fmt.Println("pipeline:", res)
+
+	//   扩展：每一步可以返回错误，遇错中止，见 pipeline.go
+	Separator()
+	parseAge := NewPipeline[string]().
+		Map("trim", strings.TrimSpace).
+		Then("digits", func(s string) (string, error) {
+			if s == "" || strings.Trim(s, "0123456789") != "" {
+				return "", fmt.Errorf("%q 不是数字", s)
+			}
+			return s, nil
+		}).
+		Then("range", func(s string) (string, error) {
+			if len(s) > 3 {
+				return "", fmt.Errorf("年龄 %s 过大", s)
+			}
+			return s, nil
+		}).
+		OnStage(LogStages[string](os.Stdout))
+	for _, in := range []string{" 42 ", "4x2", "12345"} {
+		out, err := parseAge.Run(in)
+		fmt.Printf("Run(%q) = %q, %v\n", in, out, err)
+	}
 }
@@ -0,0 +1,177 @@
+package functions
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSubtractMultiply(t *testing.T) {
+	tests := []struct {
+		a, b          int
+		diff, product int
+	}{
+		{10, 4, 6, 40},
+		{4, 10, -6, 40},
+		{-3, -5, 2, 15},
+		{0, 7, -7, 0},
+		{math.MinInt, 1, math.MaxInt, math.MinInt}, // 不检查溢出：静默回绕
+		{math.MaxInt, 2, math.MaxInt - 2, -2},
+	}
+	for _, tt := range tests {
+		if got := Subtract(tt.a, tt.b); got != tt.diff {
+			t.Errorf("Subtract(%d, %d) = %d，期望 %d", tt.a, tt.b, got, tt.diff)
+		}
+		if got := Multiply(tt.a, tt.b); got != tt.product {
+			t.Errorf("Multiply(%d, %d) = %d，期望 %d", tt.a, tt.b, got, tt.product)
+		}
+	}
+}
+
+func TestSafeAdd(t *testing.T) {
+	tests := []struct {
+		a, b     int
+		want     int
+		overflow bool
+	}{
+		{1, 2, 3, false},
+		{-1, -2, -3, false},
+		{math.MaxInt, 0, math.MaxInt, false},
+		{math.MaxInt, math.MinInt, -1, false},
+		{math.MaxInt, 1, 0, true},
+		{math.MinInt, -1, 0, true},
+		{math.MinInt, math.MinInt, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := SafeAdd(tt.a, tt.b)
+		if errors.Is(err, ErrOverflow) != tt.overflow || got != tt.want {
+			t.Errorf("SafeAdd(%d, %d) = %d, %v，期望 %d，溢出 %v", tt.a, tt.b, got, err, tt.want, tt.overflow)
+		}
+	}
+}
+
+func TestSafeMultiply(t *testing.T) {
+	tests := []struct {
+		a, b     int
+		want     int
+		overflow bool
+	}{
+		{6, 7, 42, false},
+		{-6, 7, -42, false},
+		{0, math.MinInt, 0, false},
+		{math.MinInt, 1, math.MinInt, false},
+		{math.MaxInt, -1, -math.MaxInt, false},
+		{math.MinInt, -1, 0, true},
+		{-1, math.MinInt, 0, true},
+		{math.MaxInt, 2, 0, true},
+		{1 << 32, 1 << 32, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := SafeMultiply(tt.a, tt.b)
+		if errors.Is(err, ErrOverflow) != tt.overflow || got != tt.want {
+			t.Errorf("SafeMultiply(%d, %d) = %d, %v，期望 %d，溢出 %v", tt.a, tt.b, got, err, tt.want, tt.overflow)
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	tests := []struct {
+		base, exp int
+		want      int
+		wantErr   bool
+	}{
+		{2, 10, 1024, false},
+		{3, 0, 1, false},
+		{0, 0, 1, false},
+		{0, 5, 0, false},
+		{-2, 3, -8, false},
+		{-2, 63, math.MinInt, false},
+		{2, 62, 1 << 62, false},
+		{2, 63, 0, true},
+		{10, 19, 0, true},
+		{2, -1, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Pow(tt.base, tt.exp)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Pow(%d, %d) = %d, %v，期望 %d，出错 %v", tt.base, tt.exp, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// FuzzSafeAdd 检查 SafeAdd 恰好在真实的和超出 int 范围时报告溢出
+// 运行：go test ./tutorial/02_functions -fuzz FuzzSafeAdd
+func FuzzSafeAdd(f *testing.F) {
+	for _, seed := range [][2]int64{{1, 2}, {math.MaxInt64, 1}, {math.MinInt64, -1}, {math.MaxInt64, math.MinInt64}, {-1, 0}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		got, err := SafeAdd(int(a), int(b))
+		sum := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		if !sum.IsInt64() {
+			if !errors.Is(err, ErrOverflow) {
+				t.Fatalf("SafeAdd(%d, %d) = %d, %v，真实的和 %v 溢出，期望 ErrOverflow", a, b, got, err, sum)
+			}
+			return
+		}
+		if err != nil || int64(got) != sum.Int64() {
+			t.Fatalf("SafeAdd(%d, %d) = %d, %v，期望 %v", a, b, got, err, sum)
+		}
+	})
+}
+
+// FuzzSafeMultiply 与 FuzzSafeAdd 相同，检查乘法
+func FuzzSafeMultiply(f *testing.F) {
+	for _, seed := range [][2]int64{{6, 7}, {math.MinInt64, -1}, {-1, math.MinInt64}, {1 << 32, 1 << 31}, {0, math.MinInt64}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		got, err := SafeMultiply(int(a), int(b))
+		product := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+		if !product.IsInt64() {
+			if !errors.Is(err, ErrOverflow) {
+				t.Fatalf("SafeMultiply(%d, %d) = %d, %v，真实的积 %v 溢出，期望 ErrOverflow", a, b, got, err, product)
+			}
+			return
+		}
+		if err != nil || int64(got) != product.Int64() {
+			t.Fatalf("SafeMultiply(%d, %d) = %d, %v，期望 %v", a, b, got, err, product)
+		}
+	})
+}
+
+// FuzzDivide 检查 divide 的商和余数与 math/big 向零截断的结果一致
+// 运行：go test ./tutorial/02_functions -fuzz FuzzDivide
+func FuzzDivide(f *testing.F) {
+	for _, seed := range [][2]int64{{17, 5}, {-17, 5}, {17, -5}, {10, 0}, {0, 3}, {math.MaxInt64, 2}, {math.MinInt64, -1}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		q, r, err := divide(int(a), int(b))
+		if b == 0 {
+			if err == nil {
+				t.Fatalf("divide(%d, 0) 没有返回错误", a)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("divide(%d, %d) 返回意外的错误 %v", a, b, err)
+		}
+		// 无论是否溢出，q*b + r == a 在回绕运算下都成立
+		if q*int(b)+r != int(a) {
+			t.Fatalf("divide(%d, %d) = %d, %d，q*b+r != a", a, b, q, r)
+		}
+		bq, br := new(big.Int).QuoRem(big.NewInt(a), big.NewInt(b), new(big.Int))
+		if !bq.IsInt64() {
+			// 只有 MinInt / -1：商 2^63 超出范围，Go 静默回绕为 MinInt
+			if q != math.MinInt || r != 0 {
+				t.Fatalf("divide(%d, %d) = %d, %d，期望回绕为 %d, 0", a, b, q, r, math.MinInt)
+			}
+			return
+		}
+		if int64(q) != bq.Int64() || int64(r) != br.Int64() {
+			t.Fatalf("divide(%d, %d) = %d, %d，期望 %v, %v", a, b, q, r, bq, br)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// ============================================
+// 练习 2：共享状态的累加器
+// ============================================
+//
+// add 和 sub 必须操作同一个总数：add(5) 再 sub(3) 得到 initial+2。
+// 如果两个闭包各自捕获一个变量，加和减就成了两本互不相干的账。
+//
+// Accumulator 把总数放在结构体里，用互斥锁保护，多个 goroutine 可以同时加减。
+// makeAccumulator 返回的 add / sub 是方法值（a.Add、a.Sub）：
+// 方法值绑定了接收者 a，本身就是闭包，两个函数共享同一个 a。
+// ============================================
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Accumulator 并发安全的累加器，零值可用（初始值为 0）
+type Accumulator struct {
+	mu    sync.Mutex
+	total int
+}
+
+// NewAccumulator 创建初始值为 initial 的累加器
+func NewAccumulator(initial int) *Accumulator {
+	return &Accumulator{total: initial}
+}
+
+// Add 加上 n，返回新的总数
+func (a *Accumulator) Add(n int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.total += n
+	return a.total
+}
+
+// Sub 减去 n，返回新的总数
+func (a *Accumulator) Sub(n int) int {
+	return a.Add(-n)
+}
+
+// Value 当前总数
+func (a *Accumulator) Value() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
+
+// makeAccumulator 练习题要求的函数形式
+func makeAccumulator(initial int) (add, sub func(int) int) {
+	a := NewAccumulator(initial)
+	return a.Add, a.Sub
+}
+
+// checkAccumulator 检查练习 2
+func checkAccumulator() error {
+	add, sub := makeAccumulator(5)
+	if got := add(3); got != 8 {
+		return fmt.Errorf("makeAccumulator(5) 后 add(3) = %d，期望 8", got)
+	}
+	if got := sub(3); got != 5 {
+		return fmt.Errorf("再 sub(3) = %d，期望 5（add 和 sub 共享总数）", got)
+	}
+
+	var a Accumulator
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { a.Add(2) })
+		wg.Go(func() { a.Sub(1) })
+	}
+	wg.Wait()
+	if a.Value() != 100 {
+		return fmt.Errorf("100 个 goroutine 各 Add(2)、100 个各 Sub(1) 后 Value() = %d，期望 100", a.Value())
+	}
+	if NewAccumulator(-1).Value() != -1 {
+		return errors.New("NewAccumulator(-1).Value() 应为 -1")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// ============================================
+// 练习 4（进阶）：耗时的分布
+// ============================================
+//
+// 练习 4 的计时器只打印单次耗时。同一个函数调用很多次时，
+// 每次的耗时都不一样，要看的是分布：
+//   - 中位数（P50）：典型的一次有多快，不受少数极端值影响
+//   - P90 / P99：最慢的 10% / 1% 有多慢，接口的超时和 SLO 一般按它定
+//   - 标准差：耗时是否稳定
+//   - 直方图：分布的形状，比如是否有两个峰（命中缓存与未命中）
+//
+// timing.Stopwatch 每次 Lap 记录一段耗时，Splits 取出全部样本交给 pkg/stats。
+// 这里用 clock.FakeClock 构造确定的样本，检查结果与机器快慢无关。
+// ============================================
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/stats"
+	"c03/pkg/timing"
+)
+
+// checkLatencyStats 检查练习 4 的进阶部分
+func checkLatencyStats() error {
+	// 100 次调用，耗时依次为 1ms、2ms、…、100ms
+	fc := clock.NewFakeClock(time.Unix(0, 0))
+	sw := timing.StartStopwatchWithClock(fc)
+	for i := range 100 {
+		fc.Advance(time.Duration(i+1) * time.Millisecond)
+		sw.Lap("call")
+	}
+	samples := sw.Splits()
+	if len(samples) != 100 || samples[0] != time.Millisecond || samples[99] != 100*time.Millisecond {
+		return fmt.Errorf("Splits 返回 %d 个样本，首尾为 %v、%v", len(samples), samples[0], samples[len(samples)-1])
+	}
+
+	ms := float64(time.Millisecond)
+	s := stats.Summarize(samples)
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Mean", s.Mean, 50.5 * ms},
+		{"P50", s.P50, stats.Median(samples)},
+		{"Median", stats.Median(samples), 50.5 * ms},
+		{"P90", s.P90, 90.1 * ms},
+		{"P99", s.P99, 99.01 * ms},
+		{"Min", s.Min, 1 * ms},
+		{"Max", s.Max, 100 * ms},
+		{"StdDev", s.StdDev, math.Sqrt((100*100-1)/12.0) * ms}, // 1..n 的总体标准差为 √((n²-1)/12)
+		{"Percentile(0)", stats.Percentile(samples, 0), 1 * ms},
+		{"Percentile(100)", stats.Percentile(samples, 100), 100 * ms},
+	} {
+		if math.Abs(c.got-c.want) > 1e-6*ms {
+			return fmt.Errorf("%s = %v，期望 %v", c.name, time.Duration(c.got), time.Duration(c.want))
+		}
+	}
+	if samples[0] != time.Millisecond {
+		return fmt.Errorf("Summarize 修改了传入的切片")
+	}
+
+	buckets := stats.Histogram(samples, []float64{10 * ms, 50 * ms})
+	if len(buckets) != 3 || buckets[0].Count != 10 || buckets[1].Count != 40 || buckets[2].Count != 50 || !math.IsInf(buckets[2].Upper, 1) {
+		return fmt.Errorf("Histogram(≤10ms, ≤50ms) = %v，期望计数 10、40、50，最后一个桶为 +Inf", buckets)
+	}
+
+	// 没有样本时统计量没有意义，返回 NaN 而不是 0
+	empty := stats.Summarize([]time.Duration(nil))
+	if empty.Count != 0 || !math.IsNaN(empty.Mean) || !math.IsNaN(empty.P99) || !math.IsNaN(stats.Percentile(samples, 101)) {
+		return fmt.Errorf("空样本的概况为 %v，期望各项都是 NaN", empty)
+	}
+
+	// 数值很大、差异很小时两遍法会丢失精度，Welford 算法仍然准确
+	big := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	if got := stats.SampleStdDev(big); math.Abs(got-math.Sqrt(30)) > 1e-6 {
+		return fmt.Errorf("SampleStdDev(1e9+{4,7,13,16}) = %v，期望 √30", got)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// ============================================
+// 练习 6（扩展）：可以返回错误的函数管道
+// ============================================
+//
+// 练习 6 的 pipeline(data, funcs...) 中每一步都不会失败。实际的数据处理
+// （解析、校验、转换）每一步都可能出错，出错后后面的步骤就没有意义了：
+//
+//   p := NewPipeline[string]().
+//       Then("trim", trim).          // func(string) (string, error)
+//       Then("validate", validate).
+//       Map("upper", strings.ToUpper) // 不会失败的步骤用 Map
+//   out, err := p.Run("  hello ")
+//
+// 第一个返回错误的步骤会中止整条管道，错误包装为 *StageError，
+// 用 errors.As 可以取出是哪一步失败的，用 errors.Is 仍能匹配原始错误。
+//
+// OnStage 注册的钩子在每一步之后调用，可以用来打印日志或统计耗时（见 LogStages）。
+// ============================================
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// StageFunc 管道中的一步
+type StageFunc[T any] func(T) (T, error)
+
+// StageInfo 一步执行后的信息，传给 OnStage 注册的钩子
+type StageInfo[T any] struct {
+	Index   int // 从 0 开始
+	Name    string
+	In, Out T // 出错时 Out 为该步骤返回的值（通常是零值）
+	Err     error
+	Elapsed time.Duration
+}
+
+// StageError 管道在某一步失败
+type StageError struct {
+	Index int
+	Name  string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("第 %d 步 %s 失败: %v", e.Index+1, e.Name, e.Err)
+}
+
+func (e *StageError) Unwrap() error { return e.Err }
+
+type stage[T any] struct {
+	name string
+	fn   StageFunc[T]
+}
+
+// Pipeline 按顺序执行的步骤，零值可用
+// Run 不修改 Pipeline，注册完步骤后可以被多个 goroutine 同时 Run
+type Pipeline[T any] struct {
+	Clock  clock.Clock // 统计耗时用，nil 表示 clock.Real
+	stages []stage[T]
+	hooks  []func(StageInfo[T])
+}
+
+// NewPipeline 创建空管道
+func NewPipeline[T any]() *Pipeline[T] {
+	return &Pipeline[T]{}
+}
+
+// Then 追加一个可能失败的步骤，返回 p 以便链式调用
+func (p *Pipeline[T]) Then(name string, fn StageFunc[T]) *Pipeline[T] {
+	p.stages = append(p.stages, stage[T]{name, fn})
+	return p
+}
+
+// Map 追加一个不会失败的步骤
+func (p *Pipeline[T]) Map(name string, fn func(T) T) *Pipeline[T] {
+	return p.Then(name, func(v T) (T, error) { return fn(v), nil })
+}
+
+// OnStage 注册钩子，每一步执行后按注册顺序调用（包括失败的那一步）
+func (p *Pipeline[T]) OnStage(hook func(StageInfo[T])) *Pipeline[T] {
+	p.hooks = append(p.hooks, hook)
+	return p
+}
+
+// Len 步骤数
+func (p *Pipeline[T]) Len() int { return len(p.stages) }
+
+// Run 依次执行所有步骤
+// 成功时返回最后一步的输出；失败时返回失败前最后一个成功的值和 *StageError
+func (p *Pipeline[T]) Run(in T) (T, error) {
+	clk := clock.Or(p.Clock)
+	v := in
+	for i, s := range p.stages {
+		start := clk.Now()
+		out, err := s.fn(v)
+		if len(p.hooks) > 0 {
+			info := StageInfo[T]{Index: i, Name: s.name, In: v, Out: out, Err: err, Elapsed: clk.Now().Sub(start)}
+			for _, hook := range p.hooks {
+				hook(info)
+			}
+		}
+		if err != nil {
+			return v, &StageError{Index: i, Name: s.name, Err: err}
+		}
+		v = out
+	}
+	return v, nil
+}
+
+// LogStages 返回一个把每一步的输入、输出和耗时写到 w 的钩子
+func LogStages[T any](w io.Writer) func(StageInfo[T]) {
+	return func(info StageInfo[T]) {
+		if info.Err != nil {
+			fmt.Fprintf(w, "  [%d] %-10s %v -> 错误: %v (%v)\n", info.Index+1, info.Name, info.In, info.Err, info.Elapsed)
+			return
+		}
+		fmt.Fprintf(w, "  [%d] %-10s %v -> %v (%v)\n", info.Index+1, info.Name, info.In, info.Out, info.Elapsed)
+	}
+}
+
+// checkPipeline 检查练习 6：顺序执行、遇错中止、错误包装与钩子计时
+func checkPipeline() error {
+	errNegative := errors.New("负数")
+	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var trace []string
+	p := NewPipeline[int]().
+		Map("double", func(v int) int { return v * 2 }).
+		Then("sub10", func(v int) (int, error) {
+			fc.Advance(3 * time.Millisecond) // 模拟耗时
+			return v - 10, nil
+		}).
+		Then("check", func(v int) (int, error) {
+			if v < 0 {
+				return 0, errNegative
+			}
+			return v, nil
+		}).
+		Map("square", func(v int) int { return v * v })
+	p.Clock = fc
+	p.OnStage(func(info StageInfo[int]) {
+		trace = append(trace, fmt.Sprintf("%s:%v", info.Name, info.Elapsed))
+	})
+
+	if out, err := p.Run(10); err != nil || out != 100 {
+		return fmt.Errorf("Run(10) = %d, %v，期望 100, nil", out, err)
+	}
+	if want := []string{"double:0s", "sub10:3ms", "check:0s", "square:0s"}; !slices.Equal(trace, want) {
+		return fmt.Errorf("钩子记录 %v，期望 %v", trace, want)
+	}
+
+	trace = nil
+	out, err := p.Run(2)
+	var se *StageError
+	if !errors.As(err, &se) || se.Index != 2 || se.Name != "check" || !errors.Is(err, errNegative) {
+		return fmt.Errorf("Run(2) 返回错误 %v，期望第 3 步 check 失败并包装 errNegative", err)
+	}
+	if out != -6 {
+		return fmt.Errorf("Run(2) 失败时返回 %d，期望最后一个成功的值 -6", out)
+	}
+	if len(trace) != 3 {
+		return fmt.Errorf("失败后还执行了后续步骤: %v", trace)
+	}
+
+	var empty Pipeline[string]
+	if out, err := empty.Run("x"); out != "x" || err != nil {
+		return fmt.Errorf("空管道 Run(x) = %q, %v，期望原样返回", out, err)
+	}
+	return nil
+}
@@ -12,48 +12,44 @@
 //
 // 最佳实践：
 // 1. 需要修改接收者状态时用指针接收者
-// 2. 结构体较大时用指针接收者（避免复制开销）
+// 2. 结构体较大时用指针接收者（避免复制开销：1KB 结构体的值接收者调用
+//    约慢一个数量级，8 字节的则没有差别，见 go run ./cmd/microbench）
 // 3. 保持一致性：同一类型的方法要么全用值，要么全用指针
 // 4. 嵌入用于代码复用，但不是真正的继承
 // 5. 使用 JSON tag 控制序列化行为
 // ============================================
 
-package main
+package structmethod
 
 import (
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/jsontype"
+	"c03/pkg/shape"
+	"c03/tutorial"
 )
 
 // ============================================
 // 练习：Rectangle 结构体和方法
 // ============================================
+//
+// 最初这里有一个自己的 Rectangle，Area、Scale 用指针接收者，Perimeter、IsSquare
+// 用值接收者。混用的问题：Rectangle 值的方法集里没有 Area，
+// 赋给要求 Area() 的接口时只有 &rect 可以，rect 不行。
+//
+// 现在统一为 pkg/shape 的 Rectangle（类型别名，不是新类型）：全部值接收者，
+// Scale、Rotate 返回新的矩形；值和指针都满足 shape.Shape。
 
-type Rectangle struct {
-	length float32
-	width  float32
-}
-
-func (r *Rectangle) Area() float32 {
-	return r.length * r.width
-}
-
-func (r Rectangle) Perimeter() float32 {
-	return 2 * (r.length + r.width)
-}
-
-func (r *Rectangle) Scale(factor float32) {
-	r.length = r.length * factor
-	r.width = r.width * factor
-}
-
-func (r Rectangle) IsSquare() bool {
-	return r.length == r.width
-}
+type Rectangle = shape.Rectangle
 
 // ============================================
 // 1. 结构体定义
@@ -62,9 +58,12 @@ func (r Rectangle) IsSquare() bool {
 // 结构体是字段的集合，是值类型
 
 // 基本结构体
+// Email/Phone 是可选字段，需要校验时用 NewPerson 创建（见第 10 节）
 type Person struct {
-	Name string
-	Age  int
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
 }
 
 // 包含多种类型的结构体
@@ -97,13 +96,17 @@ type Contact struct {
 }
 
 // 带有标签的结构体（常用于 JSON/XML 序列化）
+// 标签决定字段名和是否省略，值的格式由字段类型决定：
+// Birthday、LastLogin 的类型实现了 MarshalJSON / UnmarshalJSON（见 pkg/jsontype）
 type User struct {
-	ID        int       `json:"id" db:"user_id"`    // 多个标签
-	Username  string    `json:"username,omitempty"` // omitempty: 空值时省略
-	Password  string    `json:"-"`                  // -: 忽略此字段
-	Email     string    `json:"email" validate:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	IsAdmin   bool      `json:"is_admin"`
+	ID        int                      `json:"id" db:"user_id"`    // 多个标签
+	Username  string                   `json:"username,omitempty"` // omitempty: 空值时省略
+	Password  string                   `json:"-"`                  // -: 忽略此字段
+	Email     string                   `json:"email" validate:"email"`
+	CreatedAt time.Time                `json:"created_at"`
+	IsAdmin   bool                     `json:"is_admin"`
+	Birthday  jsontype.Date            `json:"birthday,omitzero"` // "2006-01-02"，omitzero: 零值时省略
+	LastLogin jsontype.Null[time.Time] `json:"last_login"`        // 从未登录时为 null
 }
 
 // ============================================
@@ -111,8 +114,8 @@ type User struct {
 // ============================================
 
 func demonstrateStructInit() {
-	// 方式1：按字段顺序初始化（不推荐，字段顺序改变会出错）
-	p1 := Person{"Alice", 30}
+	// 方式1：按字段顺序初始化（不推荐，字段顺序改变或新增字段时都要跟着改）
+	p1 := Person{"Alice", 30, "alice@example.com", ""}
 
 	// 方式2：按字段名初始化（推荐）
 	p2 := Person{
@@ -308,6 +311,7 @@ func demonstrateTag() {
 		Email:     "john@example.com",
 		CreatedAt: time.Now(),
 		IsAdmin:   false,
+		Birthday:  jsontype.Date{Year: 1990, Month: time.May, Day: 20},
 	}
 
 	// theory behind json and struct
@@ -336,7 +340,9 @@ func demonstrateTag() {
 		"username": "jane",
 		"email": "jane@example.com",
 		"created_at": "2024-01-15T10:30:00Z",
-		"is_admin": true
+		"is_admin": true,
+		"birthday": "1995-11-02",
+		"last_login": "2024-03-01T08:00:00Z"
 	}`
 
 	var decoded User
@@ -345,10 +351,18 @@ func demonstrateTag() {
 		return
 	}
 	fmt.Printf("解码后: %+v\n", decoded)
+	if t, ok := decoded.LastLogin.Get(); ok {
+		fmt.Printf("生日 %s，上次登录 %s\n", decoded.Birthday, t.Format(time.DateTime))
+	}
 
 	// read and write json from/to file
-	jsonFile := "./user.json"
-	file, err := os.OpenFile(jsonFile, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	// 写到临时目录，避免在仓库里留下运行产物
+	jsonFile := filepath.Join(os.TempDir(), "go_tutorial_user.json")
+	file, err := os.OpenFile(jsonFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		fmt.Println("fail to open json file, ", err)
+		return
+	}
 	defer file.Close()
 	jsonStr := `{
 		"id":2,
@@ -481,10 +495,116 @@ func demonstrateBankAccount() {
 }
 
 // ============================================
-// 主函数
+// 10. 完整示例：构造函数校验与自定义 JSON
 // ============================================
+//
+// 构造函数是校验不变量的唯一入口：NewPerson 返回错误，
+// 而不是像 NewBankAccount 那样悄悄修正非法输入。
+
+var ErrInvalidPerson = errors.New("无效的个人信息")
+
+var (
+	// 简化的邮箱格式：本地部分@域名.后缀，完整的 RFC 5322 规则远比这复杂
+	emailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`)
+	// 中国大陆手机号：1 开头，第二位 3-9，共 11 位，可带 +86 前缀
+	phonePattern = regexp.MustCompile(`^(\+86)?1[3-9]\d{9}$`)
+)
+
+// NewPerson 创建并校验 Person，email 和 phone 可以为空
+func NewPerson(name string, age int, email, phone string) (*Person, error) {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+	phone = strings.TrimSpace(phone)
+
+	if name == "" {
+		return nil, fmt.Errorf("%w: 姓名不能为空", ErrInvalidPerson)
+	}
+	if age < 0 || age > 150 {
+		return nil, fmt.Errorf("%w: 年龄 %d 超出范围", ErrInvalidPerson, age)
+	}
+	if email != "" && !emailPattern.MatchString(email) {
+		return nil, fmt.Errorf("%w: 邮箱格式错误 %q", ErrInvalidPerson, email)
+	}
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return nil, fmt.Errorf("%w: 手机号格式错误 %q", ErrInvalidPerson, phone)
+	}
+	return &Person{Name: name, Age: age, Email: email, Phone: phone}, nil
+}
 
-func main() {
+// MarshalJSON 自定义序列化：省略所有空字段
+// omitempty 只认零值，只有空格的字符串或 0 岁也会被输出；这里统一按"去掉空白后为空"处理。
+// 注意接收者是值类型，Person 和 *Person 序列化时都会调用它
+func (p Person) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any)
+	if v := strings.TrimSpace(p.Name); v != "" {
+		fields["name"] = v
+	}
+	if p.Age > 0 {
+		fields["age"] = p.Age
+	}
+	if v := strings.TrimSpace(p.Email); v != "" {
+		fields["email"] = v
+	}
+	if v := strings.TrimSpace(p.Phone); v != "" {
+		fields["phone"] = v
+	}
+	// map 的键在编码时按字母排序，输出是稳定的
+	return json.Marshal(fields)
+}
+
+func demonstratePersonJSON() {
+	fmt.Println("\n=== Person 校验与 JSON ===")
+
+	inputs := []struct {
+		name, email, phone string
+		age                int
+	}{
+		{"Alice", "alice@example.com", "13800138000", 30},
+		{"Bob", "", "", 25},
+		{"Carol", "carol@", "", 28},
+		{"Dave", "", "12345", 40},
+		{"  ", "", "", 20},
+	}
+	for _, in := range inputs {
+		p, err := NewPerson(in.name, in.age, in.email, in.phone)
+		if err != nil {
+			fmt.Println("创建失败:", err, "| errors.Is:", errors.Is(err, ErrInvalidPerson))
+			continue
+		}
+		data, _ := json.Marshal(p)
+		fmt.Println("JSON:", string(data))
+	}
+
+	// 直接构造的 Person 绕过了校验，但序列化时空白字段同样被省略
+	data, _ := json.Marshal(Person{Name: "Eve", Email: "   "})
+	fmt.Println("JSON:", string(data))
+
+	// 反序列化使用默认规则（按 json tag 匹配），不受 MarshalJSON 影响
+	var decoded Person
+	json.Unmarshal([]byte(`{"name":"Frank","age":33,"phone":"+8613900139000"}`), &decoded)
+	fmt.Printf("解码后: %+v\n", decoded)
+}
+
+// ============================================
+// 入口
+// ============================================
+
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "03",
+		Name:  "03_struct_method",
+		Title: "结构体与方法：值/指针接收者、嵌入、标签",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "1", Title: "Rectangle：Shape、Rotate、Contains、Union", Check: checkRectangle},
+			{ID: "2", Title: "Book：Stringer、打折与 JSON", Check: checkBook},
+			{ID: "3", Title: "嵌入：MyStudent / MyTeacher", Check: checkEmbedding},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 03
+func Run() {
 	fmt.Println("=== 结构体初始化 ===")
 	demonstrateStructInit()
 
@@ -505,33 +625,45 @@ func main() {
 
 	demonstrateBankAccount()
 
+	demonstratePersonJSON()
+
 	// ============================================
 	// 练习题
 	// ============================================
 	//
 	// 练习 1：使用 Rectangle 结构体
-	rect := Rectangle{length: 10, width: 5}
+	rect := Rectangle{Width: 10, Height: 5}
 	fmt.Println("Rectangle Area:", rect.Area())
 	fmt.Println("Rectangle Perimeter:", rect.Perimeter())
 	fmt.Println("Is Square:", rect.IsSquare())
-	rect.Scale(2)
+	rect = rect.Scale(2)
 	fmt.Println("After Scale 2x:", rect)
+	fmt.Println("After Rotate:", rect.Rotate())
+	fmt.Println("Contains (5, 5):", rect.Contains(5, 5))
+	fmt.Println("Union:", rect.Union(Rectangle{X: 15, Y: -5, Width: 10, Height: 10}))
+	var s shape.Shape = rect // 值接收者：值本身就满足接口
+	shape.PrintShapeInfo(os.Stdout, s)
 
 	//
 	// 练习 2：实现一个 Book 结构体
-	//   - 字段：Title, Author, ISBN, Price, publishSecond
+	//   - 字段：Title, Author, ISBN, Price, PublishDate
 	//   - 实现 ApplyDiscount(discountPercent float64) 打折
 	//   - 实现 GetAge() 返回书的"年龄"
 	//   - 实现 String() string 方法（格式化输出）
 	separator()
-	dateStr := "2000-01-01 00:00:00"
-	parsed, _ := time.Parse(time.DateTime, dateStr)
-	book := NewBook("OneBook", "Jack", "flandfslkfasdoiufoias", 48.0, parsed.UTC())
+	published, _ := jsontype.ParseDate("2000-01-01")
+	book := NewBook("OneBook", "Jack", "flandfslkfasdoiufoias", 48.0, published)
 	fmt.Println("original price:", book.GetOriginalPrice())
 	curPrice, _ := book.ApplyDiscount(70)
-	fmt.Println("original price:", curPrice)
+	fmt.Println("discount price:", curPrice)
+	if _, err := book.ApplyDiscount(120); err != nil {
+		fmt.Println("err:", err)
+	}
 	fmt.Println("age[day]:", book.GetAge())
-	book.PrintAll()
+	fmt.Println(book)
+	bookJSON, _ := json.Marshal(book)
+	fmt.Println("JSON:", string(bookJSON))
+	//   参考实现见本文件的 Book，检查：go run ./cmd/tutorial check 03
 
 	// 练习 3：使用嵌入实现以下结构
 	//   - 基础 Person 结构体（Name, Age）
@@ -540,9 +672,9 @@ func main() {
 	//   - 为 Student 实现 GetAverageGrade() 方法
 	separator()
 	student := &MyStudent{
-		person: &MyPerson{
+		MyPerson: MyPerson{
 			Name: "Jim",
-			Age:  "12",
+			Age:  12,
 		},
 		studentID: "789re7w9r",
 		major:     "Math",
@@ -553,6 +685,18 @@ func main() {
 		fmt.Println("err:", err.Error())
 	}
 	fmt.Println("avgGrade:", avgGrade)
+	teacher := &MyTeacher{
+		MyPerson:   MyPerson{Name: "Lucy", Age: 35},
+		teacherID:  "T001",
+		department: "数学系",
+		salary:     10000,
+	}
+	teacher.GiveRaise(10)
+	fmt.Println("teacher:", teacher.Info())
+	for _, who := range []Introducer{student.MyPerson, student, teacher} {
+		fmt.Printf("%T: %s\n", who, who.Introduce())
+	}
+	//   参考实现见本文件的 MyPerson / MyStudent / MyTeacher，检查：go run ./cmd/tutorial check 03
 	// 练习 4：实现一个缓存结构体
 	//   type Cache struct {
 	//       data map[string]interface{}
@@ -563,14 +707,17 @@ func main() {
 	//   - 实现 Delete(key string)
 	//   - Get 时检查是否过期
 	separator()
+	// 注入假时钟：用 Advance 让时间"过去" 3 秒，不必真的 Sleep
+	fakeClock := clock.NewFakeClock(time.Now())
 	cache := &MyCache{
-		data: make(map[string]interface{}),
-		ttl:  make(map[string]time.Time),
+		data:  make(map[string]interface{}),
+		ttl:   make(map[string]time.Time),
+		clock: fakeClock,
 	}
 	cache.Set("oneKey", 78, time.Duration(2*time.Second))
 	v, expired := cache.Get("oneKey")
 	fmt.Println("v:", v, ", expired:", expired)
-	time.Sleep(3 * time.Second)
+	fakeClock.Advance(3 * time.Second)
 	v, expired = cache.Get("oneKey")
 	fmt.Println("v:", v, ", expired:", expired)
 
@@ -763,21 +910,23 @@ func (list *MyLinkedList) ReverseRecursion() {
 //	- 实现 Delete(key string)
 //	- Get 时检查是否过期
 type MyCache struct {
-	data map[string]interface{} // any data map
-	ttl  map[string]time.Time   // time to live map
+	data  map[string]interface{} // any data map
+	ttl   map[string]time.Time   // time to live map
+	clock clock.Clock            // 时间来源，为 nil 时使用真实时钟
 }
 
 func (obj *MyCache) Set(key string, val interface{}, duration time.Duration) {
 	obj.data[key] = val
-	obj.ttl[key] = time.Now().Add(duration)
+	obj.ttl[key] = clock.Or(obj.clock).Now().Add(duration)
 }
 
 func (obj *MyCache) Get(key string) (interface{}, bool) {
 	if v, ok := obj.data[key]; ok {
-		timeCompare := obj.ttl[key].Compare(time.Now())
+		now := clock.Or(obj.clock).Now()
+		timeCompare := obj.ttl[key].Compare(now)
 		expired := timeCompare <= 0
 		fmt.Println("obj.ttl[key]:", obj.ttl[key].Format(time.DateTime))
-		fmt.Println("time.Now():", time.Now().Format(time.DateTime))
+		fmt.Println("now:", now.Format(time.DateTime))
 		return v, expired
 	}
 
@@ -789,24 +938,65 @@ func (obj *MyCache) Get(key string) (interface{}, bool) {
 //   - Student 嵌入 Person，添加 StudentID, Major, Grades([]float64)
 //   - Teacher 嵌入 Person，添加 TeacherID, Department, Salary
 //   - 为 Student 实现 GetAverageGrade() 方法
+//
+// MyStudent、MyTeacher 嵌入 MyPerson（值嵌入，不是指针字段）：
+//   - Name、Age 被提升，student.Name 等价于 student.MyPerson.Name
+//   - MyPerson 的方法也被提升；MyStudent 定义同名的 Introduce 后遮蔽了它，
+//     MyTeacher 没有定义，直接用 MyPerson 的
+//   - 遮蔽不是虚函数重写：在 MyPerson 的方法里调用 p.Introduce()，
+//     调到的永远是 MyPerson.Introduce，Go 没有"子类"
 type MyPerson struct {
 	Name string
-	Age  string
+	Age  int
+}
+
+// Introduce 自我介绍，嵌入 MyPerson 的类型都会得到这个方法
+func (p MyPerson) Introduce() string {
+	return fmt.Sprintf("我是%s，%d 岁", p.Name, p.Age)
 }
 
 type MyStudent struct {
-	person    *MyPerson
+	MyPerson
 	studentID string
 	major     string
 	grades    []float32
 }
 
+// Introduce 遮蔽 MyPerson.Introduce，在它的基础上加上专业
+// 通过完整路径 obj.MyPerson.Introduce() 仍能调用被遮蔽的方法
+func (obj *MyStudent) Introduce() string {
+	return obj.MyPerson.Introduce() + "，" + obj.major + "专业的学生"
+}
+
 type MyTeacher struct {
-	person     *MyPerson
+	MyPerson
+	teacherID  string
 	department string
 	salary     float32
 }
 
+// ErrInvalidRaise 加薪比例不是正数
+var ErrInvalidRaise = errors.New("无效的加薪比例")
+
+// GiveRaise 按百分比加薪（10 即加 10%），返回新的薪水
+func (obj *MyTeacher) GiveRaise(percent float32) (float32, error) {
+	if percent <= 0 {
+		return obj.salary, fmt.Errorf("%w: %g", ErrInvalidRaise, percent)
+	}
+	obj.salary *= 1 + percent*0.01
+	return obj.salary, nil
+}
+
+// Info 教师的完整信息；Name、Age 是从 MyPerson 提升来的字段
+func (obj *MyTeacher) Info() string {
+	return fmt.Sprintf("%s（%d 岁）工号 %s，%s，月薪 %.2f", obj.Name, obj.Age, obj.teacherID, obj.department, obj.salary)
+}
+
+// Introducer 能自我介绍的类型：MyPerson、*MyStudent、MyTeacher 都满足
+type Introducer interface {
+	Introduce() string
+}
+
 func (obj *MyStudent) GetAverageGrade() (avgGrade float32, err error) {
 	// default
 	avgGrade = 0.0
@@ -836,54 +1026,85 @@ func (obj *MyStudent) GetAverageGrade() (avgGrade float32, err error) {
 }
 
 // 练习 2：实现一个 Book 结构体
-//   - 字段：Title, Author, ISBN, Price, publishSecond
+//   - 字段：Title, Author, ISBN, Price, PublishDate
 //   - 实现 ApplyDiscount(discountPercent float64) 打折
 //   - 实现 GetAge() 返回书的"年龄"
 //   - 实现 String() string 方法（格式化输出）
+//
+// 出版日期用 jsontype.Date：只关心哪一天，没有时分秒和时区，
+// 用 time.Time 时同一本书在不同时区会算出不同的出版日和"年龄"
+//
+// 字段不导出，外部只能通过方法修改价格；JSON 的键名见 bookJSON
 type Book struct {
 	title         string
 	author        string
 	isbn          string
-	price         float32
-	publishSecond time.Time
+	price         float32 // 当前售价，打折后改变
+	originalPrice float32 // 定价，打折都以它为基准
+	published     jsontype.Date
 }
 
+// ErrInvalidDiscount 折扣不在 (0, 100) 之间
+var ErrInvalidDiscount = errors.New("无效的折扣")
+
+// DiscountError ApplyDiscount 的错误，带上出错的折扣
+// errors.As 取出具体的折扣，errors.Is(err, ErrInvalidDiscount) 判断错误类别
+type DiscountError struct {
+	Percent float32
+}
+
+func (e *DiscountError) Error() string {
+	return fmt.Sprintf("%v: %g 不在 (0, 100) 之间", ErrInvalidDiscount, e.Percent)
+}
+
+func (e *DiscountError) Unwrap() error { return ErrInvalidDiscount }
+
 // create one book
-func NewBook(title string, author string, isbn string, price float32, publishSecond time.Time) *Book {
+func NewBook(title string, author string, isbn string, price float32, published jsontype.Date) *Book {
 	return &Book{
 		title:         title,
 		author:        author,
 		isbn:          isbn,
 		price:         price,
-		publishSecond: publishSecond,
+		originalPrice: price,
+		published:     published,
 	}
 }
 
+// ApplyDiscount 按定价的 discountPercent% 设置售价（70 即七折），返回新的售价
+// 以定价为基准，连续打两次七折仍是七折，不会变成四九折；出错时售价不变
 func (obj *Book) ApplyDiscount(discountPercent float32) (discountPrice float32, err error) {
-	// default value
-	discountPrice = obj.price
-
 	// early check
 	if discountPercent <= 0 || discountPercent >= 100 {
-		errInfo := fmt.Sprintln("discountPercent should with in (0,100), but got ", discountPercent)
-		err = errors.New(errInfo)
-		return
+		return obj.price, &DiscountError{Percent: discountPercent}
 	}
 
 	// calculate discount price
-	discountPrice = obj.price * discountPercent * 0.01
-	return discountPrice, nil
+	obj.price = obj.originalPrice * discountPercent * 0.01
+	return obj.price, nil
 }
 
-func (obj *Book) GetOriginalPrice() float32 {
+// GetPrice 当前售价
+func (obj *Book) GetPrice() float32 {
 	return obj.price
 }
 
+func (obj *Book) GetOriginalPrice() float32 {
+	return obj.originalPrice
+}
+
+// GetAge 出版至今的天数
 func (obj *Book) GetAge() int {
-	curSec := time.Now().Unix()
-	pubSec := obj.publishSecond.Unix()
-	fmt.Println("pubSec:", pubSec, ", now curSec:", curSec)
-	return int((curSec - pubSec) / 60 / 60 / 24)
+	return jsontype.DateOf(time.Now()).DaysSince(obj.published)
+}
+
+// String 实现 fmt.Stringer：《书名》 作者 ISBN 售价，打折时带上定价
+func (obj *Book) String() string {
+	s := fmt.Sprintf("《%s》 %s ISBN %s ¥%.2f", obj.title, obj.author, obj.isbn, obj.price)
+	if obj.price != obj.originalPrice {
+		s += fmt.Sprintf("（定价 ¥%.2f）", obj.originalPrice)
+	}
+	return s + "，出版于 " + obj.published.String()
 }
 
 func (obj *Book) PrintAll() {
@@ -891,8 +1112,162 @@ func (obj *Book) PrintAll() {
 	fmt.Println("author:", obj.author)
 	fmt.Println("isbn:", obj.isbn)
 	fmt.Println("price:", obj.price)
-	fmt.Println("publish time:", obj.publishSecond.Format(time.DateTime))
-	name, offset := obj.publishSecond.Zone()
-	fmt.Println("publish timezone:", name, ", offset:", offset)
-	fmt.Println("publish time in local:", obj.publishSecond.Local().Format(time.DateTime))
+	fmt.Println("original price:", obj.originalPrice)
+	fmt.Println("publish date:", obj.published)
+}
+
+// bookJSON Book 的 JSON 形式
+// encoding/json 只处理导出字段，未导出字段上的 tag 不起作用，所以借一个导出字段的结构体
+type bookJSON struct {
+	Title         string        `json:"title"`
+	Author        string        `json:"author"`
+	ISBN          string        `json:"isbn"`
+	Price         float32       `json:"price"`
+	OriginalPrice float32       `json:"original_price"`
+	Published     jsontype.Date `json:"published"`
+}
+
+// MarshalJSON 输出全部字段，出版日期为 "2006-01-02"
+func (obj *Book) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bookJSON{
+		Title:         obj.title,
+		Author:        obj.author,
+		ISBN:          obj.isbn,
+		Price:         obj.price,
+		OriginalPrice: obj.originalPrice,
+		Published:     obj.published,
+	})
+}
+
+// UnmarshalJSON 与 MarshalJSON 对称；没有 original_price 时定价等于售价
+func (obj *Book) UnmarshalJSON(data []byte) error {
+	var v bookJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.OriginalPrice == 0 {
+		v.OriginalPrice = v.Price
+	}
+	*obj = Book{
+		title:         v.Title,
+		author:        v.Author,
+		isbn:          v.ISBN,
+		price:         v.Price,
+		originalPrice: v.OriginalPrice,
+		published:     v.Published,
+	}
+	return nil
+}
+
+func checkBook() error {
+	published, err := jsontype.ParseDate("2000-01-01")
+	if err != nil {
+		return err
+	}
+	book := NewBook("Go 语言圣经", "Donovan", "9787111558422", 80, published)
+
+	_, err = book.ApplyDiscount(120)
+	var de *DiscountError
+	if !errors.As(err, &de) || de.Percent != 120 || !errors.Is(err, ErrInvalidDiscount) {
+		return fmt.Errorf("ApplyDiscount(120) 返回 %v，期望 Percent 为 120 的 *DiscountError", err)
+	}
+	for range 2 {
+		if price, err := book.ApplyDiscount(75); err != nil || price != 60 {
+			return fmt.Errorf("ApplyDiscount(75) 返回 %v, %v，期望 60（以定价为基准）", price, err)
+		}
+	}
+	if book.GetPrice() != 60 || book.GetOriginalPrice() != 80 {
+		return fmt.Errorf("打折后售价 %v、定价 %v，期望 60 和 80", book.GetPrice(), book.GetOriginalPrice())
+	}
+
+	want := "《Go 语言圣经》 Donovan ISBN 9787111558422 ¥60.00（定价 ¥80.00），出版于 2000-01-01"
+	if got := fmt.Sprint(book); got != want {
+		return fmt.Errorf("String() = %q，期望 %q", got, want)
+	}
+
+	data, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+	wantJSON := `{"title":"Go 语言圣经","author":"Donovan","isbn":"9787111558422","price":60,"original_price":80,"published":"2000-01-01"}`
+	if string(data) != wantJSON {
+		return fmt.Errorf("JSON 为 %s，期望 %s", data, wantJSON)
+	}
+	var back Book
+	if err := json.Unmarshal(data, &back); err != nil {
+		return err
+	}
+	if back != *book {
+		return fmt.Errorf("JSON 往返后为 %v，期望 %v", &back, book)
+	}
+	return nil
+}
+
+func checkEmbedding() error {
+	student := &MyStudent{MyPerson: MyPerson{Name: "Jim", Age: 12}, major: "数学", grades: []float32{80, 90}}
+	if student.Name != "Jim" || student.Age != 12 {
+		return fmt.Errorf("提升的字段为 %q、%d，期望 Jim、12", student.Name, student.Age)
+	}
+	if got, want := student.Introduce(), "我是Jim，12 岁，数学专业的学生"; got != want {
+		return fmt.Errorf("MyStudent.Introduce() = %q，期望 %q", got, want)
+	}
+	if got, want := student.MyPerson.Introduce(), "我是Jim，12 岁"; got != want {
+		return fmt.Errorf("被遮蔽的 MyPerson.Introduce() = %q，期望 %q", got, want)
+	}
+
+	teacher := &MyTeacher{MyPerson: MyPerson{Name: "Lucy", Age: 35}, teacherID: "T001", department: "数学系", salary: 10000}
+	var who Introducer = teacher
+	if got, want := who.Introduce(), "我是Lucy，35 岁"; got != want {
+		return fmt.Errorf("MyTeacher 提升的 Introduce() = %q，期望 %q", got, want)
+	}
+	if _, err := teacher.GiveRaise(-5); !errors.Is(err, ErrInvalidRaise) {
+		return fmt.Errorf("GiveRaise(-5) 返回 %v，期望 ErrInvalidRaise", err)
+	}
+	if salary, err := teacher.GiveRaise(10); err != nil || salary != 11000 {
+		return fmt.Errorf("GiveRaise(10) 返回 %v, %v，期望 11000", salary, err)
+	}
+	if got, want := teacher.Info(), "Lucy（35 岁）工号 T001，数学系，月薪 11000.00"; got != want {
+		return fmt.Errorf("Info() = %q，期望 %q", got, want)
+	}
+	return nil
+}
+
+func checkRectangle() error {
+	r := Rectangle{X: 1, Y: 1, Width: 4, Height: 2}
+	var s shape.Shape = r
+	if s.Area() != 8 || s.Perimeter() != 12 {
+		return fmt.Errorf("面积 %v、周长 %v，期望 8、12", s.Area(), s.Perimeter())
+	}
+	if got := r.Scale(2); got != (Rectangle{X: 1, Y: 1, Width: 8, Height: 4}) || r.Width != 4 {
+		return fmt.Errorf("Scale(2) = %v（原矩形变为 %v），期望返回新矩形且原矩形不变", got, r)
+	}
+
+	rotated := r.Rotate()
+	if rotated != (Rectangle{X: 2, Y: 0, Width: 2, Height: 4}) {
+		return fmt.Errorf("Rotate() = %v，期望绕中心 (3, 2) 旋转后为 {X=2, Y=0, Width=2, Height=4}", rotated)
+	}
+	if rotated.Rotate().Rotate().Rotate() != r {
+		return fmt.Errorf("旋转四次后为 %v，期望回到 %v", rotated.Rotate().Rotate().Rotate(), r)
+	}
+
+	for _, c := range []struct {
+		x, y float64
+		want bool
+	}{
+		{1, 1, true}, {4.9, 2.9, true}, {3, 2, true},
+		{5, 2, false}, {3, 3, false}, {0.9, 2, false},
+	} {
+		if got := r.Contains(c.x, c.y); got != c.want {
+			return fmt.Errorf("Contains(%v, %v) = %v，期望 %v", c.x, c.y, got, c.want)
+		}
+	}
+
+	u := r.Union(Rectangle{X: -1, Y: 2, Width: 3, Height: 3})
+	if u != (Rectangle{X: -1, Y: 1, Width: 6, Height: 4}) {
+		return fmt.Errorf("Union = %v，期望 {X=-1, Y=1, Width=6, Height=4}", u)
+	}
+	if got := r.Union(Rectangle{X: 100, Y: 100}); got != r {
+		return fmt.Errorf("与空矩形合并得到 %v，期望 %v", got, r)
+	}
+	return nil
 }
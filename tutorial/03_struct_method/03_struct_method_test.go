@@ -0,0 +1,47 @@
+package structmethod
+
+import (
+	"testing"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+func TestMyCacheExpiresWithFakeClock(t *testing.T) {
+	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := &MyCache{
+		data:  make(map[string]interface{}),
+		ttl:   make(map[string]time.Time),
+		clock: fc,
+	}
+	cache.Set("k", 78, 2*time.Second)
+
+	// 用 Advance 推进时间，不需要真的等待 2 秒
+	steps := []struct {
+		advance time.Duration
+		expired bool
+	}{
+		{0, false},
+		{time.Second, false},
+		{time.Second - time.Nanosecond, false},
+		{time.Nanosecond, true}, // 恰好到期即视为过期
+		{time.Hour, true},
+	}
+	for _, s := range steps {
+		fc.Advance(s.advance)
+		v, expired := cache.Get("k")
+		if v != 78 || expired != s.expired {
+			t.Fatalf("推进 %v 后 Get = (%v, %v)，期望 (78, %v)", s.advance, v, expired, s.expired)
+		}
+	}
+
+	// 重新 Set 从当前时间开始计算过期时间
+	cache.Set("k", 79, time.Second)
+	if v, expired := cache.Get("k"); v != 79 || expired {
+		t.Fatalf("重新 Set 后 Get = (%v, %v)", v, expired)
+	}
+
+	if v, expired := cache.Get("missing"); v != nil || expired {
+		t.Fatalf("不存在的键 Get = (%v, %v)，期望 (nil, false)", v, expired)
+	}
+}
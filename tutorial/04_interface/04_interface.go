@@ -19,7 +19,7 @@
 // 5. 避免使用空接口，除非确实需要处理任意类型
 // ============================================
 
-package main
+package interfaces
 
 import (
 	"bytes"
@@ -29,7 +29,12 @@ import (
 	"io"
 	"math"
 	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
+
+	"c03/pkg/shape"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -465,6 +470,121 @@ func demonstrateDependencyInjection() {
 	}
 }
 
+// ============================================
+// 13. 实用示例：同一个对象的多种输出格式
+// ============================================
+//
+// IShow 让类型自己决定如何展示：纯文本、JSON、表格行。
+// ShowAll 只依赖接口，统一处理任意实现了 IShow 的集合。
+//
+// 表头是可选能力：单独定义 IShowHeader 小接口，
+// ShowAll 通过类型断言检查第一个元素是否实现了它（可选接口模式）。
+
+type IShow interface {
+	ShowText() string
+	ShowJSON() ([]byte, error)
+	ShowRow() []string // 表格中的一行，每个元素是一列
+}
+
+type IShowHeader interface {
+	ShowHeader() []string
+}
+
+type ShowFormat int
+
+const (
+	ShowFormatText ShowFormat = iota
+	ShowFormatJSON
+	ShowFormatRow
+)
+
+// 编译期检查 Point 和 Rectangle 是否实现了接口
+var (
+	_ IShow       = Point{}
+	_ IShow       = Rectangle{}
+	_ IShowHeader = Rectangle{}
+)
+
+func (p Point) ShowText() string {
+	return p.String()
+}
+
+func (p Point) ShowJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p Point) ShowRow() []string {
+	return []string{"point", fmt.Sprint(p.X), fmt.Sprint(p.Y)}
+}
+
+func (r Rectangle) ShowText() string {
+	return r.String()
+}
+
+func (r Rectangle) ShowJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r Rectangle) ShowRow() []string {
+	return []string{"rectangle", fmt.Sprint(r.Width), fmt.Sprint(r.Height), fmt.Sprint(r.Width * r.Height)}
+}
+
+func (r Rectangle) ShowHeader() []string {
+	return []string{"TYPE", "WIDTH", "HEIGHT", "AREA"}
+}
+
+// ShowAll 按指定格式打印集合
+func ShowAll(items []IShow, format ShowFormat) error {
+	switch format {
+	case ShowFormatText:
+		for _, item := range items {
+			fmt.Println(item.ShowText())
+		}
+	case ShowFormatJSON:
+		for _, item := range items {
+			data, err := item.ShowJSON()
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+	case ShowFormatRow:
+		// tabwriter 按 \t 分列并补齐空格，实现列对齐
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		if len(items) > 0 {
+			if h, ok := items[0].(IShowHeader); ok {
+				fmt.Fprintln(tw, strings.Join(h.ShowHeader(), "\t")+"\t")
+			}
+		}
+		for _, item := range items {
+			fmt.Fprintln(tw, strings.Join(item.ShowRow(), "\t")+"\t")
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("未知的输出格式: %d", format)
+	}
+	return nil
+}
+
+func demonstrateShow() {
+	fmt.Println("\n=== 多种输出格式 ===")
+
+	rects := []IShow{
+		Rectangle{Width: 3, Height: 4},
+		Rectangle{Width: 120, Height: 5},
+		Rectangle{Width: 7, Height: 1000},
+	}
+	ShowAll(rects, ShowFormatRow)
+
+	mixed := []IShow{Point{X: 1, Y: 2}, Rectangle{Width: 3, Height: 4}}
+	ShowAll(mixed, ShowFormatText)
+	ShowAll(mixed, ShowFormatJSON)
+
+	if err := ShowAll(mixed, ShowFormat(99)); err != nil {
+		fmt.Println("错误:", err)
+	}
+}
+
 // ============================================
 // 主函数
 // ============================================
@@ -473,7 +593,69 @@ func Separator04() {
 	fmt.Println("=================================")
 }
 
-func main() {
+// ============================================
+// 入口
+// ============================================
+
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "04",
+		Name:  "04_interface",
+		Title: "接口：多态、类型断言、空接口、接口组合",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "1", Title: "Shape 接口：圆形、矩形、三角形", Check: checkShapes},
+			{ID: "2", Title: "Comparable 接口与 Max / MaxOf", Check: checkComparable},
+			{ID: "3", Title: "Handler 接口、HandlerFunc 与路由", Check: checkRouter},
+			{ID: "4", Title: "事件总线：订阅、取消订阅、同步/异步发布", Check: checkEventBus},
+			{ID: "5", Title: "用 interface{} 实现栈", Check: checkStack},
+			{ID: "6", Title: "Sorter 接口：冒泡排序与快速排序", Check: checkSorters},
+		},
+	})
+}
+
+// checkShapes 检查练习 1
+func checkShapes() error {
+	const eps = 1e-9
+	shapes := []shape.Shape{
+		shape.Circle{Radius: 1},
+		shape.Rectangle{Width: 3, Height: 4},
+		&shape.Triangle{A: 3, B: 4, C: 5}, // 指针也满足接口
+	}
+	wantArea := []float64{math.Pi, 12, 6}
+	wantPerimeter := []float64{2 * math.Pi, 14, 12}
+	for i, s := range shapes {
+		if math.Abs(s.Area()-wantArea[i]) > eps || math.Abs(s.Perimeter()-wantPerimeter[i]) > eps {
+			return fmt.Errorf("%v: 面积 %v、周长 %v，期望 %v、%v", s, s.Area(), s.Perimeter(), wantArea[i], wantPerimeter[i])
+		}
+	}
+	if got := shape.TotalArea(append(shapes, nil)); math.Abs(got-(math.Pi+18)) > eps {
+		return fmt.Errorf("TotalArea = %v，期望 π+18", got)
+	}
+	if got := shape.TotalArea(nil); got != 0 {
+		return fmt.Errorf("TotalArea(nil) = %v，期望 0", got)
+	}
+
+	var buf bytes.Buffer
+	shape.PrintShapeInfo(&buf, shape.Rectangle{Width: 3, Height: 4})
+	if out := buf.String(); !strings.Contains(out, "Rectangle{Width=3, Height=4}") || !strings.Contains(out, "面积=12.00") {
+		return fmt.Errorf("PrintShapeInfo 输出 %q，缺少名称或面积", out)
+	}
+
+	if _, err := shape.NewTriangle(1, 2, 3); !errors.Is(err, shape.ErrInvalidShape) {
+		return fmt.Errorf("NewTriangle(1, 2, 3) 返回 %v，期望 ErrInvalidShape", err)
+	}
+	if _, err := shape.NewCircle(-1); !errors.Is(err, shape.ErrInvalidShape) {
+		return fmt.Errorf("NewCircle(-1) 返回 %v，期望 ErrInvalidShape", err)
+	}
+	if _, err := shape.NewRectangle(math.NaN(), 1); !errors.Is(err, shape.ErrInvalidShape) {
+		return fmt.Errorf("NewRectangle(NaN, 1) 返回 %v，期望 ErrInvalidShape", err)
+	}
+	return nil
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 04
+func Run() {
 	demonstratePolymorphism()
 	demonstrateEmptyInterface()
 	demonstrateTypeAssertion()
@@ -483,6 +665,7 @@ func main() {
 	demonstrateStringer()
 	demonstrateStandardInterfaces()
 	demonstrateDependencyInjection()
+	demonstrateShow()
 
 	// ============================================
 	// 练习题
@@ -493,11 +676,20 @@ func main() {
 	//   - 编写函数 PrintShapeInfo(s Shape) 打印形状信息
 	//   - 创建 Shape 切片，遍历并打印每个形状的信息
 	//
+	//   实现见 pkg/shape（第 4 课已有 Rectangle，单独成包避免重名），另加了 Triangle
+	//
 	Separator04()
-	shapes := []IShape{&Circle{radius: 2.3}, &MyRectangle{length: 2.3, width: 2.3}}
-	for _, shape := range shapes {
-		fmt.Println("area:", shape.Area())
-		fmt.Println("Perimeter:", shape.Perimeter())
+	shapes := []shape.Shape{
+		shape.Circle{Radius: 2.3},
+		shape.Rectangle{Width: 2.3, Height: 4},
+		shape.Triangle{A: 3, B: 4, C: 5},
+	}
+	for _, s := range shapes {
+		shape.PrintShapeInfo(os.Stdout, s)
+	}
+	fmt.Printf("总面积: %.2f\n", shape.TotalArea(shapes))
+	if _, err := shape.NewTriangle(1, 2, 3); err != nil {
+		fmt.Println("NewTriangle(1, 2, 3):", err)
 	}
 
 	// 练习 2：实现一个通用的 Max 函数，使用接口比较大小
@@ -505,12 +697,15 @@ func main() {
 	//   - 实现 Int 和 String 类型满足该接口
 	//   - 实现 Max(a, b Comparable) Comparable 返回较大者
 	//
+	//   实现见 comparable.go，另加了 MaxOf(items []Comparable)
+	//
 	Separator04()
-	datas := []IComparable{Int{data: 9}}
-	for _, data := range datas {
-		fmt.Println("data:", (data.(Int)).data)
-		maxData, _ := max(data, Int{data: 1})
-		fmt.Println("max data:", maxData.data)
+	m, _ := Max(Int(9), Int(1))
+	fmt.Println("Max(9, 1):", m)
+	m, _ = MaxOf([]Comparable{Str("go"), Str("rust"), Str("c")})
+	fmt.Println("MaxOf(go, rust, c):", m)
+	if _, err := MaxOf([]Comparable{Int(1), Str("2")}); err != nil {
+		fmt.Println("MaxOf(1, \"2\"):", err)
 	}
 
 	// 练习 3：实现一个简单的 HTTP Handler 接口模拟
@@ -519,10 +714,16 @@ func main() {
 	//   - 使用 map[string]Handler 实现路由
 	//   - 编写函数处理请求：func Handle(path string, handlers map[string]Handler)
 	//
+	//   实现见 router.go，仿照 net/http 增加了 HandlerFunc 和 NotFound
+	//
 	Separator04()
-	Handler("home")
-	Handler("notfound")
-	Handler("about")
+	router := NewRouter()
+	router.Handle("/", HomeHandler{})
+	router.Handle("/about", AboutHandler{})
+	router.HandleFunc("/time", func(string) string { return "200 " + time.Now().Format(time.TimeOnly) })
+	for _, req := range []string{"/", "/about", "/time", "/admin"} {
+		fmt.Printf("%-8s -> %s\n", req, router.ServeHTTP(req))
+	}
 
 	// 练习 4：实现一个事件系统
 	//   - 定义 Event 接口，包含 Type() string 和 Data() interface{}
@@ -530,38 +731,33 @@ func main() {
 	//   - 定义 EventHandler 接口，包含 Handle(e Event)
 	//   - 实现 EventBus，支持订阅和发布事件
 	//
+	//   实现见 eventbus.go：Subscribe / Unsubscribe / Publish，同步与异步两种分发，
+	//   每个 handler 的 panic 单独 recover
+	//
 	Separator04()
-	eventBus := EventBus{
-		events:        make(chan IEvent, 5),
-		eventRouteMap: EventRouteMap{},
-		stopSignal:    make(chan bool),
-		stoppedSignal: make(chan bool),
-	}
-	eventBus.Register(Event_UserLogin, UserLoginHandler)
-	eventBus.Register(Event_OrderCreate, OrderCreateHandler)
-
-	go eventBus.HandleEvents()
-
-	event0 := UserLoginEvent{
-		EventType: Event_UserLogin,
-		UserName:  "Jim",
-		Date:      time.Now(),
-	}
-	event1 := OrderCreateEvent{
-		EventType: Event_OrderCreate,
-		Date:      time.Now(),
-		OrderNum:  "123456789",
+	bus := NewEventBus(WithPanicHandler(func(e Event, v any) {
+		fmt.Printf("handler 处理 %s 时 panic: %v\n", e.Type(), v)
+	}))
+	bus.SubscribeFunc(EventUserLogin, func(e Event) {
+		fmt.Println("欢迎回来:", e.Data())
+	})
+	audit := bus.SubscribeFunc(EventOrderCreated, func(e Event) {
+		fmt.Println("审计日志:", e.Data())
+	})
+	bus.SubscribeFunc(EventOrderCreated, func(e Event) {
+		if e.(OrderCreatedEvent).Amount <= 0 {
+			panic("订单金额必须为正数")
+		}
+		fmt.Println("发送订单通知:", e.(OrderCreatedEvent).OrderID)
+	})
+	bus.Publish(UserLoginEvent{User: "Jim", At: time.Now().Truncate(time.Second)})
+	bus.Publish(OrderCreatedEvent{OrderID: "123456789", Amount: 99.5})
+	if err := bus.Publish(OrderCreatedEvent{OrderID: "000", Amount: 0}); err != nil {
+		fmt.Println("Publish 返回:", err)
 	}
-	eventBus.Signal(event0)
-	eventBus.Signal(event1)
-
-	// fire stop signal to kill event bus
-	go func() {
-		time.Sleep(time.Second * 2)
-		eventBus.Stop()
-	}()
-
-	eventBus.Wait()
+	bus.Unsubscribe(audit)
+	bus.Publish(OrderCreatedEvent{OrderID: "987654321", Amount: 10})
+	bus.Close()
 
 	// 练习 5：使用空接口实现一个泛型栈（Go 1.18 之前的做法）
 	//   type Stack struct { items []interface{} }
@@ -571,241 +767,34 @@ func main() {
 	//   - 实现 IsEmpty() bool
 	//   - 注意：使用时需要进行类型断言
 	//
+	//   实现见 stack.go；与第 8 课泛型 Stack[T] 的性能对比：go run ./cmd/stackbench
+	//
+	Separator04()
+	var stack Stack
+	stack.Push(42)
+	stack.Push("hello")
+	for !stack.IsEmpty() {
+		v, _ := stack.Pop()
+		switch v := v.(type) {
+		case int:
+			fmt.Println("弹出 int:", v+1)
+		case string:
+			fmt.Println("弹出 string:", strings.ToUpper(v))
+		}
+	}
+
 	// 练习 6：实现一个可排序的接口体系
 	//   - 定义 Sorter 接口，包含 Sort([]interface{}) []interface{}
 	//   - 实现 BubbleSorter、QuickSorter
 	//   - 实现一个通用函数，接收 Sorter 和待排序数据，返回排序结果
-}
-
-// 练习 4：实现一个事件系统
-//   - 定义 Event 接口，包含 Type() string 和 Data() interface{}
-//   - 实现 UserLoginEvent、OrderCreatedEvent
-//   - 定义 EventHandler 接口，包含 Handle(e Event)
-//   - 实现 EventBus，支持订阅和发布事件
-type IEvent interface {
-	Type() string
-	Data() any
-}
-
-const (
-	Event_UserLogin   = "UserLogin"
-	Event_OrderCreate = "OrderCreate"
-)
-
-type EventType = string
-
-type UserLoginEvent struct {
-	EventType EventType `json:"event_type"`
-	UserName  string    `json:"user_name"`
-	Date      time.Time `json:"login_date"`
-}
-type OrderCreateEvent struct {
-	EventType EventType `json:"event_type"`
-	Date      time.Time `json:"login_date"`
-	OrderNum  string    `json:"order_num"`
-}
-
-func (obj UserLoginEvent) Type() string {
-	return obj.EventType
-}
-
-func (obj UserLoginEvent) Data() any {
-	jsonData, err := json.Marshal(obj)
-
-	if err != nil {
-		fmt.Println("json marshal error, ", err, ", origin data:", obj)
-		return ""
-	}
-
-	return string(jsonData)
-}
-
-func (obj OrderCreateEvent) Type() string {
-	return obj.EventType
-}
-
-func (obj OrderCreateEvent) Data() any {
-	jsonData, err := json.Marshal(obj)
-
-	if err != nil {
-		fmt.Println("json marshal error, ", err, ", origin data:", obj)
-		return ""
-	}
-
-	return string(jsonData)
-}
-
-type HandleFunc = func(event IEvent)
-type EventRouteMap = map[EventType]HandleFunc
-
-type EventBus struct {
-	events        chan IEvent
-	eventRouteMap EventRouteMap
-	stopSignal    chan bool
-	stoppedSignal chan bool
-}
-
-func (obj *EventBus) Register(eventType EventType, handleFunc HandleFunc) {
-	obj.eventRouteMap[eventType] = handleFunc
-	fmt.Println("register event on func, ", eventType, handleFunc)
-}
-
-func (obj *EventBus) Signal(event IEvent) {
-	obj.events <- event
-}
-
-func (obj *EventBus) Stop() {
-	obj.stopSignal <- true
-}
-
-func (obj *EventBus) Wait() {
-	defer close(obj.events)
-
-	stopped := <-obj.stoppedSignal
-	fmt.Println("event bus stopped, ", stopped)
-}
-
-func (obj *EventBus) HandleEvents() {
-	defer func() {
-		fmt.Println("HandleEvents quit")
-		obj.stoppedSignal <- true
-	}()
-
-LOOP:
-	for {
-		select {
-		case event := <-obj.events:
-			if handleFunc, ok := obj.eventRouteMap[event.Type()]; ok {
-				go handleFunc(event)
-			}
-		case stop := <-obj.stopSignal:
-			fmt.Println("got stop signal:", stop)
-			break LOOP
-		}
-	}
-}
-
-func UserLoginHandler(event IEvent) {
-	fmt.Println("func UserLoginHandler, event type:", event.Type(), ", data:", event.Data().(string))
-}
-
-func OrderCreateHandler(event IEvent) {
-	fmt.Println("func OrderCreateHandler, event type:", event.Type(), ", data:", event.Data().(string))
-}
-
-// 练习 3：实现一个简单的 HTTP Handler 接口模拟
-//   - 定义 Handler 接口，包含 ServeHTTP(request string) string
-//   - 实现 HomeHandler、AboutHandler、NotFoundHandler
-//   - 使用 map[string]Handler 实现路由
-//   - 编写函数处理请求：func Handle(path string, handlers map[string]Handler)
-//
-
-type IHttpHandler interface {
-	ServeHttp(request string) string
-}
-
-type HomeHandler struct{}
-
-func (obj HomeHandler) ServeHttp(request string) string {
-	return "this is home page"
-}
-
-type AboutHandler struct{}
-
-func (obj AboutHandler) ServeHttp(request string) string {
-	return "this is about page"
-}
-
-type NotFoundHandler struct{}
-
-func (obj NotFoundHandler) ServeHttp(request string) string {
-	return "this is 404 page"
-}
-
-func Handler(request string) {
-	type RouterMap = map[string]IHttpHandler
-	routerMap := RouterMap{}
-
-	routerMap["home"] = &HomeHandler{}
-	routerMap["about"] = &AboutHandler{}
-	routerMap["notfound"] = &NotFoundHandler{}
-
-	if handler, ok := routerMap[request]; ok {
-		resp := handler.ServeHttp(request)
-		fmt.Println("request:", request, ", response:", resp)
-	}
-}
-
-// ///////////////////////////
-type IComparable interface {
-	Compare(other any) (int, error)
-}
-
-type Int struct {
-	data int
-}
-
-func (obj Int) Compare(other any) (int, error) {
-	var err error
-	val, ok := other.(Int)
-	if ok {
-		if obj.data < val.data {
-			return -1, nil
-		} else if obj.data == val.data {
-			return 0, nil
-		} else {
-			return 1, nil
-		}
-	} else {
-		err = errors.New("input is not Int type")
-	}
-	return 0, err
-}
-
-func max(a, b IComparable) (Int, error) {
-	aLocal, _ := a.(Int)
-	bLocal, _ := b.(Int)
-	ret, err := aLocal.Compare(bLocal)
-	if err != nil {
-		fmt.Println("error:", err.Error())
-		return Int{data: 0}, err
-	}
-
-	if ret < 0 {
-		fmt.Println("b is bigger:", bLocal.data, ", while a is:", aLocal.data)
-		return bLocal, nil
+	//
+	//   实现见 sorter.go：Sorter 接收 sort.Interface，SortWith 是泛型入口
+	//
+	Separator04()
+	words := []string{"banana", "kiwi", "apple", "fig", "cherry"}
+	for _, sorter := range []Sorter{BubbleSorter{}, QuickSorter{}} {
+		byLen := SortWith(sorter, words, func(a, b string) bool { return len(a) < len(b) })
+		fmt.Printf("%-24T %v\n", sorter, byLen)
 	}
-	return aLocal, nil
-}
-
-/////////////////////////////////
-
-type IShape interface {
-	Area() float64
-	Perimeter() float64
-}
-
-type Circle struct {
-	radius float64
-}
-
-func (obj *Circle) Area() float64 {
-	return math.Pi * obj.radius * obj.radius
-}
-
-func (obj *Circle) Perimeter() float64 {
-	return 2 * math.Pi * obj.radius
-}
-
-type MyRectangle struct {
-	width  float64
-	length float64
-}
-
-func (obj *MyRectangle) Area() float64 {
-	return obj.width * obj.length
-}
-
-func (obj *MyRectangle) Perimeter() float64 {
-	return 2 * (obj.length + obj.width)
+	fmt.Println("原切片不变:", words)
 }
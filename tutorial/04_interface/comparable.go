@@ -0,0 +1,124 @@
+// ============================================
+// 练习 2：Comparable 接口与 Max
+// ============================================
+//
+// 泛型出现之前，"能比较大小的任意类型"只能用接口表达：
+//
+//   type Comparable interface { Compare(other any) int }
+//
+// 参数是 any，编译器无法保证 Int 只和 Int 比较，所以 Max / MaxOf 在调用
+// Compare 之前先检查两边的动态类型，不一致时返回 ErrTypeMismatch。
+// 对比第 8 课：func Max[T cmp.Ordered](a, b T) T 在编译期就排除了这种错误。
+// ============================================
+
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrTypeMismatch = errors.New("类型不一致，无法比较")
+	ErrNoItems      = errors.New("没有可比较的元素")
+)
+
+// Comparable 可以与同类型的值比较大小
+// Compare 返回负数、0、正数分别表示小于、等于、大于 other；
+// other 必须与接收者是同一类型，否则 panic（由调用方保证，见 Max）
+type Comparable interface {
+	Compare(other any) int
+}
+
+// Int 可比较的整数
+type Int int
+
+func (i Int) Compare(other any) int {
+	o := other.(Int)
+	switch {
+	case i < o:
+		return -1
+	case i > o:
+		return 1
+	}
+	return 0
+}
+
+// Str 可比较的字符串，按字节序比较
+type Str string
+
+func (s Str) Compare(other any) int {
+	return strings.Compare(string(s), string(other.(Str)))
+}
+
+// Max 返回较大者，相等时返回 a；a、b 动态类型不同时返回 ErrTypeMismatch
+func Max(a, b Comparable) (Comparable, error) {
+	if err := sameType(a, b); err != nil {
+		return nil, err
+	}
+	if a.Compare(b) < 0 {
+		return b, nil
+	}
+	return a, nil
+}
+
+// MaxOf 返回切片中的最大值，多个相等时返回第一个
+// 空切片返回 ErrNoItems；类型不一致时错误中带上出错元素的下标
+func MaxOf(items []Comparable) (Comparable, error) {
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	best := items[0]
+	for i, v := range items[1:] {
+		if err := sameType(best, v); err != nil {
+			return nil, fmt.Errorf("第 %d 个元素: %w", i+1, err)
+		}
+		if best.Compare(v) < 0 {
+			best = v
+		}
+	}
+	return best, nil
+}
+
+func sameType(a, b Comparable) error {
+	if a == nil || b == nil {
+		return fmt.Errorf("%w: %T 与 %T", ErrTypeMismatch, a, b)
+	}
+	if ta, tb := reflect.TypeOf(a), reflect.TypeOf(b); ta != tb {
+		return fmt.Errorf("%w: %v 与 %v", ErrTypeMismatch, ta, tb)
+	}
+	return nil
+}
+
+// checkComparable 检查练习 2
+func checkComparable() error {
+	if m, err := Max(Int(3), Int(9)); err != nil || m != Int(9) {
+		return fmt.Errorf("Max(3, 9) = %v, %v，期望 9, nil", m, err)
+	}
+	if m, err := Max(Str("go"), Str("c")); err != nil || m != Str("go") {
+		return fmt.Errorf("Max(go, c) = %v, %v，期望 go, nil", m, err)
+	}
+	if _, err := Max(Int(1), Str("1")); !errors.Is(err, ErrTypeMismatch) {
+		return fmt.Errorf("Max(Int, Str) 返回 %v，期望 ErrTypeMismatch", err)
+	}
+	if _, err := Max(Int(1), nil); !errors.Is(err, ErrTypeMismatch) {
+		return fmt.Errorf("Max(Int, nil) 返回 %v，期望 ErrTypeMismatch", err)
+	}
+
+	if m, err := MaxOf([]Comparable{Int(4), Int(-2), Int(9), Int(0)}); err != nil || m != Int(9) {
+		return fmt.Errorf("MaxOf([4 -2 9 0]) = %v, %v，期望 9, nil", m, err)
+	}
+	if m, err := MaxOf([]Comparable{Str("b"), Str("rust"), Str("go")}); err != nil || m != Str("rust") {
+		return fmt.Errorf("MaxOf([b rust go]) = %v, %v，期望 rust, nil", m, err)
+	}
+	_, err := MaxOf([]Comparable{Int(1), Int(2), Str("3")})
+	if !errors.Is(err, ErrTypeMismatch) || !strings.Contains(err.Error(), "第 2 个元素") {
+		return fmt.Errorf("MaxOf 混合类型返回 %v，期望第 2 个元素的 ErrTypeMismatch", err)
+	}
+	if _, err := MaxOf(nil); !errors.Is(err, ErrNoItems) {
+		return fmt.Errorf("MaxOf(nil) 返回 %v，期望 ErrNoItems", err)
+	}
+	return nil
+}
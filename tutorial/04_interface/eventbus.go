@@ -0,0 +1,271 @@
+// ============================================
+// 练习 4：事件总线
+// ============================================
+//
+// 发布者只依赖 Event 接口，订阅者只依赖 EventHandler 接口，双方互不认识：
+//
+//   bus := NewEventBus()                           // 同步：Publish 返回时所有 handler 已执行完
+//   bus := NewEventBus(WithAsync())                // 异步：每个 handler 在单独的 goroutine 中执行
+//   id := bus.Subscribe(EventUserLogin, handler)
+//   bus.Publish(UserLoginEvent{User: "Jim"})
+//   bus.Unsubscribe(id)
+//   bus.Close()                                    // 等待异步 handler 执行完
+//
+// 一个 handler panic 不影响同一事件的其他 handler：同步模式下作为 ErrHandlerPanic
+// 从 Publish 返回，两种模式都会调用 WithPanicHandler 设置的回调。
+// ============================================
+
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 事件类型
+const (
+	EventUserLogin    = "user.login"
+	EventOrderCreated = "order.created"
+)
+
+var (
+	ErrHandlerPanic = errors.New("事件处理函数 panic")
+	ErrBusClosed    = errors.New("事件总线已关闭")
+)
+
+// Event 事件
+type Event interface {
+	Type() string
+	Data() any
+}
+
+// EventHandler 事件处理者
+type EventHandler interface {
+	Handle(e Event)
+}
+
+// EventHandlerFunc 让普通函数满足 EventHandler，与练习 3 的 HandlerFunc 同理
+type EventHandlerFunc func(e Event)
+
+func (f EventHandlerFunc) Handle(e Event) { f(e) }
+
+// UserLoginEvent 用户登录
+type UserLoginEvent struct {
+	User string
+	At   time.Time
+}
+
+func (e UserLoginEvent) Type() string { return EventUserLogin }
+func (e UserLoginEvent) Data() any    { return map[string]any{"user": e.User, "at": e.At} }
+
+// OrderCreatedEvent 订单创建
+type OrderCreatedEvent struct {
+	OrderID string
+	Amount  float64
+}
+
+func (e OrderCreatedEvent) Type() string { return EventOrderCreated }
+func (e OrderCreatedEvent) Data() any {
+	return map[string]any{"order_id": e.OrderID, "amount": e.Amount}
+}
+
+// SubscriptionID Subscribe 返回的订阅编号，用于 Unsubscribe
+type SubscriptionID uint64
+
+type subscription struct {
+	id SubscriptionID
+	h  EventHandler
+}
+
+// EventBus 按事件类型分发事件，并发安全
+type EventBus struct {
+	mu      sync.RWMutex
+	subs    map[string][]subscription
+	nextID  SubscriptionID
+	closed  bool
+	async   bool
+	onPanic func(e Event, v any)
+	wg      sync.WaitGroup
+}
+
+// BusOption EventBus 的可选配置
+type BusOption func(*EventBus)
+
+// WithAsync 异步分发：Publish 不等待 handler，Close 时统一等待
+func WithAsync() BusOption {
+	return func(b *EventBus) { b.async = true }
+}
+
+// WithPanicHandler handler panic 时的回调，v 为 recover() 的返回值
+// 异步模式下 panic 无法通过 Publish 返回，只能通过这个回调得知
+func WithPanicHandler(f func(e Event, v any)) BusOption {
+	return func(b *EventBus) { b.onPanic = f }
+}
+
+// NewEventBus 创建事件总线，默认同步分发
+func NewEventBus(opts ...BusOption) *EventBus {
+	b := &EventBus{subs: make(map[string][]subscription)}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscribe 订阅一种事件，同一事件的 handler 按订阅顺序调用（异步模式下不保证顺序）
+func (b *EventBus) Subscribe(eventType string, h EventHandler) SubscriptionID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	b.subs[eventType] = append(b.subs[eventType], subscription{id: b.nextID, h: h})
+	return b.nextID
+}
+
+// SubscribeFunc 订阅函数，等价于 Subscribe(eventType, EventHandlerFunc(f))
+func (b *EventBus) SubscribeFunc(eventType string, f func(e Event)) SubscriptionID {
+	return b.Subscribe(eventType, EventHandlerFunc(f))
+}
+
+// Unsubscribe 取消订阅，id 不存在时返回 false
+func (b *EventBus) Unsubscribe(id SubscriptionID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for typ, subs := range b.subs {
+		i := slices.IndexFunc(subs, func(s subscription) bool { return s.id == id })
+		if i < 0 {
+			continue
+		}
+		// 不能原地删除：Publish 可能正在遍历旧切片
+		b.subs[typ] = slices.Delete(slices.Clone(subs), i, i+1)
+		if len(b.subs[typ]) == 0 {
+			delete(b.subs, typ)
+		}
+		return true
+	}
+	return false
+}
+
+// Publish 发布事件
+// 同步模式下依次调用 handler，所有 panic 合并后返回；异步模式下总是返回 nil
+// 关闭后发布返回 ErrBusClosed
+func (b *EventBus) Publish(e Event) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	subs := b.subs[e.Type()]
+	if b.async {
+		// 在持有读锁时 Add，保证 Close 的 Wait 不会漏掉
+		b.wg.Add(len(subs))
+	}
+	b.mu.RUnlock()
+
+	if b.async {
+		for _, s := range subs {
+			go func() {
+				defer b.wg.Done()
+				b.dispatch(s.h, e)
+			}()
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, s := range subs {
+		if err := b.dispatch(s.h, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 停止接受新事件，并等待已发布的异步 handler 执行完
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// dispatch 调用一个 handler，把 panic 转换成错误
+func (b *EventBus) dispatch(h EventHandler, e Event) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrHandlerPanic, e.Type(), v)
+			if b.onPanic != nil {
+				b.onPanic(e, v)
+			}
+		}
+	}()
+	h.Handle(e)
+	return nil
+}
+
+// checkEventBus 检查练习 4
+func checkEventBus() error {
+	var got []string
+	bus := NewEventBus()
+	bus.SubscribeFunc(EventUserLogin, func(e Event) {
+		got = append(got, "a:"+e.(UserLoginEvent).User)
+	})
+	bad := bus.SubscribeFunc(EventUserLogin, func(Event) { panic("boom") })
+	bus.SubscribeFunc(EventUserLogin, func(e Event) {
+		got = append(got, "b:"+e.Data().(map[string]any)["user"].(string))
+	})
+	bus.SubscribeFunc(EventOrderCreated, func(Event) { got = append(got, "order") })
+
+	err := bus.Publish(UserLoginEvent{User: "Jim"})
+	if !errors.Is(err, ErrHandlerPanic) {
+		return fmt.Errorf("handler panic 时 Publish 返回 %v，期望 ErrHandlerPanic", err)
+	}
+	if !slices.Equal(got, []string{"a:Jim", "b:Jim"}) {
+		return fmt.Errorf("同步分发结果 %v，期望 [a:Jim b:Jim]（panic 不影响其他 handler）", got)
+	}
+
+	got = nil
+	if !bus.Unsubscribe(bad) || bus.Unsubscribe(bad) {
+		return errors.New("Unsubscribe 应当第一次返回 true、第二次返回 false")
+	}
+	if err := bus.Publish(UserLoginEvent{User: "Amy"}); err != nil {
+		return fmt.Errorf("取消订阅后 Publish 返回 %v", err)
+	}
+	if err := bus.Publish(OrderCreatedEvent{OrderID: "A1", Amount: 9.9}); err != nil {
+		return err
+	}
+	if !slices.Equal(got, []string{"a:Amy", "b:Amy", "order"}) {
+		return fmt.Errorf("取消订阅后分发结果 %v，期望 [a:Amy b:Amy order]", got)
+	}
+
+	var (
+		mu     sync.Mutex
+		count  int
+		panics int
+	)
+	async := NewEventBus(WithAsync(), WithPanicHandler(func(Event, any) {
+		mu.Lock()
+		panics++
+		mu.Unlock()
+	}))
+	async.SubscribeFunc(EventOrderCreated, func(Event) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+	async.SubscribeFunc(EventOrderCreated, func(Event) { panic("boom") })
+	for i := range 10 {
+		if err := async.Publish(OrderCreatedEvent{OrderID: fmt.Sprint(i)}); err != nil {
+			return fmt.Errorf("异步 Publish 返回 %v", err)
+		}
+	}
+	async.Close()
+	if count != 10 || panics != 10 {
+		return fmt.Errorf("异步分发 Close 后 count=%d panics=%d，期望 10 和 10", count, panics)
+	}
+	if err := async.Publish(OrderCreatedEvent{}); !errors.Is(err, ErrBusClosed) {
+		return fmt.Errorf("关闭后 Publish 返回 %v，期望 ErrBusClosed", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// ============================================
+// 练习 3：模拟 HTTP Handler 与路由
+// ============================================
+//
+// 仿照 net/http 的设计，用字符串代替 Request / ResponseWriter：
+//
+//   net/http                          本练习
+//   http.Handler                      Handler
+//   http.HandlerFunc                  HandlerFunc（函数类型实现接口）
+//   http.ServeMux                     Router（本身也是 Handler）
+//   mux.Handle / mux.HandleFunc       Handle / HandleFunc
+//   http.NotFoundHandler()            NotFoundHandler{}
+//
+// HandlerFunc 是"给函数类型定义方法"的典型用法：普通函数转换成 HandlerFunc
+// 后就满足 Handler 接口，不需要为每个处理逻辑单独定义结构体。
+// ============================================
+
+package interfaces
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Handler 处理一个请求，返回响应内容
+type Handler interface {
+	ServeHTTP(request string) string
+}
+
+// HandlerFunc 让普通函数满足 Handler 接口
+type HandlerFunc func(request string) string
+
+// ServeHTTP 调用 f(request)
+func (f HandlerFunc) ServeHTTP(request string) string { return f(request) }
+
+// HomeHandler 首页
+type HomeHandler struct{}
+
+func (HomeHandler) ServeHTTP(request string) string { return "200 首页" }
+
+// AboutHandler 关于页
+type AboutHandler struct{}
+
+func (AboutHandler) ServeHTTP(request string) string { return "200 关于我们" }
+
+// NotFoundHandler 找不到路径时的默认处理
+type NotFoundHandler struct{}
+
+func (NotFoundHandler) ServeHTTP(request string) string { return "404 找不到 " + request }
+
+// Router 按路径精确匹配的路由表，零值不可用，使用 NewRouter 创建
+type Router struct {
+	routes   map[string]Handler
+	notFound Handler
+}
+
+var _ Handler = (*Router)(nil)
+
+// NewRouter 创建空路由，未匹配的请求交给 NotFoundHandler
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]Handler), notFound: NotFoundHandler{}}
+}
+
+// Handle 注册路径，与 http.ServeMux 一样，路径为空、handler 为 nil 或重复注册时 panic
+// 这些都是程序写错了，而不是运行时可以处理的错误
+func (r *Router) Handle(path string, h Handler) {
+	if path == "" {
+		panic("router: 路径为空")
+	}
+	if h == nil {
+		panic("router: " + path + " 的 handler 为 nil")
+	}
+	if _, ok := r.routes[path]; ok {
+		panic("router: 重复注册 " + path)
+	}
+	r.routes[path] = h
+}
+
+// HandleFunc 注册函数，等价于 Handle(path, HandlerFunc(f))
+func (r *Router) HandleFunc(path string, f func(request string) string) {
+	r.Handle(path, HandlerFunc(f))
+}
+
+// NotFound 替换未匹配时的处理，h 为 nil 时恢复默认
+func (r *Router) NotFound(h Handler) {
+	if h == nil {
+		h = NotFoundHandler{}
+	}
+	r.notFound = h
+}
+
+// Routes 已注册的路径（已排序）
+func (r *Router) Routes() []string {
+	paths := make([]string, 0, len(r.routes))
+	for p := range r.routes {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// ServeHTTP 查找并调用路径对应的 Handler；请求中 ? 之后的查询参数不参与匹配
+func (r *Router) ServeHTTP(request string) string {
+	path, _, _ := strings.Cut(request, "?")
+	if h, ok := r.routes[path]; ok {
+		return h.ServeHTTP(request)
+	}
+	return r.notFound.ServeHTTP(request)
+}
+
+// checkRouter 检查练习 3
+func checkRouter() error {
+	r := NewRouter()
+	r.Handle("/", HomeHandler{})
+	r.Handle("/about", AboutHandler{})
+	r.HandleFunc("/hello", func(req string) string {
+		_, query, _ := strings.Cut(req, "?")
+		return "200 hello " + strings.TrimPrefix(query, "name=")
+	})
+
+	cases := []struct{ req, want string }{
+		{"/", "200 首页"},
+		{"/about", "200 关于我们"},
+		{"/hello?name=go", "200 hello go"},
+		{"/missing", "404 找不到 /missing"},
+	}
+	for _, c := range cases {
+		if got := r.ServeHTTP(c.req); got != c.want {
+			return fmt.Errorf("ServeHTTP(%q) = %q，期望 %q", c.req, got, c.want)
+		}
+	}
+
+	r.NotFound(HandlerFunc(func(req string) string { return "404 自定义" }))
+	if got := r.ServeHTTP("/missing"); got != "404 自定义" {
+		return fmt.Errorf("自定义 NotFound 后 ServeHTTP(/missing) = %q", got)
+	}
+	if got := r.Routes(); !slices.Equal(got, []string{"/", "/about", "/hello"}) {
+		return fmt.Errorf("Routes() = %v，期望 [/ /about /hello]", got)
+	}
+
+	// Router 本身是 Handler，可以挂到另一个 Router 下
+	outer := NewRouter()
+	outer.Handle("/about", r)
+	if got := outer.ServeHTTP("/about"); got != "200 关于我们" {
+		return fmt.Errorf("嵌套路由 ServeHTTP(/about) = %q", got)
+	}
+
+	if msg := catchPanic(func() { r.Handle("/", HomeHandler{}) }); msg == "" {
+		return fmt.Errorf("重复注册 / 应当 panic")
+	}
+	if msg := catchPanic(func() { r.Handle("/nil", nil) }); msg == "" {
+		return fmt.Errorf("注册 nil handler 应当 panic")
+	}
+	return nil
+}
+
+func catchPanic(f func()) (msg string) {
+	defer func() {
+		if v := recover(); v != nil {
+			msg = fmt.Sprint(v)
+		}
+	}()
+	f()
+	return ""
+}
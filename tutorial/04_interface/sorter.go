@@ -0,0 +1,193 @@
+// ============================================
+// 练习 6：可替换的排序算法
+// ============================================
+//
+// 排序算法只需要三个操作：长度、比较、交换，这正是标准库的 sort.Interface。
+// Sorter 接收 sort.Interface 而不是 []interface{}，好处是：
+//   - 任何切片或自定义容器都能排序，不需要先复制成 []interface{}
+//   - 比较逻辑由数据方提供，算法不需要对元素做类型断言
+//
+//   SortWith(QuickSorter{}, []int{3, 1, 2}, cmp.Less[int])   -> [1 2 3]
+//
+// SortWith 是泛型入口：把 []T 和 less 包装成 sort.Interface 交给 Sorter，
+// 返回排好序的新切片，不修改传入的 data。
+// ============================================
+
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+)
+
+// Sorter 排序算法
+type Sorter interface {
+	Sort(data sort.Interface)
+}
+
+var (
+	_ Sorter = BubbleSorter{}
+	_ Sorter = QuickSorter{}
+)
+
+// BubbleSorter 冒泡排序：O(n²)，稳定；一趟没有发生交换就提前结束，已排序的数据只需 O(n)
+type BubbleSorter struct{}
+
+func (BubbleSorter) Sort(data sort.Interface) {
+	for n := data.Len(); n > 1; n-- {
+		swapped := false
+		for i := 1; i < n; i++ {
+			if data.Less(i, i-1) {
+				data.Swap(i, i-1)
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+}
+
+// QuickSorter 快速排序：平均 O(n log n)，不稳定
+// 三数取中选基准，避免已排序数据退化到 O(n²)；先递归较短的一边，栈深度不超过 O(log n)
+type QuickSorter struct{}
+
+func (QuickSorter) Sort(data sort.Interface) {
+	quickSort(data, 0, data.Len()-1)
+}
+
+// insertionThreshold 区间不超过这个长度时改用插入排序，小数组上更快
+const insertionThreshold = 12
+
+func quickSort(data sort.Interface, lo, hi int) {
+	for hi-lo+1 > insertionThreshold {
+		p := partition(data, lo, hi)
+		if p-lo < hi-p {
+			quickSort(data, lo, p-1)
+			lo = p + 1
+		} else {
+			quickSort(data, p+1, hi)
+			hi = p - 1
+		}
+	}
+	insertionSort(data, lo, hi)
+}
+
+// partition 把基准放到最终位置并返回下标：左边都不大于它，右边都不小于它
+func partition(data sort.Interface, lo, hi int) int {
+	// 三数取中：排好 lo、mid、hi 三个位置，中间值作为基准放到 hi-1
+	mid := lo + (hi-lo)/2
+	if data.Less(mid, lo) {
+		data.Swap(mid, lo)
+	}
+	if data.Less(hi, lo) {
+		data.Swap(hi, lo)
+	}
+	if data.Less(hi, mid) {
+		data.Swap(hi, mid)
+	}
+	pivot := hi - 1
+	data.Swap(mid, pivot)
+
+	i, j := lo, pivot
+	for {
+		for i++; data.Less(i, pivot); i++ {
+		}
+		for j--; data.Less(pivot, j); j-- {
+		}
+		if i >= j {
+			break
+		}
+		data.Swap(i, j)
+	}
+	data.Swap(i, pivot)
+	return i
+}
+
+func insertionSort(data sort.Interface, lo, hi int) {
+	for i := lo + 1; i <= hi; i++ {
+		for j := i; j > lo && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
+
+// lessSlice 把切片和比较函数适配成 sort.Interface
+type lessSlice[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (s lessSlice[T]) Len() int           { return len(s.items) }
+func (s lessSlice[T]) Less(i, j int) bool { return s.less(s.items[i], s.items[j]) }
+func (s lessSlice[T]) Swap(i, j int)      { s.items[i], s.items[j] = s.items[j], s.items[i] }
+
+// SortWith 用 sorter 对 data 的副本排序并返回
+func SortWith[T any](sorter Sorter, data []T, less func(a, b T) bool) []T {
+	out := slices.Clone(data)
+	sorter.Sort(lessSlice[T]{items: out, less: less})
+	return out
+}
+
+// checkSorters 检查练习 6：随机数据与 sort.Slice 的结果逐一比较
+func checkSorters() error {
+	r := rand.New(rand.NewPCG(4, 6))
+	var inputs [][]int
+	for _, n := range []int{0, 1, 2, 3, insertionThreshold, insertionThreshold + 1, 100, 1000} {
+		random := make([]int, n)
+		dups := make([]int, n)
+		for i := range n {
+			random[i] = r.IntN(1_000_000)
+			dups[i] = r.IntN(5) // 大量重复元素
+		}
+		ascending := make([]int, n)
+		for i := range n {
+			ascending[i] = i
+		}
+		descending := slices.Clone(ascending)
+		slices.Reverse(descending)
+		inputs = append(inputs, random, dups, ascending, descending)
+	}
+
+	less := func(a, b int) bool { return a < b }
+	for _, sorter := range []Sorter{BubbleSorter{}, QuickSorter{}} {
+		for _, in := range inputs {
+			orig := slices.Clone(in)
+			want := slices.Clone(in)
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+			got := SortWith(sorter, in, less)
+			if !slices.Equal(got, want) {
+				return fmt.Errorf("%T 对 %d 个元素排序结果与 sort.Slice 不一致", sorter, len(in))
+			}
+			if !slices.Equal(in, orig) {
+				return fmt.Errorf("%T: SortWith 修改了传入的切片", sorter)
+			}
+		}
+	}
+
+	// 按结构体字段降序排序
+	type person struct {
+		Name string
+		Age  int
+	}
+	people := []person{{"Tom", 30}, {"Amy", 25}, {"Bob", 35}}
+	byAgeDesc := func(a, b person) bool { return a.Age > b.Age }
+	for _, sorter := range []Sorter{BubbleSorter{}, QuickSorter{}} {
+		got := SortWith(sorter, people, byAgeDesc)
+		if got[0].Name != "Bob" || got[1].Name != "Tom" || got[2].Name != "Amy" {
+			return fmt.Errorf("%T 按年龄降序结果 %v，期望 Bob Tom Amy", sorter, got)
+		}
+	}
+
+	// 冒泡排序是稳定的：年龄相同的保持原来的先后顺序
+	same := []person{{"A", 1}, {"B", 0}, {"C", 1}, {"D", 0}}
+	got := SortWith(BubbleSorter{}, same, func(a, b person) bool { return a.Age < b.Age })
+	if names := []string{got[0].Name, got[1].Name, got[2].Name, got[3].Name}; !slices.Equal(names, []string{"B", "D", "A", "C"}) {
+		return errors.New("BubbleSorter 应当是稳定排序，得到 " + fmt.Sprint(names))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package interfaces
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+
+	"c03/pkg/prop"
+)
+
+var sorters = []Sorter{BubbleSorter{}, QuickSorter{}}
+
+// sortInputs 各种长度的随机、大量重复、升序、降序数据
+func sortInputs() map[string][]int {
+	r := rand.New(rand.NewPCG(1, 2))
+	inputs := map[string][]int{"nil": nil}
+	for _, n := range []int{1, 2, 3, insertionThreshold, insertionThreshold + 1, 100, 1000} {
+		random := make([]int, n)
+		dups := make([]int, n)
+		ascending := make([]int, n)
+		for i := range n {
+			random[i] = r.IntN(1_000_000) - 500_000
+			dups[i] = r.IntN(5)
+			ascending[i] = i
+		}
+		descending := slices.Clone(ascending)
+		slices.Reverse(descending)
+		inputs[fmt.Sprintf("random/%d", n)] = random
+		inputs[fmt.Sprintf("dups/%d", n)] = dups
+		inputs[fmt.Sprintf("ascending/%d", n)] = ascending
+		inputs[fmt.Sprintf("descending/%d", n)] = descending
+	}
+	return inputs
+}
+
+func TestSortWithMatchesSortSlice(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, sorter := range sorters {
+		for name, in := range sortInputs() {
+			t.Run(fmt.Sprintf("%T/%s", sorter, name), func(t *testing.T) {
+				orig := slices.Clone(in)
+				want := slices.Clone(in)
+				sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+				got := SortWith(sorter, in, less)
+				if !slices.Equal(got, want) {
+					t.Fatalf("结果与 sort.Slice 不一致\n得到 %v\n期望 %v", got, want)
+				}
+				if !slices.Equal(in, orig) {
+					t.Fatal("SortWith 修改了传入的切片")
+				}
+			})
+		}
+	}
+}
+
+func TestSortWithStructDescending(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+	people := []person{{"Tom", 30}, {"Amy", 25}, {"Bob", 35}, {"Eve", 28}}
+	want := slices.Clone(people)
+	sort.Slice(want, func(i, j int) bool { return want[i].Age > want[j].Age })
+	for _, sorter := range sorters {
+		got := SortWith(sorter, people, func(a, b person) bool { return a.Age > b.Age })
+		if !slices.Equal(got, want) {
+			t.Errorf("%T 按年龄降序得到 %v，期望 %v", sorter, got, want)
+		}
+	}
+}
+
+// 性质：对随机切片排序一次和排序两次结果相同，且与 slices.Sort 一致
+func TestSortWithProperties(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, sorter := range sorters {
+		t.Run(fmt.Sprintf("%T", sorter), func(t *testing.T) {
+			err := prop.Check(prop.SliceOf(prop.Int(-1000, 1000)), func(xs []int) bool {
+				once := SortWith(sorter, xs, less)
+				twice := SortWith(sorter, once, less)
+				return slices.Equal(once, twice) && slices.Equal(once, slices.Sorted(slices.Values(xs)))
+			}, prop.WithRuns(500), prop.WithSeed(4))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestBubbleSorterStable(t *testing.T) {
+	type item struct {
+		key int
+		seq int // 原来的位置
+	}
+	r := rand.New(rand.NewPCG(3, 4))
+	in := make([]item, 200)
+	for i := range in {
+		in[i] = item{key: r.IntN(10), seq: i}
+	}
+	want := slices.Clone(in)
+	sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+
+	got := SortWith(BubbleSorter{}, in, func(a, b item) bool { return a.key < b.key })
+	if !slices.Equal(got, want) {
+		t.Fatal("BubbleSorter 的结果与 sort.SliceStable 不一致，不是稳定排序")
+	}
+}
+
+// FuzzSortWith 任意字节序列排序后与 sort.Slice 的结果一致
+// 运行：go test ./tutorial/04_interface -fuzz FuzzSortWith
+func FuzzSortWith(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{3, 1, 2})
+	f.Add([]byte("the quick brown fox jumps over the lazy dog"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := slices.Clone(data)
+		sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+		for _, sorter := range sorters {
+			if got := SortWith(sorter, data, func(a, b byte) bool { return a < b }); !slices.Equal(got, want) {
+				t.Fatalf("%T 排序 %v 得到 %v，期望 %v", sorter, data, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+// ============================================
+// 练习 5：用空接口实现的栈（Go 1.18 之前的做法）
+// ============================================
+//
+// Stack 可以放任意类型的值，代价是：
+//   - 取出时需要类型断言，类型写错要到运行时才发现（v.(string) 直接 panic）
+//   - 一个栈里可能混入不同类型的值，编译器不会阻止
+//   - 非指针的值装入 interface{} 时通常要在堆上分配（装箱）
+//
+// 第 8 课的泛型 Stack[T] 解决了这三个问题，两者的性能对比见 cmd/stackbench。
+// ============================================
+
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Stack 元素类型为 interface{} 的栈，零值可用
+type Stack struct {
+	items []interface{}
+}
+
+// Push 入栈
+func (s *Stack) Push(item interface{}) {
+	s.items = append(s.items, item)
+}
+
+// Pop 出栈，栈为空时返回 nil, false
+func (s *Stack) Pop() (interface{}, bool) {
+	if len(s.items) == 0 {
+		return nil, false
+	}
+	item := s.items[len(s.items)-1]
+	// 清掉引用，避免底层数组继续持有已出栈的值
+	s.items[len(s.items)-1] = nil
+	s.items = s.items[:len(s.items)-1]
+	return item, true
+}
+
+// Peek 查看栈顶但不出栈
+func (s *Stack) Peek() (interface{}, bool) {
+	if len(s.items) == 0 {
+		return nil, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// IsEmpty 栈是否为空
+func (s *Stack) IsEmpty() bool { return len(s.items) == 0 }
+
+// Len 元素个数
+func (s *Stack) Len() int { return len(s.items) }
+
+// checkStack 检查练习 5
+func checkStack() error {
+	var s Stack
+	if !s.IsEmpty() {
+		return errors.New("零值 Stack 应当为空")
+	}
+	if v, ok := s.Pop(); ok || v != nil {
+		return fmt.Errorf("空栈 Pop() = %v, %v，期望 nil, false", v, ok)
+	}
+	s.Push(1)
+	s.Push("two")
+	s.Push(3.0)
+	if v, ok := s.Peek(); !ok || v != 3.0 || s.Len() != 3 {
+		return fmt.Errorf("Peek() = %v, %v，Len() = %d，期望 3, true, 3", v, ok, s.Len())
+	}
+
+	// 每次取出都要断言成具体类型
+	f, _ := s.Pop()
+	str, _ := s.Pop()
+	n, _ := s.Pop()
+	if f.(float64) != 3.0 || str.(string) != "two" || n.(int) != 1 {
+		return fmt.Errorf("出栈顺序为 %v %v %v，期望 3 two 1", f, str, n)
+	}
+	if !s.IsEmpty() {
+		return errors.New("全部出栈后 IsEmpty() 应为 true")
+	}
+
+	// 断言写错类型：两值形式返回 ok=false，单值形式会 panic
+	s.Push("not an int")
+	v, _ := s.Pop()
+	if _, ok := v.(int); ok {
+		return errors.New(`"not an int".(int) 的 ok 应为 false`)
+	}
+	return nil
+}
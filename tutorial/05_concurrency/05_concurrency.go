@@ -10,7 +10,7 @@
 // - select 多路复用
 // - 关闭 Channel
 // - for-range 遍历 Channel
-// - 并发模式（Worker Pool、Pipeline、Fan-out/Fan-in）
+// - 并发模式（Worker Pool、Pipeline、Fan-out/Fan-in；泛型版 worker pool 见 pkg/workpool）
 //
 // 最佳实践：
 // 1. 不要通过共享内存来通信，而要通过通信来共享内存
@@ -18,16 +18,25 @@
 // 3. 不要从接收方关闭 channel，不要关闭已经关闭的 channel
 // 4. 使用有缓冲 channel 提高性能，但要注意缓冲区大小
 // 5. 使用 select 处理多个 channel 操作
-// 6. 总是考虑 goroutine 泄漏问题
+// 6. 总是考虑 goroutine 泄漏问题（用 pkg/rtstats 观察 goroutine 数是否只增不减）
 // ============================================
 
-package main
+package concurrency
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"math/rand"
+	"os"
 	"sync"
 	"time"
+
+	"c03/pkg/metrics"
+	"c03/pkg/rtstats"
+	"c03/pkg/workpool"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -258,17 +267,32 @@ func demonstrateTimeout() {
 // ============================================
 //
 // 固定数量的 worker 处理任务队列
+//
+// 用 pkg/metrics 观察池子的运行情况：
+// 完成了多少任务、有几个 worker 在忙、每个任务耗时的分布
+
+var (
+	poolMetrics   = metrics.NewRegistry()
+	jobsProcessed = poolMetrics.NewCounter("workerpool_jobs_processed_total", "已完成的任务数")
+	workersBusy   = poolMetrics.NewGauge("workerpool_workers_busy", "正在处理任务的 worker 数")
+	jobDuration   = poolMetrics.NewHistogram("workerpool_job_duration_seconds", "单个任务的处理耗时（秒）", []float64{.1, .25, .5})
+)
 
 func worker(id int, jobs <-chan int, results chan<- int, wg *sync.WaitGroup) {
 	defer wg.Done()
 	
 	for job := range jobs {
 		fmt.Printf("Worker %d 开始处理任务 %d\n", id, job)
+		workersBusy.Inc()
+		start := time.Now()
 		
 		// 模拟处理时间
 		time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond)
 		
 		result := job * job  // 计算平方
+		jobDuration.Observe(time.Since(start).Seconds())
+		jobsProcessed.Inc()
+		workersBusy.Dec()
 		results <- result
 		
 		fmt.Printf("Worker %d 完成任务 %d\n", id, job)
@@ -308,6 +332,60 @@ func demonstrateWorkerPool() {
 	for result := range results {
 		fmt.Printf("结果: %d\n", result)
 	}
+
+	// 指标快照（Prometheus 文本格式，线上通常通过 /metrics 暴露）
+	poolMetrics.WriteText(os.Stdout)
+}
+
+// ============================================
+// 6.1 类型化的 Worker Pool：workpool.Process
+// ============================================
+//
+// 上面的结果按完成顺序到达，而且 worker 出错了没有办法告诉调用方。
+// pkg/workpool 的 Process[I, O] 把这个模式写成泛型函数：
+//   - out[i] 对应 inputs[i]
+//   - 普通错误汇总返回，其他任务继续
+//   - workpool.Fatal 包装的错误（或 panic）取消剩余的任务
+
+// errNegative 负数的平方根：普通错误，只影响这一项
+var errNegative = errors.New("负数没有实数平方根")
+
+// slowSqrt 随机耗时的整数平方根；输入 999 表示"数据库连不上"，之后的任务都没有意义
+func slowSqrt(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(time.Duration(rand.Intn(20)) * time.Millisecond):
+	case <-ctx.Done():
+		return 0, context.Cause(ctx)
+	}
+	switch {
+	case n == 999:
+		return 0, workpool.Fatal(errors.New("数据库连接断开"))
+	case n < 0:
+		return 0, fmt.Errorf("%d: %w", n, errNegative)
+	}
+	r := 0
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r, nil
+}
+
+func demonstrateProcess() {
+	fmt.Println("\n=== 类型化的 Worker Pool ===")
+
+	inputs := []int{81, 4, 100, 9, 49, 1, 64}
+	out, err := workpool.Process(context.Background(), inputs, 3, slowSqrt)
+	fmt.Printf("输入 %v\n结果 %v（与输入顺序一致）, err = %v\n", inputs, out, err)
+
+	out, err = workpool.Process(context.Background(), []int{16, -4, 25, -9}, 2, slowSqrt)
+	fmt.Printf("普通错误: 结果 %v\n  err = %v\n  errors.Is(err, errNegative) = %v\n", out, err, errors.Is(err, errNegative))
+
+	// 单个 worker 时顺序执行：999 之后的任务不会开始
+	_, err = workpool.Process(context.Background(), []int{1, 4, 999, 9, 16, 25}, 1, slowSqrt)
+	var errs *workpool.Errors
+	if errors.As(err, &errs) {
+		fmt.Printf("致命错误: %v\n  未执行 %d 项\n", err, errs.Skipped)
+	}
 }
 
 // ============================================
@@ -474,6 +552,7 @@ func demonstratePitfalls() {
 	// 1. 向 nil channel 发送会永远阻塞
 	var ch chan int  // nil channel
 	// ch <- 1  // 永远阻塞！
+	fmt.Printf("nil channel: %v, len=%d\n", ch == nil, len(ch))
 	
 	// 2. 关闭 nil channel 会 panic
 	// close(ch)  // panic!
@@ -504,10 +583,74 @@ func demonstratePitfalls() {
 }
 
 // ============================================
-// 主函数
+// 11. Goroutine 泄漏与运行时统计
+// ============================================
+//
+// 最常见的泄漏：调用方超时返回了，后台 goroutine 还要往无缓冲 channel 发送结果，
+// 没有人接收，它就永远阻塞。泄漏不会报错，只能从 goroutine 数只增不减看出来。
+// pkg/rtstats 定期采样 goroutine 数、堆内存和 GC 停顿，发布到 metrics 并打日志。
+
+var errQueryTimeout = errors.New("查询超时")
+
+// query 模拟一次带超时的慢查询；buffered 为 false 时超时会泄漏 goroutine
+func query(timeout time.Duration, buffered bool) (int, error) {
+	ch := make(chan int)
+	if buffered {
+		// 修复：留一个缓冲位，没人接收时发送也能完成，goroutine 正常退出
+		ch = make(chan int, 1)
+	}
+	go func() {
+		time.Sleep(2 * timeout) // 查询比超时慢
+		ch <- 42
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(timeout):
+		return 0, errQueryTimeout
+	}
+}
+
+func demonstrateLeak() {
+	fmt.Println("\n=== Goroutine 泄漏与运行时统计 ===")
+
+	// 每个 Reporter 要用独立的 Registry：指标名固定，重复注册会 panic
+	reporter := rtstats.New(metrics.NewRegistry(), rtstats.WithLogger(log.New(os.Stdout, "  ", 0)))
+	reporter.Sample()
+
+	const calls = 100
+	const timeout = 5 * time.Millisecond
+	for _, buffered := range []bool{false, true} {
+		var wg sync.WaitGroup
+		for range calls {
+			wg.Go(func() { query(timeout, buffered) })
+		}
+		wg.Wait()
+		time.Sleep(4 * timeout) // 等后台查询都完成
+		fmt.Printf("%d 次超时的查询（缓冲 channel: %v）之后：\n", calls, buffered)
+		reporter.Sample()
+	}
+	// 第一轮泄漏的 goroutine 会一直留到程序退出
+}
+
+// ============================================
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "05",
+		Name:  "05_concurrency",
+		Title: "并发：goroutine、channel、select、worker pool",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "8", Title: "类型化 worker pool：保持顺序、汇总错误、致命错误取消", Check: checkProcess},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 05
+func Run() {
 	rand.Seed(time.Now().UnixNano())
 	
 	demonstrateGoroutine()
@@ -519,10 +662,12 @@ func main() {
 	demonstrateNonBlocking()
 	demonstrateTimeout()
 	demonstrateWorkerPool()
+	demonstrateProcess()
 	demonstratePipeline()
 	demonstrateFanOutFanIn()
 	demonstrateGracefulShutdown()
 	demonstratePitfalls()
+	demonstrateLeak()
 	
 	// ============================================
 	// 练习题
@@ -564,4 +709,64 @@ func main() {
 	//   - 对切片进行排序
 	//   - 使用 goroutine 并行处理子数组
 	//   - 设置阈值，小数组使用普通排序
+	//
+	// 练习 8：把第 6 节的 worker pool 写成泛型函数
+	//   func Process[I, O any](ctx, inputs []I, workers int, fn func(ctx, I) (O, error)) ([]O, error)
+	//   - 结果按输入顺序排列
+	//   - 汇总所有错误；致命错误（或 panic）时取消剩余的任务
+	//   参考实现：pkg/workpool，演示见第 6.1 节
+}
+
+// checkProcess 检查练习 8
+func checkProcess() error {
+	ctx := context.Background()
+	inputs := make([]int, 200)
+	for i := range inputs {
+		inputs[i] = i * i
+	}
+	out, err := workpool.Process(ctx, inputs, 8, slowSqrt)
+	if err != nil {
+		return err
+	}
+	for i, v := range out {
+		if v != i {
+			return fmt.Errorf("out[%d] = %d，期望 %d（结果没有按输入顺序排列）", i, v, i)
+		}
+	}
+
+	// 普通错误：其他项照常完成
+	out, err = workpool.Process(ctx, []int{-1, 4, -9, 16}, 4, slowSqrt)
+	var errs *workpool.Errors
+	if !errors.As(err, &errs) || len(errs.Jobs) != 2 || errs.Jobs[0].Index != 0 || errs.Jobs[1].Index != 2 || errs.Skipped != 0 {
+		return fmt.Errorf("两个负数输入返回 %v，期望第 0、2 项失败且没有跳过", err)
+	}
+	if out[1] != 2 || out[3] != 4 || !errors.Is(err, errNegative) {
+		return fmt.Errorf("成功的项 %v，期望 [_ 2 _ 4]，且 errors.Is(err, errNegative)", out)
+	}
+
+	// 致命错误：单个 worker 顺序执行，之后的 3 项不执行
+	_, err = workpool.Process(ctx, []int{1, 999, 4, 9, 16}, 1, slowSqrt)
+	if !errors.As(err, &errs) || errs.Skipped != 3 || !workpool.IsFatal(errs.Cause) {
+		return fmt.Errorf("致命错误返回 %v，期望跳过 3 项且 Cause 是致命错误", err)
+	}
+
+	// panic 转成致命错误，不会让程序崩溃
+	_, err = workpool.Process(ctx, []int{1, 2}, 1, func(ctx context.Context, n int) (int, error) {
+		if n == 1 {
+			panic("boom")
+		}
+		return n, nil
+	})
+	if !errors.Is(err, workpool.ErrPanicked) {
+		return fmt.Errorf("任务 panic 返回 %v，期望 workpool.ErrPanicked", err)
+	}
+
+	// ctx 已经取消：一项都不执行
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = workpool.Process(canceled, inputs, 4, slowSqrt)
+	if !errors.As(err, &errs) || errs.Skipped != len(inputs) || !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("ctx 已取消时返回 %v，期望跳过全部 %d 项", err, len(inputs))
+	}
+	return nil
 }
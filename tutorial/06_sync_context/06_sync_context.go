@@ -22,15 +22,26 @@
 // 8. Context 的取消操作应该由创建者负责
 // ============================================
 
-package main
+package synccontext
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/ctxutil"
+	"c03/pkg/metrics"
+	"c03/pkg/syncutil"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -81,6 +92,17 @@ func demonstrateMutex() {
 //
 // 读操作可以并发，写操作独占
 // 适用于读多写少的场景
+//
+// 读锁也不是免费的：每次 RLock / RUnlock 都要原子修改同一个计数器，核数多时读者之间会争用。
+// 几乎不写的数据（配置、路由表）可以用写时复制，读者完全不加锁：
+// pkg/syncutil 的 COWMap / COWSlice，与 RWMutex 的对比见 go run ./cmd/cowbench
+
+// 命中率是缓存最重要的指标；计数器是原子操作，在 RLock 下更新也不会有数据竞争
+var (
+	cacheMetrics = metrics.NewRegistry()
+	cacheHits    = cacheMetrics.NewCounter("cache_hits_total", "缓存命中次数")
+	cacheMisses  = cacheMetrics.NewCounter("cache_misses_total", "缓存未命中次数")
+)
 
 type Cache struct {
 	mu    sync.RWMutex
@@ -97,6 +119,11 @@ func (c *Cache) Get(key string) (string, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	val, ok := c.data[key]
+	if ok {
+		cacheHits.Inc()
+	} else {
+		cacheMisses.Inc()
+	}
 	return val, ok
 }
 
@@ -135,6 +162,8 @@ func demonstrateRWMutex() {
 	
 	wg.Wait()
 	fmt.Println("Cache 操作完成")
+	// 读写并发进行，部分读取发生在对应的写入之前，所以会有未命中
+	cacheMetrics.WriteText(os.Stdout)
 }
 
 // ============================================
@@ -264,6 +293,41 @@ func demonstratePool() {
 	fmt.Printf("再次获取 buffer，内容: %s\n", string(buf2))
 	
 	bufferPool.Put(buf2)
+
+	demonstratePoolLeaks()
+}
+
+// 忘记 Put 不会出错，只是池慢慢失效：每次 Get 都在新建，和不用池一样。
+// syncutil.Pool 统计 Get / Put / New 次数，调试模式下还记录每个未归还对象是在哪里取走的
+func demonstratePoolLeaks() {
+	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool := syncutil.NewPool(func() *bytes.Buffer { return new(bytes.Buffer) },
+		syncutil.WithDebug(), syncutil.WithPoolClock(fc))
+
+	// render 在出错的分支上提前返回，忘了归还缓冲区
+	render := func(name string) (string, error) {
+		buf := pool.Get()
+		buf.Reset()
+		if name == "" {
+			return "", errors.New("名字为空")
+		}
+		fmt.Fprintf(buf, "你好，%s", name)
+		s := buf.String()
+		pool.Put(buf)
+		return s, nil
+	}
+	for _, name := range []string{"alice", "", "bob", "", ""} {
+		render(name)
+	}
+	fc.Advance(5 * time.Second)
+
+	fmt.Println("计数:", pool.Stats())
+	for _, l := range pool.Leaks(time.Second) {
+		// 调用栈第一帧是 render 里调用 Get 的那一行
+		frame := strings.SplitN(l.Stack, "\n", 3)
+		fmt.Printf("%d 个缓冲区借出 %v 未归还，取出于 %s\n", l.Count, l.Oldest,
+			filepath.Base(strings.TrimSpace(frame[1])))
+	}
 }
 
 // ============================================
@@ -276,40 +340,39 @@ func demonstratePool() {
 // 2. 多个 goroutine 读写不同的 key
 // 3. 读取、写入、删除次数差不多
 
+// sync.Map 的键和值都是 interface{}，读出来要做类型断言；
+// pkg/syncutil 的 SyncMap[K, V] 是它的泛型包装，编译期检查类型，另外提供 Len
 func demonstrateSyncMap() {
 	fmt.Println("\n=== SyncMap ===")
-	
-	var m sync.Map
+
+	var m syncutil.SyncMap[string, int]
 	var wg sync.WaitGroup
-	
+
 	// 写入
 	for i := 0; i < 10; i++ {
-		wg.Add(1)
-		go func(n int) {
-			defer wg.Done()
-			m.Store(fmt.Sprintf("key%d", n), n)
-		}(i)
+		wg.Go(func() { m.Store(fmt.Sprintf("key%d", i), i) })
 	}
-	
-	// 读取
+	wg.Wait()
+
+	// 读取：val 就是 int，不需要 val.(int)
 	for i := 0; i < 10; i++ {
-		wg.Add(1)
-		go func(n int) {
-			defer wg.Done()
-			if val, ok := m.Load(fmt.Sprintf("key%d", n)); ok {
-				fmt.Printf("读取 key%d: %v\n", n, val)
+		wg.Go(func() {
+			if val, ok := m.Load(fmt.Sprintf("key%d", i)); ok {
+				fmt.Printf("读取 key%d: %d\n", i, val*10)
 			}
-		}(i)
+		})
 	}
-	
 	wg.Wait()
-	
-	// 遍历
-	fmt.Println("遍历 SyncMap:")
-	m.Range(func(key, value interface{}) bool {
-		fmt.Printf("  %s: %v\n", key, value)
-		return true  // 继续遍历
-	})
+
+	// LoadOrStore：已存在时返回旧值，不覆盖
+	actual, loaded := m.LoadOrStore("key0", 100)
+	fmt.Printf("LoadOrStore(key0, 100) = %d, loaded=%v\n", actual, loaded)
+
+	// 遍历：迭代器形式，key、value 都带类型
+	fmt.Printf("遍历 SyncMap（%d 个键）:\n", m.Len())
+	for key, value := range m.All() {
+		fmt.Printf("  %s: %d\n", key, value)
+	}
 }
 
 // ============================================
@@ -416,23 +479,62 @@ func demonstrateContextDeadline() {
 // 8.4 传递值（不用于传递业务参数，只用于元数据）
 func demonstrateContextValue() {
 	fmt.Println("\n=== Context Value ===")
-	
+
 	type contextKey string
 	const requestIDKey contextKey = "requestID"
 	const userKey contextKey = "user"
-	
+
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, requestIDKey, "req-12345")
 	ctx = context.WithValue(ctx, userKey, "alice")
-	
+
 	// 读取值
 	if reqID, ok := ctx.Value(requestIDKey).(string); ok {
 		fmt.Printf("Request ID: %s\n", reqID)
 	}
-	
+
 	if user, ok := ctx.Value(userKey).(string); ok {
 		fmt.Printf("User: %s\n", user)
 	}
+
+	// 上面的写法每次取值都要类型断言，写错类型只会在运行时得到 ok == false
+	// ctxutil.Key[T] 把值的类型固定在键上，取值不需要断言
+	ctx = requestIDCtxKey.With(ctx, "req-67890")
+	ctx = retryCtxKey.With(ctx, 3)
+	reqID, _ := requestIDCtxKey.From(ctx)
+	fmt.Printf("Typed Request ID: %s, 重试次数: %d\n", reqID, retryCtxKey.Get(ctx))
+	// retryCtxKey.With(ctx, "3")  // 编译错误：值必须是 int
+}
+
+// 带类型的键通常定义为包级变量
+var (
+	requestIDCtxKey = ctxutil.NewKey[string]("requestID")
+	retryCtxKey     = ctxutil.NewKey[int]("retry")
+)
+
+// 8.4.1 合并与分离：值和生命周期可以分开处理
+func demonstrateContextMergeDetach() {
+	fmt.Println("\n=== Context Merge / Detach ===")
+
+	// 请求 ctx 带请求 ID 和 1 秒超时；服务关闭时 shutdown 被取消
+	reqCtx, cancelReq := context.WithTimeout(requestIDCtxKey.With(context.Background(), "req-1"), time.Second)
+	defer cancelReq()
+	shutdown, stopServer := context.WithCancel(context.Background())
+
+	// 任意一个结束，处理就应该停止
+	ctx, cancel := ctxutil.Merge(reqCtx, shutdown)
+	defer cancel()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		stopServer()
+	}()
+	<-ctx.Done()
+	fmt.Println("Merge: 服务关闭先于请求超时:", ctx.Err())
+
+	// 请求已经结束，后台审计仍需要请求 ID，但不能被请求的取消波及
+	audit := ctxutil.Detach(ctx)
+	id, _ := requestIDCtxKey.From(audit)
+	fmt.Printf("Detach: err=%v, 请求 ID 仍然可用: %s\n", audit.Err(), id)
 }
 
 // 8.5 实际应用：HTTP 请求控制
@@ -566,10 +668,26 @@ func demonstrateTaskQueue() {
 }
 
 // ============================================
-// 主函数
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "06",
+		Name:  "06_sync_context",
+		Title: "同步原语与 Context：Mutex、WaitGroup、Once、超时与取消",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "2", Title: "带权重的公平信号量", Check: checkSemaphore},
+			{ID: "8", Title: "用 gob 持久化 Cache", Check: checkCachePersist},
+			{ID: "9", Title: "泛型 SyncMap", Check: checkSyncMap},
+			{ID: "10", Title: "对象池的统计与泄漏检测", Check: checkPool},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 06
+func Run() {
 	demonstrateMutex()
 	demonstrateRWMutex()
 	demonstrateWaitGroup()
@@ -581,6 +699,7 @@ func main() {
 	demonstrateContextTimeout()
 	demonstrateContextDeadline()
 	demonstrateContextValue()
+	demonstrateContextMergeDetach()
 	demonstrateContextHTTP()
 	demonstrateTaskQueue()
 	
@@ -601,6 +720,8 @@ func main() {
 	//   - Acquire() 获取许可，如果没有则阻塞
 	//   - Release() 释放许可
 	//   - TryAcquire(timeout time.Duration) bool 带超时的获取
+	//   进阶：带权重（Acquire(ctx, n) / Release(n)），等待者按 FIFO 获得许可
+	//   参考实现：pkg/semaphore，检查见 semaphore.go
 	//
 	// 练习 3：实现一个读写分离的缓存
 	//   type RWCache struct { ... }
@@ -634,4 +755,24 @@ func main() {
 	//   - 使用令牌桶算法
 	//   - Allow() bool 判断是否允许通过
 	//   - Wait(ctx context.Context) error 等待直到允许通过
+	//
+	// 练习 8：用 gob 持久化第 2 节的 Cache
+	//   - Save(w io.Writer) / Load(r io.Reader)，编码时不持有锁
+	//   - SaveFile 先写临时文件再重命名，崩溃时不留下损坏的文件
+	//   - 加载失败时缓存保持不变
+	//   - 选项 WithPassphrase：用口令加密保存的文件（AES-GCM，见 pkg/cryptutil）
+	//   实现见 persist.go
+	//
+	// 练习 9：用泛型包装 sync.Map
+	//   type SyncMap[K comparable, V any] struct { ... }
+	//   - Load / Store / LoadOrStore / LoadAndDelete / Delete / Range 都带类型，不需要断言
+	//   - Len() 返回键的个数（sync.Map 没有）
+	//   参考实现：pkg/syncutil，检查见 syncmap.go
+	//
+	// 练习 10：给对象池加上统计和泄漏检测
+	//   type Pool[T any] struct { ... }
+	//   - Get() *T / Put(*T)，统计 Get、Put、New 的次数
+	//   - 调试模式下记录未归还对象的调用栈，按调用栈汇总报告
+	//   - Put 了不是从池里取出的对象时报错
+	//   参考实现：pkg/syncutil 的 Pool，演示见第 5 节，检查见 pool.go
 }
@@ -0,0 +1,203 @@
+// ============================================
+// 练习 8：用 gob 持久化 Cache
+// ============================================
+//
+// encoding/gob 是 Go 专用的二进制格式：
+//   - 流的开头写一次类型描述，之后只写值，同一个 Encoder 连续编码时很紧凑
+//   - 不需要结构体标签，按字段名匹配，增删字段时两端可以兼容
+//   - 只有 Go 能读，跨语言的场景用 JSON 或 protobuf
+//
+// 与 JSON 的大小和速度对比见 cmd/codecbench。
+//
+// SaveFile / LoadFile 加上 WithPassphrase 时，文件内容用 AES-GCM 加密（pkg/cryptutil），
+// 缓存里有 token、会话等敏感数据时，磁盘上的快照不会以明文保存。
+// ============================================
+
+package synccontext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"c03/pkg/cryptutil"
+)
+
+// ErrSnapshotEncrypted 快照文件已加密，LoadFile 需要 WithPassphrase
+var ErrSnapshotEncrypted = errors.New("缓存快照已加密，需要口令")
+
+// PersistOption SaveFile / LoadFile 的选项
+type PersistOption func(*persistOptions)
+
+type persistOptions struct {
+	passphrase string
+}
+
+// WithPassphrase 用口令加密保存的文件；加载时提供同一个口令
+func WithPassphrase(passphrase string) PersistOption {
+	return func(o *persistOptions) { o.passphrase = passphrase }
+}
+
+func newPersistOptions(opts []PersistOption) persistOptions {
+	var o persistOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Snapshot 返回缓存内容的副本
+func (c *Cache) Snapshot() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return maps.Clone(c.data)
+}
+
+// Save 把缓存内容以 gob 格式写到 w
+// 持有读锁时只复制一份快照，编码和 I/O 在锁外进行，不阻塞其他读写
+func (c *Cache) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(c.Snapshot())
+}
+
+// Load 从 r 读取 Save 写出的内容，替换缓存的全部数据；解码失败时缓存保持不变
+func (c *Cache) Load(r io.Reader) error {
+	var data map[string]string
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("加载缓存: %w", err)
+	}
+	if data == nil {
+		data = make(map[string]string)
+	}
+	c.mu.Lock()
+	c.data = data
+	c.mu.Unlock()
+	return nil
+}
+
+// SaveFile 原子地保存到 path：先写同目录的临时文件再重命名，
+// 写到一半崩溃也不会留下损坏的文件
+func (c *Cache) SaveFile(path string, opts ...PersistOption) error {
+	o := newPersistOptions(opts)
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	if o.passphrase != "" {
+		var err error
+		if data, err = cryptutil.EncryptPassphrase(o.passphrase, data); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // 重命名成功后临时文件已不存在，删除会失败，忽略即可
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadFile 从 SaveFile 保存的文件加载
+// 文件已加密但没有提供口令时返回 ErrSnapshotEncrypted，口令错误时返回 cryptutil.ErrDecrypt
+func (c *Cache) LoadFile(path string, opts ...PersistOption) error {
+	o := newPersistOptions(opts)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch encrypted := cryptutil.IsPassphraseEncrypted(data); {
+	case encrypted && o.passphrase == "":
+		return fmt.Errorf("%w: %s", ErrSnapshotEncrypted, path)
+	case encrypted:
+		if data, err = cryptutil.DecryptPassphrase(o.passphrase, data); err != nil {
+			return fmt.Errorf("加载缓存: %w", err)
+		}
+	case o.passphrase != "":
+		return fmt.Errorf("加载缓存: %w: %s 没有加密", cryptutil.ErrFormat, path)
+	}
+	return c.Load(bytes.NewReader(data))
+}
+
+// Len 缓存的键数
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.data)
+}
+
+// checkCachePersist 检查练习 8：保存后加载到新缓存，内容完全一致；损坏的数据不影响原缓存
+func checkCachePersist() error {
+	src := NewCache()
+	for i := range 100 {
+		src.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("值%d", i))
+	}
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("cache-%d.gob", os.Getpid()))
+	defer os.Remove(path)
+	if err := src.SaveFile(path); err != nil {
+		return err
+	}
+
+	dst := NewCache()
+	dst.Set("旧数据", "会被替换")
+	if err := dst.LoadFile(path); err != nil {
+		return err
+	}
+	if !maps.Equal(src.Snapshot(), dst.Snapshot()) {
+		return fmt.Errorf("加载后有 %d 个键，期望与保存前的 %d 个键完全一致", dst.Len(), src.Len())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := dst.Load(bytes.NewReader(data[:len(data)/2])); err == nil {
+		return fmt.Errorf("加载截断的数据应当返回错误")
+	}
+	if dst.Len() != 100 {
+		return fmt.Errorf("加载失败后缓存有 %d 个键，期望保持原来的 100 个", dst.Len())
+	}
+	return checkCacheEncrypted(src, path)
+}
+
+// checkCacheEncrypted 加密保存：文件中没有明文，口令正确才能加载
+func checkCacheEncrypted(src *Cache, path string) error {
+	const passphrase = "correct horse battery staple"
+	if err := src.SaveFile(path, WithPassphrase(passphrase)); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(data, []byte("key42")) {
+		return fmt.Errorf("加密保存的文件中出现了明文键名")
+	}
+
+	dst := NewCache()
+	if err := dst.LoadFile(path); !errors.Is(err, ErrSnapshotEncrypted) {
+		return fmt.Errorf("不提供口令加载加密文件返回 %v，期望 ErrSnapshotEncrypted", err)
+	}
+	if err := dst.LoadFile(path, WithPassphrase("wrong")); !errors.Is(err, cryptutil.ErrDecrypt) {
+		return fmt.Errorf("口令错误时返回 %v，期望 cryptutil.ErrDecrypt", err)
+	}
+	if err := dst.LoadFile(path, WithPassphrase(passphrase)); err != nil {
+		return err
+	}
+	if !maps.Equal(src.Snapshot(), dst.Snapshot()) {
+		return fmt.Errorf("解密加载后有 %d 个键，期望 %d 个", dst.Len(), src.Len())
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package synccontext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"c03/pkg/cryptutil"
+)
+
+func filledCache(n int) *Cache {
+	c := NewCache()
+	for i := range n {
+		c.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("值%d", i))
+	}
+	return c
+}
+
+func TestCacheSaveLoad(t *testing.T) {
+	for _, n := range []int{0, 1, 100} {
+		src := filledCache(n)
+		var buf bytes.Buffer
+		if err := src.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		dst := NewCache()
+		dst.Set("旧数据", "会被替换")
+		if err := dst.Load(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(src.Snapshot(), dst.Snapshot()) {
+			t.Fatalf("%d 个键往返后得到 %d 个键，内容不一致", n, dst.Len())
+		}
+	}
+}
+
+func TestCacheLoadCorruptKeepsData(t *testing.T) {
+	var buf bytes.Buffer
+	if err := filledCache(100).Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	dst := filledCache(3)
+	for name, bad := range map[string][]byte{
+		"空":     nil,
+		"截断":    data[:len(data)/2],
+		"非 gob": []byte("not gob"),
+	} {
+		if err := dst.Load(bytes.NewReader(bad)); err == nil {
+			t.Errorf("%s: Load 应返回错误", name)
+		}
+		if dst.Len() != 3 {
+			t.Fatalf("%s: 加载失败后缓存有 %d 个键，期望保持原来的 3 个", name, dst.Len())
+		}
+	}
+}
+
+func TestCacheSaveFileLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	src := filledCache(50)
+	if err := src.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	// 覆盖保存：重命名替换旧文件，目录里不留下临时文件
+	src.Set("extra", "1")
+	if err := src.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Fatalf("目录中有 %d 个文件，期望只有 cache.gob", len(entries))
+	}
+
+	dst := NewCache()
+	if err := dst.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(src.Snapshot(), dst.Snapshot()) {
+		t.Fatal("从文件加载后内容不一致")
+	}
+	if err := dst.LoadFile(path, WithPassphrase("x")); !errors.Is(err, cryptutil.ErrFormat) {
+		t.Fatalf("对未加密文件提供口令返回 %v，期望 cryptutil.ErrFormat", err)
+	}
+}
+
+func TestCacheEncryptedFile(t *testing.T) {
+	const passphrase = "correct horse battery staple"
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	src := filledCache(50)
+	if err := src.SaveFile(path, WithPassphrase(passphrase)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("key42")) {
+		t.Fatal("加密保存的文件中出现了明文键名")
+	}
+
+	dst := NewCache()
+	if err := dst.LoadFile(path); !errors.Is(err, ErrSnapshotEncrypted) {
+		t.Fatalf("不提供口令返回 %v，期望 ErrSnapshotEncrypted", err)
+	}
+	if err := dst.LoadFile(path, WithPassphrase("wrong")); !errors.Is(err, cryptutil.ErrDecrypt) {
+		t.Fatalf("口令错误返回 %v，期望 cryptutil.ErrDecrypt", err)
+	}
+	if err := dst.LoadFile(path, WithPassphrase(passphrase)); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(src.Snapshot(), dst.Snapshot()) {
+		t.Fatal("解密加载后内容不一致")
+	}
+}
+
+// BenchmarkCacheEncode 对比 gob 和 JSON 编码整个缓存的速度，bytes 是编码后的大小
+//
+//	go test -bench CacheEncode -benchmem ./tutorial/06_sync_context
+func BenchmarkCacheEncode(b *testing.B) {
+	snapshot := filledCache(1000).Snapshot()
+	b.Run("gob", func(b *testing.B) {
+		var buf bytes.Buffer
+		for b.Loop() {
+			buf.Reset()
+			if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len()), "bytes")
+	})
+	b.Run("json", func(b *testing.B) {
+		var buf bytes.Buffer
+		for b.Loop() {
+			buf.Reset()
+			if err := json.NewEncoder(&buf).Encode(snapshot); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len()), "bytes")
+	})
+}
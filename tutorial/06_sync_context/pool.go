@@ -0,0 +1,94 @@
+// ============================================
+// 练习 10：对象池的统计与泄漏检测
+// ============================================
+//
+// 检查 pkg/syncutil 的 Pool：计数与 Get / Put 次数一致，
+// 调试模式下按调用栈汇总未归还的对象，Put 外来对象时 panic。
+// ============================================
+
+package synccontext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/syncutil"
+)
+
+type pooledItem struct{ data [64]byte }
+
+func checkPool() error {
+	fc := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool := syncutil.NewPool(func() *pooledItem { return new(pooledItem) },
+		syncutil.WithDebug(), syncutil.WithPoolClock(fc))
+
+	a := pool.Get()
+	pool.Put(a)
+	if st := pool.Stats(); st.Gets != 1 || st.Puts != 1 || st.News != 1 || st.Outstanding() != 0 {
+		return fmt.Errorf("Get、Put 各一次后计数为 %v", st)
+	}
+
+	// 两处代码各漏掉若干个：leakTwice 漏 2 个，leakOnce 漏 1 个
+	// 同一行代码取走的对象调用栈相同，归为一组
+	leakTwice := func() {
+		for range 2 {
+			pool.Get()
+		}
+	}
+	leakOnce := func() { pool.Get() }
+	leakTwice()
+	fc.Advance(time.Minute)
+	leakOnce()
+	fc.Advance(time.Second)
+
+	if n := pool.Stats().Outstanding(); n != 3 {
+		return fmt.Errorf("未归还 %d 个，期望 3", n)
+	}
+	leaks := pool.Leaks(0)
+	if len(leaks) != 2 || leaks[0].Count != 2 || leaks[1].Count != 1 {
+		return fmt.Errorf("按调用栈汇总得到 %+v，期望 2 组：2 个和 1 个", leaks)
+	}
+	if leaks[0].Oldest != time.Minute+time.Second || !strings.Contains(leaks[0].Stack, "checkPool") {
+		return fmt.Errorf("第一组借出 %v，调用栈：\n%s", leaks[0].Oldest, leaks[0].Stack)
+	}
+	// 阈值过滤：只有 leakTwice 的两个借出超过 10 秒
+	if leaks := pool.Leaks(10 * time.Second); len(leaks) != 1 || leaks[0].Count != 2 {
+		return fmt.Errorf("借出超过 10 秒的应当只有 leakTwice 的 2 个，得到 %+v", leaks)
+	}
+	var sb strings.Builder
+	if n := pool.WriteLeaks(&sb, 0); n != 3 {
+		return fmt.Errorf("WriteLeaks 报告 %d 个，期望 3", n)
+	}
+
+	// 外来对象和重复归还都要 panic
+	if err := catchPanic(func() { pool.Put(new(pooledItem)) }); err == nil {
+		return errors.New("调试模式下 Put 不是从池里取出的对象应当 panic")
+	}
+	b := pool.Get()
+	pool.Put(b)
+	if err := catchPanic(func() { pool.Put(b) }); err == nil {
+		return errors.New("调试模式下重复 Put 同一个对象应当 panic")
+	}
+
+	// 非调试模式：只计数，不记录调用栈
+	plain := syncutil.NewPool(func() *pooledItem { return new(pooledItem) })
+	plain.Get()
+	plain.Put(new(pooledItem))
+	if st := plain.Stats(); st.Outstanding() != 0 || plain.Leaks(0) != nil {
+		return fmt.Errorf("非调试模式下计数为 %v，Leaks=%v", st, plain.Leaks(0))
+	}
+	return nil
+}
+
+func catchPanic(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	fn()
+	return nil
+}
@@ -0,0 +1,95 @@
+// ============================================
+// 练习 2：信号量
+// ============================================
+//
+// 练习 2 的 channel 信号量（make(chan struct{}, n)）每次只能获取一个许可。
+// pkg/semaphore 的 Weighted 是它的进阶版本：
+//   - Acquire(ctx, n) / Release(n) 一次获取、归还 n 个许可
+//   - 等待者按 FIFO 顺序获得许可，大请求不会被小请求饿死
+//   - TryAcquire(n, timeout) 带超时；放弃等待时不占用许可
+//
+// 这里用 clock.FakeClock 控制超时，检查结果与真实时间无关。
+// ============================================
+
+package synccontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/semaphore"
+)
+
+func checkSemaphore() error {
+	fc := clock.NewFakeClock(time.Unix(0, 0))
+	sem := semaphore.New(10, semaphore.WithClock(fc))
+	ctx := context.Background()
+
+	if err := sem.Acquire(ctx, 11); !errors.Is(err, semaphore.ErrTooLarge) {
+		return fmt.Errorf("Acquire(11) 返回 %v，期望 ErrTooLarge", err)
+	}
+	if err := sem.Acquire(ctx, 7); err != nil {
+		return err
+	}
+	if sem.TryAcquire(5, 0) {
+		return fmt.Errorf("只剩 3 个许可时 TryAcquire(5, 0) 成功了")
+	}
+
+	// FIFO：队首要 5 个，后到的只要 1 个也不能插队
+	big, small := make(chan error, 1), make(chan error, 1)
+	go func() { big <- sem.Acquire(ctx, 5) }()
+	waitFor(func() bool { return sem.Waiting() == 1 })
+	go func() { small <- sem.Acquire(ctx, 1) }()
+	waitFor(func() bool { return sem.Waiting() == 2 })
+	if got := sem.Available(); got != 3 {
+		return fmt.Errorf("队首等待时后到的请求插队了：剩余 %d 个许可，期望 3", got)
+	}
+	sem.Release(7)
+	if err := errors.Join(<-big, <-small); err != nil {
+		return err
+	}
+	if got := sem.Available(); got != 4 {
+		return fmt.Errorf("两个等待者获得许可后剩余 %d 个，期望 4", got)
+	}
+
+	// TryAcquire 超时：不占用许可，也不留在队列里
+	done := make(chan bool, 1)
+	go func() { done <- sem.TryAcquire(5, time.Second) }()
+	fc.BlockUntil(1)
+	fc.Advance(time.Second)
+	if <-done {
+		return fmt.Errorf("许可不足时 TryAcquire(5, 1s) 超时后仍返回 true")
+	}
+	if sem.Waiting() != 0 || sem.Available() != 4 {
+		return fmt.Errorf("超时后等待者 %d 个、剩余许可 %d 个，期望 0 和 4", sem.Waiting(), sem.Available())
+	}
+
+	// 队首取消后，被它挡住的等待者应当被唤醒
+	cctx, cancel := context.WithCancel(ctx)
+	go func() { big <- sem.Acquire(cctx, 5) }()
+	waitFor(func() bool { return sem.Waiting() == 1 })
+	go func() { small <- sem.Acquire(ctx, 2) }()
+	waitFor(func() bool { return sem.Waiting() == 2 })
+	cancel()
+	if err := <-big; !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("取消等待返回 %v，期望 context.Canceled", err)
+	}
+	if err := <-small; err != nil {
+		return err
+	}
+	sem.Release(8)
+	if sem.Available() != 10 {
+		return fmt.Errorf("全部归还后剩余 %d 个许可，期望 10", sem.Available())
+	}
+	return nil
+}
+
+// waitFor 轮询直到 cond 成立（等待 goroutine 进入排队），最多 1 秒
+func waitFor(cond func() bool) {
+	for deadline := time.Now().Add(time.Second); !cond() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+// ============================================
+// 练习 9：泛型 SyncMap
+// ============================================
+//
+// 检查 pkg/syncutil 的 SyncMap：类型安全的读写、并发 LoadOrStore 只有一个成功、
+// Len 在增删之后准确，值类型是接口时存入 nil 也不会 panic。
+// ============================================
+
+package synccontext
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"c03/pkg/syncutil"
+)
+
+func checkSyncMap() error {
+	var m syncutil.SyncMap[string, int]
+	if _, ok := m.Load("a"); ok || m.Len() != 0 {
+		return errors.New("零值 SyncMap 应当为空")
+	}
+
+	// 100 个 goroutine 同时为 10 个键 LoadOrStore，每个键只有一个能存入
+	var stored atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() {
+			key := fmt.Sprintf("k%d", i%10)
+			if actual, loaded := m.LoadOrStore(key, i); !loaded {
+				stored.Add(1)
+			} else if actual%10 != i%10 {
+				stored.Add(100) // 拿到了别的键的值
+			}
+		})
+	}
+	wg.Wait()
+	if stored.Load() != 10 || m.Len() != 10 {
+		return fmt.Errorf("并发 LoadOrStore 后存入 %d 次、Len=%d，期望 10 和 10", stored.Load(), m.Len())
+	}
+
+	m.Store("k0", -1) // 覆盖不改变个数
+	if prev, loaded := m.Swap("k0", 42); !loaded || prev != -1 || m.Len() != 10 {
+		return fmt.Errorf("Swap 返回 %d, %v，Len=%d，期望 -1, true, 10", prev, loaded, m.Len())
+	}
+	if v, loaded := m.LoadAndDelete("k0"); !loaded || v != 42 {
+		return fmt.Errorf("LoadAndDelete 返回 %d, %v，期望 42, true", v, loaded)
+	}
+	m.Delete("k1")
+	m.Delete("不存在") // 删除不存在的键不影响个数
+	sum, n := 0, 0
+	for _, v := range m.All() {
+		sum += v % 10
+		n++
+	}
+	if n != 8 || m.Len() != 8 || sum != 2+3+4+5+6+7+8+9 {
+		return fmt.Errorf("删除两个键后遍历到 %d 个、Len=%d，期望 8", n, m.Len())
+	}
+	m.Clear()
+	if m.Len() != 0 {
+		return fmt.Errorf("Clear 后 Len=%d", m.Len())
+	}
+
+	var errs syncutil.SyncMap[string, error]
+	errs.Store("ok", nil)
+	if v, ok := errs.Load("ok"); !ok || v != nil {
+		return fmt.Errorf("值为接口类型时存入 nil，读出 %v, %v", v, ok)
+	}
+	return nil
+}
@@ -21,12 +21,17 @@
 // 7. 不要忽略错误（不要用 _ 接收，除非确实不需要）
 // ============================================
 
-package main
+package errorhandling
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"time"
+
+	"c03/pkg/backoff"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -409,21 +414,26 @@ func (m *MultiError) HasErrors() bool {
 }
 
 // 重试函数
+// 两次尝试之间按指数退避等待（10ms、20ms、40ms...），避免失败后立刻重试压垮对方
 func withRetry(maxRetries int, fn func() error) error {
 	var lastErr error
-	
+	b := backoff.Backoff{Initial: 10 * time.Millisecond, Jitter: backoff.EqualJitter}
+
 	for i := 0; i < maxRetries; i++ {
 		if err := fn(); err != nil {
 			lastErr = err
 			// 检查是否是临时错误
 			if t, ok := err.(temporary); ok && t.Temporary() {
+				if i < maxRetries-1 {
+					b.Sleep(context.Background())
+				}
 				continue
 			}
 			return err
 		}
 		return nil
 	}
-	
+
 	return fmt.Errorf("重试 %d 次后失败: %w", maxRetries, lastErr)
 }
 
@@ -502,10 +512,15 @@ func demonstrateHTTPError() {
 }
 
 // ============================================
-// 主函数
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{ID: "07", Name: "07_error_handling", Title: "错误处理：error 接口、包装、errors.Is/As、panic/recover", Run: Run})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 07
+func Run() {
 	demonstrateBasicError()
 	demonstrateCreatingErrors()
 	demonstrateCustomErrors()
@@ -557,4 +572,5 @@ func main() {
 	//   - 支持自定义重试次数、退避策略
 	//   - 支持只对特定错误重试
 	//   - 支持超时
+	//   延伸：退避计算可以直接使用 c03/pkg/backoff 的 Backoff（Next / Reset / Sleep(ctx)）
 }
@@ -19,12 +19,20 @@
 // 5. 泛型会增加编译时间和二进制大小，谨慎使用
 // ============================================
 
-package main
+package generics
 
 import (
 	"cmp"
+	"context"
 	"fmt"
+	"sync"
+	"time"
+
 	"golang.org/x/exp/constraints"
+
+	"c03/pkg/container"
+	"c03/pkg/optional"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -240,143 +248,24 @@ func demonstrateCustomConstraints() {
 // 4. 泛型类型
 // ============================================
 //
-// 类型也可以是泛型的
-
-// 泛型栈
-type Stack[T any] struct {
-	items []T
-}
-
-func NewStack[T any]() *Stack[T] {
-	return &Stack[T]{items: make([]T, 0)}
-}
-
-func (s *Stack[T]) Push(item T) {
-	s.items = append(s.items, item)
-}
-
-func (s *Stack[T]) Pop() (T, bool) {
-	var zero T
-	if len(s.items) == 0 {
-		return zero, false
-	}
-	item := s.items[len(s.items)-1]
-	s.items = s.items[:len(s.items)-1]
-	return item, true
-}
-
-func (s *Stack[T]) Peek() (T, bool) {
-	var zero T
-	if len(s.items) == 0 {
-		return zero, false
-	}
-	return s.items[len(s.items)-1], true
-}
-
-func (s *Stack[T]) IsEmpty() bool {
-	return len(s.items) == 0
-}
-
-func (s *Stack[T]) Size() int {
-	return len(s.items)
-}
-
-// 泛型队列
-type Queue[T any] struct {
-	items []T
-}
-
-func NewQueue[T any]() *Queue[T] {
-	return &Queue[T]{items: make([]T, 0)}
-}
-
-func (q *Queue[T]) Enqueue(item T) {
-	q.items = append(q.items, item)
-}
-
-func (q *Queue[T]) Dequeue() (T, bool) {
-	var zero T
-	if len(q.items) == 0 {
-		return zero, false
-	}
-	item := q.items[0]
-	q.items = q.items[1:]
-	return item, true
-}
-
-func (q *Queue[T]) IsEmpty() bool {
-	return len(q.items) == 0
-}
-
-// 泛型集合（基于 map）
-type Set[T comparable] struct {
-	items map[T]struct{}
-}
-
-func NewSet[T comparable]() *Set[T] {
-	return &Set[T]{items: make(map[T]struct{})}
-}
-
-func (s *Set[T]) Add(item T) {
-	s.items[item] = struct{}{}
-}
-
-func (s *Set[T]) Remove(item T) {
-	delete(s.items, item)
-}
-
-func (s *Set[T]) Contains(item T) bool {
-	_, ok := s.items[item]
-	return ok
-}
-
-func (s *Set[T]) Size() int {
-	return len(s.items)
-}
-
-func (s *Set[T]) ToSlice() []T {
-	result := make([]T, 0, len(s.items))
-	for item := range s.items {
-		result = append(result, item)
-	}
-	return result
-}
-
-// 泛型链表节点
-type ListNode[T any] struct {
-	Value T
-	Next  *ListNode[T]
-}
-
-type LinkedList[T any] struct {
-	head *ListNode[T]
-	size int
-}
-
-func NewLinkedList[T any]() *LinkedList[T] {
-	return &LinkedList[T]{}
-}
-
-func (l *LinkedList[T]) Append(value T) {
-	newNode := &ListNode[T]{Value: value}
-	
-	if l.head == nil {
-		l.head = newNode
-	} else {
-		current := l.head
-		for current.Next != nil {
-			current = current.Next
-		}
-		current.Next = newNode
-	}
-	l.size++
-}
+// 类型也可以是泛型的：类型参数写在类型名后面，方法的接收者也要带上
+//
+//   type Stack[T any] struct {
+//       items []T
+//   }
+//
+//   func (s *Stack[T]) Push(item T) { s.items = append(s.items, item) }
+//
+// 本节的 Stack、Queue（环形缓冲区）、BlockingQueue、Set、LinkedList 在 pkg/container，
+// 其他课程和 cmd 也会用到它们，实现和注释都在那里。
+// 环形缓冲区与 items = items[1:] 切片实现的性能对比：
+//   go test -bench Queue -benchmem ./pkg/container
 
 func demonstrateGenericTypes() {
 	fmt.Println("\n=== 泛型类型 ===")
 	
 	// Stack
-	intStack := NewStack[int]()
+	intStack := container.NewStack[int]()
 	intStack.Push(1)
 	intStack.Push(2)
 	intStack.Push(3)
@@ -387,12 +276,12 @@ func demonstrateGenericTypes() {
 	fmt.Printf("Stack size: %d\n", intStack.Size())
 	
 	// 字符串栈
-	strStack := NewStack[string]()
+	strStack := container.NewStack[string]()
 	strStack.Push("hello")
 	strStack.Push("world")
 	
 	// Queue
-	queue := NewQueue[int]()
+	queue := container.NewQueue[int]()
 	queue.Enqueue(1)
 	queue.Enqueue(2)
 	queue.Enqueue(3)
@@ -400,9 +289,30 @@ func demonstrateGenericTypes() {
 	if val, ok := queue.Dequeue(); ok {
 		fmt.Printf("Dequeue: %d\n", val)
 	}
+	if val, ok := queue.Peek(); ok {
+		fmt.Printf("Peek: %d, Len: %d\n", val, queue.Len())
+	}
+	
+	// 非破坏性地遍历
+	queue.Enqueue(4)
+	queue.Range(func(item int) bool {
+		fmt.Printf("Range: %d\n", item)
+		return item < 3 // 遇到 3 就停止
+	})
+	fmt.Printf("ToSlice: %v, Len: %d\n", queue.ToSlice(), queue.Len())
+	fmt.Printf("Drain: %v, Len: %d\n", queue.Drain(), queue.Len())
+	
+	queue.Enqueue(5)
+	queue.Clear()
+	
+	// 空队列：Peek/Dequeue 返回零值和 false，而不是 panic
+	_, peekOK := queue.Peek()
+	_, dequeueOK := queue.Dequeue()
+	fmt.Printf("Cleared: IsEmpty=%v, Len=%d, Peek ok=%v, Dequeue ok=%v\n",
+		queue.IsEmpty(), queue.Len(), peekOK, dequeueOK)
 	
 	// Set
-	set := NewSet[int]()
+	set := container.NewSet[int]()
 	set.Add(1)
 	set.Add(2)
 	set.Add(3)
@@ -413,11 +323,45 @@ func demonstrateGenericTypes() {
 	fmt.Printf("Contains 5: %v\n", set.Contains(5))
 	
 	// LinkedList
-	list := NewLinkedList[int]()
+	list := container.NewLinkedList[int]()
 	list.Append(1)
 	list.Append(2)
 	list.Append(3)
-	fmt.Printf("LinkedList size: %d\n", list.size)
+	fmt.Printf("LinkedList size: %d\n", list.Len())
+}
+
+func demonstrateBlockingQueue() {
+	fmt.Println("\n=== 泛型阻塞队列 ===")
+	
+	queue := container.NewBlockingQueue[string]()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	
+	// 两个消费者先启动，队列为空时阻塞在 Pop 上（不会空转）
+	var wg sync.WaitGroup
+	for id := 1; id <= 2; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := queue.Pop(ctx)
+				if err != nil {
+					fmt.Printf("消费者 %d 退出: %v\n", id, err)
+					return
+				}
+				fmt.Printf("消费者 %d 处理: %s\n", id, job)
+			}
+		}()
+	}
+	
+	for _, job := range []string{"任务A", "任务B", "任务C"} {
+		queue.Push(job)
+		time.Sleep(10 * time.Millisecond)
+	}
+	
+	// 没有新任务后，消费者在 ctx 超时时被唤醒并退出
+	wg.Wait()
+	fmt.Printf("剩余任务: %d\n", queue.Len())
 }
 
 // ============================================
@@ -527,7 +471,7 @@ func demonstrateTypeInference() {
 	fmt.Printf("x=%v, y=%v\n", x, y)
 	
 	// 泛型类型推导
-	stack := NewStack[int]()  // 必须显式指定，无法推导
+	stack := container.NewStack[int]() // 必须显式指定，无法推导
 	stack.Push(1)
 	
 	// 从字面量推导
@@ -540,83 +484,8 @@ func demonstrateTypeInference() {
 // ============================================
 // 7. 实用泛型模式
 // ============================================
-
-// 可选值类型（类似 Rust 的 Option）
-type Option[T any] struct {
-	value   T
-	present bool
-}
-
-func Some[T any](v T) Option[T] {
-	return Option[T]{value: v, present: true}
-}
-
-func None[T any]() Option[T] {
-	return Option[T]{present: false}
-}
-
-func (o Option[T]) IsSome() bool {
-	return o.present
-}
-
-func (o Option[T]) IsNone() bool {
-	return !o.present
-}
-
-func (o Option[T]) Unwrap() T {
-	if !o.present {
-		panic("called Unwrap on None")
-	}
-	return o.value
-}
-
-func (o Option[T]) UnwrapOr(defaultValue T) T {
-	if o.present {
-		return o.value
-	}
-	return defaultValue
-}
-
-// 结果类型（类似 Rust 的 Result）
-type Result[T any] struct {
-	value T
-	err   error
-}
-
-func Ok[T any](v T) Result[T] {
-	return Result[T]{value: v, err: nil}
-}
-
-func Err[T any](e error) Result[T] {
-	var zero T
-	return Result[T]{value: zero, err: e}
-}
-
-func (r Result[T]) IsOk() bool {
-	return r.err == nil
-}
-
-func (r Result[T]) IsErr() bool {
-	return r.err != nil
-}
-
-func (r Result[T]) Unwrap() T {
-	if r.err != nil {
-		panic(r.err)
-	}
-	return r.value
-}
-
-func (r Result[T]) UnwrapOr(defaultValue T) T {
-	if r.err == nil {
-		return r.value
-	}
-	return defaultValue
-}
-
-func (r Result[T]) Error() error {
-	return r.err
-}
+//
+// Option / Result 在 pkg/optional；Pair 只有两个字段，直接在这里定义
 
 // Pair 类型
 type Pair[A, B any] struct {
@@ -632,17 +501,17 @@ func demonstrateUtilityPatterns() {
 	fmt.Println("\n=== 实用泛型模式 ===")
 	
 	// Option
-	maybeValue := Some(42)
+	maybeValue := optional.Some(42)
 	if maybeValue.IsSome() {
 		fmt.Printf("Value: %d\n", maybeValue.Unwrap())
 	}
 	
-	noValue := None[int]()
+	noValue := optional.None[int]()
 	fmt.Printf("Or default: %d\n", noValue.UnwrapOr(0))
 	
 	// Result
-	success := Ok(42)
-	failure := Err[int](fmt.Errorf("something went wrong"))
+	success := optional.Ok(42)
+	failure := optional.Err[int](fmt.Errorf("something went wrong"))
 	
 	if success.IsOk() {
 		fmt.Printf("Success: %d\n", success.Unwrap())
@@ -659,14 +528,20 @@ func demonstrateUtilityPatterns() {
 }
 
 // ============================================
-// 主函数
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{ID: "08", Name: "08_generics", Title: "泛型：类型参数、约束、泛型数据结构", Run: Run})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 08
+func Run() {
 	demonstrateGenericFunctions()
 	demonstrateConstraints()
 	demonstrateCustomConstraints()
 	demonstrateGenericTypes()
+	demonstrateBlockingQueue()
 	demonstrateGenericInterfaces()
 	demonstrateTypeInference()
 	demonstrateUtilityPatterns()
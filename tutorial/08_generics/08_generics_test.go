@@ -0,0 +1,25 @@
+package generics
+
+import (
+	"slices"
+	"testing"
+
+	"c03/pkg/prop"
+)
+
+// 性质：有序切片中的每个元素都找得到，范围之外的值找不到
+func TestBinarySearchProperty(t *testing.T) {
+	err := prop.Check(prop.SliceOf(prop.Int(-1000, 1000)), func(xs []int) bool {
+		sorted := slices.Sorted(slices.Values(xs))
+		for _, x := range xs {
+			if i, ok := BinarySearch(sorted, x); !ok || sorted[i] != x {
+				return false
+			}
+		}
+		_, ok := BinarySearch(sorted, 1001)
+		return !ok
+	}, prop.WithRuns(500), prop.WithSeed(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,6 +10,7 @@
 // - 方法反射与调用
 // - 创建新值
 // - 反射的性能考量
+// - 结构体验证：标签方式（validateStruct）与流式方式（pkg/validate）
 //
 // 最佳实践：
 // 1. 尽量避免使用反射，它会降低性能并丧失类型安全
@@ -19,13 +20,22 @@
 // 5. 结构体标签解析是反射的常见用途
 // ============================================
 
-package main
+package reflection
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+
+	"c03/pkg/fake"
+	"c03/pkg/structmeta"
+	"c03/pkg/validate"
+	"c03/tutorial"
 )
 
 // ============================================
@@ -168,7 +178,12 @@ func demonstrateStructReflection() {
 		fmt.Printf("    Name: %s\n", field.Name)
 		fmt.Printf("    Type: %v\n", field.Type)
 		fmt.Printf("    Tag: %s\n", field.Tag)
-		fmt.Printf("    Value: %v\n", value.Interface())
+		// 未导出字段不能调用 Interface()（会 panic），但可以用 %v 直接打印 Value
+		if value.CanInterface() {
+			fmt.Printf("    Value: %v\n", value.Interface())
+		} else {
+			fmt.Printf("    Value: %v（未导出，CanInterface=false）\n", value)
+		}
 		fmt.Printf("    Exported: %v\n", field.PkgPath == "")  // 空表示导出
 	}
 	
@@ -427,19 +442,26 @@ func deepCopy(dst, src interface{}) error {
 func deepCopyValue(dst, src reflect.Value) {
 	switch src.Kind() {
 	case reflect.Ptr:
-		if src.IsNil() {
+		if src.IsNil() || !dst.CanSet() {
 			return
 		}
 		dst.Set(reflect.New(src.Elem().Type()))
 		deepCopyValue(dst.Elem(), src.Elem())
 		
 	case reflect.Struct:
+		// 先整体复制，未导出字段（如 time.Time 内部的字段）反射无法设置，只能这样浅拷贝；
+		// 再逐个深拷贝可以设置的字段
+		if dst.CanSet() {
+			dst.Set(src)
+		}
 		for i := 0; i < src.NumField(); i++ {
-			deepCopyValue(dst.Field(i), src.Field(i))
+			if dst.Field(i).CanSet() {
+				deepCopyValue(dst.Field(i), src.Field(i))
+			}
 		}
 		
 	case reflect.Slice:
-		if src.IsNil() {
+		if src.IsNil() || !dst.CanSet() {
 			return
 		}
 		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Cap()))
@@ -448,7 +470,7 @@ func deepCopyValue(dst, src reflect.Value) {
 		}
 		
 	case reflect.Map:
-		if src.IsNil() {
+		if src.IsNil() || !dst.CanSet() {
 			return
 		}
 		dst.Set(reflect.MakeMap(src.Type()))
@@ -485,7 +507,7 @@ func demonstrateDeepCopy() {
 	}
 	
 	var copied Node
-	if err := deepCopy(&copied, original); err != nil {
+	if err := deepCopy(&copied, *original); err != nil {
 		fmt.Printf("Copy error: %v\n", err)
 		return
 	}
@@ -501,52 +523,55 @@ func demonstrateDeepCopy() {
 // 10. 实用工具：结构体验证
 // ============================================
 
+// validateStruct 按 validate 标签检查字段
+// 字段列表和解析好的标签来自 pkg/structmeta 的缓存：同一个类型只遍历一次 reflect.Type，
+// 之后每次验证只读取字段值（加速效果见 cmd/reflectbench）
 func validateStruct(s interface{}) error {
 	v := reflect.ValueOf(s)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
-	
+
 	if v.Kind() != reflect.Struct {
 		return fmt.Errorf("expected struct, got %v", v.Kind())
 	}
-	
-	t := v.Type()
-	
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		value := v.Field(i)
-		
+
+	st, err := structmeta.Of(v.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range st.Fields {
+		value := v.Field(field.Index[0])
+		tag := field.Tag("validate")
+
 		// 检查 required
-		tag := field.Tag.Get("validate")
-		if strings.Contains(tag, "required") {
-			if isZeroValue(value) {
-				return fmt.Errorf("field %s is required", field.Name)
-			}
+		if tag.Has("required") && isZeroValue(value) {
+			return fmt.Errorf("field %s is required", field.Name)
 		}
-		
+
 		// 检查数值范围
-		if strings.Contains(tag, "min=") && (value.Kind() == reflect.Int || value.Kind() == reflect.Float64) {
-			minStr := extractTagValue(tag, "min=")
-			if minStr != "" {
-				min, _ := strconv.Atoi(minStr)
-				if int(value.Int()) < min {
-					return fmt.Errorf("field %s must be >= %d", field.Name, min)
-				}
+		var n float64
+		switch value.Kind() {
+		case reflect.Int:
+			n = float64(value.Int())
+		case reflect.Float64:
+			n = value.Float()
+		default:
+			continue
+		}
+		if minStr, ok := tag.Value("min"); ok {
+			if min, err := strconv.ParseFloat(minStr, 64); err == nil && n < min {
+				return fmt.Errorf("field %s must be >= %s", field.Name, minStr)
 			}
 		}
-		
-		if strings.Contains(tag, "max=") && (value.Kind() == reflect.Int || value.Kind() == reflect.Float64) {
-			maxStr := extractTagValue(tag, "max=")
-			if maxStr != "" {
-				max, _ := strconv.Atoi(maxStr)
-				if int(value.Int()) > max {
-					return fmt.Errorf("field %s must be <= %d", field.Name, max)
-				}
+		if maxStr, ok := tag.Value("max"); ok {
+			if max, err := strconv.ParseFloat(maxStr, 64); err == nil && n > max {
+				return fmt.Errorf("field %s must be <= %s", field.Name, maxStr)
 			}
 		}
 	}
-	
+
 	return nil
 }
 
@@ -600,11 +625,159 @@ func demonstrateValidation() {
 	}
 }
 
+// 10.1 用反射生成测试数据
+// pkg/fake 按字段类型和 validate 标签填充随机值：
+// Age 取 validate 里 min=0,max=150 的范围，Name 按字段名生成人名。
+// 固定种子时每次运行结果相同，适合批量构造合法数据来检验 validateStruct。
+func demonstrateFake() {
+	fmt.Println("\n=== 生成测试数据 ===")
+
+	f := fake.New(2024)
+	valid := 0
+	for i := range 100 {
+		var p Person
+		if err := f.Fake(&p); err != nil {
+			fmt.Println("生成失败:", err)
+			return
+		}
+		if i < 3 {
+			fmt.Printf("%+v\n", p)
+		}
+		if validateStruct(p) == nil {
+			valid++
+		}
+	}
+	fmt.Printf("100 个随机 Person 中通过验证的: %d\n", valid)
+}
+
+// 10.2 流式验证
+// 标签只能写固定的规则；pkg/validate 把规则写成代码：
+// 可以按条件启用规则、比较两个字段、复用 Go 函数，
+// 并且一次返回所有字段的结构化错误（validateStruct 遇到第一个错误就返回）。
+
+// Address 注册表单中的地址
+type Address struct {
+	City string
+	Zip  string
+}
+
+// Signup 注册表单
+type Signup struct {
+	Name     string
+	Email    string
+	Age      int
+	Role     string
+	Phone    string
+	Password string
+	Confirm  string
+	Address  *Address
+}
+
+var zipPattern = regexp.MustCompile(`^\d{6}$`)
+
+// validateSignup 管理员必须填手机号；填了地址时邮编必须是 6 位数字
+func validateSignup(u Signup) error {
+	return validate.Validate(u).
+		Field("Name", validate.NotEmpty(), validate.Length(2, 20)).
+		Field("Email", validate.NotEmpty(), validate.Email()).
+		Field("Age", validate.Between(0, 150)).
+		Field("Role", validate.OneOf("user", "admin")).
+		When(u.Role == "admin", func(v *validate.Validator) {
+			v.Field("Phone", validate.NotEmpty())
+		}).
+		Field("Password", validate.NotEmpty(), validate.Length(8, -1)).
+		Check("Confirm", u.Confirm == u.Password, "两次输入的密码不一致").
+		When(u.Address != nil, func(v *validate.Validator) {
+			v.Field("Address.Zip", validate.Matches(zipPattern, "邮编必须是 6 位数字"))
+		}).
+		Error()
+}
+
+func demonstrateFluentValidation() {
+	fmt.Println("\n=== 流式验证 ===")
+
+	ok := Signup{Name: "Alice", Email: "alice@example.com", Age: 30, Role: "user", Password: "wonderland", Confirm: "wonderland"}
+	fmt.Println("合法表单:", validateSignup(ok))
+
+	bad := Signup{Name: "A", Email: "alice@", Age: 200, Role: "admin", Password: "short", Confirm: "shrot",
+		Address: &Address{City: "上海", Zip: "2000"}}
+	err := validateSignup(bad)
+	fmt.Println("errors.Is(err, validate.ErrInvalid):", errors.Is(err, validate.ErrInvalid))
+	var fieldErrs validate.Errors
+	if errors.As(err, &fieldErrs) {
+		for _, e := range fieldErrs {
+			fmt.Printf("  %-12s %-10s %s\n", e.Field, e.Rule, e.Message)
+		}
+		// 结构化错误可以直接作为 API 的响应体
+		body, _ := json.Marshal(fieldErrs[:2])
+		fmt.Printf("JSON: %s\n", body)
+	}
+}
+
+// checkFluentValidation 检查练习 8
+func checkFluentValidation() error {
+	ok := Signup{Name: "Alice", Email: "alice@example.com", Age: 30, Role: "user", Password: "wonderland", Confirm: "wonderland"}
+	if err := validateSignup(ok); err != nil {
+		return fmt.Errorf("合法表单返回 %v，期望 nil", err)
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*Signup)
+		fields []string
+	}{
+		{"邮箱为空只报 NotEmpty", func(u *Signup) { u.Email = "" }, []string{"Email"}},
+		{"年龄越界", func(u *Signup) { u.Age = -1 }, []string{"Age"}},
+		{"管理员缺手机号", func(u *Signup) { u.Role = "admin" }, []string{"Phone"}},
+		{"普通用户不要求手机号", func(u *Signup) { u.Role = "user" }, nil},
+		{"密码不一致", func(u *Signup) { u.Confirm = "other" }, []string{"Confirm"}},
+		{"邮编格式", func(u *Signup) { u.Address = &Address{Zip: "abc"} }, []string{"Address.Zip"}},
+		{"多个字段同时出错", func(u *Signup) { u.Name, u.Age = "", 151 }, []string{"Name", "Age"}},
+	}
+	for _, c := range cases {
+		u := ok
+		c.modify(&u)
+		err := validateSignup(u)
+		var got []string
+		var fieldErrs validate.Errors
+		if errors.As(err, &fieldErrs) {
+			for _, e := range fieldErrs {
+				got = append(got, e.Field)
+			}
+		}
+		if !slices.Equal(got, c.fields) {
+			return fmt.Errorf("%s: 出错的字段 %v，期望 %v（err = %v）", c.name, got, c.fields, err)
+		}
+	}
+
+	err := validate.Validate(ok).Field("Nickname", validate.NotEmpty()).Error()
+	var fe *validate.FieldError
+	if !errors.As(err, &fe) || fe.Rule != "field" {
+		return fmt.Errorf("不存在的字段返回 %v，期望 rule 为 field 的错误", err)
+	}
+	return nil
+}
+
 // ============================================
-// 主函数
+// 入口
 // ============================================
 
-func main() {
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "09",
+		Name:  "09_reflect",
+		Title: "反射：Type/Value、结构体标签、动态调用",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "2", Title: "StructToMap：按 json 标签转换，递归嵌套结构体", Check: checkStructToMap},
+			{ID: "8", Title: "流式验证：条件规则与结构化字段错误", Check: checkFluentValidation},
+			{ID: "9", Title: "深拷贝：反射、gob 与 Clone 方法", Check: checkClone},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 09
+func Run() {
 	demonstrateBasicReflection()
 	demonstrateModifyValue()
 	demonstrateTypeInspection()
@@ -614,7 +787,10 @@ func main() {
 	demonstrateSliceMapReflection()
 	demonstrateCreateValues()
 	demonstrateDeepCopy()
+	demonstrateClone()
 	demonstrateValidation()
+	demonstrateFake()
+	demonstrateFluentValidation()
 	
 	// ============================================
 	// 练习题
@@ -630,6 +806,7 @@ func main() {
 	//   - 只处理导出字段
 	//   - 使用 json tag 作为 key
 	//   - 递归处理嵌套结构体
+	//   参考实现：structmap.go（字段和标签缓存在 pkg/structmeta）
 	//
 	// 练习 3：实现 map 到结构体的转换
 	//   func MapToStruct(m map[string]interface{}, s interface{}) error
@@ -648,6 +825,7 @@ func main() {
 	//   - Call(method string, args []interface{}, reply interface{}) error
 	//   - 使用反射检查方法签名
 	//   - 验证参数数量和类型
+	//   延伸：真实的 RPC 协议见 pkg/bankrpc（gRPC + protobuf），运行 go run ./cmd/bankrpc
 	//
 	// 练习 6：实现一个 ORM 风格的查询构建器
 	//   type Query struct { ... }
@@ -659,4 +837,15 @@ func main() {
 	//   func GenerateSchema(t interface{}) map[string]interface{}
 	//   - 从结构体标签生成 JSON Schema
 	//   - 支持 required、type、format 等字段
+	//
+	// 练习 8：实现流式验证器，作为标签验证的补充
+	//   Validate(u).Field("Email", NotEmpty(), Email()).Field("Age", Between(0, 150)).Error()
+	//   - 支持条件规则（When）和跨字段检查（Check）
+	//   - 返回所有字段的结构化错误，而不是遇到第一个就停止
+	//   参考实现：pkg/validate，演示见第 10.2 节
+	//
+	// 练习 9：比较三种深拷贝方式
+	//   - 反射 deepCopy、CloneViaGob[T any](v T) (T, error)、为类型写的 Clone 方法
+	//   - 对比未导出字段、空 map、time.Time 的处理结果
+	//   参考实现：clone.go，演示见第 9.1 节，基准见 cmd/clonebench
 }
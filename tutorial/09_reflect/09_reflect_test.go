@@ -0,0 +1,48 @@
+package reflection
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzExtractTagValue 检查 extractTagValue 不会 panic，
+// 取出的值以 "," 或空格结尾，并且确实紧跟在 key 后面出现在标签里
+// 运行：go test ./tutorial/09_reflect -fuzz FuzzExtractTagValue
+func FuzzExtractTagValue(f *testing.F) {
+	f.Add("min=0,max=150", "min=")
+	f.Add("min=0,max=150", "max=")
+	f.Add("required", "min=")
+	f.Add("min=,max=3", "min=")
+	f.Add("min=1 max=2", "min=")
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, tag, key string) {
+		got := extractTagValue(tag, key)
+		if strings.ContainsAny(got, ", ") {
+			t.Fatalf("extractTagValue(%q, %q) = %q，值中不应包含分隔符", tag, key, got)
+		}
+		if !strings.Contains(tag, key) {
+			if got != "" {
+				t.Fatalf("extractTagValue(%q, %q) = %q，标签中没有 key，期望空", tag, key, got)
+			}
+			return
+		}
+		if !strings.Contains(tag, key+got) {
+			t.Fatalf("extractTagValue(%q, %q) = %q，标签中没有 %q", tag, key, got, key+got)
+		}
+	})
+}
+
+// FuzzExtractTagValueRoundTrip 把任意键值拼成 "k=v,..." 后再取回
+func FuzzExtractTagValueRoundTrip(f *testing.F) {
+	f.Add("min", "0", "max=150")
+	f.Add("len", "", "required")
+	f.Fuzz(func(t *testing.T, key, value, rest string) {
+		if key == "" || strings.ContainsAny(key+value, ", =") {
+			return
+		}
+		tag := key + "=" + value + "," + rest
+		if got := extractTagValue(tag, key+"="); got != value {
+			t.Fatalf("extractTagValue(%q, %q) = %q，期望 %q", tag, key+"=", got, value)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+// ============================================
+// 9.1 深拷贝的三种方式
+// ============================================
+//
+// 第 9 节的 deepCopy 用反射递归复制；另外两种做法：
+//
+//   CloneViaGob  编码成 gob 再解码回来，借用序列化做深拷贝
+//   Clone 方法    为具体类型写（或用工具生成）的拷贝代码，见 Order.Clone
+//
+// 怎么选：
+//
+//                      反射 deepCopy        CloneViaGob            生成的 Clone
+//   未导出字段         浅拷贝（共享指针）   丢失（gob 只编码导出字段）  可以正确复制
+//   time.Time 等       整体复制             调用 GobEncode，正确     正确
+//   nil 与空切片       保留                 空切片变成 nil          保留
+//   多处指向同一对象   复制成多份           复制成多份              由代码决定
+//   环（链表成环等）   无限递归，栈溢出     无限递归，栈溢出        由代码决定
+//   interface 字段     浅拷贝（共享）       需要先 gob.Register      由代码决定
+//   chan、func         共享                 返回错误                由代码决定
+//   速度               中                   最慢（编码 + 解码）     最快，几乎不分配多余内存
+//
+//   - 性能敏感、类型固定：写 Clone 方法（字段多时用代码生成，和 cmd/enumgen 一样用 go:generate）
+//   - 任意类型、偶尔调用：反射 deepCopy
+//   - 类型里有 GobEncoder / BinaryMarshaler 实现、或本来就要序列化：CloneViaGob
+//   - 有环的数据结构三种都不能直接用，需要记录已复制对象的 map[指针]副本
+//
+// 基准对比见 cmd/clonebench。
+// ============================================
+
+package reflection
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"time"
+)
+
+// DeepCopy 用反射深拷贝 v，是 deepCopy 的泛型版本
+func DeepCopy[T any](v T) (T, error) {
+	var out T
+	err := deepCopy(&out, v)
+	return out, err
+}
+
+// CloneViaGob 先用 gob 编码 v，再解码到新值，得到不共享内存的副本
+// 限制见本文件开头的表格：未导出字段丢失，空切片变为 nil，不支持有环的数据
+func CloneViaGob[T any](v T) (T, error) {
+	var out T
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return out, fmt.Errorf("CloneViaGob: 编码 %T: %w", v, err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		return out, fmt.Errorf("CloneViaGob: 解码 %T: %w", v, err)
+	}
+	return out, nil
+}
+
+// Order 对比三种拷贝方式用的订单：切片、map、指针、time.Time 和一个未导出字段
+type Order struct {
+	ID        string
+	Customer  *Customer
+	Items     []OrderItem
+	Tags      map[string]string
+	CreatedAt time.Time
+	note      string // 未导出：只有 Clone 方法能复制
+}
+
+type Customer struct {
+	Name  string
+	Email string
+}
+
+type OrderItem struct {
+	SKU   string
+	Qty   int
+	Price float64
+}
+
+// Clone 深拷贝订单
+// 这就是代码生成工具会为 Order 生成的代码：逐字段复制，遇到指针、切片、map 就分配新的
+func (o *Order) Clone() *Order {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	if o.Customer != nil {
+		cust := *o.Customer
+		c.Customer = &cust
+	}
+	if o.Items != nil {
+		c.Items = make([]OrderItem, len(o.Items))
+		copy(c.Items, o.Items)
+	}
+	c.Tags = maps.Clone(o.Tags)
+	return &c
+}
+
+// NewSampleOrder 示例订单，items 件商品
+func NewSampleOrder(items int) *Order {
+	o := &Order{
+		ID:        "ORD-20240101-0001",
+		Customer:  &Customer{Name: "张三", Email: "zhangsan@example.com"},
+		Tags:      map[string]string{"channel": "app", "coupon": "NEW10"},
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		note:      "放门口",
+	}
+	for i := range items {
+		o.Items = append(o.Items, OrderItem{SKU: fmt.Sprintf("SKU-%03d", i), Qty: i%3 + 1, Price: 9.9 * float64(i+1)})
+	}
+	return o
+}
+
+func demonstrateClone() {
+	fmt.Println("\n=== 深拷贝的三种方式 ===")
+	o := NewSampleOrder(2)
+	viaReflect, _ := DeepCopy(o)
+	viaGob, _ := CloneViaGob(o)
+	generated := o.Clone()
+	o.Customer.Name = "李四" // 修改原值，三份副本都不受影响
+	for _, c := range []struct {
+		name string
+		o    *Order
+	}{{"反射", viaReflect}, {"gob", viaGob}, {"Clone", generated}} {
+		fmt.Printf("%-6s 顾客=%s 创建于=%s 备注=%q\n", c.name, c.o.Customer.Name, c.o.CreatedAt.Format(time.DateOnly), c.o.note)
+	}
+}
+
+// checkClone 检查练习 9
+func checkClone() error {
+	o := NewSampleOrder(3)
+	copies := map[string]func() (*Order, error){
+		"DeepCopy":    func() (*Order, error) { return DeepCopy(o) },
+		"CloneViaGob": func() (*Order, error) { return CloneViaGob(o) },
+		"Clone":       func() (*Order, error) { return o.Clone(), nil },
+	}
+	for name, clone := range copies {
+		c, err := clone()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if c == o || c.Customer == o.Customer || &c.Items[0] == &o.Items[0] {
+			return fmt.Errorf("%s: 副本与原值共享了内存", name)
+		}
+		if !reflect.DeepEqual(c.Items, o.Items) || *c.Customer != *o.Customer || !c.CreatedAt.Equal(o.CreatedAt) {
+			return fmt.Errorf("%s: 副本与原值不一致: %+v", name, c)
+		}
+		if !maps.Equal(c.Tags, o.Tags) {
+			return fmt.Errorf("%s: Tags 为 %v，期望 %v", name, c.Tags, o.Tags)
+		}
+		wantNote := o.note
+		if name == "CloneViaGob" {
+			wantNote = ""
+		}
+		if c.note != wantNote {
+			return fmt.Errorf("%s: 未导出字段 note 为 %q，期望 %q", name, c.note, wantNote)
+		}
+	}
+
+	// 空切片：反射保留，gob 不传输零值，解码后是 nil
+	type list struct{ S []int }
+	empty := list{S: []int{}}
+	if c, err := DeepCopy(empty); err != nil || c.S == nil {
+		return fmt.Errorf("DeepCopy 复制空切片得到 %#v, %v，期望保留为空切片", c.S, err)
+	}
+	if c, err := CloneViaGob(empty); err != nil || c.S != nil {
+		return fmt.Errorf("CloneViaGob 复制空切片得到 %#v, %v，期望 nil", c.S, err)
+	}
+
+	if _, err := CloneViaGob(struct{ F func() }{F: func() {}}); err == nil {
+		return errors.New("CloneViaGob 复制 func 字段应当返回错误")
+	}
+	return nil
+}
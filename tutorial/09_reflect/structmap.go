@@ -0,0 +1,167 @@
+// ============================================
+// 练习 2：结构体转 map
+// ============================================
+//
+// StructToMap 按 json 标签把结构体转换为 map[string]interface{}，规则与 encoding/json 接近：
+//   - 只处理导出字段；没有 json 标签时用字段名作为键，json:"-" 的字段跳过
+//   - omitempty 的字段为零值时省略
+//   - 嵌套的结构体（及指向结构体的指针）递归转换为 map，nil 指针为 nil
+//   - 没有标签的嵌入结构体，字段展开到外层
+//   - 实现了 json.Marshaler / encoding.TextMarshaler 的类型（time.Time 等）原样保留
+//
+// 字段和解析后的标签来自 pkg/structmeta 的缓存，同一个类型只解析一次。
+// ============================================
+
+package reflection
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"time"
+
+	"c03/pkg/structmeta"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// StructToMap 把结构体（或指向结构体的指针）转换为 map，其他类型返回 nil
+func StructToMap(s interface{}) map[string]interface{} {
+	v := reflect.ValueOf(s)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	m := make(map[string]interface{})
+	structToMap(v, m)
+	return m
+}
+
+func structToMap(v reflect.Value, m map[string]interface{}) {
+	st, err := structmeta.Of(v.Type())
+	if err != nil {
+		return
+	}
+	for _, f := range st.Fields {
+		tag := f.Tag("json")
+		name := tag.Name()
+		if name == "-" && len(tag.Items) == 1 {
+			continue
+		}
+		fv := v.Field(f.Index[0])
+		if f.Anonymous && name == "" && nestedStruct(fv.Type()) {
+			if fv = derefStruct(fv); fv.IsValid() {
+				structToMap(fv, m)
+			}
+			continue
+		}
+		if tag.Has("omitempty") && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		m[name] = fieldValue(fv)
+	}
+}
+
+// fieldValue 嵌套的结构体转换为 map，其他值原样返回
+func fieldValue(v reflect.Value) interface{} {
+	if !nestedStruct(v.Type()) {
+		return v.Interface()
+	}
+	sv := derefStruct(v)
+	if !sv.IsValid() {
+		return nil
+	}
+	m := make(map[string]interface{})
+	structToMap(sv, m)
+	return m
+}
+
+// nestedStruct 结构体或指向结构体的指针，且没有自定义的序列化方式
+func nestedStruct(t reflect.Type) bool {
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct &&
+		!reflect.PointerTo(t).Implements(jsonMarshalerType) && !reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// derefStruct 解引用指针，nil 指针返回无效的 Value
+func derefStruct(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		return v.Elem()
+	}
+	return v
+}
+
+// checkStructToMap 检查练习 2
+func checkStructToMap() error {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+	}
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type Account struct {
+		Audit
+		ID       int       `json:"id"`
+		Name     string    `json:"name"`
+		Password string    `json:"-"`
+		Nick     string    `json:"nick,omitempty"`
+		Home     Address   `json:"home"`
+		Work     *Address  `json:"work"`
+		Joined   time.Time `json:"joined"`
+		Tags     []string
+		secret   string
+	}
+	joined := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := Account{
+		Audit: Audit{CreatedBy: "admin"},
+		ID:    7, Name: "alice", Password: "p@ss",
+		Home:   Address{City: "北京"},
+		Joined: joined,
+		Tags:   []string{"vip"},
+		secret: "x",
+	}
+	want := map[string]interface{}{
+		"created_by": "admin",
+		"id":         7,
+		"name":       "alice",
+		"home":       map[string]interface{}{"city": "北京"},
+		"work":       nil,
+		"joined":     joined,
+		"Tags":       []string{"vip"},
+	}
+	if got := StructToMap(&a); !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("StructToMap = %v，期望 %v", got, want)
+	}
+
+	a.Work = &Address{City: "上海", Zip: "200000"}
+	got := StructToMap(a)
+	if work, ok := got["work"].(map[string]interface{}); !ok || !maps.Equal(work, map[string]interface{}{"city": "上海", "zip": "200000"}) {
+		return fmt.Errorf("指针字段 work 转换为 %v", got["work"])
+	}
+	if StructToMap(42) != nil || StructToMap((*Account)(nil)) != nil {
+		return fmt.Errorf("非结构体和 nil 指针应当返回 nil")
+	}
+	return nil
+}
@@ -0,0 +1,1464 @@
+// ============================================
+// Go 标准库常用包教程
+// ============================================
+//
+// 本文件涵盖 Go 标准库中常用的包：
+// - fmt - 格式化 I/O
+// - strings/bytes - 字符串和字节操作（Unicode 见 pkg/unitext）
+// - strconv - 类型转换
+// - time - 时间处理
+// - os/path/filepath - 文件系统
+// - archive/zip、archive/tar - 压缩包
+// - io/bufio - I/O 操作
+// - encoding/json - JSON 处理（自定义编解码见 pkg/jsontype）
+// - encoding/xml - XML 处理（多格式导出见 pkg/export）
+// - net/http - HTTP 服务（JWT 认证见 pkg/jwt，密码哈希见 pkg/cryptutil）
+// - net - UDP 数据报
+// - sync - 同步原语（已在 06_sync_context.go 覆盖）
+// - sort - 排序
+// - regexp - 正则表达式
+//
+// 最佳实践：
+// 1. 优先使用标准库，避免不必要的第三方依赖
+// 2. 了解每个包的适用场景和限制
+// 3. 注意资源释放（Close）和错误处理
+// 4. 使用 context 控制超时和取消
+// ============================================
+
+package stdlib
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"c03/pkg/clock"
+	"c03/pkg/cryptutil"
+	"c03/pkg/fsutil"
+	"c03/pkg/export"
+	"c03/pkg/httpserver"
+	"c03/pkg/jsontype"
+	"c03/pkg/jwt"
+	"c03/pkg/middleware"
+	"c03/pkg/udpmsg"
+	"c03/pkg/unitext"
+	"c03/tutorial"
+)
+
+// ============================================
+// 1. fmt 包 - 格式化 I/O
+// ============================================
+
+func demonstrateFmt() {
+	fmt.Println("=== fmt 包 ===")
+	
+	// Println - 自动添加空格和换行
+	fmt.Println("Hello", "World")  // Hello World
+	
+	// Printf - 格式化输出
+	name := "Go"
+	version := 1.22
+	fmt.Printf("语言: %s, 版本: %.2f\n", name, version)
+	
+	// 常用格式化动词
+	fmt.Printf("字符串: %s / %q\n", "hello", "hello")  // %q 带引号
+	fmt.Printf("整数: %d / %x / %b\n", 42, 42, 42)     // 十进制/十六进制/二进制
+	fmt.Printf("浮点数: %f / %.2f / %e\n", 3.14159, 3.14159, 3.14159)
+	fmt.Printf("布尔: %t\n", true)
+	fmt.Printf("任意类型: %v / %+v / %#v\n", 
+		struct{A int}{42},
+		struct{A int}{42},
+		struct{A int}{42})
+	
+	// Sprintf - 返回字符串
+	s := fmt.Sprintf("格式化后的字符串: %d", 42)
+	fmt.Println(s)
+	
+	// Fprintf - 写入 io.Writer
+	fmt.Fprintf(os.Stdout, "写入标准输出: %s\n", "test")
+	
+	// Scanf - 格式化输入
+	// var input string
+	// fmt.Print("输入: ")
+	// fmt.Scanf("%s", &input)
+	
+	// Errorf - 创建格式化错误
+	err := fmt.Errorf("发生错误，代码: %d", 500)
+	fmt.Printf("错误: %v\n", err)
+}
+
+// ============================================
+// 2. strings 包 - 字符串操作
+// ============================================
+
+func demonstrateStrings() {
+	fmt.Println("\n=== strings 包 ===")
+	
+	s := "  Hello, World!  "
+	
+	// 基本操作
+	fmt.Printf("Contains 'World': %v\n", strings.Contains(s, "World"))
+	fmt.Printf("HasPrefix 'Hello': %v\n", strings.HasPrefix(strings.TrimSpace(s), "Hello"))
+	fmt.Printf("HasSuffix '!': %v\n", strings.HasSuffix(strings.TrimSpace(s), "!"))
+	
+	// 查找
+	fmt.Printf("Index 'World': %d\n", strings.Index(s, "World"))
+	fmt.Printf("Count 'l': %d\n", strings.Count(s, "l"))
+	
+	// 大小写
+	fmt.Printf("ToUpper: %s\n", strings.ToUpper(s))
+	fmt.Printf("ToLower: %s\n", strings.ToLower(s))
+	
+	// 修剪
+	fmt.Printf("TrimSpace: %q\n", strings.TrimSpace(s))
+	fmt.Printf("Trim: %q\n", strings.Trim(s, " !"))
+	
+	// 替换
+	fmt.Printf("Replace: %s\n", strings.Replace(s, "World", "Go", 1))
+	fmt.Printf("ReplaceAll: %s\n", strings.ReplaceAll(s, "l", "L"))
+	
+	// 分割和连接
+	parts := strings.Split("a,b,c,d", ",")
+	fmt.Printf("Split: %v\n", parts)
+	
+	joined := strings.Join(parts, "-")
+	fmt.Printf("Join: %s\n", joined)
+	
+	// Fields - 按空白分割
+	fields := strings.Fields("  hello   world  ")
+	fmt.Printf("Fields: %v\n", fields)
+	
+	// Builder - 高效字符串构建（推荐用于大量拼接）
+	var builder strings.Builder
+	builder.WriteString("Hello")
+	builder.WriteByte(' ')
+	builder.WriteString("World")
+	fmt.Printf("Builder: %s\n", builder.String())
+	
+	// Reader - 字符串作为 io.Reader
+	reader := strings.NewReader("Hello, Reader!")
+	buf := make([]byte, 5)
+	reader.Read(buf)
+	fmt.Printf("Reader: %s\n", string(buf))
+}
+
+// ============================================
+// 2.1 Unicode - 字节、rune、字素与显示宽度
+// ============================================
+//
+// 上面的 strings 函数都按字节工作。处理中文和 emoji 时，
+// len、s[:n] 和 %-10s 都会"算错"，pkg/unitext 提供按字符处理的版本。
+
+func demonstrateUnicode() {
+	fmt.Println("\n=== Unicode 字符串 ===")
+
+	s := "Hello, 世界"
+	fmt.Printf("%q: 字节 %d, rune %d, 显示宽度 %d\n",
+		s, len(s), utf8.RuneCountInString(s), unitext.DisplayWidth(s))
+
+	// 按字节截断会切坏多字节字符
+	fmt.Printf("s[:8]              = %q\n", s[:8])
+	fmt.Printf("TruncateRunes(s,8) = %q\n", unitext.TruncateRunes(s, 8))
+
+	// 一个"字符"可能由多个 rune 组成：e + 组合重音、👍 + 肤色、👨 + ZWJ + 👩 + ZWJ + 👧
+	t := "cafe\u0301 👍🏽 👨\u200d👩\u200d👧 🇨🇳"
+	fmt.Printf("%q: rune %d, 字素 %d\n", t, utf8.RuneCountInString(t), unitext.GraphemeCount(t))
+	fmt.Printf("ReverseRunes:     %s\n", unitext.ReverseRunes(t))
+	fmt.Printf("ReverseGraphemes: %s\n", unitext.ReverseGraphemes(t))
+	fmt.Printf("SubstrGraphemes(t, 5, 6) = %s\n", unitext.SubstrGraphemes(t, 5, 6))
+
+	// 对齐表格：%-8s 按 rune 补空格，中文每个字占两列，会错位
+	rows := [][2]string{{"Go", "1.25"}, {"北京", "晴"}, {"東京タワー", "333m"}}
+	fmt.Println("按 rune 补齐（fmt 的宽度）:")
+	for _, r := range rows {
+		fmt.Printf("  |%-10s|%s\n", r[0], r[1])
+	}
+	fmt.Println("PadDisplayWidth:")
+	for _, r := range rows {
+		fmt.Printf("  |%s|%s\n", unitext.PadDisplayWidth(r[0], 10), r[1])
+	}
+
+	// 按显示宽度截断，不会把宽字符切成两半
+	title := "Go 语言并发编程实战：从入门到精通"
+	fmt.Printf("TruncateDisplayWidth(title, 16): %q\n", unitext.TruncateDisplayWidth(title, 16, "…"))
+}
+
+// ============================================
+// 3. strconv 包 - 类型转换
+// ============================================
+
+func demonstrateStrconv() {
+	fmt.Println("\n=== strconv 包 ===")
+	
+	// 字符串转整数
+	i, err := strconv.Atoi("42")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Atoi: %d\n", i)
+	
+	// 带基数的转换
+	i64, _ := strconv.ParseInt("1010", 2, 64)  // 二进制
+	fmt.Printf("ParseInt (binary): %d\n", i64)
+	
+	i64, _ = strconv.ParseInt("FF", 16, 64)    // 十六进制
+	fmt.Printf("ParseInt (hex): %d\n", i64)
+	
+	// 字符串转浮点数
+	f, _ := strconv.ParseFloat("3.14159", 64)
+	fmt.Printf("ParseFloat: %f\n", f)
+	
+	// 字符串转布尔值
+	b, _ := strconv.ParseBool("true")
+	fmt.Printf("ParseBool: %v\n", b)
+	
+	// 整数转字符串
+	s := strconv.Itoa(42)
+	fmt.Printf("Itoa: %s\n", s)
+	
+	// 带格式转换
+	s = strconv.FormatInt(42, 16)  // 十六进制
+	fmt.Printf("FormatInt (hex): %s\n", s)
+	
+	s = strconv.FormatFloat(3.14159, 'f', 2, 64)
+	fmt.Printf("FormatFloat: %s\n", s)
+	
+	// 引用和取消引用
+	quoted := strconv.Quote("Hello\nWorld!")
+	fmt.Printf("Quote: %s\n", quoted)
+	
+	unquoted, _ := strconv.Unquote(`"Hello\nWorld!"`)
+	fmt.Printf("Unquote: %q\n", unquoted)
+}
+
+// ============================================
+// 4. time 包 - 时间处理
+// ============================================
+
+func demonstrateTime() {
+	fmt.Println("\n=== time 包 ===")
+	
+	// 当前时间
+	now := time.Now()
+	fmt.Printf("Now: %v\n", now)
+	fmt.Printf("Formatted: %s\n", now.Format("2006-01-02 15:04:05"))
+	
+	// 时间解析
+	t, _ := time.Parse("2006-01-02", "2024-03-15")
+	fmt.Printf("Parsed: %v\n", t)
+	
+	// 时间戳
+	timestamp := now.Unix()
+	fmt.Printf("Unix timestamp: %d\n", timestamp)
+	
+	// 时间计算
+	tomorrow := now.Add(24 * time.Hour)
+	fmt.Printf("Tomorrow: %v\n", tomorrow)
+	
+	diff := tomorrow.Sub(now)
+	fmt.Printf("Difference: %v\n", diff)
+	fmt.Printf("Hours: %f\n", diff.Hours())
+	
+	// 时间比较
+	fmt.Printf("Before: %v\n", now.Before(tomorrow))
+	fmt.Printf("After: %v\n", now.After(tomorrow))
+	fmt.Printf("Equal: %v\n", now.Equal(tomorrow))
+	
+	// 定时器
+	timer := time.NewTimer(100 * time.Millisecond)
+	<-timer.C
+	fmt.Println("Timer expired")
+	
+	// Ticker
+	ticker := time.NewTicker(50 * time.Millisecond)
+	go func() {
+		count := 0
+		for range ticker.C {
+			count++
+			if count >= 3 {
+				ticker.Stop()
+				return
+			}
+			fmt.Println("Tick")
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+	
+	// 睡眠
+	start := time.Now()
+	time.Sleep(50 * time.Millisecond)
+	fmt.Printf("Slept for: %v\n", time.Since(start))
+	
+	// 时区
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	shanghaiTime := now.In(loc)
+	fmt.Printf("Shanghai time: %v\n", shanghaiTime)
+}
+
+// ============================================
+// 5. os 和 filepath 包 - 文件系统
+// ============================================
+
+func demonstrateOS() {
+	fmt.Println("\n=== os/filepath 包 ===")
+	
+	// 获取当前工作目录
+	wd, _ := os.Getwd()
+	fmt.Printf("Working directory: %s\n", wd)
+	
+	// 创建临时文件
+	tmpfile, _ := os.CreateTemp("", "example-*.txt")
+	defer os.Remove(tmpfile.Name())
+	
+	tmpfile.WriteString("Hello, World!")
+	tmpfile.Close()
+	fmt.Printf("Temp file: %s\n", tmpfile.Name())
+	
+	// 文件操作
+	filename := "test.txt"
+	
+	// 写入文件
+	os.WriteFile(filename, []byte("Hello, File!"), 0644)
+	
+	// 读取文件
+	content, _ := os.ReadFile(filename)
+	fmt.Printf("File content: %s\n", string(content))
+	
+	// 删除文件
+	os.Remove(filename)
+	
+	// 目录操作
+	os.Mkdir("testdir", 0755)
+	os.MkdirAll("testdir/subdir1/subdir2", 0755)
+	
+	// 读取目录
+	entries, _ := os.ReadDir(".")
+	fmt.Printf("Entries in current dir: %d\n", len(entries))
+	
+	// 清理
+	os.RemoveAll("testdir")
+	
+	// 路径操作
+	path := "/usr/local/bin/go"
+	fmt.Printf("Dir: %s\n", filepath.Dir(path))
+	fmt.Printf("Base: %s\n", filepath.Base(path))
+	fmt.Printf("Ext: %s\n", filepath.Ext(path))
+	fmt.Printf("Clean: %s\n", filepath.Clean("/usr//local/../bin/go"))
+	
+	// 拼接路径
+	newPath := filepath.Join("usr", "local", "bin", "go")
+	fmt.Printf("Join: %s\n", newPath)
+	
+	// 路径分隔符转换
+	fmt.Printf("ToSlash: %s\n", filepath.ToSlash(`C:\Users\name`))
+}
+
+// ============================================
+// 5.1 压缩包 - archive/zip、archive/tar、compress/gzip
+// ============================================
+//
+// 打包和解包的实现见 pkg/fsutil（ZipDir/Unzip/TarGzDir/UntarGz）。
+// 解包时必须检查条目路径：名为 "../evil.txt" 的条目如果直接 Join 到目标目录，
+// 会写到目标目录之外（Zip Slip 漏洞），filepath.IsLocal 可以识别这类路径。
+
+func demonstrateArchive() {
+	fmt.Println("\n=== archive/zip 和 archive/tar ===")
+
+	tmp, err := os.MkdirTemp("", "archive-demo-*")
+	if err != nil {
+		fmt.Printf("MkdirTemp error: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	// 准备一个小目录树
+	src := filepath.Join(tmp, "src")
+	os.MkdirAll(filepath.Join(src, "docs", "empty"), 0755)
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(src, "docs", "README.md"), []byte("# demo\n"), 0644)
+
+	for _, a := range []struct {
+		name   string
+		pack   func(src, dest string) error
+		unpack func(src, destDir string) error
+	}{
+		{"demo.zip", fsutil.ZipDir, fsutil.Unzip},
+		{"demo.tar.gz", fsutil.TarGzDir, fsutil.UntarGz},
+	} {
+		archive := filepath.Join(tmp, a.name)
+		if err := a.pack(src, archive); err != nil {
+			fmt.Printf("%s 打包失败: %v\n", a.name, err)
+			continue
+		}
+		out := filepath.Join(tmp, "out-"+a.name)
+		if err := a.unpack(archive, out); err != nil {
+			fmt.Printf("%s 解包失败: %v\n", a.name, err)
+			continue
+		}
+
+		var files []string
+		filepath.WalkDir(out, func(p string, d os.DirEntry, err error) error {
+			if err == nil && p != out {
+				rel, _ := filepath.Rel(out, p)
+				if d.IsDir() {
+					rel += "/"
+				}
+				files = append(files, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		info, _ := os.Stat(archive)
+		fmt.Printf("%s (%d 字节) 解包得到: %v\n", a.name, info.Size(), files)
+	}
+
+	// 构造一个带 "../" 条目的恶意 zip，解包时被拒绝
+	evil := filepath.Join(tmp, "evil.zip")
+	f, _ := os.Create(evil)
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("../evil.txt")
+	w.Write([]byte("pwned"))
+	zw.Close()
+	f.Close()
+
+	err = fsutil.Unzip(evil, filepath.Join(tmp, "out-evil"))
+	fmt.Printf("恶意 zip: %v (ErrUnsafePath: %v)\n", err, errors.Is(err, fsutil.ErrUnsafePath))
+	if _, err := os.Stat(filepath.Join(tmp, "evil.txt")); os.IsNotExist(err) {
+		fmt.Println("目标目录之外没有写入任何文件")
+	}
+}
+
+// ============================================
+// 6. io 和 bufio 包 - I/O 操作
+// ============================================
+
+func demonstrateIO() {
+	fmt.Println("\n=== io/bufio 包 ===")
+	
+	// 使用 bytes.Buffer 作为 io.Writer
+	var buf bytes.Buffer
+	buf.WriteString("Hello")
+	buf.WriteByte(' ')
+	buf.Write([]byte("World"))
+	fmt.Printf("Buffer: %s\n", buf.String())
+	
+	// 使用 strings.Reader 作为 io.Reader
+	reader := strings.NewReader("Hello, Reader!")
+	data := make([]byte, 5)
+	n, _ := reader.Read(data)
+	fmt.Printf("Read: %s (%d bytes)\n", string(data[:n]), n)
+	
+	// io.Copy
+	reader2 := strings.NewReader("Copy this text")
+	var dest bytes.Buffer
+	io.Copy(&dest, reader2)
+	fmt.Printf("Copied: %s\n", dest.String())
+	
+	// bufio.Scanner - 逐行读取
+	input := "line1\nline2\nline3"
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		fmt.Printf("Scanned: %s\n", scanner.Text())
+	}
+	
+	// bufio.Reader - 带缓冲的读取
+	br := bufio.NewReader(strings.NewReader("Hello\nWorld"))
+	line, _ := br.ReadString('\n')
+	fmt.Printf("ReadString: %s\n", line)
+	
+	// bufio.Writer - 带缓冲的写入
+	var out bytes.Buffer
+	bw := bufio.NewWriter(&out)
+	bw.WriteString("Buffered write")
+	bw.Flush()  // 必须 Flush
+	fmt.Printf("Writer output: %s\n", out.String())
+}
+
+// ============================================
+// 7. encoding/json 包 - JSON 处理
+// ============================================
+
+// Birthday、SessionTimeout、Nickname 使用 pkg/jsontype 的自定义编解码，见 7.1 节
+// xml 和 csv 标签用于 7.3 节的多格式导出；",attr" 表示编码为 XML 属性而不是子元素
+type User struct {
+	ID             int                   `json:"id" xml:"id,attr" csv:"id"`
+	Name           string                `json:"name" xml:"name" csv:"name"`
+	Email          string                `json:"email,omitempty" xml:"email,omitempty" csv:"email"`
+	Age            int                   `json:"age" xml:"age" csv:"age"`
+	CreatedAt      time.Time             `json:"created_at" xml:"created_at" csv:"created_at"`
+	IsActive       bool                  `json:"is_active" xml:"active,attr" csv:"is_active"`
+	Birthday       jsontype.Date         `json:"birthday,omitzero" xml:"birthday" csv:"birthday"`
+	SessionTimeout jsontype.Duration     `json:"session_timeout,omitzero" xml:"session_timeout,omitempty" csv:"session_timeout"`
+	Nickname       jsontype.Null[string] `json:"nickname" xml:"nickname" csv:"nickname"`
+}
+
+func demonstrateJSON() {
+	fmt.Println("\n=== encoding/json 包 ===")
+	
+	// 结构体转 JSON（编码）
+	user := User{
+		ID:             1,
+		Name:           "Alice",
+		Email:          "alice@example.com",
+		Age:            30,
+		CreatedAt:      time.Now(),
+		IsActive:       true,
+		Birthday:       jsontype.Date{Year: 1994, Month: time.March, Day: 8},
+		SessionTimeout: jsontype.Duration(2*time.Hour + 30*time.Minute),
+		Nickname:       jsontype.NullOf("小艾"),
+	}
+	
+	// 紧凑格式
+	jsonData, _ := json.Marshal(user)
+	fmt.Printf("JSON: %s\n", string(jsonData))
+	
+	// 缩进格式
+	jsonPretty, _ := json.MarshalIndent(user, "", "  ")
+	fmt.Printf("Pretty JSON:\n%s\n", string(jsonPretty))
+	
+	// JSON 转结构体（解码）
+	jsonInput := `{"id":2,"name":"Bob","age":25,"created_at":"2024-01-15T10:30:00Z","is_active":false}`
+	
+	var decoded User
+	if err := json.Unmarshal([]byte(jsonInput), &decoded); err != nil {
+		fmt.Printf("Unmarshal error: %v\n", err)
+		return
+	}
+	fmt.Printf("Decoded: %+v\n", decoded)
+	
+	// 处理未知结构（使用 map）
+	var generic map[string]interface{}
+	json.Unmarshal([]byte(jsonInput), &generic)
+	fmt.Printf("Generic: %v\n", generic)
+	
+	// 流式解码
+	decoder := json.NewDecoder(strings.NewReader(jsonInput))
+	var streamUser User
+	decoder.Decode(&streamUser)
+	fmt.Printf("Stream decoded: %+v\n", streamUser)
+	
+	// 流式编码
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(user)
+	fmt.Printf("Stream encoded:\n%s", out.String())
+}
+
+// ============================================
+// 7.1 自定义编解码：json.Marshaler / json.Unmarshaler
+// ============================================
+//
+// 标签只能控制字段名和是否省略，值的格式由类型的 MarshalJSON / UnmarshalJSON 决定。
+// pkg/jsontype 提供三个常用类型：
+//   Date      "1994-03-08"，time.Time 会编码成带时分秒和时区的 RFC 3339
+//   Duration  "2h30m"，time.Duration 会编码成纳秒数 9000000000000
+//   Null[T]   null 与零值不同："nickname": null 表示没有设置，"" 表示设置成了空字符串
+//
+// 要点：
+// 1. MarshalJSON 用值接收者，UnmarshalJSON 用指针接收者（要修改接收者）
+// 2. 字段在 JSON 里不存在时不会调用 UnmarshalJSON，字段保持原值
+// 3. 实现 IsZero 后可以用 omitzero 标签省略零值（omitempty 对结构体无效）
+
+func demonstrateJSONCustom() {
+	fmt.Println("\n=== 自定义 JSON 编解码 ===")
+
+	// time.Duration 默认编码为纳秒数，jsontype.Duration 编码为字符串
+	raw, _ := json.Marshal(map[string]any{
+		"time.Duration":     90 * time.Minute,
+		"jsontype.Duration": jsontype.Duration(90 * time.Minute),
+	})
+	fmt.Printf("Duration: %s\n", raw)
+
+	inputs := []string{
+		`{"id":3,"name":"Carol","birthday":"2001-12-31","session_timeout":"45m","nickname":"小卡"}`,
+		`{"id":4,"name":"Dave","session_timeout":1800000000000,"nickname":null}`, // 旧数据：纳秒数
+		`{"id":5,"name":"Eve","nickname":""}`,                                    // 空字符串不是 null
+		`{"id":6,"name":"Mallory","birthday":"2001-02-30"}`,                      // 不存在的日期
+	}
+	for _, in := range inputs {
+		var u User
+		if err := json.Unmarshal([]byte(in), &u); err != nil {
+			fmt.Printf("  解码失败: %v\n", err)
+			continue
+		}
+		nick, ok := u.Nickname.Get()
+		fmt.Printf("  %-8s birthday=%-10s timeout=%-5v nickname=%q（有值: %v）\n",
+			u.Name, u.Birthday, u.SessionTimeout, nick, ok)
+	}
+
+	// 往返：编码再解码，自定义类型的字段不变
+	orig := User{ID: 7, Birthday: jsontype.Date{Year: 2000, Month: time.February, Day: 29}, SessionTimeout: jsontype.Duration(time.Hour)}
+	data, _ := json.Marshal(orig)
+	var back User
+	json.Unmarshal(data, &back)
+	fmt.Printf("往返: %s\n  birthday 相同: %v, timeout 相同: %v, nickname 仍为 null: %v\n",
+		data, back.Birthday == orig.Birthday, back.SessionTimeout == orig.SessionTimeout, !back.Nickname.Valid)
+}
+
+// ============================================
+// 7.2 JSON 路径查询
+// ============================================
+//
+// 解码到 map[string]any 的文档只能一层层做类型断言，
+// JSONGet 用 "users[2].email" 这样的路径一次取出嵌套的值：
+//   .name  取对象的字段      [2]  取数组的第 3 个元素（从 0 开始）
+// 路径可以以 [i] 开头，用于顶层就是数组的文档。
+
+var (
+	ErrPathSyntax   = errors.New("路径语法错误")
+	ErrPathNotFound = errors.New("路径不存在")
+)
+
+// JSONGet 按路径在 json.Unmarshal 到 any 的数据中取值
+// 数字是 float64，对象是 map[string]any，数组是 []any
+func JSONGet(data any, path string) (any, error) {
+	cur := data
+	walked := "" // 已经走过的路径，用于错误信息
+	rest := path
+
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q 中的 [ 没有闭合", ErrPathSyntax, path)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("%w: %q 中的下标 %q 无效", ErrPathSyntax, path, rest[1:end])
+			}
+			arr, ok := cur.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s 不是数组（%T）", ErrPathNotFound, displayPath(walked), cur)
+			}
+			if i >= len(arr) {
+				return nil, fmt.Errorf("%w: %s 只有 %d 个元素，下标 %d 越界", ErrPathNotFound, displayPath(walked), len(arr), i)
+			}
+			cur = arr[i]
+			walked += rest[:end+1]
+			rest = rest[end+1:]
+
+		default:
+			// 字段名前的 "."：开头可以省略，其余位置必须有
+			if rest[0] == '.' {
+				rest = rest[1:]
+			} else if walked != "" {
+				return nil, fmt.Errorf("%w: %q 在 %q 之后缺少 .", ErrPathSyntax, path, walked)
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("%w: %q 含有空字段名", ErrPathSyntax, path)
+			}
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s 不是对象（%T）", ErrPathNotFound, displayPath(walked), cur)
+			}
+			v, ok := obj[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s 没有字段 %q", ErrPathNotFound, displayPath(walked), key)
+			}
+			cur = v
+			if walked != "" {
+				walked += "."
+			}
+			walked += key
+			rest = rest[end:]
+		}
+	}
+	return cur, nil
+}
+
+func displayPath(p string) string {
+	if p == "" {
+		return "根节点"
+	}
+	return p
+}
+
+func demonstrateJSONGet() {
+	fmt.Println("\n=== JSON 路径查询 ===")
+
+	doc := `{
+		"users": [
+			{"name": "Alice", "email": "alice@example.com", "tags": ["admin"]},
+			{"name": "Bob", "email": "bob@example.com", "tags": []},
+			{"name": "Carol", "email": "carol@example.com", "tags": ["dev", "ops"]}
+		],
+		"meta": {"total": 3}
+	}`
+	var data any
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		fmt.Printf("Unmarshal error: %v\n", err)
+		return
+	}
+
+	for _, path := range []string{
+		"users[2].email",
+		"users[2].tags[1]",
+		"meta.total", // JSON 数字解码为 float64
+		"users[5].email",
+		"users.name",
+		"meta.count",
+		"users[x]",
+	} {
+		v, err := JSONGet(data, path)
+		if err != nil {
+			fmt.Printf("%-18s -> 错误: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("%-18s -> %v (%T)\n", path, v, v)
+	}
+
+	// 顶层是数组的文档
+	var list any
+	json.Unmarshal([]byte(`[[1, 2], [3, 4]]`), &list)
+	v, _ := JSONGet(list, "[1][0]")
+	fmt.Printf("%-18s -> %v\n", "[1][0]", v)
+}
+
+// ============================================
+// 7.3 encoding/xml 与多格式导出
+// ============================================
+//
+// encoding/xml 与 encoding/json 的用法几乎相同（Marshal / Unmarshal / Encoder / Decoder），
+// 区别在标签：
+//   xml:"name"           子元素 <name>...</name>
+//   xml:"id,attr"        属性 <User id="1">
+//   xml:",chardata"      元素的文本内容
+//   xml:"a>b"            嵌套元素 <a><b>...</b></a>
+//   XMLName xml.Name     指定元素名，否则使用类型名
+// 实现了 encoding.TextMarshaler 的类型（time.Time、jsontype.Date……）按文本编码。
+//
+// pkg/export 把 JSON、CSV、XML 统一成 Export(w, format, rows) / Import(r, format)。
+
+// sampleUsers 导出示例和练习 8 使用的数据
+func sampleUsers() []User {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return []User{
+		{ID: 1, Name: "Alice", Email: "alice@example.com", Age: 30, CreatedAt: created, IsActive: true,
+			Birthday: jsontype.Date{Year: 1994, Month: time.March, Day: 8}, SessionTimeout: jsontype.Duration(2 * time.Hour), Nickname: jsontype.NullOf("小艾")},
+		{ID: 2, Name: "Bob <admin>", Age: 25, CreatedAt: created.Add(36 * time.Hour)}, // 特殊字符会被转义
+	}
+}
+
+func demonstrateExport() {
+	fmt.Println("\n=== encoding/xml 与多格式导出 ===")
+
+	users := sampleUsers()
+	for _, format := range []export.Format{export.XML, export.CSV} {
+		var buf bytes.Buffer
+		if err := export.Export(&buf, format, users, export.WithRoot("users")); err != nil {
+			fmt.Printf("导出 %s 失败: %v\n", format, err)
+			continue
+		}
+		fmt.Printf("--- %s ---\n%s", format, buf.String())
+	}
+
+	// 导入：XML 解码回 []User
+	var buf bytes.Buffer
+	export.Export(&buf, export.XML, users)
+	back, err := export.Import[User](&buf, export.XML)
+	if err != nil {
+		fmt.Printf("导入失败: %v\n", err)
+		return
+	}
+	fmt.Printf("从 XML 导入 %d 个用户，第 2 个: %q，昵称 %v\n", len(back), back[1].Name, back[1].Nickname)
+}
+
+// checkExport 检查练习 8：每种格式导出后再导入，数据不变
+func checkExport() error {
+	users := sampleUsers()
+	for _, format := range export.Formats {
+		var buf bytes.Buffer
+		if err := export.Export(&buf, format, users); err != nil {
+			return fmt.Errorf("导出 %s: %w", format, err)
+		}
+		back, err := export.Import[User](&buf, format)
+		if err != nil {
+			return fmt.Errorf("导入 %s: %w", format, err)
+		}
+		if len(back) != len(users) {
+			return fmt.Errorf("%s 往返后有 %d 个用户，期望 %d 个", format, len(back), len(users))
+		}
+		for i := range users {
+			want, got := users[i], back[i]
+			// time.Time 解码后时区表示可能不同，用 Equal 比较时刻
+			if !got.CreatedAt.Equal(want.CreatedAt) {
+				return fmt.Errorf("%s 往返后第 %d 个用户 CreatedAt = %v，期望 %v", format, i, got.CreatedAt, want.CreatedAt)
+			}
+			got.CreatedAt = want.CreatedAt
+			if got != want {
+				return fmt.Errorf("%s 往返后第 %d 个用户\n  得到 %+v\n  期望 %+v", format, i, got, want)
+			}
+		}
+	}
+	if _, err := export.ParseFormat("yaml"); !errors.Is(err, export.ErrUnknownFormat) {
+		return fmt.Errorf("ParseFormat(\"yaml\") 返回 %v，期望 ErrUnknownFormat", err)
+	}
+	return nil
+}
+
+// ============================================
+// 8. net/http 包 - HTTP 服务
+// ============================================
+
+func helloHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "Hello, %s!\n", r.URL.Path[1:])
+}
+
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	
+	response := map[string]interface{}{
+		"method": r.Method,
+		"path":   r.URL.Path,
+		"query":  r.URL.Query(),
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+func demonstrateHTTP() {
+	fmt.Println("\n=== net/http 包 ===")
+
+	// 注册处理器
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", helloHandler)
+	mux.HandleFunc("/api", jsonHandler)
+
+	// 启动服务器（在后台）
+	// http.ListenAndServe 会一直阻塞且无法优雅关闭，
+	// 这里用 pkg/httpserver：ctx 取消或收到 Ctrl+C 时先排空进行中的请求再退出。
+	// 监听 127.0.0.1:0 让系统分配空闲端口，从 ln.Addr() 得到实际地址
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Printf("Listen error: %v\n", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- httpserver.ServeListener(ctx, ln, mux,
+			httpserver.WithDrainTimeout(2*time.Second),
+			httpserver.WithLogger(log.New(os.Stdout, "  [server] ", 0)))
+	}()
+	defer func() {
+		cancel()
+		if err := <-stopped; err != nil {
+			fmt.Printf("Server error: %v\n", err)
+		}
+	}()
+	baseURL := "http://" + ln.Addr().String()
+
+	// HTTP 客户端示例
+	fmt.Println("HTTP Client examples:")
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	// GET 请求
+	resp, err := client.Get(baseURL + "/hello")
+	if err != nil {
+		fmt.Printf("GET error: %v\n", err)
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	fmt.Printf("Status: %s\n", resp.Status)
+	fmt.Printf("Body: %s", body)
+
+	// 带查询参数的 JSON 接口
+	resp, err = client.Get(baseURL + "/api?lang=go&v=1")
+	if err != nil {
+		fmt.Printf("GET error: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	fmt.Printf("Content-Type: %s\n", resp.Header.Get("Content-Type"))
+	var apiResp map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		fmt.Printf("Decode error: %v\n", err)
+		return
+	}
+	fmt.Printf("API response: %v\n", apiResp)
+}
+
+// ============================================
+// 8.1 注册、登录与 JWT 无状态认证
+// ============================================
+//
+// 注册时只保存密码的慢哈希（cryptutil.HashPassword），登录时用 VerifyPassword 比较。
+// 登录成功后服务端签发一个 JWT（pkg/jwt），客户端之后每个请求都带上
+// Authorization: Bearer <令牌>。middleware.AuthJWT 只验证签名和有效期，
+// 不查数据库也不保存会话，任何一台持有密钥的服务器都能处理请求。
+
+var (
+	errUserExists   = errors.New("用户名已被注册")
+	errWeakPassword = errors.New("密码至少 8 个字符")
+	errLoginFailed  = errors.New("用户名或密码错误")
+)
+
+// userStore 用户名 -> 密码哈希
+type userStore struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func newUserStore() *userStore {
+	return &userStore{hashes: make(map[string]string)}
+}
+
+// register 计算哈希在锁外进行（约 0.1 秒），不阻塞其他请求
+func (s *userStore) register(user, password string) error {
+	if utf8.RuneCountInString(password) < 8 {
+		return errWeakPassword
+	}
+	hash, err := cryptutil.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.hashes[user]; ok {
+		return errUserExists
+	}
+	s.hashes[user] = hash
+	return nil
+}
+
+// dummyHash 用户不存在时也做一次同样耗时的验证，
+// 否则"用户不存在"比"密码错误"返回得快，响应时间会暴露哪些用户名已注册
+// 第一次用到时才计算，不拖慢程序启动
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := cryptutil.HashPassword("dummy password")
+	return hash
+})
+
+func (s *userStore) authenticate(user, password string) error {
+	s.mu.Lock()
+	hash, ok := s.hashes[user]
+	s.mu.Unlock()
+	if !ok {
+		cryptutil.VerifyPassword(password, dummyHash())
+		return errLoginFailed
+	}
+	if err := cryptutil.VerifyPassword(password, hash); err != nil {
+		return errLoginFailed
+	}
+	return nil
+}
+
+// credentials 注册和登录的请求体
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// authMux /register 注册，/login 签发令牌，/me 需要认证
+func authMux(signer *jwt.Signer, users *userStore) http.Handler {
+	decode := func(w http.ResponseWriter, r *http.Request) (credentials, bool) {
+		var c credentials
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil || c.User == "" {
+			http.Error(w, "请求体应为 {\"user\": ..., \"password\": ...}", http.StatusBadRequest)
+			return c, false
+		}
+		return c, true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /register", func(w http.ResponseWriter, r *http.Request) {
+		c, ok := decode(w, r)
+		if !ok {
+			return
+		}
+		switch err := users.register(c.User, c.Password); {
+		case errors.Is(err, errUserExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, errWeakPassword):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		c, ok := decode(w, r)
+		if !ok {
+			return
+		}
+		if err := users.authenticate(c.User, c.Password); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		token, err := signer.IssueClaims(jwt.Claims{Subject: c.User, Role: "user"})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": token})
+	})
+
+	me := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.ClaimsFrom(r.Context())
+		fmt.Fprintf(w, "你好 %s（角色 %s），令牌 %s 过期\n", claims.Subject, claims.Role, claims.ExpiresAt.Format(time.TimeOnly))
+	})
+	mux.Handle("GET /me", middleware.AuthJWT(signer)(me))
+	return mux
+}
+
+func demonstrateJWT() {
+	fmt.Println("\n=== 注册、登录与 JWT 无状态认证 ===")
+
+	// 假时钟：不用真的等 15 分钟就能演示过期
+	fc := clock.NewFakeClock(time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local))
+	signer := jwt.NewSigner([]byte("至少 32 字节的随机密钥，这里只是演示用的"),
+		jwt.WithIssuer("go-tutorial"), jwt.WithTTL(15*time.Minute), jwt.WithClock(fc))
+	users := newUserStore()
+
+	srv := httptest.NewServer(authMux(signer, users))
+	defer srv.Close()
+	client := srv.Client()
+
+	post := func(path, body string) (int, string) {
+		resp, err := client.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			return 0, err.Error()
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		fmt.Printf("POST %-9s %s -> %d %.50s\n", path, body, resp.StatusCode, strings.TrimSpace(string(b)))
+		return resp.StatusCode, string(b)
+	}
+	post("/register", `{"user":"alice","password":"123"}`)
+	post("/register", `{"user":"alice","password":"wonderland"}`)
+	post("/register", `{"user":"alice","password":"wonderland"}`)
+	fmt.Printf("保存的是哈希: %.40s…\n", users.hashes["alice"])
+	post("/login", `{"user":"alice","password":"wrong password"}`)
+	code, body := post("/login", `{"user":"alice","password":"wonderland"}`)
+	if code != http.StatusOK {
+		return
+	}
+
+	var login struct{ Token string }
+	json.Unmarshal([]byte(body), &login)
+	parts := strings.Split(login.Token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	fmt.Printf("  令牌: %s.%s.%s\n  载荷: %s\n",
+		parts[0][:10]+"…", parts[1][:10]+"…", parts[2][:10]+"…", payload)
+
+	get := func(label, token string) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/me", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Printf("GET /me error: %v\n", err)
+			return
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("GET /me（%s）-> %d %s", label, resp.StatusCode, body)
+	}
+	get("没有令牌", "")
+	get("有效令牌", login.Token)
+
+	// 把载荷里的 role 改成 admin：签名对不上
+	forged, _ := json.Marshal(map[string]any{"sub": "alice", "role": "admin", "iss": "go-tutorial"})
+	get("篡改载荷", parts[0]+"."+base64.RawURLEncoding.EncodeToString(forged)+"."+parts[2])
+
+	fc.Advance(16 * time.Minute)
+	get("16 分钟后", login.Token)
+}
+
+// ============================================
+// 8.2 net 包 - UDP
+// ============================================
+//
+// HTTP 跑在 TCP 上，连接、顺序和重传都由内核负责。
+// UDP 只负责把一个数据报送出去：可能丢、可能乱序、可能重复，
+// 应用层要自己分帧、给请求编号、超时重传。
+// pkg/udpmsg 是一个最小的实现，运行 go run ./cmd/udpdemo 可以看到丢包后的重传。
+
+func demonstrateUDP() {
+	fmt.Println("\n=== net 包：UDP ===")
+
+	// 最底层：ListenPacket + WriteTo/ReadFrom，一次读取正好是一个完整的数据报
+	a, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Printf("Listen error: %v\n", err)
+		return
+	}
+	defer a.Close()
+	b, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Printf("Listen error: %v\n", err)
+		return
+	}
+	defer b.Close()
+
+	a.WriteTo([]byte("hello"), b.LocalAddr())
+	buf := make([]byte, 1500)
+	b.SetReadDeadline(time.Now().Add(time.Second)) // 没有超时的话，丢包会让 ReadFrom 永远阻塞
+	n, from, err := b.ReadFrom(buf)
+	if err != nil {
+		fmt.Printf("ReadFrom error: %v\n", err)
+		return
+	}
+	fmt.Printf("收到 %q，来自 %s\n", buf[:n], from)
+
+	// 在上面加上包头、请求 ID 和重传，就得到可靠一些的请求/响应
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go udpmsg.Serve(ctx, a, func(_ net.Addr, req []byte) []byte {
+		return bytes.ToUpper(req)
+	}, log.New(io.Discard, "", 0))
+
+	client := udpmsg.NewClient(b, a.LocalAddr())
+	resp, err := client.Request(ctx, []byte("ping"))
+	if err != nil {
+		fmt.Printf("Request error: %v\n", err)
+		return
+	}
+	fmt.Printf("请求 \"ping\"，响应 %q\n", resp)
+}
+
+// ============================================
+// 9. sort 包 - 排序
+// ============================================
+
+func demonstrateSort() {
+	fmt.Println("\n=== sort 包 ===")
+	
+	// 整数切片排序
+	ints := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	sort.Ints(ints)
+	fmt.Printf("Sorted ints: %v\n", ints)
+	
+	// 字符串切片排序
+	strs := []string{"banana", "apple", "cherry"}
+	sort.Strings(strs)
+	fmt.Printf("Sorted strings: %v\n", strs)
+	
+	// 检查是否已排序
+	fmt.Printf("Ints are sorted: %v\n", sort.IntsAreSorted(ints))
+	
+	// 二分查找
+	idx := sort.SearchInts(ints, 5)
+	fmt.Printf("Found 5 at index: %d\n", idx)
+	
+	// 自定义排序
+	type Person struct {
+		Name string
+		Age  int
+	}
+	
+	people := []Person{
+		{"Bob", 25},
+		{"Alice", 30},
+		{"Charlie", 20},
+	}
+	
+	// 使用 sort.Slice
+	sort.Slice(people, func(i, j int) bool {
+		return people[i].Age < people[j].Age
+	})
+	fmt.Printf("Sorted by age: %+v\n", people)
+	
+	// 实现 sort.Interface
+	sort.Slice(people, func(i, j int) bool {
+		return people[i].Name < people[j].Name
+	})
+	fmt.Printf("Sorted by name: %+v\n", people)
+}
+
+// ============================================
+// 10. regexp 包 - 正则表达式
+// ============================================
+
+func demonstrateRegexp() {
+	fmt.Println("\n=== regexp 包 ===")
+	
+	// 编译正则表达式
+	re := regexp.MustCompile(`\b\w+@\w+\.\w+\b`)
+	
+	// 匹配检查
+	text := "Contact us at support@example.com or sales@company.org"
+	fmt.Printf("Contains email: %v\n", re.MatchString(text))
+	
+	// 查找匹配
+	match := re.FindString(text)
+	fmt.Printf("First match: %s\n", match)
+	
+	// 查找所有匹配
+	matches := re.FindAllString(text, -1)
+	fmt.Printf("All matches: %v\n", matches)
+	
+	// 提取子匹配
+	re2 := regexp.MustCompile(`(\w+)@(\w+)\.(\w+)`)
+	submatches := re2.FindStringSubmatch("user@example.com")
+	fmt.Printf("Submatches: %v\n", submatches)
+	
+	// 替换
+	replaced := re.ReplaceAllString(text, "[EMAIL]")
+	fmt.Printf("Replaced: %s\n", replaced)
+	
+	// 使用函数替换
+	masked := re.ReplaceAllStringFunc(text, func(s string) string {
+		parts := strings.Split(s, "@")
+		return "***@" + parts[1]
+	})
+	fmt.Printf("Masked: %s\n", masked)
+	
+	// 分割
+	re3 := regexp.MustCompile(`\s+`)
+	parts := re3.Split("hello   world  go", -1)
+	fmt.Printf("Split: %v\n", parts)
+	
+	// 查找索引
+	idx := re.FindStringIndex(text)
+	fmt.Printf("Match index: %v, matched: %s\n", idx, text[idx[0]:idx[1]])
+}
+
+// ============================================
+// 入口
+// ============================================
+
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "10",
+		Name:  "10_standard_lib",
+		Title: "标准库：fmt、strings、time、io、encoding/json、net/http",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "3", Title: "配置热加载", Check: checkReload},
+			{ID: "8", Title: "JSON / CSV / XML 多格式导出与导入", Check: checkExport},
+			{ID: "9", Title: "JWT 签发、验证与过期", Check: checkJWT},
+			{ID: "10", Title: "密码哈希与用户注册", Check: checkRegister},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 10
+func Run() {
+	demonstrateFmt()
+	demonstrateStrings()
+	demonstrateUnicode()
+	demonstrateStrconv()
+	demonstrateTime()
+	demonstrateOS()
+	demonstrateArchive()
+	demonstrateIO()
+	demonstrateJSON()
+	demonstrateJSONCustom()
+	demonstrateJSONGet()
+	demonstrateExport()
+	demonstrateHTTP()
+	demonstrateJWT()
+	demonstrateUDP()
+	demonstrateSort()
+	demonstrateRegexp()
+	
+	// ============================================
+	// 练习题
+	// ============================================
+	//
+	// 练习 1：实现一个日志分析工具
+	//   - 读取日志文件
+	//   - 使用 regexp 解析日志格式
+	//   - 统计各种级别的日志数量（INFO, WARN, ERROR）
+	//   - 按时间范围过滤日志
+	//   参考实现：pkg/logstat，运行 go run ./cmd/logstat cmd/logstat/sample.log
+	//
+	// 练习 2：实现一个简单的 Web 爬虫
+	//   - 接收起始 URL
+	//   - 使用 http.Get 获取页面
+	//   - 使用 regexp 提取所有链接
+	//   - 递归爬取（限制深度）
+	//   - 保存页面内容到文件
+	//   参考实现：pkg/crawler，运行 go run ./cmd/crawler -depth 1 https://go.dev/
+	//
+	// 练习 3：实现一个配置文件解析器
+	//   - 支持 JSON 格式
+	//   - 支持环境变量替换（${VAR}）
+	//   - 支持默认值（${VAR:-default}）
+	//   - 将配置加载到结构体
+	//   参考实现：pkg/config，运行 go run ./cmd/configcheck cmd/configcheck/sample.json
+	//   进阶：热加载——监视文件，修改后重新加载、校验，通过后原子替换并通知订阅者；
+	//   新配置无效时继续使用旧配置
+	//   参考实现：config.Config[T]，运行 go run ./cmd/configcheck -watch cmd/configcheck/sample.ini，检查见 reload.go
+	//
+	// 练习 4：实现一个 CSV 处理工具
+	//   - 读取 CSV 文件
+	//   - 解析为结构体切片
+	//   - 支持类型转换（使用 strconv）
+	//   - 写入 CSV 文件
+	//   - 支持过滤和排序
+	//   参考实现：pkg/csvutil，运行 go run ./cmd/csvtool -min-score 80 -sort score cmd/csvtool/sample.csv
+	//
+	// 练习 5：实现一个文件同步工具
+	//   - 比较两个目录的内容
+	//   - 使用 filepath.Walk 遍历
+	//   - 根据修改时间决定同步方向
+	//   - 支持排除某些文件模式
+	//   参考实现：pkg/dirsync，运行 go run ./cmd/dirsync -n -exclude '*.tmp,.git' <目录 A> <目录 B>
+	//
+	// 练习 6：实现一个 HTTP 中间件链
+	//   - LoggingMiddleware - 记录请求日志
+	//   - AuthMiddleware - 简单的 Token 验证
+	//   - RateLimitMiddleware - 限流
+	//   - RecoveryMiddleware - panic 恢复
+	//   - 使用函数式编程组合中间件
+	//   参考实现：pkg/middleware，运行 go run ./cmd/middlewaredemo
+	//
+	// 练习 7：实现一个模板引擎（简化版）
+	//   - 支持变量替换 {{.Name}}
+	//   - 支持条件语句 {{if .Condition}}...{{end}}
+	//   - 支持循环 {{range .Items}}...{{end}}
+	//   - 使用 regexp 和 strings 实现
+	//   参考实现：pkg/minitmpl，运行 go run ./cmd/tmpldemo
+	//
+	// 练习 8：实现多格式导出
+	//   - 为 User 加上 xml 和 csv 标签
+	//   - Export(w, format, rows) 支持 JSON、CSV、XML，Import(r, format) 读回
+	//   - 每种格式导出再导入后数据不变
+	//   参考实现：pkg/export，检查 go run ./cmd/tutorial check 10
+	//
+	// 练习 9：实现 JWT 无状态认证
+	//   - HS256 签名和验证，Claims 包含 sub、iss、iat、exp
+	//   - 拒绝 alg 不是 HS256 的令牌（包括 "none"）、篡改过的令牌、过期的令牌
+	//   - 中间件验证 Bearer 令牌，把 Claims 放进 context
+	//   参考实现：pkg/jwt 和 middleware.AuthJWT，演示见第 8.1 节
+	//
+	// 练习 10：实现用户注册
+	//   - 只保存加盐的慢哈希，参数和盐编码在保存的字符串里
+	//   - 用户名重复返回 409，密码太短返回 400
+	//   - 用户不存在和密码错误返回同样的错误，耗时也相同
+	//   参考实现：cryptutil.HashPassword / VerifyPassword 和第 8.1 节的 userStore
+}
+
+// checkRegister 检查练习 10
+func checkRegister() error {
+	// 练习里用 1000 次迭代，检查不用等太久
+	fast := cryptutil.PasswordHasher{Iterations: 1000}
+	hash, err := fast.Hash("wonderland")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(hash, "$pbkdf2-sha256$i=1000$") || strings.Contains(hash, "wonderland") {
+		return fmt.Errorf("保存的哈希 %q 应以 $pbkdf2-sha256$i=1000$ 开头且不含明文", hash)
+	}
+	if err := cryptutil.VerifyPassword("wonderland", hash); err != nil {
+		return fmt.Errorf("正确的密码验证失败: %w", err)
+	}
+	if err := cryptutil.VerifyPassword("Wonderland", hash); !errors.Is(err, cryptutil.ErrPasswordMismatch) {
+		return fmt.Errorf("错误的密码返回 %v，期望 ErrPasswordMismatch", err)
+	}
+	if again, _ := fast.Hash("wonderland"); again == hash {
+		return fmt.Errorf("同一个密码两次哈希结果相同，盐没有随机生成")
+	}
+	if !(cryptutil.PasswordHasher{}).NeedsRehash(hash) {
+		return fmt.Errorf("迭代 1000 次的哈希应当需要用默认参数重新计算")
+	}
+
+	h := authMux(jwt.NewSigner([]byte("check-secret")), newUserStore())
+	post := func(path, body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec.Code
+	}
+	for _, c := range []struct {
+		path, body string
+		want       int
+	}{
+		{"/register", `{"user":"bob","password":"short"}`, http.StatusBadRequest},
+		{"/register", `{"user":"bob","password":"long enough"}`, http.StatusCreated},
+		{"/register", `{"user":"bob","password":"another one"}`, http.StatusConflict},
+		{"/login", `{"user":"bob","password":"another one"}`, http.StatusUnauthorized},
+		{"/login", `{"user":"nobody","password":"long enough"}`, http.StatusUnauthorized},
+		{"/login", `{"user":"bob","password":"long enough"}`, http.StatusOK},
+	} {
+		if got := post(c.path, c.body); got != c.want {
+			return fmt.Errorf("POST %s %s 返回 %d，期望 %d", c.path, c.body, got, c.want)
+		}
+	}
+	return nil
+}
+
+// checkJWT 检查练习 9：登录拿到令牌后访问 /me，篡改、alg=none、过期的令牌都被拒绝
+func checkJWT() error {
+	fc := clock.NewFakeClock(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	signer := jwt.NewSigner([]byte("check-secret-check-secret-check!"), jwt.WithIssuer("check"), jwt.WithClock(fc))
+	users := newUserStore()
+	if err := users.register("alice", "wonderland"); err != nil {
+		return err
+	}
+	h := authMux(signer, users)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"alice","password":"wonderland"}`)))
+	var login struct{ Token string }
+	if err := json.NewDecoder(rec.Body).Decode(&login); err != nil || rec.Code != http.StatusOK {
+		return fmt.Errorf("登录返回 %d，解析令牌: %v", rec.Code, err)
+	}
+	me := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := me(login.Token); code != http.StatusOK {
+		return fmt.Errorf("有效令牌访问 /me 返回 %d，期望 200", code)
+	}
+
+	parts := strings.Split(login.Token, ".")
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","role":"admin","iss":"check"}`))
+	for name, tok := range map[string]string{
+		"alg=none": none + "." + parts[1] + ".",
+		"篡改载荷":     parts[0] + "." + forged + "." + parts[2],
+		"两段":       parts[0] + "." + parts[1],
+	} {
+		if code := me(tok); code != http.StatusUnauthorized {
+			return fmt.Errorf("%s 的令牌返回 %d，期望 401", name, code)
+		}
+	}
+	if _, err := signer.Verify(none + "." + parts[1] + "."); !errors.Is(err, jwt.ErrAlgorithm) {
+		return fmt.Errorf("alg=none 的令牌 Verify 返回 %v，期望 jwt.ErrAlgorithm", err)
+	}
+	if _, err := jwt.NewSigner([]byte("other-secret")).Verify(login.Token); !errors.Is(err, jwt.ErrSignature) {
+		return fmt.Errorf("用其他密钥验证返回 %v，期望 jwt.ErrSignature", err)
+	}
+
+	fc.Advance(jwt.DefaultTTL)
+	if _, err := signer.Verify(login.Token); !errors.Is(err, jwt.ErrExpired) {
+		return fmt.Errorf("%v 后 Verify 返回 %v，期望 jwt.ErrExpired", jwt.DefaultTTL, err)
+	}
+	if code := me(login.Token); code != http.StatusUnauthorized {
+		return fmt.Errorf("过期令牌访问 /me 返回 %d，期望 401", code)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package stdlib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"c03/pkg/export"
+)
+
+// TestUserExportRoundTrip User 的 json / xml / csv 标签在每种格式下都能往返
+func TestUserExportRoundTrip(t *testing.T) {
+	if err := checkExport(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserXMLTags(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.Export(&buf, export.XML, sampleUsers(), export.WithRoot("users")); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<User id="1" active="true">`, // xml:"id,attr"、xml:"active,attr"
+		`<User id="2" active="false">`,
+		"<email>alice@example.com</email>",
+		"<name>Bob &lt;admin&gt;</name>", // 特殊字符被转义
+		"<session_timeout>2h</session_timeout>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML 输出中没有 %q：\n%s", want, out)
+		}
+	}
+	// omitempty：Bob 没有 Email 和 SessionTimeout
+	if strings.Count(out, "<email>") != 1 || strings.Count(out, "<session_timeout>") != 1 {
+		t.Errorf("omitempty 的空字段不应输出：\n%s", out)
+	}
+}
@@ -0,0 +1,126 @@
+// ============================================
+// 练习 3 进阶：配置热加载
+// ============================================
+//
+// 检查 pkg/config 的 Config[T]：
+//   - 首次加载、Reload 换上新配置并通知订阅者，Get 立即看到新值
+//   - 新配置没有通过 Validate 时保留旧配置
+//   - Watch 在文件修改后自动重新加载（用假时钟推进轮询）
+// ============================================
+
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"c03/pkg/clock"
+	"c03/pkg/config"
+)
+
+type reloadConfig struct {
+	Level string `json:"level" config:"required"`
+	Limit int    `json:"limit"`
+}
+
+func (c *reloadConfig) Validate() error {
+	if c.Limit < 0 {
+		return fmt.Errorf("limit 不能为负数: %d", c.Limit)
+	}
+	return nil
+}
+
+// checkReload 检查练习 3 的进阶部分
+func checkReload() error {
+	dir, err := os.MkdirTemp("", "reload-check-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.json")
+
+	// 每次写入把修改时间往后拨一秒，不依赖文件系统时间戳的精度
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	write := func(content string) error {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+		mtime = mtime.Add(time.Second)
+		return os.Chtimes(path, mtime, mtime)
+	}
+	if err := write(`{"level": "${LEVEL:-info}", "limit": 10}`); err != nil {
+		return err
+	}
+
+	fc := clock.NewFakeClock(mtime)
+	cfg, err := config.New[reloadConfig](path,
+		config.WithClock(fc),
+		config.WithPollInterval(time.Second),
+		config.WithLookup(func(string) (string, bool) { return "", false }),
+		config.WithLogger(log.New(io.Discard, "", 0)))
+	if err != nil {
+		return err
+	}
+	if c := cfg.Get(); c.Level != "info" || c.Limit != 10 || cfg.Version() != 1 {
+		return fmt.Errorf("首次加载得到 %+v，版本 %d", *c, cfg.Version())
+	}
+
+	changes := make(chan [2]reloadConfig, 4)
+	cfg.Subscribe(func(old, cur *reloadConfig) { changes <- [2]reloadConfig{*old, *cur} })
+
+	if err := write(`{"level": "debug", "limit": 20}`); err != nil {
+		return err
+	}
+	if err := cfg.Reload(); err != nil {
+		return err
+	}
+	if ch := <-changes; ch[0].Level != "info" || ch[1].Level != "debug" || cfg.Get().Limit != 20 {
+		return fmt.Errorf("Reload 后通知 %+v，当前 %+v", ch, *cfg.Get())
+	}
+
+	// 无效的配置：校验失败、语法错误都保留旧配置
+	for _, bad := range []string{`{"level": "warn", "limit": -1}`, `{"level": "warn",`} {
+		if err := write(bad); err != nil {
+			return err
+		}
+		if err := cfg.Reload(); err == nil {
+			return fmt.Errorf("加载 %s 应当失败", bad)
+		}
+		if c := cfg.Get(); c.Level != "debug" || cfg.Version() != 2 {
+			return fmt.Errorf("加载失败后配置变成了 %+v，版本 %d", *c, cfg.Version())
+		}
+	}
+	if err := cfg.Reload(); !errors.Is(err, config.ErrSyntax) {
+		return fmt.Errorf("语法错误返回 %v，期望 ErrSyntax", err)
+	}
+
+	// Watch：修改文件后推进一个轮询间隔，订阅者收到新配置
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- cfg.Watch(ctx) }()
+	fc.BlockUntil(1) // Watch 的 ticker 已经创建
+	if err := write(`{"level": "error", "limit": 5}`); err != nil {
+		return err
+	}
+	fc.Advance(time.Second)
+	select {
+	case ch := <-changes:
+		if ch[1].Level != "error" || cfg.Version() != 3 {
+			return fmt.Errorf("Watch 重新加载后得到 %+v，版本 %d", ch[1], cfg.Version())
+		}
+	case <-time.After(2 * time.Second):
+		return errors.New("修改文件并推进轮询间隔后，Watch 没有重新加载")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("Watch 返回 %v，期望 context.Canceled", err)
+	}
+	return nil
+}
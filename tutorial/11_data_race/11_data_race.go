@@ -0,0 +1,240 @@
+// ============================================
+// Go 数据竞争教程
+// ============================================
+//
+// 本文件涵盖：
+// - 什么是数据竞争：两个 goroutine 同时访问同一变量，且至少一个是写
+// - 竞争检测器：go run -race / go build -race ⭐
+// - 修复方式：互斥锁、原子操作、把"检查 - 执行"放进同一个临界区
+// - map 和切片的并发写：MutexMap、MutexSlice
+// - 竞争条件（race condition）≠ 数据竞争（data race）：
+//   每一步都加了锁，整体逻辑仍可能出错，这种问题 -race 检测不到
+//
+// 文件组织：
+// - racy.go    故意写错的版本，全部小写，包外无法使用
+// - fixed.go   修复后的版本，导出给其他代码和练习检查使用
+//
+// 运行：
+//   go run ./cmd/tutorial 11          观察丢失更新和重复加载
+//   go run -race ./cmd/tutorial 11    竞争检测器会报告 racyCounter 的数据竞争，
+//                                      程序最后以状态码 66 退出
+//   go run -race ./cmd/tutorial check 11   练习只用修复后的版本，不会报告竞争
+//   go test -race ./tutorial/11_data_race  测试并发使用修复后的版本，去掉锁就会报告竞争
+//
+// 最佳实践：
+// 1. 测试和 CI 中始终加 -race 运行（有 5-10 倍的性能开销，不用于生产）
+// 2. -race 只能发现实际执行到的竞争，测试要真的并发访问
+// 3. map 的并发读写不是"结果不对"，而是直接 fatal error 终止程序，无法 recover
+// 4. 不要"只读不加锁"：读和写同时发生同样是数据竞争
+// ============================================
+
+package datarace
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"c03/tutorial"
+)
+
+// ============================================
+// 1. 丢失更新：n++ 不是原子操作
+// ============================================
+
+// hammer 启动 workers 个 goroutine，每个调用 c.Inc() times 次
+func hammer(c Counter, workers, times int) int64 {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range times {
+				c.Inc()
+			}
+		})
+	}
+	wg.Wait()
+	return c.Value()
+}
+
+func demonstrateLostUpdate() {
+	fmt.Println("=== 丢失更新 ===")
+	const workers, times = 100, 1000
+	fmt.Printf("%d 个 goroutine 各加 %d 次，期望 %d\n", workers, times, workers*times)
+
+	if raceEnabled {
+		fmt.Println("（-race 模式：下面的 racyCounter 会触发 WARNING: DATA RACE 报告）")
+	}
+	racy := hammer(&racyCounter{}, workers, times)
+	fmt.Printf("  racyCounter:   %d\n", racy)
+	fmt.Printf("  MutexCounter:  %d\n", hammer(&MutexCounter{}, workers, times))
+	fmt.Printf("  AtomicCounter: %d\n", hammer(&AtomicCounter{}, workers, times))
+	if racy == workers*times {
+		// 单核时 goroutine 很少在 n++ 中间被切换，结果碰巧正确，但竞争依然存在
+		fmt.Printf("  racyCounter 这次碰巧正确（GOMAXPROCS=%d），用 -race 运行可以看到竞争报告\n", runtime.GOMAXPROCS(0))
+	}
+}
+
+// ============================================
+// 2. check-then-act：没有数据竞争的竞争条件
+// ============================================
+
+// loader 统计加载次数的 GetOrLoad
+type loader interface {
+	GetOrLoad(key string, load func() (string, error)) (string, error)
+}
+
+func concurrentLoads(c loader, callers int) int64 {
+	var loads atomic.Int64
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Go(func() {
+			c.GetOrLoad("config", func() (string, error) {
+				loads.Add(1)
+				return "loaded", nil
+			})
+		})
+	}
+	wg.Wait()
+	return loads.Load()
+}
+
+func demonstrateCheckThenAct() {
+	fmt.Println("\n=== check-then-act ===")
+	fmt.Println("20 个 goroutine 同时读取同一个未缓存的键，期望只加载 1 次")
+	fmt.Printf("  racyCache（每步单独加锁）: 加载 %d 次，-race 不会报告\n", concurrentLoads(newRacyCache[string, string](), 20))
+	fmt.Printf("  Cache（登记后锁外加载）:   加载 %d 次\n", concurrentLoads(NewCache[string, string](), 20))
+}
+
+// ============================================
+// 3. 并发读写 map
+// ============================================
+
+func demonstrateMapRace() {
+	fmt.Println("\n=== 并发读写 map ===")
+	// 不实际运行：运行时检测到并发写 map 会直接 fatal error，整个程序退出，recover 也拦不住
+	//
+	//   m := map[int]int{}
+	//   for i := range 100 {
+	//       go func() { m[i] = i }()   // fatal error: concurrent map writes
+	//   }
+	//
+	// 修复：用 sync.Mutex / sync.RWMutex 保护（MutexMap），或者使用 sync.Map（见第 6 课）
+	var m MutexMap[int, int]
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() { m.Set(i, i*i) })
+	}
+	wg.Wait()
+	fmt.Printf("加锁后 100 个 goroutine 并发写入，Len() = %d\n", m.Len())
+}
+
+// ============================================
+// 4. 并发 append 切片
+// ============================================
+
+// appendAll 启动 workers 个 goroutine，每个 append times 次
+func appendAll(appendFn func(int), workers, times int) {
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range times {
+				appendFn(w*times + i)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func demonstrateSliceRace() {
+	fmt.Println("\n=== 并发 append 切片 ===")
+	const workers, times = 100, 100
+	fmt.Printf("%d 个 goroutine 各 append %d 次，期望长度 %d\n", workers, times, workers*times)
+
+	racy := &racySlice{}
+	appendAll(racy.Append, workers, times)
+	fmt.Printf("  racySlice:  %d\n", racy.Len())
+	fixed := &MutexSlice[int]{}
+	appendAll(func(v int) { fixed.Append(v) }, workers, times)
+	fmt.Printf("  MutexSlice: %d\n", fixed.Len())
+	if racy.Len() == workers*times {
+		fmt.Printf("  racySlice 这次碰巧正确（GOMAXPROCS=%d），用 -race 运行可以看到竞争报告\n", runtime.GOMAXPROCS(0))
+	}
+}
+
+// ============================================
+// 入口
+// ============================================
+
+func init() {
+	tutorial.Register(tutorial.Lesson{
+		ID:    "11",
+		Name:  "11_data_race",
+		Title: "数据竞争：竞争检测器、丢失更新、check-then-act",
+		Run:   Run,
+		Exercises: []tutorial.Exercise{
+			{ID: "1", Title: "用互斥锁和原子操作修复计数器", Check: checkCounters},
+			{ID: "2", Title: "并发 GetOrLoad 只加载一次", Check: checkCache},
+		},
+	})
+}
+
+// Run 运行本课的全部示例：go run ./cmd/tutorial 11
+func Run() {
+	demonstrateLostUpdate()
+	demonstrateCheckThenAct()
+	demonstrateMapRace()
+	demonstrateSliceRace()
+
+	// ============================================
+	// 练习题
+	// ============================================
+	//
+	// 练习 1：修复 racyCounter
+	//   - 分别用 sync.Mutex 和 atomic.Int64 实现 Counter 接口
+	//   - 用 go run -race ./cmd/tutorial check 11 确认没有竞争报告
+	//
+	// 练习 2：修复 racyCache 的 check-then-act
+	//   - 同一个键并发未命中时只调用一次 load，其他调用者等待结果
+	//   - load 失败时不缓存；load panic 时等待者不能永远阻塞
+	//   - 提示：持有锁时登记"正在加载"，在锁外执行 load
+	//   参考实现：pkg/syncutil 的 LoadCache（本课的 Cache 是它的别名）
+}
+
+// checkCounters 检查练习 1：并发累加的结果准确（用 -race 运行时还会检查数据竞争）
+func checkCounters() error {
+	for _, c := range []Counter{&MutexCounter{}, &AtomicCounter{}} {
+		if got := hammer(c, 50, 1000); got != 50000 {
+			return fmt.Errorf("%T: 50 个 goroutine 各加 1000 次得到 %d，期望 50000", c, got)
+		}
+	}
+	return nil
+}
+
+// checkCache 检查练习 2
+func checkCache() error {
+	c := NewCache[string, string]()
+	if loads := concurrentLoads(c, 50); loads != 1 {
+		return fmt.Errorf("50 个 goroutine 并发读取同一个键，加载了 %d 次，期望 1 次", loads)
+	}
+
+	// 失败不缓存
+	errBoom := errors.New("boom")
+	if _, err := c.GetOrLoad("k", func() (string, error) { return "", errBoom }); !errors.Is(err, errBoom) {
+		return fmt.Errorf("加载失败时返回 %v，期望 errBoom", err)
+	}
+	if v, err := c.GetOrLoad("k", func() (string, error) { return "ok", nil }); v != "ok" || err != nil {
+		return fmt.Errorf("失败后重新加载得到 %q, %v，期望 \"ok\", nil", v, err)
+	}
+
+	// load panic：panic 照常向上传播，之后的调用可以重新加载
+	func() {
+		defer func() { recover() }()
+		c.GetOrLoad("p", func() (string, error) { panic("boom") })
+	}()
+	if v, err := c.GetOrLoad("p", func() (string, error) { return "again", nil }); v != "again" || err != nil {
+		return fmt.Errorf("load panic 后重新加载得到 %q, %v，期望 \"again\", nil", v, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// ============================================
+// 修复后的版本
+// ============================================
+
+package datarace
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"c03/pkg/syncutil"
+)
+
+// ErrLoadPanicked 加载函数 panic，等待同一个键的其他调用者收到这个错误
+var ErrLoadPanicked = syncutil.ErrLoadPanicked
+
+// Counter 计数器
+type Counter interface {
+	Inc()
+	Value() int64
+}
+
+var (
+	_ Counter = (*MutexCounter)(nil)
+	_ Counter = (*AtomicCounter)(nil)
+	_ Counter = (*racyCounter)(nil)
+)
+
+// MutexCounter 用互斥锁保护的计数器，零值可用
+// 适合需要同时修改多个字段的场景
+type MutexCounter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *MutexCounter) Inc() {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *MutexCounter) Value() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// AtomicCounter 用原子操作实现的计数器，零值可用
+// 只有一个整数时比互斥锁更轻量
+type AtomicCounter struct {
+	n atomic.Int64
+}
+
+func (c *AtomicCounter) Inc()         { c.n.Add(1) }
+func (c *AtomicCounter) Value() int64 { return c.n.Load() }
+
+// MutexMap 用互斥锁保护的 map，零值可用
+// 并发写普通 map 会直接 fatal error；只读也要加锁，读写同时发生同样是数据竞争
+type MutexMap[K comparable, V any] struct {
+	mu sync.Mutex
+	m  map[K]V
+}
+
+func (m *MutexMap[K, V]) Set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = make(map[K]V)
+	}
+	m.m[key] = value
+}
+
+func (m *MutexMap[K, V]) Get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.m[key]
+	return v, ok
+}
+
+func (m *MutexMap[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.m)
+}
+
+// MutexSlice 用互斥锁保护的切片，零值可用
+// append 会读写切片头（指针、长度、容量），并发 append 既是数据竞争也会丢元素
+type MutexSlice[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (s *MutexSlice[T]) Append(items ...T) {
+	s.mu.Lock()
+	s.items = append(s.items, items...)
+	s.mu.Unlock()
+}
+
+func (s *MutexSlice[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// Snapshot 返回当前元素的副本，调用方可以在锁外随意使用
+func (s *MutexSlice[T]) Snapshot() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.items)
+}
+
+// Cache 并发安全的加载缓存：同一个键并发未命中时只加载一次，其他调用者等待结果
+// 实现在 pkg/syncutil（LoadCache），这里是别名：登记后锁外加载的写法见那里
+type Cache[K comparable, V any] = syncutil.LoadCache[K, V]
+
+// NewCache 创建空缓存
+func NewCache[K comparable, V any]() *Cache[K, V] { return syncutil.NewLoadCache[K, V]() }
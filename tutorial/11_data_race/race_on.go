@@ -0,0 +1,6 @@
+//go:build race
+
+package datarace
+
+// raceEnabled 用 go run -race / go build -race 构建时为 true
+const raceEnabled = true
@@ -0,0 +1,65 @@
+// ============================================
+// 故意写错的版本（只在本课的 Run 中演示，不导出）
+// ============================================
+//
+// 这里的类型都是小写的，包外无法使用；练习检查只使用 fixed.go 中的正确版本，
+// 所以 go run -race ./cmd/tutorial check 11 不会报告竞争。
+// ============================================
+
+package datarace
+
+import (
+	"sync"
+	"time"
+)
+
+// racyCounter 错误：多个 goroutine 同时执行 n++ 是数据竞争
+// n++ 实际是"读 n、加 1、写回 n"三步，两个 goroutine 可能读到同一个旧值，
+// 各自加 1 后写回，结果只加了 1（丢失更新）
+type racyCounter struct {
+	n int64
+}
+
+func (c *racyCounter) Inc()         { c.n++ }
+func (c *racyCounter) Value() int64 { return c.n }
+
+// racySlice 错误：多个 goroutine 同时 append 同一个切片
+// 两个 goroutine 可能读到同一个长度，把元素写到同一个位置，后写的覆盖先写的
+type racySlice struct {
+	items []int
+}
+
+func (s *racySlice) Append(v int) { s.items = append(s.items, v) }
+func (s *racySlice) Len() int     { return len(s.items) }
+
+// racyCache 错误：每一步单独加锁，但"查询 - 加载 - 写入"整体不是原子的（check-then-act）
+// 没有数据竞争，-race 检测不到；但多个 goroutine 同时未命中时，load 会被执行多次
+type racyCache[K comparable, V any] struct {
+	mu sync.Mutex
+	m  map[K]V
+}
+
+func newRacyCache[K comparable, V any]() *racyCache[K, V] {
+	return &racyCache[K, V]{m: make(map[K]V)}
+}
+
+func (c *racyCache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	v, ok := c.m[key]
+	c.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	// 锁已经释放：其他 goroutine 此时也会发现未命中，各自去加载
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	time.Sleep(time.Millisecond) // 放大窗口，让演示稳定复现
+
+	c.mu.Lock()
+	c.m[key] = v
+	c.mu.Unlock()
+	return v, nil
+}
@@ -0,0 +1,98 @@
+// ============================================
+// tutorial 包：课程注册表
+// ============================================
+//
+// 每一课是 tutorial/ 下的一个子包（如 tutorial/05_concurrency），
+// 原来的 main() 改为导出的 Run()，并在 init 中把自己注册到这里：
+//
+//   func init() {
+//       tutorial.Register(tutorial.Lesson{ID: "05", Name: "05_concurrency", Title: "...", Run: Run})
+//   }
+//
+// cmd/tutorial 匿名导入所有课程包，再通过 Lessons / Lookup 列出和运行：
+//
+//   go run ./cmd/tutorial list
+//   go run ./cmd/tutorial 05
+//
+// 与 database/sql 注册驱动的方式相同：导入即注册，注册表本身不依赖任何课程。
+//
+// 学习进度（运行过哪些课、通过了哪些练习）见 progress.go。
+// ============================================
+
+package tutorial
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Lesson 一课
+type Lesson struct {
+	ID    string // 两位编号，如 "05"
+	Name  string // 目录名，如 "05_concurrency"
+	Title string // 一行说明，显示在课程列表中
+	Run   func() // 运行本课的全部示例
+
+	// Exercises 可以自动检查的练习，由 tutorial check 运行，结果记录到学习进度
+	Exercises []Exercise
+}
+
+// Exercise 一道练习及其检查函数
+type Exercise struct {
+	ID    string       // 练习编号，与文件中的"练习 N"对应，如 "3"
+	Title string       // 一行说明
+	Check func() error // 返回 nil 表示通过；错误信息应说明期望值和实际值
+}
+
+var (
+	mu      sync.RWMutex
+	lessons = make(map[string]Lesson)
+)
+
+// Register 注册一课，通常在课程包的 init 中调用
+// 编号重复或 Run 为 nil 时 panic：这是程序错误，应当在启动时就暴露出来
+func Register(l Lesson) {
+	mu.Lock()
+	defer mu.Unlock()
+	if l.Run == nil {
+		panic("tutorial: 课程 " + l.ID + " 的 Run 为 nil")
+	}
+	if _, dup := lessons[l.ID]; dup {
+		panic("tutorial: 重复注册课程 " + l.ID)
+	}
+	lessons[l.ID] = l
+}
+
+// Lessons 按编号排序返回所有已注册的课程
+func Lessons() []Lesson {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Lesson, 0, len(lessons))
+	for _, l := range lessons {
+		out = append(out, l)
+	}
+	slices.SortFunc(out, func(a, b Lesson) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// Lookup 按编号或名字查找课程
+// 接受 "05"、"5"、"05_concurrency" 和 "concurrency"
+func Lookup(key string) (Lesson, bool) {
+	if n, err := strconv.Atoi(key); err == nil {
+		key = fmt.Sprintf("%02d", n)
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if l, ok := lessons[key]; ok {
+		return l, true
+	}
+	for _, l := range lessons {
+		if l.Name == key || strings.TrimPrefix(l.Name, l.ID+"_") == key {
+			return l, true
+		}
+	}
+	return Lesson{}, false
+}
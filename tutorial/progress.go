@@ -0,0 +1,235 @@
+// ============================================
+// 学习进度
+// ============================================
+//
+// 记录运行过哪些课、哪些练习通过了检查，保存在用户主目录下的 JSON 文件中：
+//
+//   ~/.go_tutorial/progress.json    （可以用环境变量 GO_TUTORIAL_PROGRESS 指定其他路径）
+//
+//   {
+//     "lessons":   {"05": {"runs": 2, "first_run": "...", "last_run": "..."}},
+//     "exercises": {"01/3": {"passed": true, "attempts": 1, "passed_at": "..."}}
+//   }
+//
+// 每次修改都是"读取 - 修改 - 写回"，写回时先写临时文件再 rename，
+// 中途被 Ctrl+C 打断也不会留下半个文件。
+// ============================================
+
+package tutorial
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"c03/pkg/clock"
+)
+
+// ProgressEnv 设置后覆盖默认的进度文件路径
+const ProgressEnv = "GO_TUTORIAL_PROGRESS"
+
+// Progress 学习进度
+type Progress struct {
+	Lessons   map[string]*LessonRecord   `json:"lessons"`   // 键为课程编号
+	Exercises map[string]*ExerciseRecord `json:"exercises"` // 键为 "课程编号/练习编号"
+}
+
+// LessonRecord 一课的运行记录
+type LessonRecord struct {
+	Runs     int       `json:"runs"`
+	FirstRun time.Time `json:"first_run"`
+	LastRun  time.Time `json:"last_run"`
+}
+
+// ExerciseRecord 一道练习的检查记录
+type ExerciseRecord struct {
+	Passed    bool      `json:"passed"`
+	Attempts  int       `json:"attempts"`
+	PassedAt  time.Time `json:"passed_at,omitzero"` // 第一次通过的时间
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// ExerciseKey 练习在 Progress.Exercises 中的键
+func ExerciseKey(lessonID, exerciseID string) string {
+	return lessonID + "/" + exerciseID
+}
+
+// Passed 返回一课中已通过的练习数
+func (p *Progress) Passed(l Lesson) int {
+	n := 0
+	for _, e := range l.Exercises {
+		if r := p.Exercises[ExerciseKey(l.ID, e.ID)]; r != nil && r.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// ProgressStore 进度文件，零值不可用，使用 NewProgressStore 创建
+type ProgressStore struct {
+	path  string
+	clock clock.Clock
+}
+
+// ProgressOption NewProgressStore 的选项
+type ProgressOption func(*ProgressStore)
+
+// WithClock 指定记录时间使用的时钟
+func WithClock(c clock.Clock) ProgressOption {
+	return func(s *ProgressStore) { s.clock = c }
+}
+
+// NewProgressStore 使用 path 作为进度文件，文件不存在时视为空进度
+func NewProgressStore(path string, opts ...ProgressOption) *ProgressStore {
+	s := &ProgressStore{path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.clock = clock.Or(s.clock)
+	return s
+}
+
+// DefaultProgressPath 返回 $GO_TUTORIAL_PROGRESS，未设置时为 ~/.go_tutorial/progress.json
+func DefaultProgressPath() (string, error) {
+	if p := os.Getenv(ProgressEnv); p != "" {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("定位进度文件: %w", err)
+	}
+	return filepath.Join(home, ".go_tutorial", "progress.json"), nil
+}
+
+// Path 进度文件路径
+func (s *ProgressStore) Path() string { return s.path }
+
+// Load 读取进度
+func (s *ProgressStore) Load() (*Progress, error) {
+	p := &Progress{
+		Lessons:   make(map[string]*LessonRecord),
+		Exercises: make(map[string]*ExerciseRecord),
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取进度: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("解析进度文件 %s: %w", s.path, err)
+	}
+	// 文件中可能没有某个字段（如只运行过课程），保证 map 非 nil
+	if p.Lessons == nil {
+		p.Lessons = make(map[string]*LessonRecord)
+	}
+	if p.Exercises == nil {
+		p.Exercises = make(map[string]*ExerciseRecord)
+	}
+	return p, nil
+}
+
+// save 原子地写回进度
+func (s *ProgressStore) save(p *Progress) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("保存进度: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("保存进度: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // rename 成功后是空操作
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("保存进度: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("保存进度: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("保存进度: %w", err)
+	}
+	return nil
+}
+
+// update 读取、修改、写回
+func (s *ProgressStore) update(fn func(p *Progress, now time.Time)) error {
+	p, err := s.Load()
+	if err != nil {
+		return err
+	}
+	fn(p, s.clock.Now())
+	return s.save(p)
+}
+
+// RecordRun 记录运行了一课
+func (s *ProgressStore) RecordRun(lessonID string) error {
+	return s.update(func(p *Progress, now time.Time) {
+		r := p.Lessons[lessonID]
+		if r == nil {
+			r = &LessonRecord{FirstRun: now}
+			p.Lessons[lessonID] = r
+		}
+		r.Runs++
+		r.LastRun = now
+	})
+}
+
+// RecordExercise 记录一次练习检查的结果，checkErr 为 nil 表示通过
+// 通过之后再失败（比如改坏了代码）会把 Passed 改回 false，但保留第一次通过的时间
+func (s *ProgressStore) RecordExercise(lessonID, exerciseID string, checkErr error) error {
+	return s.update(func(p *Progress, now time.Time) {
+		key := ExerciseKey(lessonID, exerciseID)
+		r := p.Exercises[key]
+		if r == nil {
+			r = &ExerciseRecord{}
+			p.Exercises[key] = r
+		}
+		r.Attempts++
+		r.Passed = checkErr == nil
+		r.LastError = ""
+		if checkErr != nil {
+			r.LastError = checkErr.Error()
+		} else if r.PassedAt.IsZero() {
+			r.PassedAt = now
+		}
+	})
+}
+
+// Reset 清除进度；指定 lessonID 时只清除该课的运行记录和练习记录
+func (s *ProgressStore) Reset(lessonID string) error {
+	if lessonID == "" {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("清除进度: %w", err)
+		}
+		return nil
+	}
+	return s.update(func(p *Progress, _ time.Time) {
+		delete(p.Lessons, lessonID)
+		for key := range p.Exercises {
+			if strings.HasPrefix(key, lessonID+"/") {
+				delete(p.Exercises, key)
+			}
+		}
+	})
+}
+
+// RunCheck 运行练习的检查函数，把 panic 转换为错误：检查的是学习者写的代码，不应让整个程序崩溃
+func RunCheck(e Exercise) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return e.Check()
+}